@@ -10,6 +10,7 @@ require (
 	github.com/spf13/viper v1.20.1 // Configuration management - handles config files and environment variables
 	github.com/stretchr/testify v1.10.0 // Testing toolkit with assertions and mocking capabilities
 	github.com/zalando/go-keyring v0.2.6 // Secure credential storage using OS keychain (macOS, Windows, Linux)
+	golang.org/x/sys v0.35.0 // Low-level OS interface for system calls (file locking on Windows)
 	golang.org/x/term v0.34.0 // Terminal handling utilities for raw mode and terminal size detection
 )
 
@@ -61,7 +62,6 @@ require (
 	golang.org/x/image v0.30.0 // indirect; indirect - Image manipulation (clipboard image support)
 	golang.org/x/mobile v0.0.0-20250808145247-395d808d53cd // indirect; indirect - Mobile platform support (clipboard dependency)
 	golang.org/x/sync v0.16.0 // indirect; indirect - Synchronization primitives and concurrent patterns
-	golang.org/x/sys v0.35.0 // indirect; indirect - Low-level OS interface for system calls
 	golang.org/x/text v0.28.0 // indirect; indirect - Text processing, encoding, and Unicode support
 	gopkg.in/yaml.v3 v3.0.1 // indirect - YAML parsing and serialization (config and test files)
 )
@@ -0,0 +1,135 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+)
+
+// PollBulkStatuses polls several batches at the specified interval and sends
+// their merged status, so a config submitted in chunks is followed as one
+// batch. Batches that reached a final status are not polled again.
+func (c *Client) PollBulkStatuses(ctx context.Context, batchIDs []string, interval time.Duration) (<-chan dto.BulkStatusResponse, error) {
+	if len(batchIDs) == 0 {
+		return nil, fmt.Errorf("batch ID cannot be empty")
+	}
+
+	statusChan := make(chan dto.BulkStatusResponse, 1)
+
+	go func() {
+		defer close(statusChan)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		latest := make(map[string]dto.BulkStatusData, len(batchIDs))
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, batchID := range batchIDs {
+					if status, ok := latest[batchID]; ok && bulkStatusFinal(status.Status) {
+						continue
+					}
+					status, err := c.GetBulkStatus(ctx, batchID)
+					if err != nil {
+						if c.debug {
+							logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+							logger.Debug("Failed to get bulk status", "batchID", batchID, "error", err)
+						}
+						continue
+					}
+					latest[batchID] = status.Data
+				}
+				if len(latest) == 0 {
+					continue
+				}
+
+				var statuses []dto.BulkStatusData
+				for _, batchID := range batchIDs {
+					if status, ok := latest[batchID]; ok {
+						statuses = append(statuses, status)
+					}
+				}
+				merged := MergeBulkStatuses(statuses)
+				if len(statuses) < len(batchIDs) && bulkStatusFinal(merged.Status) {
+					merged.Status = "PROCESSING"
+				}
+
+				select {
+				case statusChan <- dto.BulkStatusResponse{Data: merged}:
+				case <-ctx.Done():
+					return
+				}
+
+				if bulkStatusFinal(merged.Status) {
+					return
+				}
+			}
+		}
+	}()
+
+	return statusChan, nil
+}
+
+// MergeBulkStatuses combines batch statuses into one. The merged batch is
+// final once every batch is; it is COMPLETED or FAILED only when all batches
+// are, and PARTIALLY_FAILED otherwise.
+func MergeBulkStatuses(statuses []dto.BulkStatusData) dto.BulkStatusData {
+	if len(statuses) == 1 {
+		return statuses[0]
+	}
+
+	var merged dto.BulkStatusData
+	var batchIDs []string
+	final, completed, failed := true, true, true
+	for _, status := range statuses {
+		batchIDs = append(batchIDs, status.BatchID)
+		if merged.BatchTitle == nil {
+			merged.BatchTitle = status.BatchTitle
+		}
+		merged.Runs = append(merged.Runs, status.Runs...)
+
+		meta := status.Metadata
+		merged.Metadata.TotalRuns += meta.TotalRuns
+		merged.Metadata.Completed += meta.Completed
+		merged.Metadata.Processing += meta.Processing
+		merged.Metadata.Queued += meta.Queued
+		merged.Metadata.Failed += meta.Failed
+		if merged.Metadata.StartedAt == "" || (meta.StartedAt != "" && meta.StartedAt < merged.Metadata.StartedAt) {
+			merged.Metadata.StartedAt = meta.StartedAt
+		}
+		if meta.EstimatedCompletionTime != nil && (merged.Metadata.EstimatedCompletionTime == nil || *meta.EstimatedCompletionTime > *merged.Metadata.EstimatedCompletionTime) {
+			merged.Metadata.EstimatedCompletionTime = meta.EstimatedCompletionTime
+		}
+
+		final = final && bulkStatusFinal(status.Status)
+		completed = completed && status.Status == "COMPLETED"
+		failed = failed && status.Status == "FAILED"
+	}
+	merged.BatchID = strings.Join(batchIDs, ",")
+
+	switch {
+	case !final:
+		merged.Status = "PROCESSING"
+	case completed:
+		merged.Status = "COMPLETED"
+	case failed:
+		merged.Status = "FAILED"
+	default:
+		merged.Status = "PARTIALLY_FAILED"
+	}
+	return merged
+}
+
+func bulkStatusFinal(status string) bool {
+	return status == "COMPLETED" || status == "FAILED" || status == "PARTIALLY_FAILED"
+}
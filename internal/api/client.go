@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/daemon"
 	"github.com/repobird/repobird-cli/internal/errors"
 	"github.com/repobird/repobird-cli/internal/models"
 	"github.com/repobird/repobird-cli/internal/retry"
@@ -43,7 +44,8 @@ func NewClient(apiKey, baseURL string, debug bool) *Client {
 
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
+			Timeout:   DefaultTimeout,
+			Transport: daemon.Transport(baseURL, apiKey),
 		},
 		baseURL:        baseURL,
 		apiKey:         apiKey,
@@ -596,6 +598,21 @@ func (c *Client) GetRunWithRetry(ctx context.Context, id string) (*models.RunRes
 	return &runResp, nil
 }
 
+// CancelRun cancels a run that has not finished.
+func (c *Client) CancelRun(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("run ID cannot be empty")
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, http.MethodDelete, RunDetailsURL(id), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return ValidateResponseOK(resp)
+}
+
 // ListRepositories retrieves a list of repositories for the authenticated user
 func (c *Client) ListRepositories(ctx context.Context) ([]models.APIRepository, error) {
 	resp, err := c.doRequestWithRetry(ctx, "GET", EndpointRepositories, nil)
@@ -827,3 +844,23 @@ func (c *Client) PollBulkStatus(ctx context.Context, batchID string, interval ti
 
 	return statusChan, nil
 }
+
+// ListModels returns the OpenCode models that runs may select. It does not
+// retry, since callers fall back to a cached list.
+func (c *Client) ListModels() ([]models.ModelInfo, error) {
+	resp, err := c.doRequest("GET", EndpointModels, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := ValidateResponseOK(resp); err != nil {
+		return nil, err
+	}
+
+	var modelListResp models.ModelListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelListResp); err != nil {
+		return nil, fmt.Errorf("failed to decode models response: %w", err)
+	}
+	return modelListResp.Data, nil
+}
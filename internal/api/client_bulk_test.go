@@ -720,3 +720,50 @@ func TestClient_BulkRetryBehavior(t *testing.T) {
 		assert.Equal(t, 2, callCount)
 	})
 }
+
+func TestClient_PollBulkStatuses(t *testing.T) {
+	statuses := map[string]dto.BulkStatusData{
+		"batch-1": {BatchID: "batch-1", Status: "COMPLETED", Runs: []dto.RunStatusItem{{ID: 1, Status: "DONE"}},
+			Metadata: dto.BulkStatusMetadata{TotalRuns: 1, Completed: 1, StartedAt: "2024-01-01T10:05:00Z"}},
+		"batch-2": {BatchID: "batch-2", Status: "PROCESSING", Runs: []dto.RunStatusItem{{ID: 2, Status: "PROCESSING"}},
+			Metadata: dto.BulkStatusMetadata{TotalRuns: 1, Processing: 1, StartedAt: "2024-01-01T10:00:00Z"}},
+	}
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batchID := r.URL.Path[len("/api/v1/runs/bulk/"):]
+		calls[batchID]++
+		status := statuses[batchID]
+		if batchID == "batch-2" && calls[batchID] > 1 {
+			status.Status = "FAILED"
+			status.Runs = []dto.RunStatusItem{{ID: 2, Status: "FAILED"}}
+			status.Metadata.Processing, status.Metadata.Failed = 0, 1
+		}
+		_ = json.NewEncoder(w).Encode(dto.BulkStatusResponse{Data: status})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL, false)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statusChan, err := client.PollBulkStatuses(ctx, []string{"batch-1", "batch-2"}, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	var updates []dto.BulkStatusData
+	for status := range statusChan {
+		updates = append(updates, status.Data)
+	}
+	require.Len(t, updates, 2)
+	assert.Equal(t, "PROCESSING", updates[0].Status)
+	assert.Equal(t, "batch-1,batch-2", updates[0].BatchID)
+	assert.Equal(t, "2024-01-01T10:00:00Z", updates[0].Metadata.StartedAt)
+
+	final := updates[1]
+	assert.Equal(t, "PARTIALLY_FAILED", final.Status)
+	assert.Equal(t, dto.BulkStatusMetadata{TotalRuns: 2, Completed: 1, Failed: 1, StartedAt: "2024-01-01T10:00:00Z"}, final.Metadata)
+	assert.Len(t, final.Runs, 2)
+	assert.Equal(t, 1, calls["batch-1"], "a finished batch is not polled again")
+
+	_, err = client.PollBulkStatuses(ctx, nil, time.Second)
+	assert.Error(t, err)
+}
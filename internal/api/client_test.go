@@ -4,6 +4,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -444,3 +445,19 @@ func TestAPIError(t *testing.T) {
 		t.Errorf("unexpected error message: got %q, want %q", err.Error(), expectedMsg)
 	}
 }
+
+func TestCancelRunSendsDelete(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := NewClient("test-key", server.URL, false).CancelRun(context.Background(), "42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodDelete || path != "/api/v1/runs/42" {
+		t.Fatalf("unexpected request %s %s", method, path)
+	}
+}
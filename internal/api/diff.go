@@ -0,0 +1,62 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// runDiffResponse covers the JSON forms of the diff endpoint: wrapped in
+// data or not, under diff or the older diffString.
+type runDiffResponse struct {
+	Diff       string           `json:"diff"`
+	DiffString string           `json:"diffString"`
+	Data       *runDiffResponse `json:"data"`
+}
+
+func (r runDiffResponse) text() string {
+	if r.Data != nil {
+		return r.Data.text()
+	}
+	if r.Diff != "" {
+		return r.Diff
+	}
+	return r.DiffString
+}
+
+// GetRunDiff returns the unified diff of the changes a run made. The
+// endpoint may answer with the plain diff or with JSON.
+func (c *Client) GetRunDiff(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("run ID cannot be empty")
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, http.MethodGet, RunDiffURL(id), nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := ValidateResponseOK(resp); err != nil {
+		return "", err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read diff: %w", err)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return string(body), nil
+	}
+	var diff runDiffResponse
+	if err := json.Unmarshal(body, &diff); err != nil {
+		return "", fmt.Errorf("failed to decode diff response: %w", err)
+	}
+	return diff.text(), nil
+}
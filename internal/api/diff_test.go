@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRunDiffAcceptsPlainAndJSON(t *testing.T) {
+	const patch = "diff --git a/x b/x\n+added\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/runs/1/diff":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(patch))
+		case "/api/v1/runs/2/diff":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"diffString":"diff --git a/x b/x\n+added\n"}}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL, false)
+	for _, id := range []string{"1", "2"} {
+		diff, err := client.GetRunDiff(context.Background(), id)
+		if err != nil {
+			t.Fatalf("run %s: unexpected error: %v", id, err)
+		}
+		if diff != patch {
+			t.Fatalf("run %s: unexpected diff %q", id, diff)
+		}
+	}
+}
@@ -1,26 +1,48 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
 
 // BulkRunRequest represents a request to create multiple runs
 type BulkRunRequest struct {
-	RepositoryName string      `json:"repositoryName,omitempty"`
-	RepoID         int         `json:"repoId,omitempty"`
-	RunType        string      `json:"runType"`
-	SourceBranch   string      `json:"sourceBranch,omitempty"`
-	BatchTitle     string      `json:"batchTitle,omitempty"`
-	Force          bool        `json:"force,omitempty"`
-	Runs           []RunItem   `json:"runs"`
-	Options        BulkOptions `json:"options,omitempty"`
+	RepositoryName   string      `json:"repositoryName,omitempty"`
+	RepoID           int         `json:"repoId,omitempty"`
+	RunType          string      `json:"runType"`
+	SourceBranch     string      `json:"sourceBranch,omitempty"`
+	BatchTitle       string      `json:"batchTitle,omitempty"`
+	OpenCodeModel    string      `json:"opencodeModel,omitempty"`
+	OpenCodeProvider string      `json:"opencodeProvider,omitempty"`
+	Force            bool        `json:"force,omitempty"`
+	Runs             []RunItem   `json:"runs"`
+	Options          BulkOptions `json:"options,omitempty"`
 }
 
-// RunItem represents a single run within a bulk request
+// RunItem represents a single run within a bulk request. Settings left
+// empty fall back to the batch settings.
 type RunItem struct {
-	Prompt   string `json:"prompt"`
-	Title    string `json:"title,omitempty"`
-	Target   string `json:"target,omitempty"`
-	Context  string `json:"context,omitempty"`
-	FileHash string `json:"fileHash,omitempty"`
+	Prompt                string                          `json:"prompt"`
+	Title                 string                          `json:"title,omitempty"`
+	Target                string                          `json:"target,omitempty"`
+	Context               string                          `json:"context,omitempty"`
+	FileHash              string                          `json:"fileHash,omitempty"`
+	SourceBranch          string                          `json:"sourceBranch,omitempty"`
+	BaseBranch            string                          `json:"baseBranch,omitempty"`
+	OutputMode            string                          `json:"outputMode,omitempty"`
+	OutputBranch          string                          `json:"outputBranch,omitempty"`
+	PRTargetBranch        string                          `json:"prTargetBranch,omitempty"`
+	OutputBranchPolicy    string                          `json:"outputBranchPolicy,omitempty"`
+	RunType               string                          `json:"runType,omitempty"`
+	OpenCodeModel         string                          `json:"opencodeModel,omitempty"`
+	OpenCodeProvider      string                          `json:"opencodeProvider,omitempty"`
+	Files                 []string                        `json:"files,omitempty"`
+	ProviderCredentialID  string                          `json:"providerCredentialId,omitempty"`
+	ProviderMode          string                          `json:"providerMode,omitempty"`
+	GitLabCredential      *models.GitLabCredentialRequest `json:"gitlabCredential,omitempty"`
+	BranchOnly            bool                            `json:"branchOnly,omitempty"`
+	AcknowledgePromptRisk bool                            `json:"acknowledgePromptRisk,omitempty"`
 }
 
 // BulkOptions represents options for bulk run execution
@@ -44,6 +66,15 @@ type BulkRunData struct {
 	Metadata   BulkResponseMetadata `json:"metadata"`
 }
 
+// RunIDs returns the IDs of the created runs.
+func (d BulkRunData) RunIDs() []int {
+	ids := make([]int, 0, len(d.Successful))
+	for _, run := range d.Successful {
+		ids = append(ids, run.ID)
+	}
+	return ids
+}
+
 // RunCreatedItem represents a successfully created run
 type RunCreatedItem struct {
 	ID             int    `json:"id"`
@@ -39,6 +39,12 @@ const (
 
 	// EndpointRunLogsTemplate is the API-key-authenticated agent log endpoint.
 	EndpointRunLogsTemplate = "/api/v1/runs/%s/agent-logs"
+
+	// EndpointRunDiffTemplate is the endpoint template for the diff of a run's changes.
+	EndpointRunDiffTemplate = "/api/v1/runs/%s/diff"
+
+	// EndpointModels is the endpoint for listing the OpenCode models runs may use
+	EndpointModels = "/api/v1/models"
 )
 
 // RunDetailsURL builds the URL for getting a specific run by ID
@@ -77,3 +83,8 @@ func RunLogsURL(id string, afterSeq int) string {
 	}
 	return path
 }
+
+// RunDiffURL builds the URL for the diff of a run's changes.
+func RunDiffURL(id string) string {
+	return fmt.Sprintf(EndpointRunDiffTemplate, url.PathEscape(id))
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package budget checks configured credit budgets before a run is submitted.
+// Run costs come from the agent logs of earlier runs, which are kept in a
+// local history: a new run is estimated from the latest finished runs of its
+// repository, and spending is the cost of the runs created in the current
+// day or week. Log costs are taken to be credits.
+package budget
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Enabled reports whether any budget that can block a run is set.
+func Enabled(limits config.Budget) bool {
+	return limits.DailyCap > 0 || limits.WeeklyCap > 0 || limits.MinReserve > 0
+}
+
+// Configured reports whether any budget setting, including the warning
+// threshold, is set.
+func Configured(limits config.Budget) bool {
+	return Enabled(limits) || limits.WarnAbove > 0
+}
+
+// LoadLimits reads the budget settings from the user config.
+func LoadLimits() (config.Budget, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return config.Budget{}, err
+	}
+	return cfg.Budget, nil
+}
+
+// Decision is the outcome of checking a run against the budgets.
+type Decision struct {
+	Estimate      Estimate `json:"estimate"`
+	SpentToday    float64  `json:"spentToday"`
+	SpentThisWeek float64  `json:"spentThisWeek"`
+	// Pending is the estimated cost of runs already let through that do not
+	// show in the history yet.
+	Pending   float64  `json:"pending,omitempty"`
+	Available *float64 `json:"available,omitempty"`
+	Breaches  []string `json:"breaches,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// Blocked reports whether the run would breach a budget.
+func (d Decision) Blocked() bool {
+	return len(d.Breaches) > 0
+}
+
+// Err describes the breaches, or returns nil when there are none.
+func (d Decision) Err() error {
+	if !d.Blocked() {
+		return nil
+	}
+	message := d.Breaches[0]
+	for _, breach := range d.Breaches[1:] {
+		message += "; " + breach
+	}
+	return fmt.Errorf("%s; pass --override-budget to submit anyway", message)
+}
+
+// PeriodStarts returns the start of the calendar day and of the week,
+// starting Monday, that contain now.
+func PeriodStarts(now time.Time) (day, week time.Time) {
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day, day.AddDate(0, 0, -offset)
+}
+
+// Check compares a run for repository against the budgets. balance may be
+// nil when the credit balance is not known.
+func Check(limits config.Budget, history *History, repository string, balance *models.CreditBalance, now time.Time) Decision {
+	return CheckPending(limits, history, repository, balance, 0, now)
+}
+
+// CheckPending is Check for a command that creates several runs: pending is
+// the estimated cost of the runs it already let through, which counts
+// against the budgets until their logs show up in the history.
+func CheckPending(limits config.Budget, history *History, repository string, balance *models.CreditBalance, pending float64, now time.Time) Decision {
+	day, week := PeriodStarts(now)
+	decision := Decision{
+		Estimate:      history.Estimate(repository),
+		SpentToday:    history.Spent(day),
+		SpentThisWeek: history.Spent(week),
+		Pending:       pending,
+	}
+	estimate := decision.Estimate.Credits
+	pendingNote := ""
+	if pending > 0 {
+		pendingNote = fmt.Sprintf(", %s pending for other runs", models.FormatCredits(pending))
+	}
+
+	if limits.DailyCap > 0 && decision.SpentToday+pending+estimate > limits.DailyCap {
+		decision.Breaches = append(decision.Breaches, fmt.Sprintf(
+			"the daily budget of %s credits would be exceeded (%s spent today%s, %s estimated for this run)",
+			models.FormatCredits(limits.DailyCap), models.FormatCredits(decision.SpentToday), pendingNote, models.FormatCredits(estimate)))
+	}
+	if limits.WeeklyCap > 0 && decision.SpentThisWeek+pending+estimate > limits.WeeklyCap {
+		decision.Breaches = append(decision.Breaches, fmt.Sprintf(
+			"the weekly budget of %s credits would be exceeded (%s spent this week%s, %s estimated for this run)",
+			models.FormatCredits(limits.WeeklyCap), models.FormatCredits(decision.SpentThisWeek), pendingNote, models.FormatCredits(estimate)))
+	}
+	if limits.MinReserve > 0 {
+		if balance == nil {
+			decision.Warnings = append(decision.Warnings, "the credit balance is unknown, so the minimum reserve was not checked")
+		} else {
+			available := balance.AvailableCredits
+			decision.Available = &available
+			if available-pending-estimate < limits.MinReserve {
+				decision.Breaches = append(decision.Breaches, fmt.Sprintf(
+					"the credit balance would drop below the %s credit reserve (%s available%s, %s estimated for this run)",
+					models.FormatCredits(limits.MinReserve), models.FormatCredits(available), pendingNote, models.FormatCredits(estimate)))
+			}
+		}
+	}
+	if limits.WarnAbove > 0 && estimate > limits.WarnAbove {
+		decision.Warnings = append(decision.Warnings, fmt.Sprintf(
+			"this run is estimated at %s credits, above the %s credit warning threshold",
+			models.FormatCredits(estimate), models.FormatCredits(limits.WarnAbove)))
+	}
+	return decision
+}
+
+// EvalClient is the API access Evaluate needs.
+type EvalClient interface {
+	Client
+	GetUserInfoWithContext(ctx context.Context) (*models.UserInfo, error)
+}
+
+// Evaluate refreshes the history at historyPath with the runs of the
+// current week and checks a run for repository against the budgets.
+func Evaluate(ctx context.Context, client EvalClient, historyPath string, limits config.Budget, repository string, now time.Time) (Decision, error) {
+	history, balance, err := Load(ctx, client, historyPath, limits, now)
+	if err != nil {
+		return Decision{}, err
+	}
+	return Check(limits, history, repository, balance, now), nil
+}
+
+// Load refreshes the history at historyPath with the runs of the current
+// week and, when a minimum reserve is set, fetches the credit balance.
+func Load(ctx context.Context, client EvalClient, historyPath string, limits config.Budget, now time.Time) (*History, *models.CreditBalance, error) {
+	history, err := LoadHistory(historyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, week := PeriodStarts(now)
+	if err := history.Refresh(ctx, client, week); err != nil {
+		return nil, nil, err
+	}
+	if err := history.Save(); err != nil {
+		return nil, nil, err
+	}
+
+	var balance *models.CreditBalance
+	if limits.MinReserve > 0 {
+		info, err := client.GetUserInfoWithContext(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch the credit balance: %w", err)
+		}
+		balance = info.CreditBalance
+	}
+	return history, balance, nil
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package budget
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// fakeClient serves runs and a single costed log message per run.
+type fakeClient struct {
+	runs      []*models.RunResponse
+	costs     map[string]float64
+	logCalls  []string
+	available float64
+}
+
+func (c *fakeClient) ListRuns(_ context.Context, page, limit int) (*models.ListRunsResponse, error) {
+	start := (page - 1) * limit
+	if start >= len(c.runs) {
+		return &models.ListRunsResponse{}, nil
+	}
+	end := min(start+limit, len(c.runs))
+	return &models.ListRunsResponse{Data: c.runs[start:end]}, nil
+}
+
+func (c *fakeClient) GetRunLogs(_ context.Context, id string, _ int) ([]models.RunLogMessage, error) {
+	c.logCalls = append(c.logCalls, id)
+	cost, ok := c.costs[id]
+	if !ok {
+		return nil, fmt.Errorf("no logs for run %s", id)
+	}
+	return []models.RunLogMessage{{Type: "result", Cost: &cost}}, nil
+}
+
+func (c *fakeClient) GetUserInfoWithContext(context.Context) (*models.UserInfo, error) {
+	return &models.UserInfo{CreditBalance: &models.CreditBalance{AvailableCredits: c.available}}, nil
+}
+
+func costRun(id int, repository, status string, created time.Time) *models.RunResponse {
+	return &models.RunResponse{ID: strconv.Itoa(id), Repository: repository, Status: models.RunStatus(status), CreatedAt: created}
+}
+
+func TestPeriodStartsWeekBeginsMonday(t *testing.T) {
+	now := time.Date(2025, 6, 12, 15, 30, 0, 0, time.UTC) // Thursday
+	day, week := PeriodStarts(now)
+	assert.Equal(t, time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC), day)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), week)
+
+	sunday := time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC)
+	_, week = PeriodStarts(sunday)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), week)
+}
+
+func TestEstimatePrefersRepositoryHistory(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	history := &History{Runs: map[string]RunCost{
+		"1": {RunID: "1", Repository: "acme/web", CreatedAt: now.Add(-3 * time.Hour), Cost: 2, Final: true},
+		"2": {RunID: "2", Repository: "acme/web", CreatedAt: now.Add(-2 * time.Hour), Cost: 4, Final: true},
+		"3": {RunID: "3", Repository: "acme/api", CreatedAt: now.Add(-time.Hour), Cost: 12, Final: true},
+		"4": {RunID: "4", Repository: "acme/web", CreatedAt: now, Cost: 1, Final: false},
+	}}
+
+	estimate := history.Estimate("ACME/web")
+	assert.InDelta(t, 3.0, estimate.Credits, 0.001)
+	assert.Equal(t, 2, estimate.Samples)
+	assert.True(t, estimate.Repository)
+
+	estimate = history.Estimate("acme/new")
+	assert.InDelta(t, 6.0, estimate.Credits, 0.001)
+	assert.Equal(t, 3, estimate.Samples)
+	assert.False(t, estimate.Repository)
+
+	assert.Equal(t, Estimate{}, (&History{Runs: map[string]RunCost{}}).Estimate("acme/web"))
+}
+
+func TestCheckReportsBreachesAndWarnings(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	history := &History{Runs: map[string]RunCost{
+		"1": {RunID: "1", Repository: "acme/web", CreatedAt: now.Add(-time.Hour), Cost: 8, Final: true},
+		"2": {RunID: "2", Repository: "acme/web", CreatedAt: now.AddDate(0, 0, -2), Cost: 30, Final: true},
+		"3": {RunID: "3", Repository: "acme/web", CreatedAt: now.AddDate(0, 0, -10), Cost: 100, Final: true},
+	}}
+
+	decision := Check(config.Budget{DailyCap: 100, WeeklyCap: 500}, history, "acme/web", nil, now)
+	assert.False(t, decision.Blocked())
+	assert.NoError(t, decision.Err())
+	assert.InDelta(t, 8.0, decision.SpentToday, 0.001)
+	assert.InDelta(t, 38.0, decision.SpentThisWeek, 0.001)
+
+	decision = Check(config.Budget{DailyCap: 40, WeeklyCap: 60, WarnAbove: 20}, history, "acme/web", nil, now)
+	require.True(t, decision.Blocked())
+	assert.Len(t, decision.Breaches, 2)
+	assert.Contains(t, decision.Breaches[0], "daily budget of 40 credits")
+	assert.Contains(t, decision.Breaches[1], "weekly budget of 60 credits")
+	assert.Contains(t, decision.Warnings[0], "above the 20 credit warning threshold")
+	assert.Contains(t, decision.Err().Error(), "--override-budget")
+
+	balance := &models.CreditBalance{AvailableCredits: 70}
+	decision = Check(config.Budget{MinReserve: 50}, history, "acme/web", balance, now)
+	require.True(t, decision.Blocked())
+	assert.Contains(t, decision.Breaches[0], "below the 50 credit reserve")
+	require.NotNil(t, decision.Available)
+	assert.InDelta(t, 70.0, *decision.Available, 0.001)
+
+	decision = Check(config.Budget{MinReserve: 50}, history, "acme/web", nil, now)
+	assert.False(t, decision.Blocked())
+	assert.Contains(t, decision.Warnings[0], "minimum reserve was not checked")
+}
+
+func TestRefreshRecordsCostsAndSkipsFinishedRuns(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	_, week := PeriodStarts(now)
+	client := &fakeClient{
+		runs: []*models.RunResponse{
+			costRun(3, "acme/web", "PROCESSING", now.Add(-time.Hour)),
+			costRun(2, "acme/web", "DONE", now.Add(-2*time.Hour)),
+			costRun(1, "acme/api", "FAILED", now.AddDate(0, 0, -20)),
+		},
+		costs: map[string]float64{"1": 9, "2": 5, "3": 1.5},
+	}
+	path := filepath.Join(t.TempDir(), "run_costs.json")
+	history, err := LoadHistory(path)
+	require.NoError(t, err)
+
+	require.NoError(t, history.Refresh(context.Background(), client, week))
+	assert.Equal(t, []string{"3", "2", "1"}, client.logCalls)
+	assert.True(t, history.Runs["2"].Final)
+	assert.False(t, history.Runs["3"].Final)
+	require.NoError(t, history.Save())
+
+	reloaded, err := LoadHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, history.Runs, reloaded.Runs)
+
+	client.logCalls = nil
+	require.NoError(t, reloaded.Refresh(context.Background(), client, week))
+	assert.Equal(t, []string{"3"}, client.logCalls)
+}
+
+func TestEvaluateChecksReserveAgainstBalance(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		runs:      []*models.RunResponse{costRun(1, "acme/web", "DONE", now.Add(-time.Hour))},
+		costs:     map[string]float64{"1": 25},
+		available: 60,
+	}
+	path := filepath.Join(t.TempDir(), "run_costs.json")
+
+	decision, err := Evaluate(context.Background(), client, path, config.Budget{MinReserve: 50}, "acme/web", now)
+	require.NoError(t, err)
+	assert.True(t, decision.Blocked())
+	assert.InDelta(t, 25.0, decision.Estimate.Credits, 0.001)
+	assert.FileExists(t, path)
+}
+
+func TestRefreshCoversTheWholeWindow(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	_, week := PeriodStarts(now)
+	client := &fakeClient{costs: make(map[string]float64)}
+	for i := 250; i > 0; i-- {
+		client.runs = append(client.runs, costRun(i, "acme/web", "DONE", now.Add(-time.Duration(251-i)*time.Minute)))
+		client.costs[strconv.Itoa(i)] = 1
+	}
+	history, err := LoadHistory(filepath.Join(t.TempDir(), "run_costs.json"))
+	require.NoError(t, err)
+
+	require.NoError(t, history.Refresh(context.Background(), client, week))
+	assert.Len(t, client.logCalls, 250)
+	assert.InDelta(t, 250.0, history.Spent(week), 0.001)
+}
+
+func TestCheckPendingCountsRunsAlreadyLetThrough(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.UTC)
+	history := &History{Runs: map[string]RunCost{
+		"1": {RunID: "1", Repository: "acme/web", CreatedAt: now.Add(-time.Hour), Cost: 10, Final: true},
+	}}
+	limits := config.Budget{DailyCap: 35}
+
+	assert.False(t, CheckPending(limits, history, "acme/web", nil, 10, now).Blocked())
+	decision := CheckPending(limits, history, "acme/web", nil, 20, now)
+	require.True(t, decision.Blocked())
+	assert.Contains(t, decision.Breaches[0], "(10 spent today, 20 pending for other runs, 10 estimated for this run)")
+}
@@ -0,0 +1,223 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/usage"
+)
+
+const (
+	historyPageSize = 50
+	// estimateSamples is how many recent finished runs an estimate averages.
+	estimateSamples = 10
+)
+
+// RunCost is the cost of one run, summed from its agent logs.
+type RunCost struct {
+	RunID      string    `json:"run_id"`
+	Repository string    `json:"repository"`
+	CreatedAt  time.Time `json:"created_at"`
+	Cost       float64   `json:"cost"`
+	// Final is set once the run has finished, so its cost no longer changes.
+	Final bool `json:"final"`
+}
+
+// History is the local record of run costs. Costs of finished runs are
+// kept, so their logs are downloaded once.
+type History struct {
+	Runs map[string]RunCost `json:"runs"`
+
+	path string
+}
+
+// Client is the API access a history refresh needs.
+type Client interface {
+	ListRuns(ctx context.Context, page, limit int) (*models.ListRunsResponse, error)
+	GetRunLogs(ctx context.Context, id string, afterSeq int) ([]models.RunLogMessage, error)
+}
+
+// DefaultHistoryPath returns the history file in the user cache dir.
+func DefaultHistoryPath() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "repobird", "run_costs.json")
+}
+
+// LoadHistory reads the history at path. A missing file is an empty
+// history.
+func LoadHistory(path string) (*History, error) {
+	history := &History{Runs: make(map[string]RunCost), path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("failed to read run cost history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse run cost history: %w", err)
+	}
+	if history.Runs == nil {
+		history.Runs = make(map[string]RunCost)
+	}
+	return history, nil
+}
+
+// Save writes the history back to its file.
+func (h *History) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run cost history: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run cost history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write run cost history: %w", err)
+	}
+	return nil
+}
+
+// Record stores the cost of a run, replacing an earlier record.
+func (h *History) Record(cost RunCost) {
+	h.Runs[cost.RunID] = cost
+}
+
+// RecordSummary stores the cost of a run from its usage summary.
+func (h *History) RecordSummary(run *models.RunResponse, summary usage.Summary) {
+	h.Record(RunCost{
+		RunID:      run.GetIDString(),
+		Repository: run.GetRepositoryName(),
+		CreatedAt:  run.CreatedAt,
+		Cost:       summary.Cost,
+		Final:      !models.IsActiveStatus(string(run.Status)),
+	})
+}
+
+// Refresh records the cost of every run created since since, newest first,
+// and of at least the newest page of runs. Finished runs already recorded
+// are not fetched again, so only the first refresh of a busy week downloads
+// many logs; the list is paged until it reaches since, so the spending of
+// the window is never undercounted.
+func (h *History) Refresh(ctx context.Context, client Client, since time.Time) error {
+	for page := 1; ; page++ {
+		resp, err := client.ListRuns(ctx, page, historyPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list runs: %w", err)
+		}
+		if resp == nil || len(resp.Data) == 0 {
+			return nil
+		}
+		for _, run := range resp.Data {
+			if run == nil {
+				continue
+			}
+			if page > 1 && run.CreatedAt.Before(since) {
+				return nil
+			}
+			if recorded, ok := h.Runs[run.GetIDString()]; ok && recorded.Final {
+				continue
+			}
+			messages, err := client.GetRunLogs(ctx, run.GetIDString(), 0)
+			if err != nil {
+				continue
+			}
+			h.RecordSummary(run, usage.Summarize(messages))
+		}
+		last := resp.Data[len(resp.Data)-1]
+		if last != nil && last.CreatedAt.Before(since) {
+			return nil
+		}
+		if resp.Metadata != nil && page >= resp.Metadata.TotalPages {
+			return nil
+		}
+	}
+}
+
+// Spent returns the credits spent by runs created at or after since.
+func (h *History) Spent(since time.Time) float64 {
+	spent := 0.0
+	for _, run := range h.Runs {
+		if !run.CreatedAt.Before(since) {
+			spent += run.Cost
+		}
+	}
+	return spent
+}
+
+// Estimate is the expected cost of a new run.
+type Estimate struct {
+	Credits float64 `json:"credits"`
+	// Samples is the number of finished runs averaged; zero means there is
+	// no history to estimate from.
+	Samples int `json:"samples"`
+	// Repository is set when the samples are runs of the same repository
+	// rather than of every repository.
+	Repository bool `json:"repository"`
+}
+
+func (e Estimate) String() string {
+	if e.Samples == 0 {
+		return "unknown (no finished runs recorded yet)"
+	}
+	scope := "all repositories"
+	if e.Repository {
+		scope = "this repository"
+	}
+	return fmt.Sprintf("about %s credits (average of the last %d runs in %s)", models.FormatCredits(e.Credits), e.Samples, scope)
+}
+
+// Estimate averages the cost of the latest finished runs of repository, or
+// of every repository when it has none.
+func (h *History) Estimate(repository string) Estimate {
+	var repositoryRuns, allRuns []RunCost
+	for _, run := range h.Runs {
+		if !run.Final {
+			continue
+		}
+		allRuns = append(allRuns, run)
+		if repository != "" && strings.EqualFold(run.Repository, repository) {
+			repositoryRuns = append(repositoryRuns, run)
+		}
+	}
+	if len(repositoryRuns) > 0 {
+		estimate := averageLatest(repositoryRuns)
+		estimate.Repository = true
+		return estimate
+	}
+	return averageLatest(allRuns)
+}
+
+func averageLatest(runs []RunCost) Estimate {
+	if len(runs) == 0 {
+		return Estimate{}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].CreatedAt.After(runs[j].CreatedAt) })
+	if len(runs) > estimateSamples {
+		runs = runs[:estimateSamples]
+	}
+	total := 0.0
+	for _, run := range runs {
+		total += run.Cost
+	}
+	return Estimate{Credits: total / float64(len(runs)), Samples: len(runs)}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+)
+
+// ChunkRequest returns the sub-batch of req holding runs [start, end). The
+// runs keep the file hashes computed for the whole config, and the batch
+// title is marked with the part number when there are several parts.
+func ChunkRequest(req *dto.BulkRunRequest, start, end, part, parts int) *dto.BulkRunRequest {
+	chunk := *req
+	chunk.Runs = req.Runs[start:end]
+	if parts > 1 {
+		title := req.BatchTitle
+		if title == "" {
+			title = "Bulk runs"
+		}
+		chunk.BatchTitle = fmt.Sprintf("%s (part %d/%d)", title, part, parts)
+	}
+	return &chunk
+}
+
+// MergeResponses combines the responses of submitted chunks. Request indexes
+// are shifted by each chunk's offset so they refer to the whole config.
+func MergeResponses(responses []*dto.BulkRunResponse, offsets []int) *dto.BulkRunResponse {
+	merged := &dto.BulkRunResponse{}
+	for i, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if merged.Data.BatchID == "" {
+			merged.Data.BatchID = resp.Data.BatchID
+		}
+		if merged.StatusCode == 0 || resp.StatusCode > merged.StatusCode {
+			merged.StatusCode = resp.StatusCode
+		}
+		for _, run := range resp.Data.Successful {
+			run.RequestIndex += offsets[i]
+			merged.Data.Successful = append(merged.Data.Successful, run)
+		}
+		for _, failure := range resp.Data.Failed {
+			failure.RequestIndex += offsets[i]
+			merged.Data.Failed = append(merged.Data.Failed, failure)
+		}
+		merged.Data.Metadata.TotalRequested += resp.Data.Metadata.TotalRequested
+		merged.Data.Metadata.TotalSuccessful += resp.Data.Metadata.TotalSuccessful
+		merged.Data.Metadata.TotalFailed += resp.Data.Metadata.TotalFailed
+	}
+	return merged
+}
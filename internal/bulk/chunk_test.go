@@ -0,0 +1,45 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+)
+
+func TestChunkRequestAndMergeResponses(t *testing.T) {
+	req := &dto.BulkRunRequest{RepositoryName: "org/repo", BatchTitle: "Nightly", RunType: "run"}
+	for i := 0; i < 50; i++ {
+		req.Runs = append(req.Runs, dto.RunItem{Prompt: fmt.Sprintf("Run %d", i+1), FileHash: fmt.Sprintf("hash-%d", i)})
+	}
+
+	second := ChunkRequest(req, 40, 50, 2, 2)
+	assert.Equal(t, "Nightly (part 2/2)", second.BatchTitle)
+	assert.Equal(t, "org/repo", second.RepositoryName)
+	assert.Len(t, second.Runs, 10)
+	assert.Equal(t, "hash-40", second.Runs[0].FileHash, "runs keep the hash of their position in the whole config")
+	assert.Equal(t, "Nightly", req.BatchTitle)
+	assert.Equal(t, "Nightly", ChunkRequest(req, 0, 50, 1, 1).BatchTitle)
+
+	merged := MergeResponses([]*dto.BulkRunResponse{
+		{StatusCode: 201, Data: dto.BulkRunData{
+			BatchID:    "batch-1",
+			Successful: []dto.RunCreatedItem{{ID: 1, RequestIndex: 0}},
+			Metadata:   dto.BulkResponseMetadata{TotalRequested: 40, TotalSuccessful: 40},
+		}},
+		{StatusCode: 207, Data: dto.BulkRunData{
+			BatchID:  "batch-2",
+			Failed:   []dto.RunError{{RequestIndex: 3, Message: "invalid target"}},
+			Metadata: dto.BulkResponseMetadata{TotalRequested: 10, TotalSuccessful: 9, TotalFailed: 1},
+		}},
+	}, []int{0, 40})
+	assert.Equal(t, "batch-1", merged.Data.BatchID)
+	assert.Equal(t, 207, merged.StatusCode)
+	assert.Equal(t, 43, merged.Data.Failed[0].RequestIndex)
+	assert.Equal(t, dto.BulkResponseMetadata{TotalRequested: 50, TotalSuccessful: 49, TotalFailed: 1}, merged.Data.Metadata)
+}
@@ -8,10 +8,14 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
 	"github.com/repobird/repobird-cli/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
+// MaxBulkBatchSize is the most runs the API accepts in one batch. Larger
+// configs are submitted as several batches.
 const MaxBulkBatchSize = 40
 
 // BulkConfig represents a bulk run configuration
@@ -21,16 +25,44 @@ type BulkConfig struct {
 	BatchTitle string          `json:"batchTitle,omitempty" yaml:"batchTitle,omitempty"`
 	Source     string          `json:"source,omitempty" yaml:"source,omitempty"`
 	RunType    string          `json:"runType,omitempty" yaml:"runType,omitempty"`
+	Model      string          `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider   string          `json:"provider,omitempty" yaml:"provider,omitempty"`
 	Force      bool            `json:"force,omitempty" yaml:"force,omitempty"`
 	Runs       []BulkRunConfig `json:"runs" yaml:"runs"`
+	// Template names a prompt template rendered once per Vars row. The
+	// rendered runs are appended to Runs by ExpandTemplate.
+	Template string           `json:"template,omitempty" yaml:"template,omitempty"`
+	Vars     []map[string]any `json:"vars,omitempty" yaml:"vars,omitempty"`
 }
 
 // BulkRunConfig represents a single run within a bulk configuration
 type BulkRunConfig struct {
-	Prompt  string `json:"prompt" yaml:"prompt"`
-	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
-	Target  string `json:"target,omitempty" yaml:"target,omitempty"`
-	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	Prompt       string `json:"prompt" yaml:"prompt"`
+	Title        string `json:"title,omitempty" yaml:"title,omitempty"`
+	Target       string `json:"target,omitempty" yaml:"target,omitempty"`
+	Context      string `json:"context,omitempty" yaml:"context,omitempty"`
+	RunOverrides `yaml:",inline"`
+}
+
+// RunOverrides are run settings a single bulk run sets in place of the batch
+// settings. Repository may only repeat the batch repository.
+type RunOverrides struct {
+	Repository            string                          `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Source                string                          `json:"source,omitempty" yaml:"source,omitempty"`
+	BaseBranch            string                          `json:"baseBranch,omitempty" yaml:"baseBranch,omitempty"`
+	OutputMode            string                          `json:"outputMode,omitempty" yaml:"outputMode,omitempty"`
+	OutputBranch          string                          `json:"outputBranch,omitempty" yaml:"outputBranch,omitempty"`
+	PRTargetBranch        string                          `json:"prTargetBranch,omitempty" yaml:"prTargetBranch,omitempty"`
+	OutputBranchPolicy    string                          `json:"outputBranchPolicy,omitempty" yaml:"outputBranchPolicy,omitempty"`
+	RunType               string                          `json:"runType,omitempty" yaml:"runType,omitempty"`
+	Model                 string                          `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider              string                          `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Files                 []string                        `json:"files,omitempty" yaml:"files,omitempty"`
+	ProviderCredentialID  string                          `json:"providerCredentialId,omitempty" yaml:"providerCredentialId,omitempty"`
+	ProviderMode          string                          `json:"providerMode,omitempty" yaml:"providerMode,omitempty"`
+	GitLabCredential      *models.GitLabCredentialRequest `json:"gitlabCredential,omitempty" yaml:"gitlabCredential,omitempty"`
+	BranchOnly            bool                            `json:"branchOnly,omitempty" yaml:"branchOnly,omitempty"`
+	AcknowledgePromptRisk bool                            `json:"acknowledgePromptRisk,omitempty" yaml:"acknowledgePromptRisk,omitempty"`
 }
 
 // BulkRunRequest is what gets sent to the API (includes generated fields)
@@ -41,21 +73,31 @@ type BulkRunRequest struct {
 
 // LoadBulkConfig loads bulk configuration from file(s)
 func LoadBulkConfig(paths []string) (*BulkConfig, error) {
+	return LoadBulkConfigWithOptions(paths, ParseOptions{})
+}
+
+// LoadBulkConfigWithOptions loads bulk configuration from file(s) using the
+// parse options, such as a CSV column map.
+func LoadBulkConfigWithOptions(paths []string, opts ParseOptions) (*BulkConfig, error) {
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("no files specified")
 	}
 
 	if len(paths) == 1 {
 		// Single file - could be bulk or single run config
-		return ParseBulkConfig(paths[0])
+		return parseBulkConfig(paths[0], opts)
 	}
 
 	// Multiple files - combine into bulk config
-	return CreateBulkFromSingleConfigs(paths)
+	return createBulkFromSingleConfigs(paths, opts)
 }
 
 // ParseBulkConfig parses a single file as bulk configuration
 func ParseBulkConfig(path string) (*BulkConfig, error) {
+	return parseBulkConfig(path, ParseOptions{})
+}
+
+func parseBulkConfig(path string, opts ParseOptions) (*BulkConfig, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
@@ -69,6 +111,19 @@ func ParseBulkConfig(path string) (*BulkConfig, error) {
 		return parseJSONL(path)
 	}
 
+	// Check if it's a spreadsheet export
+	if isDelimitedFile(path) {
+		delimiter := ','
+		if ext == ".tsv" {
+			delimiter = '\t'
+		}
+		config, err := parseDelimited(content, delimiter, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return config, nil
+	}
+
 	// Check if it's a markdown file
 	if ext == ".md" || ext == ".markdown" {
 		return parseMarkdown(content)
@@ -83,8 +138,8 @@ func ParseBulkConfig(path string) (*BulkConfig, error) {
 		}
 	}
 
-	// Check if it has "runs" array (bulk)
-	if _, hasBulk := test["runs"]; hasBulk {
+	// Check if it has "runs" array or template rows (bulk)
+	if isBulkDocument(test) {
 		// It's a bulk config
 		return parseFile(path, content)
 	}
@@ -109,36 +164,53 @@ func ParseBulkConfig(path string) (*BulkConfig, error) {
 		Repository: runConfig.Repository,
 		Source:     runConfig.Source,
 		RunType:    runConfig.RunType,
+		Model:      runConfig.Model,
+		Provider:   runConfig.Provider,
 		Runs: []BulkRunConfig{{
-			Prompt:  runConfig.Prompt,
-			Title:   runConfig.Title,
-			Target:  runConfig.Target,
-			Context: context,
+			Prompt:       runConfig.Prompt,
+			Title:        runConfig.Title,
+			Target:       runConfig.Target,
+			Context:      context,
+			RunOverrides: overridesFromRunConfig(runConfig),
 		}},
 	}
+	bulk.Runs[0].clearInherited(bulk)
 
 	return validateBulkConfig(bulk)
 }
 
 // CreateBulkFromSingleConfigs creates a bulk config from multiple single-run files
 func CreateBulkFromSingleConfigs(paths []string) (*BulkConfig, error) {
+	return createBulkFromSingleConfigs(paths, ParseOptions{})
+}
+
+func createBulkFromSingleConfigs(paths []string, opts ParseOptions) (*BulkConfig, error) {
 	var runs []BulkRunConfig
 	var repository string
 	var repoID int
 	var source string
 	var runType string
+	var model, provider string
 
 	for _, path := range paths {
 		// Try to parse as bulk config first
-		bulkConfig, bulkErr := ParseBulkConfig(path)
+		bulkConfig, bulkErr := parseBulkConfig(path, opts)
+		if bulkErr != nil && isDelimitedFile(path) {
+			return nil, bulkErr
+		}
 		if bulkErr == nil {
-			// It's a bulk config - add all runs
-			runs = append(runs, bulkConfig.Runs...)
+			// It's a bulk config - add all runs, keeping the file's batch
+			// settings as run overrides
+			for _, run := range bulkConfig.Runs {
+				run.inherit(bulkConfig)
+				runs = append(runs, run)
+			}
 			if repository == "" {
 				repository = bulkConfig.Repository
 				repoID = bulkConfig.RepoID
 				source = bulkConfig.Source
 				runType = bulkConfig.RunType
+				model, provider = bulkConfig.Model, bulkConfig.Provider
 			}
 		} else {
 			// Try as single run config
@@ -159,19 +231,21 @@ func CreateBulkFromSingleConfigs(paths []string) (*BulkConfig, error) {
 
 			// Convert single run to bulk run
 			run := BulkRunConfig{
-				Prompt:  runConfig.Prompt,
-				Title:   runConfig.Title,
-				Target:  runConfig.Target,
-				Context: context,
+				Prompt:       runConfig.Prompt,
+				Title:        runConfig.Title,
+				Target:       runConfig.Target,
+				Context:      context,
+				RunOverrides: overridesFromRunConfig(runConfig),
 			}
-			runs = append(runs, run)
 
 			// Use repository from first file if not set
 			if repository == "" {
 				repository = runConfig.Repository
 				source = runConfig.Source
 				runType = runConfig.RunType
+				model, provider = runConfig.Model, runConfig.Provider
 			}
+			runs = append(runs, run)
 		}
 	}
 
@@ -181,9 +255,14 @@ func CreateBulkFromSingleConfigs(paths []string) (*BulkConfig, error) {
 		RepoID:     repoID,
 		Source:     source,
 		RunType:    runType,
+		Model:      model,
+		Provider:   provider,
 		Runs:       runs,
 		BatchTitle: fmt.Sprintf("Batch of %d tasks", len(runs)),
 	}
+	for i := range bulk.Runs {
+		bulk.Runs[i].clearInherited(bulk)
+	}
 
 	return validateBulkConfig(bulk)
 }
@@ -203,6 +282,20 @@ func parseFile(path string, content []byte) (*BulkConfig, error) {
 		}
 	}
 
+	if config.Template != "" {
+		library, err := templates.LoadDefault()
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := library.Get(config.Template)
+		if err != nil {
+			return nil, err
+		}
+		if err := ExpandTemplate(&config, tmpl); err != nil {
+			return nil, err
+		}
+	}
+	// Validated after expansion so the rendered runs are checked too.
 	return validateBulkConfig(&config)
 }
 
@@ -232,6 +325,7 @@ func parseJSONL(path string) (*BulkConfig, error) {
 			Title      string `json:"title,omitempty"`
 			Target     string `json:"target,omitempty"`
 			Context    string `json:"context,omitempty"`
+			RunOverrides
 		}
 
 		if err := json.Unmarshal([]byte(line), &item); err != nil {
@@ -247,10 +341,11 @@ func parseJSONL(path string) (*BulkConfig, error) {
 		}
 
 		runs = append(runs, BulkRunConfig{
-			Prompt:  item.Prompt,
-			Title:   item.Title,
-			Target:  item.Target,
-			Context: item.Context,
+			Prompt:       item.Prompt,
+			Title:        item.Title,
+			Target:       item.Target,
+			Context:      item.Context,
+			RunOverrides: item.RunOverrides,
 		})
 	}
 
@@ -373,10 +468,8 @@ func parseMarkdown(content []byte) (*BulkConfig, error) {
 
 // validateBulkConfig validates a bulk configuration
 func validateBulkConfig(config *BulkConfig) (*BulkConfig, error) {
-	// Validate batch size
-	if len(config.Runs) > MaxBulkBatchSize {
-		return nil, fmt.Errorf("batch size exceeds maximum of %d runs (got %d)",
-			MaxBulkBatchSize, len(config.Runs))
+	if len(config.Vars) > 0 && config.Template == "" {
+		return nil, fmt.Errorf("vars requires a template")
 	}
 
 	// Validate required fields
@@ -384,11 +477,14 @@ func validateBulkConfig(config *BulkConfig) (*BulkConfig, error) {
 		return nil, fmt.Errorf("either repository or repoId is required")
 	}
 
-	// Validate each run has a prompt
+	// Validate each run has a prompt and valid overrides
 	for i, run := range config.Runs {
 		if run.Prompt == "" {
 			return nil, fmt.Errorf("run %d is missing required prompt field", i+1)
 		}
+		if err := validateRunOverrides(config, &config.Runs[i].RunOverrides); err != nil {
+			return nil, fmt.Errorf("run %d: %w", i+1, err)
+		}
 	}
 
 	// Set defaults
@@ -406,8 +502,9 @@ func IsBulkConfig(path string) (bool, error) {
 		return false, err
 	}
 
-	// Check for JSONL
-	if strings.ToLower(filepath.Ext(path)) == ".jsonl" {
+	// Check for JSONL and spreadsheet exports
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".csv", ".tsv":
 		return true, nil
 	}
 
@@ -427,6 +524,13 @@ func IsBulkConfig(path string) (bool, error) {
 		}
 	}
 
-	_, hasBulk := test["runs"]
-	return hasBulk, nil
+	return isBulkDocument(test), nil
+}
+
+// isBulkDocument reports whether a decoded JSON or YAML document is a bulk
+// configuration: it lists runs or template variable rows.
+func isBulkDocument(doc map[string]interface{}) bool {
+	_, hasRuns := doc["runs"]
+	_, hasVars := doc["vars"]
+	return hasRuns || hasVars
 }
@@ -130,9 +130,9 @@ func TestParseBulkConfig_JSON(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name: "invalid JSON - exceeds max batch size",
+			name: "valid JSON - more runs than MaxBulkBatchSize",
 			content: func() string {
-				runs := make([]string, 41) // Exceed MaxBulkBatchSize of 40
+				runs := make([]string, 41) // Submitted in chunks
 				for i := 0; i < 41; i++ {
 					runs[i] = fmt.Sprintf(`{"prompt": "Run %d"}`, i+1)
 				}
@@ -141,7 +141,13 @@ func TestParseBulkConfig_JSON(t *testing.T) {
 					"runs": [%s]
 				}`, strings.Join(runs, ","))
 			}(),
-			expectError: true,
+			expected: func() *BulkConfig {
+				runs := make([]BulkRunConfig, 41)
+				for i := range runs {
+					runs[i] = BulkRunConfig{Prompt: fmt.Sprintf("Run %d", i+1)}
+				}
+				return &BulkConfig{Repository: "org/repo", RunType: "run", Runs: runs}
+			}(),
 		},
 	}
 
@@ -177,6 +183,8 @@ func TestParseBulkConfig_YAML(t *testing.T) {
 batchTitle: Authentication Refactor
 source: main
 runType: run
+model: openrouter/z-ai/glm-5.2
+provider: openrouter
 force: true
 runs:
   - prompt: |
@@ -196,6 +204,8 @@ runs:
 				BatchTitle: "Authentication Refactor",
 				Source:     "main",
 				RunType:    "run",
+				Model:      "openrouter/z-ai/glm-5.2",
+				Provider:   "openrouter",
 				Force:      true,
 				Runs: []BulkRunConfig{
 					{
@@ -584,9 +594,9 @@ func TestValidateBulkConfig(t *testing.T) {
 			errorMsg:    "run 1 is missing required prompt field",
 		},
 		{
-			name: "exceeds max batch size",
+			name: "allows more runs than MaxBulkBatchSize",
 			config: func() *BulkConfig {
-				runs := make([]BulkRunConfig, 41) // Exceed MaxBulkBatchSize of 40
+				runs := make([]BulkRunConfig, 41) // Submitted in chunks
 				for i := 0; i < 41; i++ {
 					runs[i] = BulkRunConfig{Prompt: fmt.Sprintf("Run %d", i+1)}
 				}
@@ -595,8 +605,7 @@ func TestValidateBulkConfig(t *testing.T) {
 					Runs:       runs,
 				}
 			}(),
-			expectError: true,
-			errorMsg:    "batch size exceeds maximum",
+			expectError: false,
 		},
 		{
 			name: "sets default runType",
@@ -823,3 +832,75 @@ func TestBulkConfigMarshaling(t *testing.T) {
 		assert.Equal(t, original, &decoded)
 	})
 }
+
+func TestParseBulkConfig_TemplateVars(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	templateDir := filepath.Join(configHome, "repobird", "templates")
+	require.NoError(t, os.MkdirAll(templateDir, 0o755))
+	createTempFile(t, templateDir, "fix-issue.md", `---
+title: "Fix #{{.issue}}"
+variables:
+  - name: issue
+    required: true
+  - name: area
+    default: api
+---
+Fix issue #{{.issue}} in the {{.area}} package.`)
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := createTempFile(t, dir, "bulk.yaml", `repository: org/repo
+template: fix-issue
+vars:
+  - issue: 12
+  - issue: 13
+    area: cli
+`)
+
+	isBulk, err := IsBulkConfig(path)
+	require.NoError(t, err)
+	assert.True(t, isBulk)
+
+	config, err := ParseBulkConfig(path)
+	require.NoError(t, err)
+	require.Len(t, config.Runs, 2)
+	assert.Nil(t, config.Vars)
+	assert.Equal(t, "Fix issue #12 in the api package.", config.Runs[0].Prompt)
+	assert.Equal(t, "Fix #12", config.Runs[0].Title)
+	assert.Equal(t, "Fix issue #13 in the cli package.", config.Runs[1].Prompt)
+
+	missing := createTempFile(t, dir, "missing.yaml", `repository: org/repo
+template: fix-issue
+vars:
+  - area: cli
+`)
+	_, err = ParseBulkConfig(missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vars[0]")
+	assert.Contains(t, err.Error(), "missing required variable(s): issue")
+
+	noTemplate := createTempFile(t, dir, "no-template.yaml", `repository: org/repo
+vars:
+  - issue: 1
+`)
+	_, err = ParseBulkConfig(noTemplate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vars requires a template")
+
+	createTempFile(t, templateDir, "note.md", `---
+variables:
+  - name: note
+    default: ""
+---
+{{.note}}`)
+	emptyPrompt := createTempFile(t, dir, "empty-prompt.yaml", `repository: org/repo
+template: note
+vars:
+  - note: Tidy the README
+  - note: ""
+`)
+	_, err = ParseBulkConfig(emptyPrompt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run 2 is missing required prompt field")
+}
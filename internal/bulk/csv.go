@@ -0,0 +1,272 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// IgnoreColumn is the --map target that skips a CSV column.
+const IgnoreColumn = "-"
+
+// ParseOptions adjust how bulk files are parsed.
+type ParseOptions struct {
+	// ColumnMap maps CSV/TSV header names, compared case-insensitively, to
+	// run fields or to IgnoreColumn.
+	ColumnMap map[string]string
+}
+
+// csvFields are the run fields a CSV column can fill, keyed by field name.
+var csvFields = map[string]func(run *BulkRunConfig, value string) error{
+	"prompt":               func(r *BulkRunConfig, v string) error { r.Prompt = v; return nil },
+	"title":                func(r *BulkRunConfig, v string) error { r.Title = v; return nil },
+	"target":               func(r *BulkRunConfig, v string) error { r.Target = v; return nil },
+	"context":              func(r *BulkRunConfig, v string) error { r.Context = v; return nil },
+	"repository":           func(r *BulkRunConfig, v string) error { r.Repository = v; return nil },
+	"source":               func(r *BulkRunConfig, v string) error { r.Source = v; return nil },
+	"baseBranch":           func(r *BulkRunConfig, v string) error { r.BaseBranch = v; return nil },
+	"outputMode":           func(r *BulkRunConfig, v string) error { r.OutputMode = v; return nil },
+	"outputBranch":         func(r *BulkRunConfig, v string) error { r.OutputBranch = v; return nil },
+	"prTargetBranch":       func(r *BulkRunConfig, v string) error { r.PRTargetBranch = v; return nil },
+	"outputBranchPolicy":   func(r *BulkRunConfig, v string) error { r.OutputBranchPolicy = v; return nil },
+	"runType":              func(r *BulkRunConfig, v string) error { r.RunType = v; return nil },
+	"model":                func(r *BulkRunConfig, v string) error { r.Model = v; return nil },
+	"provider":             func(r *BulkRunConfig, v string) error { r.Provider = v; return nil },
+	"providerCredentialId": func(r *BulkRunConfig, v string) error { r.ProviderCredentialID = v; return nil },
+	"providerMode":         func(r *BulkRunConfig, v string) error { r.ProviderMode = v; return nil },
+	"files": func(r *BulkRunConfig, v string) error {
+		r.Files = splitList(v)
+		return nil
+	},
+	"branchOnly": func(r *BulkRunConfig, v string) error {
+		return parseBoolCell(v, &r.BranchOnly)
+	},
+	"acknowledgePromptRisk": func(r *BulkRunConfig, v string) error {
+		return parseBoolCell(v, &r.AcknowledgePromptRisk)
+	},
+}
+
+// CSVFieldNames returns the run fields a CSV column can map to.
+func CSVFieldNames() []string {
+	names := make([]string, 0, len(csvFields))
+	for name := range csvFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseColumnMap parses --map column=field pairs. Fields are matched
+// case-insensitively and IgnoreColumn skips the column.
+func ParseColumnMap(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	columnMap := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		column, field, ok := strings.Cut(pair, "=")
+		column, field = strings.TrimSpace(column), strings.TrimSpace(field)
+		if !ok || column == "" || field == "" {
+			return nil, fmt.Errorf("invalid --map %q: expected column=field", pair)
+		}
+		if field != IgnoreColumn {
+			name, known := lookupCSVField(field)
+			if !known {
+				return nil, unknownFieldError(fmt.Sprintf("--map %s: unknown field %q", column, field), field)
+			}
+			field = name
+		}
+		columnMap[normalizeColumn(column)] = field
+	}
+	return columnMap, nil
+}
+
+// isDelimitedFile reports whether path is a CSV or TSV export.
+func isDelimitedFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".csv" || ext == ".tsv"
+}
+
+// parseDelimited parses CSV or TSV content with a header row into a bulk
+// config. Every row is one run; blank rows are skipped. The batch repository
+// is taken from the first row that names one.
+func parseDelimited(content []byte, delimiter rune, opts ParseOptions) (*BulkConfig, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\ufeff"))))
+	reader.Comma = delimiter
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file is empty; the first row must name the columns")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+
+	setters := make([]func(*BulkRunConfig, string) error, len(header))
+	seen := make(map[string]string, len(header))
+	var unknown []string
+	for i, column := range header {
+		field, ok := opts.ColumnMap[normalizeColumn(column)]
+		if !ok {
+			field, ok = lookupCSVField(column)
+		}
+		switch {
+		case field == IgnoreColumn:
+			continue
+		case !ok:
+			unknown = append(unknown, describeUnknownColumn(column))
+			continue
+		}
+		if previous, dup := seen[field]; dup {
+			return nil, fmt.Errorf("columns %q and %q both map to %s", previous, column, field)
+		}
+		seen[field] = column
+		setters[i] = csvFields[field]
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown column(s): %s\n\nUse --map column=field to map a column to a run field, or --map column=- to ignore it",
+			strings.Join(unknown, ", "))
+	}
+	if _, ok := seen["prompt"]; !ok {
+		return nil, fmt.Errorf("no prompt column; use --map column=prompt to choose one")
+	}
+
+	config := &BulkConfig{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse row: %w", err)
+		}
+		if blankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		var run BulkRunConfig
+		for i, value := range record {
+			if setters[i] == nil {
+				continue
+			}
+			if err := setters[i](&run, strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, header[i], err)
+			}
+		}
+		if config.Repository == "" {
+			config.Repository = run.Repository
+		}
+		config.Runs = append(config.Runs, run)
+	}
+	if len(config.Runs) == 0 {
+		return nil, fmt.Errorf("no runs found below the header row")
+	}
+
+	for i := range config.Runs {
+		config.Runs[i].clearInherited(config)
+	}
+	config.BatchTitle = fmt.Sprintf("Batch of %d tasks", len(config.Runs))
+	return validateBulkConfig(config)
+}
+
+// lookupCSVField finds the run field for a header, ignoring case, spaces,
+// underscores and dashes, so "Base Branch" and "base_branch" both match
+// baseBranch.
+func lookupCSVField(column string) (string, bool) {
+	normalized := normalizeColumn(column)
+	for name := range csvFields {
+		if normalizeColumn(name) == normalized {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func normalizeColumn(column string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(column)))
+}
+
+func describeUnknownColumn(column string) string {
+	if suggestion := suggestCSVField(column); suggestion != "" {
+		return fmt.Sprintf("%q (did you mean %s?)", column, suggestion)
+	}
+	return fmt.Sprintf("%q", column)
+}
+
+func unknownFieldError(message, field string) error {
+	if suggestion := suggestCSVField(field); suggestion != "" {
+		return fmt.Errorf("%s (did you mean %s?)", message, suggestion)
+	}
+	return fmt.Errorf("%s (fields: %s)", message, strings.Join(CSVFieldNames(), ", "))
+}
+
+// suggestCSVField returns the closest field name in its canonical spelling.
+func suggestCSVField(column string) string {
+	normalized := make([]string, 0, len(csvFields))
+	canonical := make(map[string]string, len(csvFields))
+	for _, name := range CSVFieldNames() {
+		normalized = append(normalized, normalizeColumn(name))
+		canonical[normalizeColumn(name)] = name
+	}
+	return canonical[utils.SuggestFieldName(normalizeColumn(column), normalized)]
+}
+
+func blankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList splits a cell listing files by commas, semicolons or newlines.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseBoolCell(value string, dest *bool) error {
+	if value == "" {
+		return nil
+	}
+	switch strings.ToLower(value) {
+	case "yes", "y":
+		*dest = true
+		return nil
+	case "no", "n":
+		*dest = false
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("expected true or false, got %q", value)
+	}
+	*dest = parsed
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBulkConfig_CSV(t *testing.T) {
+	dir := t.TempDir()
+	path := createTempFile(t, dir, "tasks.csv", "\ufeffRepository,Title,Prompt,Base Branch,files,branch_only\n"+
+		"org/repo,Fix auth,\"Fix the login flow.\n\nKeep the \"\"remember me\"\" option.\",develop,\"a.go, b.go\",yes\n"+
+		",,,,,\n"+
+		"org/repo,Add docs,Document the API,,,\n")
+
+	config, err := ParseBulkConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "org/repo", config.Repository)
+	assert.Equal(t, "Batch of 2 tasks", config.BatchTitle)
+	assert.Equal(t, "run", config.RunType)
+	require.Len(t, config.Runs, 2)
+
+	first := config.Runs[0]
+	assert.Equal(t, "Fix auth", first.Title)
+	assert.Equal(t, "Fix the login flow.\n\nKeep the \"remember me\" option.", first.Prompt)
+	assert.Equal(t, "develop", first.BaseBranch)
+	assert.Equal(t, []string{"a.go", "b.go"}, first.Files)
+	assert.True(t, first.BranchOnly)
+	assert.Empty(t, first.Repository, "the batch repository is not repeated as an override")
+	assert.Equal(t, "Document the API", config.Runs[1].Prompt)
+}
+
+func TestLoadBulkConfigWithOptions_TSVColumnMap(t *testing.T) {
+	dir := t.TempDir()
+	path := createTempFile(t, dir, "tasks.tsv", "Repo\tPromt\tAssignee\nOrg/Repo\tFix auth\tdana\n")
+
+	_, err := ParseBulkConfig(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"Promt" (did you mean prompt?)`)
+	assert.Contains(t, err.Error(), `"Repo"`)
+	assert.Contains(t, err.Error(), "--map column=field")
+
+	columnMap, err := ParseColumnMap([]string{"repo=repository", "Promt=Prompt", "assignee=-"})
+	require.NoError(t, err)
+	config, err := LoadBulkConfigWithOptions([]string{path}, ParseOptions{ColumnMap: columnMap})
+	require.NoError(t, err)
+	assert.Equal(t, "Org/Repo", config.Repository)
+	require.Len(t, config.Runs, 1)
+	assert.Equal(t, "Fix auth", config.Runs[0].Prompt)
+}
+
+func TestParseBulkConfig_CSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no prompt column", content: "repository,title\norg/repo,Fix\n", wantErr: "no prompt column"},
+		{name: "no rows", content: "repository,prompt\n", wantErr: "no runs found"},
+		{name: "duplicate field", content: "prompt,Prompt\nx,y\n", wantErr: "both map to prompt"},
+		{name: "bad bool", content: "repository,prompt,branchOnly\norg/repo,Fix,maybe\n", wantErr: `line 2, column "branchOnly"`},
+		{name: "missing repository", content: "prompt\nFix auth\n", wantErr: "either repository or repoId is required"},
+		{name: "second repository", content: "repository,prompt\norg/repo,Fix\norg/other,Fix\n", wantErr: "differs from the batch repository"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := createTempFile(t, t.TempDir(), "tasks.csv", tt.content)
+			_, err := ParseBulkConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseColumnMap(t *testing.T) {
+	columnMap, err := ParseColumnMap([]string{"Ticket Summary = title", "Details=prompt"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ticketsummary": "title", "details": "prompt"}, columnMap)
+
+	_, err = ParseColumnMap([]string{"summary"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected column=field")
+
+	_, err = ParseColumnMap([]string{"summary=titel"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did you mean title?")
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// overridesFromRunConfig copies the run settings of a single-run config.
+func overridesFromRunConfig(runConfig *models.RunConfig) RunOverrides {
+	return RunOverrides{
+		Repository:            runConfig.Repository,
+		Source:                runConfig.Source,
+		BaseBranch:            runConfig.BaseBranch,
+		OutputMode:            runConfig.OutputMode,
+		OutputBranch:          runConfig.OutputBranch,
+		PRTargetBranch:        runConfig.PRTargetBranch,
+		OutputBranchPolicy:    runConfig.OutputBranchPolicy,
+		RunType:               runConfig.RunType,
+		Model:                 runConfig.Model,
+		Provider:              runConfig.Provider,
+		Files:                 runConfig.Files,
+		ProviderCredentialID:  runConfig.ProviderCredentialID,
+		ProviderMode:          runConfig.ProviderMode,
+		GitLabCredential:      runConfig.GitLabCredential,
+		BranchOnly:            runConfig.BranchOnly,
+		AcknowledgePromptRisk: runConfig.AcknowledgePromptRisk,
+	}
+}
+
+// inherit fills unset overrides from the batch settings, so a run keeps them
+// when it is combined into another batch.
+func (o *RunOverrides) inherit(batch *BulkConfig) {
+	for _, field := range []struct {
+		value *string
+		batch string
+	}{
+		{&o.Source, batch.Source},
+		{&o.RunType, batch.RunType},
+		{&o.Model, batch.Model},
+		{&o.Provider, batch.Provider},
+	} {
+		if *field.value == "" {
+			*field.value = field.batch
+		}
+	}
+}
+
+// clearInherited drops overrides that only repeat the batch settings, so a
+// run converted from a single-run file lists just what differs.
+func (o *RunOverrides) clearInherited(batch *BulkConfig) {
+	for _, field := range []struct {
+		value *string
+		batch string
+	}{
+		{&o.Repository, batch.Repository},
+		{&o.Source, batch.Source},
+		{&o.RunType, batch.RunType},
+		{&o.Model, batch.Model},
+		{&o.Provider, batch.Provider},
+	} {
+		if *field.value == field.batch {
+			*field.value = ""
+		}
+	}
+}
+
+// validateRunOverrides checks one run's overrides against the batch.
+func validateRunOverrides(batch *BulkConfig, o *RunOverrides) error {
+	var problems []string
+	if o.Repository != "" && !strings.EqualFold(o.Repository, batch.Repository) {
+		problems = append(problems, fmt.Sprintf("repository %q differs from the batch repository; a batch targets one repository", o.Repository))
+	}
+
+	runConfig := &models.RunConfig{
+		RunType:            o.RunType,
+		OutputMode:         o.OutputMode,
+		OutputBranchPolicy: o.OutputBranchPolicy,
+		ProviderMode:       o.ProviderMode,
+		GitLabCredential:   o.GitLabCredential,
+		BranchOnly:         o.BranchOnly,
+	}
+	problems = append(problems, utils.ValidateRunSettings(runConfig)...)
+	o.OutputMode = runConfig.OutputMode
+
+	if o.OutputMode == "branch" && o.PRTargetBranch != "" {
+		problems = append(problems, "prTargetBranch cannot be set when outputMode is 'branch'")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Summary lists the set overrides as key=value pairs in a stable order.
+func (o RunOverrides) Summary() []string {
+	var pairs []string
+	for _, field := range []struct {
+		key   string
+		value string
+	}{
+		{"runType", o.RunType},
+		{"source", o.Source},
+		{"baseBranch", o.BaseBranch},
+		{"outputMode", o.OutputMode},
+		{"outputBranch", o.OutputBranch},
+		{"prTargetBranch", o.PRTargetBranch},
+		{"outputBranchPolicy", o.OutputBranchPolicy},
+		{"model", o.Model},
+		{"provider", o.Provider},
+		{"providerMode", o.ProviderMode},
+		{"providerCredentialId", o.ProviderCredentialID},
+		{"files", strings.Join(o.Files, ",")},
+	} {
+		if field.value != "" {
+			pairs = append(pairs, field.key+"="+field.value)
+		}
+	}
+	if o.GitLabCredential != nil {
+		pairs = append(pairs, "gitlabCredential="+o.GitLabCredential.TokenReferenceID)
+	}
+	if o.BranchOnly {
+		pairs = append(pairs, "branchOnly=true")
+	}
+	if o.AcknowledgePromptRisk {
+		pairs = append(pairs, "acknowledgePromptRisk=true")
+	}
+	return pairs
+}
+
+// RunFromConfig returns the bulk run of a single-run config, with every run
+// setting kept as an override.
+func RunFromConfig(runConfig *models.RunConfig) BulkRunConfig {
+	return BulkRunConfig{
+		Prompt:       runConfig.Prompt,
+		Title:        runConfig.Title,
+		Target:       runConfig.Target,
+		Context:      runConfig.Context,
+		RunOverrides: overridesFromRunConfig(runConfig),
+	}
+}
+
+// RunConfig returns the single-run config of the run, with its overrides
+// taking precedence over the batch settings.
+func (r BulkRunConfig) RunConfig(batch *BulkConfig) *models.RunConfig {
+	runConfig := &models.RunConfig{
+		Prompt:                r.Prompt,
+		Repository:            batch.Repository,
+		Source:                batch.Source,
+		Target:                r.Target,
+		BaseBranch:            r.BaseBranch,
+		OutputMode:            r.OutputMode,
+		OutputBranch:          r.OutputBranch,
+		PRTargetBranch:        r.PRTargetBranch,
+		OutputBranchPolicy:    r.OutputBranchPolicy,
+		RunType:               batch.RunType,
+		Model:                 batch.Model,
+		Provider:              batch.Provider,
+		Title:                 r.Title,
+		Context:               r.Context,
+		Files:                 r.Files,
+		ProviderCredentialID:  r.ProviderCredentialID,
+		ProviderMode:          r.ProviderMode,
+		GitLabCredential:      r.GitLabCredential,
+		BranchOnly:            r.BranchOnly,
+		AcknowledgePromptRisk: r.AcknowledgePromptRisk,
+	}
+	for _, field := range []struct {
+		value    *string
+		override string
+	}{
+		{&runConfig.Repository, r.Repository},
+		{&runConfig.Source, r.Source},
+		{&runConfig.RunType, r.RunType},
+		{&runConfig.Model, r.Model},
+		{&runConfig.Provider, r.Provider},
+	} {
+		if field.override != "" {
+			*field.value = field.override
+		}
+	}
+	return runConfig
+}
+
+// RunItem converts the run to its API form. FileHash is left to the caller.
+func (r BulkRunConfig) RunItem() dto.RunItem {
+	return dto.RunItem{
+		Prompt:                r.Prompt,
+		Title:                 r.Title,
+		Target:                r.Target,
+		Context:               r.Context,
+		SourceBranch:          r.Source,
+		BaseBranch:            r.BaseBranch,
+		OutputMode:            r.OutputMode,
+		OutputBranch:          r.OutputBranch,
+		PRTargetBranch:        r.PRTargetBranch,
+		OutputBranchPolicy:    r.OutputBranchPolicy,
+		RunType:               r.RunType,
+		OpenCodeModel:         r.Model,
+		OpenCodeProvider:      r.Provider,
+		Files:                 r.Files,
+		ProviderCredentialID:  r.ProviderCredentialID,
+		ProviderMode:          r.ProviderMode,
+		GitLabCredential:      r.GitLabCredential,
+		BranchOnly:            r.BranchOnly,
+		AcknowledgePromptRisk: r.AcknowledgePromptRisk,
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func TestParseBulkConfig_RunOverrides(t *testing.T) {
+	path := createTempFile(t, t.TempDir(), "bulk.yaml", `repository: org/repo
+source: main
+model: openrouter/cheap/model
+runs:
+  - prompt: Bump lint dependencies
+  - prompt: Split the auth service
+    baseBranch: develop
+    outputMode: pr
+    prTargetBranch: release
+    model: openrouter/strong/model
+    files:
+      - internal/auth/service.go
+    gitlabCredential:
+      mode: stored_token_reference
+      tokenReferenceId: glref_123
+`)
+
+	config, err := ParseBulkConfig(path)
+	require.NoError(t, err)
+	require.Len(t, config.Runs, 2)
+	assert.Empty(t, config.Runs[0].Summary())
+
+	overrides := config.Runs[1].RunOverrides
+	assert.Equal(t, "develop", overrides.BaseBranch)
+	assert.Equal(t, "pull_request", overrides.OutputMode, "legacy pr output mode is normalized")
+	assert.Equal(t, []string{"internal/auth/service.go"}, overrides.Files)
+
+	item := config.Runs[1].RunItem()
+	assert.Equal(t, "develop", item.BaseBranch)
+	assert.Equal(t, "release", item.PRTargetBranch)
+	assert.Equal(t, "openrouter/strong/model", item.OpenCodeModel)
+	require.NotNil(t, item.GitLabCredential)
+	assert.Equal(t, "glref_123", item.GitLabCredential.TokenReferenceID)
+
+	assert.Equal(t, []string{
+		"baseBranch=develop",
+		"outputMode=pull_request",
+		"prTargetBranch=release",
+		"model=openrouter/strong/model",
+		"files=internal/auth/service.go",
+		"gitlabCredential=glref_123",
+	}, config.Runs[1].Summary())
+}
+
+func TestValidateBulkConfig_RunOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides RunOverrides
+		errMsg    string
+	}{
+		{"invalid output mode", RunOverrides{OutputMode: "draft"}, "run 1: invalid outputMode 'draft'"},
+		{"invalid run type", RunOverrides{RunType: "fast"}, "invalid runType 'fast'"},
+		{"other repository", RunOverrides{Repository: "org/other"}, "differs from the batch repository"},
+		{"branch output with PR target", RunOverrides{OutputMode: "branch", PRTargetBranch: "main"}, "prTargetBranch cannot be set"},
+		{"incomplete GitLab credential", RunOverrides{GitLabCredential: &models.GitLabCredentialRequest{Mode: "stored_token_reference"}}, "tokenReferenceId is required"},
+		{"same repository", RunOverrides{Repository: "Org/Repo", ProviderMode: "byok-user"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &BulkConfig{
+				Repository: "org/repo",
+				Runs:       []BulkRunConfig{{Prompt: "Fix the bug", RunOverrides: tt.overrides}},
+			}
+			_, err := validateBulkConfig(config)
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestCreateBulkFromSingleConfigs_KeepsDifferingSettings(t *testing.T) {
+	dir := t.TempDir()
+	first := createTempFile(t, dir, "first.json", `{"prompt": "Task 1", "repository": "org/repo", "runType": "run"}`)
+	second := createTempFile(t, dir, "second.json", `{"prompt": "Task 2", "repository": "org/repo", "runType": "run", "baseBranch": "develop", "model": "pro"}`)
+
+	config, err := LoadBulkConfig([]string{first, second})
+	require.NoError(t, err)
+	require.Len(t, config.Runs, 2)
+	assert.Empty(t, config.Runs[0].Summary())
+	assert.Equal(t, []string{"baseBranch=develop", "model=pro"}, config.Runs[1].Summary())
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// ScanSecrets scans every run prompt and context in the batch. Field names
+// are prefixed with the run index, for example "runs[2].context".
+func ScanSecrets(config *BulkConfig) []utils.SecretFinding {
+	if config == nil {
+		return nil
+	}
+
+	var fields []utils.SecretField
+	for i, run := range config.Runs {
+		fields = append(fields,
+			utils.SecretField{Name: fmt.Sprintf("runs[%d].prompt", i), Value: run.Prompt},
+			utils.SecretField{Name: fmt.Sprintf("runs[%d].context", i), Value: run.Context},
+		)
+		for j, file := range run.Files {
+			fields = append(fields, utils.SecretField{Name: fmt.Sprintf("runs[%d].files[%d]", i, j), Value: file})
+		}
+	}
+	return utils.ScanSecrets(fields...)
+}
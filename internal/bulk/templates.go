@@ -0,0 +1,30 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+// ExpandTemplate renders tmpl once per Vars row and appends the results to
+// Runs. Rows are consumed so a config is only expanded once.
+func ExpandTemplate(config *BulkConfig, tmpl *templates.Template) error {
+	for i, row := range config.Vars {
+		vars := make(map[string]string, len(row))
+		for name, value := range row {
+			vars[name] = fmt.Sprint(value)
+		}
+		runConfig, err := tmpl.Render(vars)
+		if err != nil {
+			return fmt.Errorf("vars[%d]: %w", i, err)
+		}
+		run := RunFromConfig(runConfig)
+		run.clearInherited(config)
+		config.Runs = append(config.Runs, run)
+	}
+	config.Vars = nil
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// BulkBatchRecord is the local ledger entry of a submitted bulk batch. It
+// keeps the submitted request so failed runs can be resubmitted with the same
+// per-run settings.
+type BulkBatchRecord struct {
+	BatchID      string             `json:"batch_id"`
+	SuperBatchID string             `json:"super_batch_id,omitempty"`
+	RetryOf      string             `json:"retry_of,omitempty"`
+	Repository   string             `json:"repository"`
+	BatchTitle   string             `json:"batch_title,omitempty"`
+	Request      dto.BulkRunRequest `json:"request"`
+	// RunIDs maps request indexes to the IDs of the runs created for them.
+	RunIDs map[int]int `json:"run_ids,omitempty"`
+	// CreateErrors holds the request indexes the server rejected.
+	CreateErrors []int     `json:"create_errors,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewBulkBatchRecord records the request and the server response of a batch.
+func NewBulkBatchRecord(req *dto.BulkRunRequest, resp *dto.BulkRunResponse) *BulkBatchRecord {
+	record := &BulkBatchRecord{
+		BatchID:    resp.Data.BatchID,
+		Repository: req.RepositoryName,
+		BatchTitle: req.BatchTitle,
+		Request:    *req,
+		RunIDs:     make(map[int]int, len(resp.Data.Successful)),
+		CreatedAt:  time.Now().UTC(),
+	}
+	for _, run := range resp.Data.Successful {
+		record.RunIDs[run.RequestIndex] = run.ID
+	}
+	for _, failure := range resp.Data.Failed {
+		record.CreateErrors = append(record.CreateErrors, failure.RequestIndex)
+	}
+	return record
+}
+
+// BulkLedgerDir returns the directory holding the local batch ledger.
+func BulkLedgerDir() string {
+	return filepath.Join(BulkManifestDir(), "batches")
+}
+
+// SaveBulkBatch writes the record to dir as <batch id>.json.
+func SaveBulkBatch(dir string, record *BulkBatchRecord) error {
+	if record.BatchID == "" {
+		return fmt.Errorf("batch ID cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create batch ledger directory: %w", err)
+	}
+	return writeJSONAtomic(filepath.Join(dir, record.BatchID+".json"), record)
+}
+
+// LoadBulkBatch reads the ledger entry of batchID from dir.
+func LoadBulkBatch(dir, batchID string) (*BulkBatchRecord, error) {
+	var record BulkBatchRecord
+	if err := readJSON(filepath.Join(dir, filepath.Base(batchID)+".json"), &record); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("batch %s is not in the local batch ledger", batchID)
+		}
+		return nil, fmt.Errorf("failed to read batch %s from the ledger: %w", batchID, err)
+	}
+	return &record, nil
+}
+
+// ListBulkBatches returns the ledger entries in dir, newest first. Unreadable
+// files are skipped.
+func ListBulkBatches(dir string) ([]*BulkBatchRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read batch ledger directory: %w", err)
+	}
+	var records []*BulkBatchRecord
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		var record BulkBatchRecord
+		if err := readJSON(filepath.Join(dir, entry.Name()), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// FailedIndexes returns the request indexes that failed: those the server
+// rejected or created no run for, and those whose run ended with a failure
+// status.
+func (r *BulkBatchRecord) FailedIndexes(status dto.BulkStatusData) []int {
+	failedRuns := make(map[int]bool)
+	for _, run := range status.Runs {
+		if models.IsFailureStatus(strings.ToUpper(run.Status)) {
+			failedRuns[run.ID] = true
+		}
+	}
+	var failed []int
+	for index := range r.Request.Runs {
+		runID := r.RunIDs[index]
+		if runID == 0 || failedRuns[runID] {
+			failed = append(failed, index)
+		}
+	}
+	return failed
+}
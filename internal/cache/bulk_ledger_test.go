@@ -0,0 +1,73 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+)
+
+func TestBulkBatchLedger(t *testing.T) {
+	dir := t.TempDir()
+	req := &dto.BulkRunRequest{
+		RepositoryName: "org/repo",
+		BatchTitle:     "Nightly",
+		Runs:           []dto.RunItem{{Prompt: "one"}, {Prompt: "two", BaseBranch: "develop"}, {Prompt: "three"}},
+	}
+	record := NewBulkBatchRecord(req, &dto.BulkRunResponse{Data: dto.BulkRunData{
+		BatchID:    "batch-1",
+		Successful: []dto.RunCreatedItem{{ID: 11, RequestIndex: 0}, {ID: 12, RequestIndex: 1}},
+		Failed:     []dto.RunError{{RequestIndex: 2, Message: "invalid"}},
+	}})
+	require.NoError(t, SaveBulkBatch(dir, record))
+
+	older := NewBulkBatchRecord(req, &dto.BulkRunResponse{Data: dto.BulkRunData{BatchID: "batch-0"}})
+	older.CreatedAt = record.CreatedAt.Add(-time.Hour)
+	require.NoError(t, SaveBulkBatch(dir, older))
+
+	records, err := ListBulkBatches(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "batch-1", records[0].BatchID, "newest batch first")
+
+	loaded, err := LoadBulkBatch(dir, "batch-1")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", loaded.Request.Runs[1].BaseBranch)
+	assert.Equal(t, []int{1, 2}, loaded.FailedIndexes(dto.BulkStatusData{Runs: []dto.RunStatusItem{
+		{ID: 11, Status: "DONE"},
+		{ID: 12, Status: "FAILED"},
+	}}))
+
+	_, err = LoadBulkBatch(dir, "batch-missing")
+	assert.ErrorContains(t, err, "not in the local batch ledger")
+}
+
+func TestBulkBatchFailedIndexes(t *testing.T) {
+	req := &dto.BulkRunRequest{
+		RepositoryName: "org/repo",
+		Runs:           []dto.RunItem{{Prompt: "one"}, {Prompt: "two"}, {Prompt: "three"}, {Prompt: "four"}, {Prompt: "five"}},
+	}
+	record := NewBulkBatchRecord(req, &dto.BulkRunResponse{Data: dto.BulkRunData{
+		BatchID: "batch-1",
+		Successful: []dto.RunCreatedItem{
+			{ID: 11, RequestIndex: 0},
+			{ID: 12, RequestIndex: 1},
+			{ID: 13, RequestIndex: 2},
+			{ID: 14, RequestIndex: 3},
+		},
+	}})
+
+	failed := record.FailedIndexes(dto.BulkStatusData{Runs: []dto.RunStatusItem{
+		{ID: 11, Status: "COMPLETED"},
+		{ID: 12, Status: "CANCELLED"},
+		{ID: 13, Status: "error"},
+		{ID: 14, Status: "PROCESSING"},
+	}})
+	assert.Equal(t, []int{1, 2, 4}, failed, "cancelled and errored runs and requests without a run are retried")
+}
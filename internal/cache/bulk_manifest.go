@@ -0,0 +1,196 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BulkManifest tracks a bulk config that was split into several batches, so
+// progress can be followed across all of them and an interrupted submission
+// can resume with the chunks that were not submitted yet.
+type BulkManifest struct {
+	ID         string              `json:"id"`
+	ConfigHash string              `json:"config_hash"`
+	Repository string              `json:"repository"`
+	BatchTitle string              `json:"batch_title,omitempty"`
+	TotalRuns  int                 `json:"total_runs"`
+	ChunkSize  int                 `json:"chunk_size"`
+	RunHashes  []string            `json:"run_hashes"`
+	Chunks     []BulkManifestChunk `json:"chunks"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// BulkManifestChunk is one sub-batch covering runs [Start, End) of the config.
+// A submitted chunk has SubmittedAt set; BatchID may still be empty when the
+// API created its runs without returning a batch.
+type BulkManifestChunk struct {
+	Start       int        `json:"start"`
+	End         int        `json:"end"`
+	BatchID     string     `json:"batch_id,omitempty"`
+	RunIDs      []int      `json:"run_ids,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// NewBulkManifest splits runHashes into chunks of chunkSize runs. The config
+// hash identifies the same config on a later attempt.
+func NewBulkManifest(repository, batchTitle string, runHashes []string, chunkSize int) *BulkManifest {
+	now := time.Now().UTC()
+	manifest := &BulkManifest{
+		ID:         newBulkManifestID(),
+		ConfigHash: BulkConfigHash(repository, runHashes, chunkSize),
+		Repository: repository,
+		BatchTitle: batchTitle,
+		TotalRuns:  len(runHashes),
+		ChunkSize:  chunkSize,
+		RunHashes:  runHashes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	for start := 0; start < len(runHashes); start += chunkSize {
+		end := min(start+chunkSize, len(runHashes))
+		manifest.Chunks = append(manifest.Chunks, BulkManifestChunk{Start: start, End: end})
+	}
+	return manifest
+}
+
+// BulkConfigHash identifies a bulk config by its repository, run hashes and
+// chunk size.
+func BulkConfigHash(repository string, runHashes []string, chunkSize int) string {
+	content := fmt.Sprintf("%s\x00%d\x00%s", strings.ToLower(repository), chunkSize, strings.Join(runHashes, "\x00"))
+	return CalculateStringHash(content)
+}
+
+func newBulkManifestID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("sb_%d", time.Now().UnixNano())
+	}
+	return "sb_" + hex.EncodeToString(b)
+}
+
+// BulkManifestDir returns the directory holding bulk manifests in the user
+// cache dir.
+func BulkManifestDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "bulk")
+}
+
+// SaveBulkManifest writes the manifest to dir as <id>.json.
+func SaveBulkManifest(dir string, manifest *BulkManifest) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create bulk manifest directory: %w", err)
+	}
+	manifest.UpdatedAt = time.Now().UTC()
+	return writeJSONAtomic(filepath.Join(dir, manifest.ID+".json"), manifest)
+}
+
+// LoadBulkManifest reads the manifest with the given ID from dir.
+func LoadBulkManifest(dir, id string) (*BulkManifest, error) {
+	var manifest BulkManifest
+	if err := readJSON(filepath.Join(dir, id+".json"), &manifest); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("bulk manifest %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to read bulk manifest %s: %w", id, err)
+	}
+	return &manifest, nil
+}
+
+// ListBulkManifests returns the manifests in dir, newest first. Unreadable
+// files are skipped.
+func ListBulkManifests(dir string) ([]*BulkManifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bulk manifest directory: %w", err)
+	}
+	var manifests []*BulkManifest
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		var manifest BulkManifest
+		if err := readJSON(filepath.Join(dir, entry.Name()), &manifest); err != nil {
+			continue
+		}
+		manifests = append(manifests, &manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// FindIncompleteBulkManifest returns the newest manifest for configHash that
+// still has chunks to submit, or nil when there is none.
+func FindIncompleteBulkManifest(dir, configHash string) (*BulkManifest, error) {
+	manifests, err := ListBulkManifests(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		if manifest.ConfigHash == configHash && !manifest.Complete() {
+			return manifest, nil
+		}
+	}
+	return nil, nil
+}
+
+// Pending returns the indexes of chunks that have not been submitted.
+func (m *BulkManifest) Pending() []int {
+	var pending []int
+	for i, chunk := range m.Chunks {
+		if chunk.SubmittedAt == nil {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// Complete reports whether every chunk has been submitted.
+func (m *BulkManifest) Complete() bool {
+	return len(m.Pending()) == 0
+}
+
+// BatchIDs returns the batch IDs of the submitted chunks in chunk order.
+func (m *BulkManifest) BatchIDs() []string {
+	var ids []string
+	for _, chunk := range m.Chunks {
+		if chunk.BatchID != "" {
+			ids = append(ids, chunk.BatchID)
+		}
+	}
+	return ids
+}
+
+// RecordChunk stores the outcome of submitting chunk index, with the IDs of
+// the runs it created.
+func (m *BulkManifest) RecordChunk(index int, batchID string, runIDs []int, err error) {
+	chunk := &m.Chunks[index]
+	if err != nil {
+		chunk.Error = err.Error()
+		return
+	}
+	now := time.Now().UTC()
+	chunk.BatchID = batchID
+	chunk.RunIDs = runIDs
+	chunk.SubmittedAt = &now
+	chunk.Error = ""
+}
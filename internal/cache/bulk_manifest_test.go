@@ -0,0 +1,59 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkManifestChunksAndResume(t *testing.T) {
+	dir := t.TempDir()
+	hashes := make([]string, 90)
+	for i := range hashes {
+		hashes[i] = fmt.Sprintf("hash-%d", i)
+	}
+
+	manifest := NewBulkManifest("org/repo", "Nightly", hashes, 40)
+	require.Len(t, manifest.Chunks, 3)
+	assert.Equal(t, BulkManifestChunk{Start: 80, End: 90}, manifest.Chunks[2])
+	assert.Equal(t, []int{0, 1, 2}, manifest.Pending())
+
+	manifest.RecordChunk(0, "batch-1", nil, nil)
+	manifest.RecordChunk(1, "", nil, errors.New("quota exceeded"))
+	require.NoError(t, SaveBulkManifest(dir, manifest))
+
+	found, err := FindIncompleteBulkManifest(dir, BulkConfigHash("Org/Repo", hashes, 40))
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, manifest.ID, found.ID)
+	assert.Equal(t, []int{1, 2}, found.Pending())
+	assert.Equal(t, "quota exceeded", found.Chunks[1].Error)
+	assert.Equal(t, []string{"batch-1"}, found.BatchIDs())
+
+	// A different chunk size is a different super-batch.
+	other, err := FindIncompleteBulkManifest(dir, BulkConfigHash("org/repo", hashes, 20))
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	found.RecordChunk(1, "batch-2", nil, nil)
+	// A chunk whose runs were created without a batch ID is still submitted.
+	found.RecordChunk(2, "", []int{301, 302}, nil)
+	require.NoError(t, SaveBulkManifest(dir, found))
+	assert.True(t, found.Complete())
+	assert.Empty(t, found.Chunks[1].Error)
+
+	done, err := FindIncompleteBulkManifest(dir, found.ConfigHash)
+	require.NoError(t, err)
+	assert.Nil(t, done, "a complete manifest is not resumed")
+
+	loaded, err := LoadBulkManifest(dir, found.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch-1", "batch-2"}, loaded.BatchIDs())
+	assert.Equal(t, []int{301, 302}, loaded.Chunks[2].RunIDs)
+}
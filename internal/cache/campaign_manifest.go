@@ -0,0 +1,162 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrCampaignNotFound is returned when a campaign has no manifest yet.
+var ErrCampaignNotFound = errors.New("not found")
+
+// CampaignManifest tracks the runs of a multi-repository campaign. It is
+// keyed by the campaign name, so starting the same campaign again only
+// submits the repositories that do not have a run yet.
+type CampaignManifest struct {
+	Name       string         `json:"name"`
+	ConfigPath string         `json:"config_path,omitempty"`
+	Repos      []CampaignRepo `json:"repos"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// CampaignRepo is the state of one repository in a campaign.
+type CampaignRepo struct {
+	Repository     string     `json:"repository"`
+	RunID          string     `json:"run_id,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	PullRequestURL string     `json:"pr_url,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	SubmittedAt    *time.Time `json:"submitted_at,omitempty"`
+}
+
+// NewCampaignManifest returns an empty manifest for the campaign.
+func NewCampaignManifest(name, configPath string) *CampaignManifest {
+	now := time.Now().UTC()
+	return &CampaignManifest{
+		Name:       name,
+		ConfigPath: configPath,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// CampaignDir returns the directory holding campaign manifests in the user
+// cache dir.
+func CampaignDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "campaigns")
+}
+
+// SaveCampaignManifest writes the manifest to dir as <name>.json.
+func SaveCampaignManifest(dir string, manifest *CampaignManifest) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create campaign directory: %w", err)
+	}
+	manifest.UpdatedAt = time.Now().UTC()
+	return writeJSONAtomic(filepath.Join(dir, filepath.Base(manifest.Name)+".json"), manifest)
+}
+
+// LoadCampaignManifest reads the manifest of the named campaign from dir.
+func LoadCampaignManifest(dir, name string) (*CampaignManifest, error) {
+	var manifest CampaignManifest
+	if err := readJSON(filepath.Join(dir, filepath.Base(name)+".json"), &manifest); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("campaign %s %w", name, ErrCampaignNotFound)
+		}
+		return nil, fmt.Errorf("failed to read campaign %s: %w", name, err)
+	}
+	return &manifest, nil
+}
+
+// ListCampaignManifests returns the manifests in dir, newest first.
+// Unreadable files are skipped.
+func ListCampaignManifests(dir string) ([]*CampaignManifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read campaign directory: %w", err)
+	}
+	var manifests []*CampaignManifest
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		var manifest CampaignManifest
+		if err := readJSON(filepath.Join(dir, entry.Name()), &manifest); err != nil {
+			continue
+		}
+		manifests = append(manifests, &manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// AddRepositories appends the repositories that are not in the manifest yet.
+func (m *CampaignManifest) AddRepositories(repositories []string) {
+	known := make(map[string]bool, len(m.Repos))
+	for _, repo := range m.Repos {
+		known[strings.ToLower(repo.Repository)] = true
+	}
+	for _, repository := range repositories {
+		if !known[strings.ToLower(repository)] {
+			known[strings.ToLower(repository)] = true
+			m.Repos = append(m.Repos, CampaignRepo{Repository: repository})
+		}
+	}
+}
+
+// Pending returns the indexes of repositories without a run, including those
+// whose submission failed.
+func (m *CampaignManifest) Pending() []int {
+	var pending []int
+	for i, repo := range m.Repos {
+		if repo.RunID == "" {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// RecordSubmission stores the outcome of creating the run of repository index.
+func (m *CampaignManifest) RecordSubmission(index int, runID, status string, err error) {
+	repo := &m.Repos[index]
+	if err != nil {
+		repo.Error = err.Error()
+		return
+	}
+	now := time.Now().UTC()
+	repo.RunID = runID
+	repo.Status = status
+	repo.Error = ""
+	repo.SubmittedAt = &now
+}
+
+// Counts returns how many repositories have a run and how many failed,
+// either to submit or as a run.
+func (m *CampaignManifest) Counts() (submitted, failed int) {
+	for _, repo := range m.Repos {
+		if repo.RunID != "" {
+			submitted++
+		}
+		if repo.Error != "" || strings.EqualFold(repo.Status, "failed") {
+			failed++
+		}
+	}
+	return submitted, failed
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCampaignManifestTracksRepositories(t *testing.T) {
+	dir := t.TempDir()
+	manifest := NewCampaignManifest("upgrade-ci", "upgrade-ci.yaml")
+	manifest.AddRepositories([]string{"acme/api", "acme/web", "acme/cli"})
+	manifest.RecordSubmission(0, "101", "queued", nil)
+	manifest.RecordSubmission(1, "", "", errors.New("repository not found"))
+	require.NoError(t, SaveCampaignManifest(dir, manifest))
+
+	loaded, err := LoadCampaignManifest(dir, "upgrade-ci")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, loaded.Pending())
+	assert.Equal(t, "repository not found", loaded.Repos[1].Error)
+
+	// Repositories already in the campaign are not added twice.
+	loaded.AddRepositories([]string{"ACME/api", "acme/docs"})
+	require.Len(t, loaded.Repos, 4)
+	assert.Equal(t, "acme/docs", loaded.Repos[3].Repository)
+
+	loaded.Repos[0].Status = "failed"
+	submitted, failed := loaded.Counts()
+	assert.Equal(t, 1, submitted)
+	assert.Equal(t, 2, failed)
+
+	manifests, err := ListCampaignManifests(dir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+
+	_, err = LoadCampaignManifest(dir, "missing")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCampaignNotFound)
+	assert.Contains(t, err.Error(), "campaign missing not found")
+}
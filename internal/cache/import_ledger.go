@@ -0,0 +1,97 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Import states recorded in the ledger.
+const (
+	ImportStateExported  = "exported"
+	ImportStateSubmitted = "submitted"
+)
+
+// ImportRecord remembers what became of an imported issue or a scanned
+// TODO marker.
+type ImportRecord struct {
+	Source     string `json:"source"`
+	Repository string `json:"repository"`
+	IssueKey   string `json:"issue_key"`
+	Title      string `json:"title,omitempty"`
+	// State is ImportStateExported when the issue was written to a bulk file
+	// for review, or ImportStateSubmitted once its run was created.
+	State      string    `json:"state"`
+	RunID      string    `json:"run_id,omitempty"`
+	File       string    `json:"file,omitempty"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// ImportLedger maps imported issues and TODO markers to what was done with
+// them, so a later import or scan can skip them.
+type ImportLedger struct {
+	Records map[string]ImportRecord `json:"records"`
+}
+
+// ImportLedgerPath returns the ledger file in the user cache dir.
+func ImportLedgerPath() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "imports.json")
+}
+
+// LoadImportLedger reads the ledger at path. A missing file is an empty
+// ledger.
+func LoadImportLedger(path string) (*ImportLedger, error) {
+	ledger := &ImportLedger{}
+	if err := readJSON(path, ledger); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read import ledger: %w", err)
+	}
+	if ledger.Records == nil {
+		ledger.Records = make(map[string]ImportRecord)
+	}
+	return ledger, nil
+}
+
+// SaveImportLedger writes the ledger to path.
+func SaveImportLedger(path string, ledger *ImportLedger) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create import ledger directory: %w", err)
+	}
+	return writeJSONAtomic(path, ledger)
+}
+
+// Lookup returns the record of an issue imported into repository. Issues
+// without a key are never recorded, so they are never found.
+func (l *ImportLedger) Lookup(source, repository, issueKey string) (ImportRecord, bool) {
+	if issueKey == "" {
+		return ImportRecord{}, false
+	}
+	record, ok := l.Records[importKey(source, repository, issueKey)]
+	return record, ok
+}
+
+// Record stores record, replacing an earlier record of the same issue. A
+// record without an issue key is dropped, since it would share its ledger
+// entry with every other issue missing a key.
+func (l *ImportLedger) Record(record ImportRecord) {
+	if record.IssueKey == "" {
+		return
+	}
+	if record.ImportedAt.IsZero() {
+		record.ImportedAt = time.Now().UTC()
+	}
+	l.Records[importKey(record.Source, record.Repository, record.IssueKey)] = record
+}
+
+func importKey(source, repository, issueKey string) string {
+	return strings.ToLower(source + ":" + repository + "#" + issueKey)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repobird", "imports.json")
+	ledger, err := LoadImportLedger(path)
+	require.NoError(t, err)
+	_, ok := ledger.Lookup("github", "acme/webapp", "42")
+	assert.False(t, ok)
+
+	ledger.Record(ImportRecord{Source: "github", Repository: "acme/webapp", IssueKey: "42", State: ImportStateExported, File: "tasks.yaml"})
+	ledger.Record(ImportRecord{Source: "github", Repository: "acme/webapp", IssueKey: "42", State: ImportStateSubmitted, RunID: "101"})
+	require.NoError(t, SaveImportLedger(path, ledger))
+
+	loaded, err := LoadImportLedger(path)
+	require.NoError(t, err)
+	record, ok := loaded.Lookup("GitHub", "Acme/WebApp", "42")
+	require.True(t, ok)
+	assert.Equal(t, ImportStateSubmitted, record.State)
+	assert.Equal(t, "101", record.RunID)
+	assert.False(t, record.ImportedAt.IsZero())
+
+	_, ok = loaded.Lookup("github", "acme/other", "42")
+	assert.False(t, ok)
+
+	// Issues without a key would all share one entry, so they are not
+	// recorded.
+	loaded.Record(ImportRecord{Source: "github", Repository: "acme/webapp", State: ImportStateSubmitted, RunID: "102"})
+	_, ok = loaded.Lookup("github", "acme/webapp", "")
+	assert.False(t, ok)
+	assert.Len(t, loaded.Records, 1)
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrLocked is returned when another process holds a lock file.
+var ErrLocked = errors.New("lock is held by another process")
+
+// LockFile creates the lock file at path and returns a func that removes it.
+// A lock file older than stale is taken to be left by a process that died
+// and is replaced.
+func LockFile(path string, stale time.Duration) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) < stale {
+			break
+		}
+		_ = os.Remove(path)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrLocked, path)
+}
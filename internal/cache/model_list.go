@@ -0,0 +1,77 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// ModelListTTL is how long a fetched model list is trusted before it is
+// fetched again.
+const ModelListTTL = 24 * time.Hour
+
+// ModelListFailureTTL is how long a failed fetch is remembered before the
+// list is fetched again, so an API without the model list is not asked on
+// every run.
+const ModelListFailureTTL = 24 * time.Hour
+
+// ModelList is the cached list of models runs may select.
+type ModelList struct {
+	Models    []models.ModelInfo `json:"models"`
+	FetchedAt time.Time          `json:"fetched_at"`
+	// FailedAt is when fetching the list last failed.
+	FailedAt time.Time `json:"failed_at,omitempty"`
+}
+
+// ModelListPath returns the model list cache file in the user cache dir. It
+// fails when neither the cache dir nor the home directory is known, rather
+// than caching relative to the working directory.
+func ModelListPath() (string, error) {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the model list cache: %w", err)
+		}
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "models.json"), nil
+}
+
+// LoadModelList reads the cached model list. It returns nil without an
+// error when nothing is cached.
+func LoadModelList(path string) (*ModelList, error) {
+	var list ModelList
+	if err := readJSON(path, &list); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read model list cache: %w", err)
+	}
+	return &list, nil
+}
+
+// SaveModelList writes the model list to the cache.
+func SaveModelList(path string, list *ModelList) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return writeJSONAtomic(path, list)
+}
+
+// Fresh reports whether the list was fetched within ModelListTTL of now.
+func (l *ModelList) Fresh(now time.Time) bool {
+	return l != nil && now.Sub(l.FetchedAt) < ModelListTTL
+}
+
+// RecentlyFailed reports whether fetching the list failed within
+// ModelListFailureTTL of now.
+func (l *ModelList) RecentlyFailed(now time.Time) bool {
+	return l != nil && !l.FailedAt.IsZero() && now.Sub(l.FailedAt) < ModelListFailureTTL
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Outcomes of a schedule slot.
+const (
+	ScheduleRunSubmitted = "submitted"
+	ScheduleRunFailed    = "failed"
+	ScheduleRunMissed    = "missed"
+	ScheduleRunDuplicate = "duplicate"
+)
+
+// maxScheduleRuns is the number of outcomes kept per schedule.
+const maxScheduleRuns = 50
+
+// ScheduleState records the slots a schedule has handled.
+type ScheduleState struct {
+	Name string `json:"name"`
+	// LastSlot is the latest slot that was fired or passed over; later runs
+	// start counting from it.
+	LastSlot time.Time     `json:"last_slot,omitempty"`
+	Runs     []ScheduleRun `json:"runs,omitempty"`
+}
+
+// ScheduleRun is the outcome of one slot, newest last.
+type ScheduleRun struct {
+	Slot    time.Time `json:"slot"`
+	Status  string    `json:"status"`
+	RunID   string    `json:"run_id,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Skipped int       `json:"skipped,omitempty"`
+	FiredAt time.Time `json:"fired_at"`
+}
+
+// ScheduleDir returns the directory holding schedule states in the user cache
+// dir.
+func ScheduleDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "schedules")
+}
+
+// LoadScheduleState reads the state of the named schedule from dir. A
+// schedule that never fired has an empty state.
+func LoadScheduleState(dir, name string) (*ScheduleState, error) {
+	state := &ScheduleState{Name: name}
+	if err := readJSON(scheduleStatePath(dir, name), state); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read schedule state %s: %w", name, err)
+	}
+	return state, nil
+}
+
+// SaveScheduleState writes the state to dir as <name>.json.
+func SaveScheduleState(dir string, state *ScheduleState) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create schedule directory: %w", err)
+	}
+	return writeJSONAtomic(scheduleStatePath(dir, state.Name), state)
+}
+
+// RemoveScheduleState deletes the state of the named schedule.
+func RemoveScheduleState(dir, name string) error {
+	if err := os.Remove(scheduleStatePath(dir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove schedule state %s: %w", name, err)
+	}
+	return nil
+}
+
+// Record appends the outcome of a slot and advances LastSlot to it. A failed
+// slot leaves LastSlot alone, so the slot is tried again.
+func (s *ScheduleState) Record(run ScheduleRun) {
+	if run.FiredAt.IsZero() {
+		run.FiredAt = time.Now().UTC()
+	}
+	s.Runs = append(s.Runs, run)
+	if len(s.Runs) > maxScheduleRuns {
+		s.Runs = s.Runs[len(s.Runs)-maxScheduleRuns:]
+	}
+	if run.Status != ScheduleRunFailed && run.Slot.After(s.LastSlot) {
+		s.LastSlot = run.Slot
+	}
+}
+
+// Last returns the newest outcome, or nil.
+func (s *ScheduleState) Last() *ScheduleRun {
+	if len(s.Runs) == 0 {
+		return nil
+	}
+	return &s.Runs[len(s.Runs)-1]
+}
+
+func scheduleStatePath(dir, name string) string {
+	return filepath.Join(dir, filepath.Base(name)+".json")
+}
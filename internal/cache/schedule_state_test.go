@@ -0,0 +1,64 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleStateRecord(t *testing.T) {
+	dir := t.TempDir()
+	state, err := LoadScheduleState(dir, "nightly")
+	require.NoError(t, err)
+	assert.True(t, state.LastSlot.IsZero())
+	assert.Nil(t, state.Last())
+
+	slot := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < maxScheduleRuns+5; i++ {
+		state.Record(ScheduleRun{Slot: slot.AddDate(0, 0, i), Status: ScheduleRunSubmitted, RunID: "run"})
+	}
+	assert.Len(t, state.Runs, maxScheduleRuns)
+	assert.Equal(t, slot.AddDate(0, 0, maxScheduleRuns+4), state.LastSlot)
+
+	state.Record(ScheduleRun{Slot: slot.AddDate(0, 0, maxScheduleRuns+5), Status: ScheduleRunFailed})
+	assert.Equal(t, slot.AddDate(0, 0, maxScheduleRuns+4), state.LastSlot)
+	assert.Equal(t, ScheduleRunFailed, state.Last().Status)
+	state.Record(ScheduleRun{Slot: slot.AddDate(0, 0, maxScheduleRuns+5), Status: ScheduleRunSubmitted, RunID: "run"})
+
+	require.NoError(t, SaveScheduleState(dir, state))
+	loaded, err := LoadScheduleState(dir, "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, state.LastSlot, loaded.LastSlot)
+	assert.Equal(t, ScheduleRunSubmitted, loaded.Last().Status)
+
+	require.NoError(t, RemoveScheduleState(dir, "nightly"))
+	require.NoError(t, RemoveScheduleState(dir, "nightly"))
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "run.lock")
+	unlock, err := LockFile(path, time.Minute)
+	require.NoError(t, err)
+
+	_, err = LockFile(path, time.Minute)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	unlock()
+	unlock, err = LockFile(path, time.Minute)
+	require.NoError(t, err)
+	defer unlock()
+
+	// A lock older than the stale age is replaced.
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	_, err = LockFile(path, time.Minute)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Workflow step states.
+const (
+	WorkflowStepPending   = "pending"
+	WorkflowStepRunning   = "running"
+	WorkflowStepSucceeded = "succeeded"
+	WorkflowStepFailed    = "failed"
+	WorkflowStepSkipped   = "skipped"
+)
+
+// WorkflowState tracks the steps of a workflow run. It is keyed by the
+// workflow name, so 'workflow resume' picks up where a run stopped.
+type WorkflowState struct {
+	Name       string         `json:"name"`
+	ConfigPath string         `json:"config_path"`
+	Steps      []WorkflowStep `json:"steps"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// WorkflowStep is the state of one workflow step.
+type WorkflowStep struct {
+	ID             string     `json:"id"`
+	Needs          []string   `json:"needs,omitempty"`
+	Status         string     `json:"status"`
+	RunID          string     `json:"run_id,omitempty"`
+	RunStatus      string     `json:"run_status,omitempty"`
+	BaseBranch     string     `json:"base_branch,omitempty"`
+	OutputBranch   string     `json:"output_branch,omitempty"`
+	PullRequestURL string     `json:"pr_url,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewWorkflowState returns a state with every step pending. needs maps each
+// step ID, in file order, to the steps it needs.
+func NewWorkflowState(name, configPath string, ids []string, needs map[string][]string) *WorkflowState {
+	now := time.Now().UTC()
+	state := &WorkflowState{Name: name, ConfigPath: configPath, CreatedAt: now, UpdatedAt: now}
+	state.SyncSteps(ids, needs)
+	return state
+}
+
+// SyncSteps adds steps that are not tracked yet, drops steps that are gone
+// and updates the dependencies of the rest.
+func (s *WorkflowState) SyncSteps(ids []string, needs map[string][]string) {
+	existing := make(map[string]WorkflowStep, len(s.Steps))
+	for _, step := range s.Steps {
+		existing[step.ID] = step
+	}
+	steps := make([]WorkflowStep, 0, len(ids))
+	for _, id := range ids {
+		step, ok := existing[id]
+		if !ok {
+			step = WorkflowStep{ID: id, Status: WorkflowStepPending}
+		}
+		step.Needs = needs[id]
+		steps = append(steps, step)
+	}
+	s.Steps = steps
+}
+
+// WorkflowDir returns the directory holding workflow states in the user cache
+// dir.
+func WorkflowDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, appName, "workflows")
+}
+
+// SaveWorkflowState writes the state to dir as <name>.json.
+func SaveWorkflowState(dir string, state *WorkflowState) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create workflow directory: %w", err)
+	}
+	state.UpdatedAt = time.Now().UTC()
+	return writeJSONAtomic(filepath.Join(dir, filepath.Base(state.Name)+".json"), state)
+}
+
+// LoadWorkflowState reads the state of the named workflow from dir.
+func LoadWorkflowState(dir, name string) (*WorkflowState, error) {
+	var state WorkflowState
+	if err := readJSON(filepath.Join(dir, filepath.Base(name)+".json"), &state); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("workflow %s not found", name)
+		}
+		return nil, fmt.Errorf("failed to read workflow %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+// Step returns the step with id, or nil.
+func (s *WorkflowState) Step(id string) *WorkflowStep {
+	for i := range s.Steps {
+		if s.Steps[i].ID == id {
+			return &s.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Ready returns the indexes of pending steps whose dependencies succeeded.
+func (s *WorkflowState) Ready() []int {
+	var ready []int
+	for i, step := range s.Steps {
+		if step.Status != WorkflowStepPending {
+			continue
+		}
+		if s.needsSucceeded(step) {
+			ready = append(ready, i)
+		}
+	}
+	return ready
+}
+
+// SkipBlocked marks pending steps that depend on a failed or skipped step as
+// skipped, until no more steps are blocked.
+func (s *WorkflowState) SkipBlocked() {
+	for changed := true; changed; {
+		changed = false
+		for i := range s.Steps {
+			step := &s.Steps[i]
+			if step.Status != WorkflowStepPending {
+				continue
+			}
+			for _, id := range step.Needs {
+				need := s.Step(id)
+				if need != nil && (need.Status == WorkflowStepFailed || need.Status == WorkflowStepSkipped) {
+					step.Status = WorkflowStepSkipped
+					step.Error = fmt.Sprintf("needs %s, which %s", need.ID, need.Status)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// SkipPending marks every pending step as skipped with reason.
+func (s *WorkflowState) SkipPending(reason string) {
+	for i := range s.Steps {
+		if s.Steps[i].Status == WorkflowStepPending {
+			s.Steps[i].Status = WorkflowStepSkipped
+			s.Steps[i].Error = reason
+		}
+	}
+}
+
+// ResetUnfinished makes failed and skipped steps pending again so a resumed
+// workflow retries them. Running steps keep their runs.
+func (s *WorkflowState) ResetUnfinished() {
+	for i := range s.Steps {
+		step := &s.Steps[i]
+		if step.Status == WorkflowStepFailed || step.Status == WorkflowStepSkipped {
+			*step = WorkflowStep{ID: step.ID, Needs: step.Needs, Status: WorkflowStepPending}
+		}
+	}
+}
+
+// HasFailed reports whether a step failed.
+func (s *WorkflowState) HasFailed() bool {
+	for _, step := range s.Steps {
+		if step.Status == WorkflowStepFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// Finished reports whether every step succeeded.
+func (s *WorkflowState) Finished() bool {
+	for _, step := range s.Steps {
+		if step.Status != WorkflowStepSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// Counts returns the number of steps in each status.
+func (s *WorkflowState) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, step := range s.Steps {
+		counts[step.Status]++
+	}
+	return counts
+}
+
+func (s *WorkflowState) needsSucceeded(step WorkflowStep) bool {
+	for _, id := range step.Needs {
+		need := s.Step(id)
+		if need == nil || need.Status != WorkflowStepSucceeded {
+			return false
+		}
+	}
+	return true
+}
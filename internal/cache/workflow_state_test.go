@@ -0,0 +1,44 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowStateScheduling(t *testing.T) {
+	ids := []string{"schema", "api", "docs", "ui"}
+	needs := map[string][]string{"api": {"schema"}, "ui": {"api", "docs"}}
+	state := NewWorkflowState("profiles", "profiles.yaml", ids, needs)
+	assert.Equal(t, []int{0, 2}, state.Ready())
+
+	state.Step("schema").Status = WorkflowStepSucceeded
+	state.Step("docs").Status = WorkflowStepRunning
+	assert.Equal(t, []int{1}, state.Ready())
+
+	state.Step("api").Status = WorkflowStepFailed
+	state.SkipBlocked()
+	assert.Equal(t, WorkflowStepSkipped, state.Step("ui").Status)
+	assert.Equal(t, "needs api, which failed", state.Step("ui").Error)
+	assert.False(t, state.Finished())
+
+	dir := t.TempDir()
+	require.NoError(t, SaveWorkflowState(dir, state))
+	loaded, err := LoadWorkflowState(dir, "profiles")
+	require.NoError(t, err)
+
+	// Resuming retries the failed and skipped steps and keeps running ones.
+	loaded.ResetUnfinished()
+	loaded.SyncSteps(append(ids, "release"), map[string][]string{"api": {"schema"}, "ui": {"api", "docs"}, "release": {"ui"}})
+	assert.Equal(t, map[string]int{WorkflowStepSucceeded: 1, WorkflowStepRunning: 1, WorkflowStepPending: 3}, loaded.Counts())
+	assert.Equal(t, []int{1}, loaded.Ready())
+	assert.Empty(t, loaded.Step("ui").Error)
+
+	_, err = LoadWorkflowState(dir, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow missing not found")
+}
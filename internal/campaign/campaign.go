@@ -0,0 +1,282 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package campaign runs one task across many repositories. A campaign config
+// names the repositories, directly or as globs over the repositories the API
+// lists, and a prompt or template rendered once per repository.
+package campaign
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+const (
+	// DefaultConcurrency is how many runs are created at once when the config
+	// does not set a limit.
+	DefaultConcurrency = 4
+	// MaxConcurrency caps the concurrency a config or flag may request.
+	MaxConcurrency = 20
+)
+
+// Config is a campaign configuration file.
+type Config struct {
+	Name string `json:"name" yaml:"name"`
+	// Repositories are owner/name entries or globs such as "acme/*" or
+	// "acme/service-*", matched against the repositories the API lists.
+	Repositories []string `json:"repositories" yaml:"repositories"`
+	// Exclude removes matching repositories, using the same syntax.
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Prompt and Title are Go templates rendered with the repository
+	// variables. Exactly one of Prompt and Template must be set.
+	Prompt   string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+	Title    string `json:"title,omitempty" yaml:"title,omitempty"`
+	Context  string `json:"context,omitempty" yaml:"context,omitempty"`
+	// Vars apply to every repository; RepoVars, keyed by owner/name, add or
+	// replace variables for one repository.
+	Vars        map[string]string            `json:"vars,omitempty" yaml:"vars,omitempty"`
+	RepoVars    map[string]map[string]string `json:"repoVars,omitempty" yaml:"repoVars,omitempty"`
+	RunType     string                       `json:"runType,omitempty" yaml:"runType,omitempty"`
+	Source      string                       `json:"source,omitempty" yaml:"source,omitempty"`
+	Target      string                       `json:"target,omitempty" yaml:"target,omitempty"`
+	Model       string                       `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider    string                       `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Concurrency int                          `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// LoadConfig reads and validates a YAML or JSON campaign config.
+func LoadConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign config: %w", err)
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse campaign config %s: %w", file, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid campaign config %s: %w", file, err)
+	}
+	return &config, nil
+}
+
+// Validate checks the config for missing or conflicting settings.
+func (c *Config) Validate() error {
+	switch {
+	case c.Name == "":
+		return fmt.Errorf("name is required")
+	case !namePattern.MatchString(c.Name):
+		return fmt.Errorf("name %q may only contain letters, digits, '.', '_' and '-'", c.Name)
+	case len(c.Repositories) == 0:
+		return fmt.Errorf("at least one repository is required")
+	case c.Prompt == "" && c.Template == "":
+		return fmt.Errorf("either prompt or template is required")
+	case c.Prompt != "" && c.Template != "":
+		return fmt.Errorf("prompt and template cannot be used together")
+	case c.Concurrency < 0 || c.Concurrency > MaxConcurrency:
+		return fmt.Errorf("concurrency must be between 1 and %d", MaxConcurrency)
+	}
+	for _, pattern := range append(append([]string(nil), c.Repositories...), c.Exclude...) {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// EffectiveConcurrency returns the configured concurrency or the default.
+func (c *Config) EffectiveConcurrency() int {
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+// HasGlobs reports whether resolving the repositories needs the repository
+// list from the API.
+func (c *Config) HasGlobs() bool {
+	for _, pattern := range append(append([]string(nil), c.Repositories...), c.Exclude...) {
+		if isGlob(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRepositories expands the repository entries against available, the
+// repositories the API lists. Plain names are kept as written, globs match
+// enabled repositories case-insensitively, and the result keeps the config
+// order without duplicates. A glob that matches nothing is an error so a typo
+// does not silently shrink the campaign.
+func (c *Config) ResolveRepositories(available []models.APIRepository) ([]string, error) {
+	var names []string
+	for _, repo := range available {
+		if repo.IsEnabled && repo.FullName() != "" {
+			names = append(names, repo.FullName())
+		}
+	}
+
+	seen := make(map[string]bool)
+	var resolved []string
+	for _, pattern := range c.Repositories {
+		if !isGlob(pattern) {
+			if !seen[strings.ToLower(pattern)] {
+				seen[strings.ToLower(pattern)] = true
+				resolved = append(resolved, pattern)
+			}
+			continue
+		}
+		matched := false
+		for _, name := range names {
+			if !matchRepository(pattern, name) {
+				continue
+			}
+			matched = true
+			if !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				resolved = append(resolved, name)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("repository pattern %q matches no enabled repository", pattern)
+		}
+	}
+
+	var included []string
+	for _, name := range resolved {
+		excluded := false
+		for _, pattern := range c.Exclude {
+			if matchRepository(pattern, name) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			included = append(included, name)
+		}
+	}
+	if len(included) == 0 {
+		return nil, fmt.Errorf("no repositories left after applying exclude")
+	}
+	return included, nil
+}
+
+// Variables returns the variables for repository: the automatic repo, owner
+// and name variables, then Vars, then the repository's RepoVars.
+func (c *Config) Variables(repository string) map[string]string {
+	owner, name, _ := strings.Cut(repository, "/")
+	vars := map[string]string{
+		"repo":  repository,
+		"owner": owner,
+		"name":  name,
+	}
+	for key, value := range c.Vars {
+		vars[key] = value
+	}
+	for repo, repoVars := range c.RepoVars {
+		if strings.EqualFold(repo, repository) {
+			for key, value := range repoVars {
+				vars[key] = value
+			}
+		}
+	}
+	return vars
+}
+
+// Render returns the run configuration for repository. tmpl must be the
+// template named by the config, or nil for an inline prompt.
+func (c *Config) Render(repository string, tmpl *templates.Template) (*models.RunConfig, error) {
+	vars := c.Variables(repository)
+	var runConfig *models.RunConfig
+	if tmpl != nil {
+		rendered, err := tmpl.Render(declaredVariables(tmpl, vars))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repository, err)
+		}
+		runConfig = rendered
+	} else {
+		prompt, err := renderText("prompt", c.Prompt, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repository, err)
+		}
+		runConfig = &models.RunConfig{Prompt: prompt}
+	}
+
+	if c.Title != "" {
+		title, err := renderText("title", c.Title, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repository, err)
+		}
+		runConfig.Title = title
+	}
+	runConfig.Repository = repository
+	for _, field := range []struct {
+		value string
+		dest  *string
+	}{
+		{c.Context, &runConfig.Context},
+		{c.RunType, &runConfig.RunType},
+		{c.Source, &runConfig.Source},
+		{c.Target, &runConfig.Target},
+		{c.Model, &runConfig.Model},
+		{c.Provider, &runConfig.Provider},
+	} {
+		if field.value != "" {
+			*field.dest = field.value
+		}
+	}
+	if runConfig.RunType == "" {
+		runConfig.RunType = "run"
+	}
+	return runConfig, nil
+}
+
+// declaredVariables drops the automatic variables the template does not
+// declare, since templates reject unknown variables. Variables set in the
+// config are passed through so typos are still reported.
+func declaredVariables(tmpl *templates.Template, vars map[string]string) map[string]string {
+	declared := make(map[string]bool, len(tmpl.Variables))
+	for _, variable := range tmpl.Variables {
+		declared[variable.Name] = true
+	}
+	for _, automatic := range []string{"repo", "owner", "name"} {
+		if !declared[automatic] {
+			delete(vars, automatic)
+		}
+	}
+	return vars
+}
+
+func renderText(name, text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func matchRepository(pattern, repository string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(repository))
+	return matched
+}
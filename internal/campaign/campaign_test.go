@@ -0,0 +1,116 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package campaign
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+func TestLoadConfigValidates(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "name: upgrade-ci\nrepositories: [acme/*]\nprompt: Upgrade CI\n"},
+		{name: "missing name", content: "repositories: [acme/api]\nprompt: Upgrade CI\n", wantErr: "name is required"},
+		{name: "bad name", content: "name: upgrade ci\nrepositories: [acme/api]\nprompt: Upgrade CI\n", wantErr: "may only contain"},
+		{name: "no repositories", content: "name: upgrade-ci\nprompt: Upgrade CI\n", wantErr: "at least one repository"},
+		{name: "prompt and template", content: "name: upgrade-ci\nrepositories: [acme/api]\nprompt: x\ntemplate: y\n", wantErr: "cannot be used together"},
+		{name: "concurrency", content: "name: upgrade-ci\nrepositories: [acme/api]\nprompt: x\nconcurrency: 50\n", wantErr: "concurrency must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0644))
+			config, err := LoadConfig(file)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, DefaultConcurrency, config.EffectiveConcurrency())
+		})
+	}
+}
+
+func TestResolveRepositoriesExpandsGlobs(t *testing.T) {
+	available := []models.APIRepository{
+		{RepoOwner: "acme", RepoName: "api", IsEnabled: true},
+		{RepoOwner: "acme", RepoName: "web", IsEnabled: true},
+		{RepoOwner: "acme", RepoName: "legacy", IsEnabled: false},
+		{RepoOwner: "other", RepoName: "api", IsEnabled: true},
+	}
+	config := &Config{
+		Repositories: []string{"other/tools", "ACME/*", "acme/api"},
+		Exclude:      []string{"acme/web"},
+	}
+	assert.True(t, config.HasGlobs())
+
+	repositories, err := config.ResolveRepositories(available)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other/tools", "acme/api"}, repositories)
+
+	config.Repositories = []string{"missing/*"}
+	_, err = config.ResolveRepositories(available)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "matches no enabled repository")
+}
+
+func TestRenderUsesRepositoryVariables(t *testing.T) {
+	config := &Config{
+		Prompt:   "Upgrade {{.name}} to Go {{.go}}",
+		Title:    "CI upgrade for {{.repo}}",
+		Vars:     map[string]string{"go": "1.24"},
+		RepoVars: map[string]map[string]string{"acme/legacy": {"go": "1.22"}},
+		Source:   "main",
+	}
+
+	runConfig, err := config.Render("acme/api", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Upgrade api to Go 1.24", runConfig.Prompt)
+	assert.Equal(t, "CI upgrade for acme/api", runConfig.Title)
+	assert.Equal(t, "acme/api", runConfig.Repository)
+	assert.Equal(t, "main", runConfig.Source)
+	assert.Equal(t, "run", runConfig.RunType)
+
+	runConfig, err = config.Render("Acme/Legacy", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Upgrade Legacy to Go 1.22", runConfig.Prompt)
+
+	config.Prompt = "Fix {{.missing}}"
+	_, err = config.Render("acme/api", nil)
+	require.Error(t, err)
+}
+
+func TestRenderTemplatePassesDeclaredVariables(t *testing.T) {
+	tmpl, err := templates.Parse("upgrade", "upgrade.md", []byte(`---
+variables:
+  - name: repo
+  - name: version
+    required: true
+---
+Upgrade {{.repo}} to {{.version}}`))
+	require.NoError(t, err)
+
+	config := &Config{Template: "upgrade", Vars: map[string]string{"version": "v2"}}
+	runConfig, err := config.Render("acme/api", tmpl)
+	require.NoError(t, err)
+	assert.Equal(t, "Upgrade acme/api to v2", runConfig.Prompt)
+
+	config.Vars["typo"] = "x"
+	_, err = config.Render("acme/api", tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown variable")
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/budget"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+	"github.com/repobird/repobird-cli/internal/usage"
+)
+
+var (
+	// overrideBudget submits a run even if it would breach a credit budget.
+	overrideBudget bool
+	// newBudgetClient returns the API client the budget check uses.
+	newBudgetClient = func() (budget.EvalClient, error) {
+		client, err := newRepoAPIClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	// budgetNow is the clock budget periods are computed from.
+	budgetNow = time.Now
+	// currentBudget is the budget state of the runs the process is creating.
+	currentBudget *budgetSession
+)
+
+// budgetSessionTTL is how long a refreshed history is reused. Runs created
+// before the next refresh show up in it with their own costs, so their
+// estimates stop counting as pending.
+const budgetSessionTTL = time.Minute
+
+// budgetSession is the history refreshed for the runs a command creates, so
+// a command that creates many runs refreshes it once and counts the
+// estimates of the runs it already let through.
+type budgetSession struct {
+	history     *budget.History
+	balance     *models.CreditBalance
+	refreshedAt time.Time
+	pending     float64
+}
+
+// budgetLimits returns the configured credit budgets.
+func budgetLimits() config.Budget {
+	if cfg == nil || cfg.Config == nil {
+		return config.Budget{}
+	}
+	return cfg.Budget
+}
+
+// evaluateRunBudget refreshes the run cost history and checks a run for
+// repository against the budgets.
+func evaluateRunBudget(ctx context.Context, repository string) (budget.Decision, error) {
+	client, err := newBudgetClient()
+	if err != nil {
+		return budget.Decision{}, err
+	}
+	return budget.Evaluate(ctx, client, budget.DefaultHistoryPath(), budgetLimits(), repository, budgetNow())
+}
+
+// loadBudgetSession returns the budget state of the current command,
+// refreshing the history when it is older than budgetSessionTTL.
+func loadBudgetSession(ctx context.Context) (*budgetSession, error) {
+	now := budgetNow()
+	if currentBudget != nil && now.Sub(currentBudget.refreshedAt) < budgetSessionTTL {
+		return currentBudget, nil
+	}
+	client, err := newBudgetClient()
+	if err != nil {
+		return nil, err
+	}
+	history, balance, err := budget.Load(ctx, client, budget.DefaultHistoryPath(), budgetLimits(), now)
+	if err != nil {
+		return nil, err
+	}
+	currentBudget = &budgetSession{history: history, balance: balance, refreshedAt: now}
+	return currentBudget, nil
+}
+
+// checkRunBudget enforces the credit budgets before a run is created.
+// Warnings are printed to stderr; a breach fails with the quota exit code
+// unless --override-budget is set. A run that passes adds its estimate to
+// the pending cost the next run of the command is checked with.
+func checkRunBudget(ctx context.Context, repository string) error {
+	return checkRunsBudget(ctx, repository, 1)
+}
+
+// checkRunsBudget is checkRunBudget for a batch of runs in repository,
+// which is checked as the sum of their estimates.
+func checkRunsBudget(ctx context.Context, repository string, runs int) error {
+	if runs < 1 {
+		return nil
+	}
+	if !budget.Configured(budgetLimits()) {
+		return nil
+	}
+	session, err := loadBudgetSession(ctx)
+	if err != nil {
+		// A run that is going to be queued offline is checked when the queue
+		// flush sends it
+		if overrideBudget || (queueOnNetworkError && errors.IsNetworkError(err)) {
+			printBudgetWarning(fmt.Errorf("could not check the credit budget: %s", errors.FormatUserError(err)))
+			return nil
+		}
+		return fmt.Errorf("failed to check the credit budget: %s; pass --override-budget to submit anyway", errors.FormatUserError(err))
+	}
+	others := float64(runs-1) * session.history.Estimate(repository).Credits
+	decision := budget.CheckPending(budgetLimits(), session.history, repository, session.balance, session.pending+others, budgetNow())
+	for _, warning := range decision.Warnings {
+		printBudgetWarning(fmt.Errorf("%s", warning))
+	}
+	if decision.Blocked() {
+		if !overrideBudget {
+			return wrapExitError(ExitCodeQuota, decision.Err())
+		}
+		printBudgetWarning(fmt.Errorf("submitting despite the credit budget: %v", decision.Breaches[0]))
+	}
+	session.pending += float64(runs) * decision.Estimate.Credits
+	return nil
+}
+
+// budgetGate wraps submit so queued runs are held back while they would
+// breach a credit budget; the budget may allow them on a later flush.
+func budgetGate(ctx context.Context, submit outbox.Submitter) outbox.Submitter {
+	return func(req *models.APIRunRequest) (*models.RunResponse, error) {
+		if err := checkRunBudget(ctx, req.RepositoryName); err != nil {
+			return nil, fmt.Errorf("%w: %s", outbox.ErrHeld, errors.FormatUserError(err))
+		}
+		return submit(req)
+	}
+}
+
+func printBudgetWarning(err error) {
+	fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+}
+
+// estimateRunBudget returns the cost estimate shown by --dry-run. The
+// history is refreshed from the API only when budgets are configured;
+// otherwise the estimate comes from the costs already recorded locally.
+func estimateRunBudget(ctx context.Context, repository string) (budget.Decision, error) {
+	limits := budgetLimits()
+	if budget.Configured(limits) {
+		return evaluateRunBudget(ctx, repository)
+	}
+	history, err := budget.LoadHistory(budget.DefaultHistoryPath())
+	if err != nil {
+		return budget.Decision{}, err
+	}
+	return budget.Check(limits, history, repository, nil, budgetNow()), nil
+}
+
+// printBudgetEstimate prints the estimate and budget check of a dry run.
+func printBudgetEstimate(out io.Writer, decision budget.Decision) {
+	styler := styleFor(out)
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Estimated cost:"), decision.Estimate)
+	if budget.Configured(budgetLimits()) {
+		_, _ = fmt.Fprintf(out, "%s %s credits today, %s this week\n", styler.Label("Spent:"),
+			models.FormatCredits(decision.SpentToday), models.FormatCredits(decision.SpentThisWeek))
+	}
+	for _, breach := range decision.Breaches {
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Error("Over budget:"), breach)
+	}
+	for _, warning := range decision.Warnings {
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Warning("Warning:"), warning)
+	}
+}
+
+// recordRunCosts adds the usage 'repobird cost' collected to the run cost
+// history, so later estimates need not fetch the logs again.
+func recordRunCosts(runs []usage.RunUsage) error {
+	history, err := budget.LoadHistory(budget.DefaultHistoryPath())
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		history.Record(budget.RunCost{
+			RunID:      run.RunID,
+			Repository: run.Repository,
+			CreatedAt:  run.CreatedAt,
+			Cost:       run.Summary.Cost,
+			Final:      !models.IsActiveStatus(run.Status),
+		})
+	}
+	return history.Save()
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/budget"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+)
+
+// budgetTestClient reports earlier runs that each cost the same credits.
+type budgetTestClient struct {
+	runs []*models.RunResponse
+	cost float64
+}
+
+func (c *budgetTestClient) ListRuns(_ context.Context, page, _ int) (*models.ListRunsResponse, error) {
+	if page > 1 {
+		return &models.ListRunsResponse{}, nil
+	}
+	return &models.ListRunsResponse{Data: c.runs}, nil
+}
+
+func (c *budgetTestClient) GetRunLogs(context.Context, string, int) ([]models.RunLogMessage, error) {
+	cost := c.cost
+	return []models.RunLogMessage{{Type: "result", Cost: &cost}}, nil
+}
+
+func (c *budgetTestClient) GetUserInfoWithContext(context.Context) (*models.UserInfo, error) {
+	return &models.UserInfo{CreditBalance: &models.CreditBalance{AvailableCredits: 1000}}, nil
+}
+
+func configureBudgetTest(t *testing.T, limits config.Budget) *capacityTestServer {
+	t.Helper()
+	server := configureCapacityTest(t, config.RunDefaults{})
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local)
+	client := &budgetTestClient{
+		cost: 15,
+		runs: []*models.RunResponse{
+			{ID: "41", Repository: "acme/web", Status: "DONE", CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "40", Repository: "acme/web", Status: "DONE", CreatedAt: now.Add(-3 * time.Hour)},
+		},
+	}
+	originalBudget, originalClient, originalNow, originalOverride := cfg.Budget, newBudgetClient, budgetNow, overrideBudget
+	t.Cleanup(func() {
+		cfg.Budget, newBudgetClient, budgetNow, overrideBudget = originalBudget, originalClient, originalNow, originalOverride
+		currentBudget = nil
+	})
+	currentBudget = nil
+	cfg.Budget = limits
+	newBudgetClient = func() (budget.EvalClient, error) { return client, nil }
+	budgetNow = func() time.Time { return now }
+	return server
+}
+
+func TestRunBlockedByBudgetUnlessOverridden(t *testing.T) {
+	server := configureBudgetTest(t, config.Budget{DailyCap: 40})
+
+	var err error
+	captureRunStdout(t, func() { err = processSingleRun(capacityRunConfig(), "") })
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeQuota, exitCodeForError(err))
+	assert.Contains(t, err.Error(), "daily budget of 40 credits would be exceeded (30 spent today, 15 estimated for this run)")
+	assert.Empty(t, server.created)
+
+	overrideBudget = true
+	captureRunStdout(t, func() { require.NoError(t, processSingleRun(capacityRunConfig(), "")) })
+	assert.Equal(t, []string{"acme/web"}, server.created)
+}
+
+func TestRunDryRunIncludesCostEstimate(t *testing.T) {
+	server := configureBudgetTest(t, config.Budget{WeeklyCap: 20})
+	dryRun = true
+	t.Cleanup(func() { dryRun = false })
+
+	output := captureRunStdout(t, func() { require.NoError(t, processSingleRun(capacityRunConfig(), "")) })
+	var result runDryRunJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.InDelta(t, 15.0, result.Budget.Estimate.Credits, 0.001)
+	assert.Equal(t, 2, result.Budget.Estimate.Samples)
+	require.Len(t, result.Budget.Breaches, 1)
+	assert.Contains(t, result.Budget.Breaches[0], "weekly budget of 20 credits")
+	assert.Empty(t, server.created)
+}
+
+func TestPrepareRunRequestCountsRunsAlreadyLetThrough(t *testing.T) {
+	configureBudgetTest(t, config.Budget{DailyCap: 70})
+
+	for i := 0; i < 2; i++ {
+		_, err := prepareRunRequest(capacityRunConfig())
+		require.NoError(t, err)
+	}
+	_, err := prepareRunRequest(capacityRunConfig())
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeQuota, exitCodeForError(err))
+	assert.Contains(t, err.Error(), "(30 spent today, 30 pending for other runs, 15 estimated for this run)")
+
+	overrideBudget = true
+	_, err = prepareRunRequest(capacityRunConfig())
+	require.NoError(t, err)
+}
+
+func TestBulkSubmissionBlockedByBudget(t *testing.T) {
+	configureBudgetTest(t, config.Budget{DailyCap: 60})
+	originalJSONOutput := jsonOutput
+	jsonOutput = true
+	t.Cleanup(func() { jsonOutput = originalJSONOutput })
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(dto.BulkRunResponse{Data: dto.BulkRunData{BatchID: "batch-1"}})
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-key", server.URL, false)
+
+	// Three runs at 15 credits each would bring today's 30 credits to 75.
+	bulkConfig := &bulk.BulkConfig{Repository: "acme/web", RunType: "run"}
+	for i := 0; i < 3; i++ {
+		bulkConfig.Runs = append(bulkConfig.Runs, bulk.BulkRunConfig{Prompt: fmt.Sprintf("Run %d", i+1)})
+	}
+	_, _, err := submitBulk(client, prepareBulkRequest(bulkConfig), bulkConfig, bulk.MaxBulkBatchSize, 0)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeQuota, exitCodeForError(err))
+	assert.Contains(t, err.Error(), "30 pending for other runs")
+	assert.Zero(t, requests)
+
+	overrideBudget = true
+	_, _, err = submitBulk(client, prepareBulkRequest(bulkConfig), bulkConfig, bulk.MaxBulkBatchSize, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestQueueFlushHoldsRunsOverBudget(t *testing.T) {
+	server := configureBudgetTest(t, config.Budget{DailyCap: 40})
+	queueBackground = false
+
+	req, err := validateRunRequest(capacityRunConfig(), false)
+	require.NoError(t, err)
+	require.NoError(t, queueCreateRequest(io.Discard, req, "the API is unreachable", nil))
+
+	var out bytes.Buffer
+	require.NoError(t, runQueueFlush(&out))
+	assert.Contains(t, out.String(), "still queued: the daily budget of 40 credits would be exceeded")
+	assert.Empty(t, server.created)
+	entries, err := outbox.New(outbox.DefaultDir()).List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 0, entries[0].Attempts)
+
+	overrideBudget = true
+	out.Reset()
+	require.NoError(t, runQueueFlush(&out))
+	assert.Contains(t, out.String(), "submitted as run 801")
+	assert.Equal(t, []string{"acme/web"}, server.created)
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/repobird/repobird-cli/internal/cache"
 	"github.com/repobird/repobird-cli/internal/config"
 	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
 	tuicache "github.com/repobird/repobird-cli/internal/tui/cache"
 	tuiviews "github.com/repobird/repobird-cli/internal/tui/views"
 	"github.com/repobird/repobird-cli/internal/utils"
@@ -41,6 +43,9 @@ var (
 	bulkDryRun      bool
 	bulkForce       bool
 	bulkInteractive bool
+	bulkChunkSize   int
+	bulkChunkDelay  time.Duration
+	bulkColumnMap   []string
 )
 
 // NewBulkCommand creates the bulk command
@@ -64,10 +69,18 @@ legacy workflow against a development server.`,
 	cmd.Flags().BoolVar(&bulkForce, "force", false, "Deprecated - has no effect (kept for backwards compatibility)")
 	cmd.Flags().BoolVarP(&bulkInteractive, "interactive", "i", false, "Interactive bulk mode")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+	cmd.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if prompts or context contain suspected secrets")
+	cmd.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the runs would exceed a configured credit budget")
+	cmd.Flags().IntVar(&bulkChunkSize, "chunk-size", bulk.MaxBulkBatchSize, fmt.Sprintf("runs per batch when splitting a large config (max %d)", bulk.MaxBulkBatchSize))
+	cmd.Flags().StringArrayVar(&bulkColumnMap, "map", nil, "map a CSV/TSV column to a run field as column=field, or column=- to ignore it (repeatable)")
+	cmd.Flags().DurationVar(&bulkChunkDelay, "chunk-delay", 0, "wait between batch submissions when splitting a large config (e.g. 30s)")
+	addHookFlags(cmd)
 
 	// Mark force flag as deprecated
 	_ = cmd.Flags().MarkDeprecated("force", "file hashes are now for tracking only and won't block runs")
 
+	addBulkSubcommands(cmd)
+
 	return cmd
 }
 
@@ -75,6 +88,9 @@ func runBulk(cmd *cobra.Command, args []string) error {
 	if !config.IsBulkRunsEnabled() {
 		return bulkRunsUnavailableError()
 	}
+	if err := requireHookFollow(bulkFollow, "--follow"); err != nil {
+		return err
+	}
 	return runBulkLegacy(cmd, args)
 }
 
@@ -101,12 +117,27 @@ func runBulkLegacy(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load bulk configuration
-	bulkConfig, err := bulk.LoadBulkConfig(files)
+	columnMap, err := bulk.ParseColumnMap(bulkColumnMap)
+	if err != nil {
+		return err
+	}
+	bulkConfig, err := bulk.LoadBulkConfigWithOptions(files, bulk.ParseOptions{ColumnMap: columnMap})
 	if err != nil {
 		return fmt.Errorf("%s", errors.FormatUserError(err))
 	}
 
+	if err := checkSubmissionSecrets(bulk.ScanSecrets(bulkConfig), allowSecrets, bulkDryRun); err != nil {
+		return err
+	}
+	if err := resolveBulkModel(bulkConfig); err != nil {
+		return err
+	}
+
 	// Handle dry run
+	if bulkChunkSize < 1 || bulkChunkSize > bulk.MaxBulkBatchSize {
+		return fmt.Errorf("--chunk-size must be between 1 and %d", bulk.MaxBulkBatchSize)
+	}
+
 	if bulkDryRun {
 		return printDryRunSummary(bulkConfig)
 	}
@@ -118,26 +149,27 @@ func runBulkLegacy(cmd *cobra.Command, args []string) error {
 	// Prepare bulk request
 	bulkRequest := prepareBulkRequest(bulkConfig)
 
-	// Submit with progress indicator
-	bulkResp, err := submitBulkRunsWithProgress(client, bulkRequest, bulkConfig)
+	// Submit with progress indicator, in chunks when the config is large
+	bulkResp, manifest, err := submitBulk(client, bulkRequest, bulkConfig, bulkChunkSize, bulkChunkDelay)
 	if err != nil {
 		return err
 	}
 
 	// Display results
-	displayBulkSubmissionResults(bulkResp)
+	displayBulkSubmissionResults(bulkResp, manifest)
+
+	batchIDs := bulkBatchIDs(bulkResp, manifest)
 
 	// Follow progress if requested
-	if bulkFollow && len(bulkResp.Data.Successful) > 0 {
+	if bulkFollow && len(batchIDs) > 0 {
 		fmt.Println("\nFollowing batch progress...")
 		// Create context with 1h 30m timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
 		defer cancel()
-		return followBulkProgress(ctx, client, bulkResp.Data.BatchID)
+		return followBulkProgress(ctx, client, batchIDs)
 	}
 
-	fmt.Printf("\nBatch ID: %s\n", bulkResp.Data.BatchID)
-	fmt.Println("Use 'repobird bulk status " + bulkResp.Data.BatchID + "' to check progress")
+	printBulkStatusHint(bulkResp, manifest)
 
 	return nil
 }
@@ -187,6 +219,9 @@ func printDryRunSummary(bulkConfig *bulk.BulkConfig) error {
 			title = fmt.Sprintf("Run %d", i+1)
 		}
 		fmt.Printf("  - %s\n", title)
+		if overrides := run.Summary(); len(overrides) > 0 {
+			fmt.Printf("    %s\n", styler.Muted(strings.Join(overrides, " ")))
+		}
 	}
 	return nil
 }
@@ -197,23 +232,20 @@ func prepareBulkRequest(bulkConfig *bulk.BulkConfig) *dto.BulkRunRequest {
 
 	// Convert to API request format
 	bulkRequest := &dto.BulkRunRequest{
-		RepositoryName: bulkConfig.Repository,
-		RepoID:         bulkConfig.RepoID,
-		RunType:        bulkConfig.RunType,
-		SourceBranch:   bulkConfig.Source,
-		BatchTitle:     bulkConfig.BatchTitle,
-		Force:          false, // Deprecated
-		Runs:           make([]dto.RunItem, len(bulkConfig.Runs)),
-		Options:        dto.BulkOptions{},
+		RepositoryName:   bulkConfig.Repository,
+		RepoID:           bulkConfig.RepoID,
+		RunType:          bulkConfig.RunType,
+		SourceBranch:     bulkConfig.Source,
+		BatchTitle:       bulkConfig.BatchTitle,
+		OpenCodeModel:    bulkConfig.Model,
+		OpenCodeProvider: bulkConfig.Provider,
+		Force:            false, // Deprecated
+		Runs:             make([]dto.RunItem, len(bulkConfig.Runs)),
+		Options:          dto.BulkOptions{},
 	}
 
 	for i, run := range bulkConfig.Runs {
-		item := dto.RunItem{
-			Prompt:  run.Prompt,
-			Title:   run.Title,
-			Target:  run.Target,
-			Context: run.Context,
-		}
+		item := run.RunItem()
 		if i < len(runHashes) {
 			item.FileHash = runHashes[i]
 		}
@@ -223,6 +255,36 @@ func prepareBulkRequest(bulkConfig *bulk.BulkConfig) *dto.BulkRunRequest {
 	return bulkRequest
 }
 
+// resolveBulkModel expands model profile names in the bulk config and its
+// runs and validates each selection against the allowed model list.
+func resolveBulkModel(bulkConfig *bulk.BulkConfig) error {
+	profiles, err := loadModelProfiles()
+	if err != nil {
+		return err
+	}
+	resolve := func(model, provider *string) error {
+		selection := selectRunModel(&models.RunConfig{Model: *model, Provider: *provider}, project.Defaults{}, profiles)
+		if err := checkRunModel(selection); err != nil {
+			return err
+		}
+		*model, *provider = selection.Model, selection.Provider
+		return nil
+	}
+	if err := resolve(&bulkConfig.Model, &bulkConfig.Provider); err != nil {
+		return err
+	}
+	for i := range bulkConfig.Runs {
+		run := &bulkConfig.Runs[i]
+		if run.Model == "" && run.Provider == "" {
+			continue
+		}
+		if err := resolve(&run.Model, &run.Provider); err != nil {
+			return fmt.Errorf("run %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
 func generateRunHashes(bulkConfig *bulk.BulkConfig) []string {
 	var runHashes []string
 	fileHashCache := cache.NewFileHashCache()
@@ -246,8 +308,6 @@ func generateRunHashes(bulkConfig *bulk.BulkConfig) []string {
 }
 
 func submitBulkRunsWithProgress(client *api.Client, bulkRequest *dto.BulkRunRequest, bulkConfig *bulk.BulkConfig) (*dto.BulkRunResponse, error) {
-	ctx := context.Background()
-
 	// Display submission info
 	if !jsonOutput {
 		styler := stdoutStyle()
@@ -257,6 +317,17 @@ func submitBulkRunsWithProgress(client *api.Client, bulkRequest *dto.BulkRunRequ
 		fmt.Println("\nThis may take up to 5 minutes. Please wait...")
 	}
 
+	return createBulkRuns(client, bulkRequest, bulkConfig)
+}
+
+// createBulkRuns checks one batch against the credit budgets and submits
+// it while showing a progress spinner.
+func createBulkRuns(client *api.Client, bulkRequest *dto.BulkRunRequest, bulkConfig *bulk.BulkConfig) (*dto.BulkRunResponse, error) {
+	ctx := context.Background()
+	if err := checkRunsBudget(ctx, bulkConfig.Repository, len(bulkRequest.Runs)); err != nil {
+		return nil, err
+	}
+
 	// Show progress spinner
 	done := showProgressSpinner()
 
@@ -325,9 +396,9 @@ func handleBulkSubmissionError(err error, ctx context.Context, bulkConfig *bulk.
 	return fmt.Errorf("%s", errors.FormatUserError(err))
 }
 
-func displayBulkSubmissionResults(bulkResp *dto.BulkRunResponse) {
+func displayBulkSubmissionResults(bulkResp *dto.BulkRunResponse, manifest *cache.BulkManifest) {
 	if jsonOutput {
-		_ = printBulkCreateJSON(os.Stdout, bulkResp)
+		_ = printBulkCreateJSON(os.Stdout, bulkResp, manifest)
 		return
 	}
 
@@ -399,12 +470,14 @@ func runBulkInteractive() error {
 	return err
 }
 
-func followBulkProgress(ctx context.Context, client *api.Client, batchID string) error {
+// followBulkProgress follows one or more batches, merging the batches of a
+// chunked submission into a single progress display.
+func followBulkProgress(ctx context.Context, client *api.Client, batchIDs []string) error {
 	// Poll for status updates every 20 seconds
 	if debug {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Starting to poll batch %s\n", batchID)
+		fmt.Fprintf(os.Stderr, "[DEBUG] Starting to poll batches %s\n", strings.Join(batchIDs, ", "))
 	}
-	statusChan, err := client.PollBulkStatus(ctx, batchID, 20*time.Second)
+	statusChan, err := client.PollBulkStatuses(ctx, batchIDs, 20*time.Second)
 	if err != nil {
 		return err
 	}
@@ -467,6 +540,7 @@ func followBulkProgress(ctx context.Context, client *api.Client, batchID string)
 				}
 				fmt.Println("\nBatch completed!")
 				displayBulkResults(status.Data)
+				fireBulkCompletionHooks(status.Data)
 				return nil
 			}
 
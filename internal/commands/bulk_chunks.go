@@ -0,0 +1,128 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+// submitBulk submits the bulk request as one batch, or as sequential batches
+// of chunkSize runs when the config is larger. A chunked submission is
+// tracked in a manifest, so running the same config again after an
+// interruption submits only the batches that are still missing.
+func submitBulk(client *api.Client, bulkRequest *dto.BulkRunRequest, bulkConfig *bulk.BulkConfig, chunkSize int, delay time.Duration) (*dto.BulkRunResponse, *cache.BulkManifest, error) {
+	if chunkSize < 1 || chunkSize > bulk.MaxBulkBatchSize {
+		chunkSize = bulk.MaxBulkBatchSize
+	}
+	if len(bulkRequest.Runs) <= chunkSize {
+		bulkResp, err := submitBulkRunsWithProgress(client, bulkRequest, bulkConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		recordBulkBatch(bulkRequest, bulkResp, "", "")
+		return bulkResp, nil, nil
+	}
+
+	// The manifest is keyed by the file hashes of the whole config, so every
+	// run keeps the hash it would have had in a single batch.
+	runHashes := make([]string, len(bulkRequest.Runs))
+	for i, run := range bulkRequest.Runs {
+		runHashes[i] = run.FileHash
+	}
+	dir := cache.BulkManifestDir()
+	manifest := cache.NewBulkManifest(bulkConfig.Repository, bulkConfig.BatchTitle, runHashes, chunkSize)
+	resumed, err := cache.FindIncompleteBulkManifest(dir, manifest.ConfigHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resumed != nil {
+		manifest = resumed
+	}
+	if err := cache.SaveBulkManifest(dir, manifest); err != nil {
+		return nil, nil, err
+	}
+
+	styler := stdoutStyle()
+	parts := len(manifest.Chunks)
+	if !jsonOutput {
+		fmt.Println(styler.Heading("Submitting bulk runs..."))
+		fmt.Printf("%s %s\n", styler.Label("Repository:"), bulkConfig.Repository)
+		fmt.Printf("%s %d\n", styler.Label("Total runs:"), len(bulkConfig.Runs))
+		fmt.Printf("%s %d batches of up to %d runs\n", styler.Label("Chunks:"), parts, chunkSize)
+		fmt.Printf("%s %s\n", styler.Label("Super-batch:"), manifest.ID)
+		if resumed != nil {
+			fmt.Println(styler.Info(fmt.Sprintf("ℹ  Resuming: %d of %d batches were already submitted", parts-len(manifest.Pending()), parts)))
+		}
+		fmt.Println("\nEach batch may take up to 5 minutes. Please wait...")
+	}
+
+	var responses []*dto.BulkRunResponse
+	var offsets []int
+	for n, index := range manifest.Pending() {
+		if n > 0 && delay > 0 {
+			if !jsonOutput {
+				fmt.Printf("Waiting %s before the next batch...\n", delay)
+			}
+			time.Sleep(delay)
+		}
+
+		chunk := manifest.Chunks[index]
+		if !jsonOutput {
+			fmt.Printf("\nBatch %d/%d (runs %d-%d)\n", index+1, parts, chunk.Start+1, chunk.End)
+		}
+		chunkRequest := bulk.ChunkRequest(bulkRequest, chunk.Start, chunk.End, index+1, parts)
+		bulkResp, err := createBulkRuns(client, chunkRequest, bulkConfig)
+
+		var batchID string
+		var runIDs []int
+		if bulkResp != nil {
+			batchID, runIDs = bulkResp.Data.BatchID, bulkResp.Data.RunIDs()
+		}
+		manifest.RecordChunk(index, batchID, runIDs, err)
+		if saveErr := cache.SaveBulkManifest(dir, manifest); saveErr != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), saveErr)
+		}
+		if err != nil {
+			return nil, manifest, fmt.Errorf("%w\n\nBatch %d/%d was not submitted. Run the same command again to resume super-batch %s with the remaining batches", err, index+1, parts, manifest.ID)
+		}
+		recordBulkBatch(chunkRequest, bulkResp, manifest.ID, "")
+		responses = append(responses, bulkResp)
+		offsets = append(offsets, chunk.Start)
+	}
+
+	bulkResp := bulk.MergeResponses(responses, offsets)
+	bulkResp.Data.BatchTitle = bulkConfig.BatchTitle
+	return bulkResp, manifest, nil
+}
+
+// bulkBatchIDs returns the batches to follow after a submission.
+func bulkBatchIDs(bulkResp *dto.BulkRunResponse, manifest *cache.BulkManifest) []string {
+	if manifest != nil {
+		return manifest.BatchIDs()
+	}
+	if len(bulkResp.Data.Successful) == 0 {
+		return nil
+	}
+	return []string{bulkResp.Data.BatchID}
+}
+
+func printBulkStatusHint(bulkResp *dto.BulkRunResponse, manifest *cache.BulkManifest) {
+	styler := stdoutStyle()
+	if manifest == nil {
+		fmt.Printf("\n%s %s\n", styler.Label("Batch ID:"), bulkResp.Data.BatchID)
+		fmt.Println("Use 'repobird bulk status " + bulkResp.Data.BatchID + "' to check progress")
+		return
+	}
+	fmt.Printf("\n%s %s\n", styler.Label("Super-batch:"), manifest.ID)
+	fmt.Printf("%s %s\n", styler.Label("Batch IDs:"), strings.Join(manifest.BatchIDs(), ", "))
+	fmt.Println("Use 'repobird bulk status " + manifest.ID + "' to check the progress of all batches")
+}
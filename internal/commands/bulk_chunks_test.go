@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+func TestSubmitBulkChunksAndResumes(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	originalJSONOutput := jsonOutput
+	jsonOutput = true
+	defer func() { jsonOutput = originalJSONOutput }()
+
+	var requests []dto.BulkRunRequest
+	failSecond := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dto.BulkRunRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if len(requests) == 1 && failSecond {
+			failSecond = false
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "quota exceeded"}`))
+			return
+		}
+		requests = append(requests, req)
+		resp := dto.BulkRunResponse{Data: dto.BulkRunData{BatchID: fmt.Sprintf("batch-%d", len(requests))}}
+		for i := range req.Runs {
+			resp.Data.Successful = append(resp.Data.Successful, dto.RunCreatedItem{ID: len(requests)*100 + i, RequestIndex: i})
+		}
+		resp.Data.Metadata = dto.BulkResponseMetadata{TotalRequested: len(req.Runs), TotalSuccessful: len(req.Runs)}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := api.NewClient("test-key", server.URL, false)
+
+	bulkConfig := &bulk.BulkConfig{Repository: "org/repo", BatchTitle: "Nightly", RunType: "run"}
+	for i := 0; i < 45; i++ {
+		bulkConfig.Runs = append(bulkConfig.Runs, bulk.BulkRunConfig{Prompt: fmt.Sprintf("Run %d", i+1)})
+	}
+	bulkRequest := prepareBulkRequest(bulkConfig)
+
+	_, manifest, err := submitBulk(client, bulkRequest, bulkConfig, 20, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Batch 2/3 was not submitted")
+	require.NotNil(t, manifest)
+	assert.Equal(t, []string{"batch-1"}, manifest.BatchIDs())
+
+	var resp *dto.BulkRunResponse
+	output := captureBulkStdout(t, func() {
+		resp, manifest, err = submitBulk(client, bulkRequest, bulkConfig, 20, 0)
+	})
+	require.NoError(t, err)
+	assert.Empty(t, output, "JSON mode prints nothing while submitting")
+	assert.Equal(t, []string{"batch-1", "batch-2", "batch-3"}, manifest.BatchIDs())
+	assert.True(t, manifest.Complete())
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "Nightly (part 2/3)", requests[1].BatchTitle)
+	assert.Len(t, requests[2].Runs, 5)
+	assert.Equal(t, bulkRequest.Runs[20].FileHash, requests[1].Runs[0].FileHash)
+
+	// Only the resumed batches are in the response, indexed within the whole config.
+	assert.Equal(t, 25, resp.Data.Metadata.TotalSuccessful)
+	assert.Equal(t, 20, resp.Data.Successful[0].RequestIndex)
+	assert.Equal(t, manifest.BatchIDs(), bulkBatchIDs(resp, manifest))
+
+	stored, err := cache.LoadBulkManifest(cache.BulkManifestDir(), manifest.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Complete())
+}
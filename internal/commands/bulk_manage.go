@@ -0,0 +1,340 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var bulkListLimit int
+
+// addBulkSubcommands registers the commands that manage submitted batches.
+func addBulkSubcommands(cmd *cobra.Command) {
+	status := &cobra.Command{
+		Use:   "status <batch-id>",
+		Short: "Show the progress of a submitted batch",
+		Long: `Show the progress of a submitted batch. A super-batch ID (sb_...) from a
+chunked submission shows the combined progress of all of its batches.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBulkStatus,
+	}
+	status.Flags().BoolVarP(&bulkFollow, "follow", "f", false, "Follow batch progress until it finishes")
+	status.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+	addHookFlags(status)
+
+	cancel := &cobra.Command{
+		Use:   "cancel <batch-id>",
+		Short: "Cancel the runs of a submitted batch",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runBulkCancel,
+	}
+	cancel.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List batches submitted from this machine",
+		Args:  cobra.NoArgs,
+		RunE:  runBulkList,
+	}
+	list.Flags().IntVar(&bulkListLimit, "limit", 20, "maximum number of batches to list (0 for all)")
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	retry := &cobra.Command{
+		Use:   "retry-failed <batch-id>",
+		Short: "Resubmit the failed runs of a batch",
+		Long: `Resubmit the runs of a batch that failed or were rejected when the batch was
+created. Each run keeps the settings it was first submitted with. Only batches
+in the local batch ledger, that is batches submitted from this machine, can be
+retried.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBulkRetryFailed,
+	}
+	retry.Flags().BoolVarP(&bulkFollow, "follow", "f", false, "Follow the progress of the resubmitted runs")
+	retry.Flags().BoolVar(&bulkDryRun, "dry-run", false, "List the runs that would be resubmitted")
+	retry.Flags().BoolVar(&overrideBudget, "override-budget", false, "Resubmit even if the runs would exceed a configured credit budget")
+	addHookFlags(retry)
+
+	cmd.AddCommand(status, cancel, list, retry)
+}
+
+// bulkManageClient checks the development gate and returns an API client.
+func bulkManageClient() (*api.Client, error) {
+	if !config.IsBulkRunsEnabled() {
+		return nil, bulkRunsUnavailableError()
+	}
+	cfg, err := loadAndValidateConfig()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(cfg.APIKey, utils.GetAPIURL(cfg.APIURL), debug), nil
+}
+
+// resolveBulkBatchIDs expands a super-batch ID to the batches it submitted.
+func resolveBulkBatchIDs(id string) ([]string, error) {
+	if !strings.HasPrefix(id, "sb_") {
+		return []string{id}, nil
+	}
+	manifest, err := cache.LoadBulkManifest(cache.BulkManifestDir(), id)
+	if err != nil {
+		return nil, err
+	}
+	batchIDs := manifest.BatchIDs()
+	if len(batchIDs) == 0 {
+		return nil, fmt.Errorf("super-batch %s has no submitted batches", id)
+	}
+	return batchIDs, nil
+}
+
+func fetchBulkStatus(ctx context.Context, client *api.Client, batchIDs []string) (dto.BulkStatusData, error) {
+	var statuses []dto.BulkStatusData
+	for _, batchID := range batchIDs {
+		status, err := client.GetBulkStatus(ctx, batchID)
+		if err != nil {
+			return dto.BulkStatusData{}, fmt.Errorf("failed to get status of batch %s: %w", batchID, err)
+		}
+		statuses = append(statuses, status.Data)
+	}
+	return api.MergeBulkStatuses(statuses), nil
+}
+
+func runBulkStatus(cmd *cobra.Command, args []string) error {
+	if err := requireHookFollow(bulkFollow && !jsonOutput, "--follow without --json"); err != nil {
+		return err
+	}
+	client, err := bulkManageClient()
+	if err != nil {
+		return err
+	}
+	batchIDs, err := resolveBulkBatchIDs(args[0])
+	if err != nil {
+		return err
+	}
+
+	if bulkFollow && !jsonOutput {
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
+		defer cancel()
+		return followBulkProgress(ctx, client, batchIDs)
+	}
+
+	status, err := fetchBulkStatus(context.Background(), client, batchIDs)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printBulkStatusJSON(cmd.OutOrStdout(), args[0], batchIDs, status)
+	}
+
+	styler := stdoutStyle()
+	fmt.Printf("%s %s\n", styler.Label("Batch:"), args[0])
+	if len(batchIDs) > 1 {
+		fmt.Printf("%s %s\n", styler.Label("Batches:"), strings.Join(batchIDs, ", "))
+	}
+	fmt.Printf("%s %s\n", styler.Label("Status:"), styler.Status(normalizeBulkStatus(status.Status)))
+	displayBulkResults(status)
+	return nil
+}
+
+func runBulkCancel(cmd *cobra.Command, args []string) error {
+	client, err := bulkManageClient()
+	if err != nil {
+		return err
+	}
+	batchIDs, err := resolveBulkBatchIDs(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, batchID := range batchIDs {
+		if err := client.CancelBulkRuns(ctx, batchID); err != nil {
+			return fmt.Errorf("failed to cancel batch %s: %w", batchID, err)
+		}
+		if !jsonOutput {
+			fmt.Println(stdoutStyle().Success("✓ Cancelled batch " + batchID))
+		}
+	}
+	if jsonOutput {
+		return printJSON(cmd.OutOrStdout(), bulkCancelJSONOutput{
+			Schema:    "repobird.bulk.cancel.v1",
+			Operation: "bulk.cancel",
+			Success:   true,
+			BatchIDs:  batchIDs,
+		})
+	}
+	return nil
+}
+
+func runBulkList(cmd *cobra.Command, _ []string) error {
+	if !config.IsBulkRunsEnabled() {
+		return bulkRunsUnavailableError()
+	}
+	records, err := cache.ListBulkBatches(cache.BulkLedgerDir())
+	if err != nil {
+		return err
+	}
+	if bulkListLimit > 0 && len(records) > bulkListLimit {
+		records = records[:bulkListLimit]
+	}
+	if jsonOutput {
+		if records == nil {
+			records = []*cache.BulkBatchRecord{}
+		}
+		return printJSON(cmd.OutOrStdout(), bulkListJSONOutput{
+			Schema:    "repobird.bulk.list.v1",
+			Operation: "bulk.list",
+			Batches:   records,
+		})
+	}
+	return printBulkBatchList(cmd.OutOrStdout(), records)
+}
+
+func printBulkBatchList(out io.Writer, records []*cache.BulkBatchRecord) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(out, styleFor(out).Muted("No batches have been submitted from this machine."))
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "BATCH ID\tCREATED\tRUNS\tREPOSITORY\tTITLE\tNOTE")
+	for _, record := range records {
+		note := "-"
+		switch {
+		case record.RetryOf != "":
+			note = "retry of " + record.RetryOf
+		case record.SuperBatchID != "":
+			note = "part of " + record.SuperBatchID
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			record.BatchID,
+			record.CreatedAt.Local().Format("2006-01-02 15:04"),
+			len(record.Request.Runs),
+			record.Repository,
+			valueOrDash(record.BatchTitle),
+			note,
+		)
+	}
+	return w.Flush()
+}
+
+func runBulkRetryFailed(_ *cobra.Command, args []string) error {
+	if err := requireHookFollow(bulkFollow, "--follow"); err != nil {
+		return err
+	}
+	client, err := bulkManageClient()
+	if err != nil {
+		return err
+	}
+	batchIDs, err := resolveBulkBatchIDs(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	styler := stdoutStyle()
+	var retried []string
+	for _, batchID := range batchIDs {
+		record, err := cache.LoadBulkBatch(cache.BulkLedgerDir(), batchID)
+		if err != nil {
+			return fmt.Errorf("%w; only batches submitted from this machine can be retried", err)
+		}
+		status, err := client.GetBulkStatus(ctx, batchID)
+		if err != nil {
+			return fmt.Errorf("failed to get status of batch %s: %w", batchID, err)
+		}
+
+		retryRequest := bulkRetryRequest(record, record.FailedIndexes(status.Data))
+		if retryRequest == nil {
+			fmt.Printf("No failed runs in batch %s\n", batchID)
+			continue
+		}
+		if bulkDryRun {
+			fmt.Printf("%s %d failed runs from batch %s\n", styler.Label("Would resubmit:"), len(retryRequest.Runs), batchID)
+			for _, run := range retryRequest.Runs {
+				fmt.Printf("  - %s\n", bulkRunLabel(run))
+			}
+			continue
+		}
+
+		fmt.Printf("Resubmitting %d failed runs from batch %s...\n", len(retryRequest.Runs), batchID)
+		bulkResp, err := createBulkRuns(client, retryRequest, &bulk.BulkConfig{Repository: record.Repository})
+		if err != nil {
+			return err
+		}
+		recordBulkBatch(retryRequest, bulkResp, "", batchID)
+		displayBulkSubmissionResults(bulkResp, nil)
+		if len(bulkResp.Data.Successful) > 0 {
+			retried = append(retried, bulkResp.Data.BatchID)
+		}
+	}
+
+	if bulkFollow && len(retried) > 0 {
+		fmt.Println("\nFollowing batch progress...")
+		followCtx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
+		defer cancel()
+		return followBulkProgress(followCtx, client, retried)
+	}
+	for _, batchID := range retried {
+		fmt.Printf("\n%s %s\n", styler.Label("Batch ID:"), batchID)
+	}
+	return nil
+}
+
+// bulkRetryRequest copies the batch request with only the runs at indexes.
+// It returns nil when there is nothing to retry.
+func bulkRetryRequest(record *cache.BulkBatchRecord, indexes []int) *dto.BulkRunRequest {
+	if len(indexes) == 0 {
+		return nil
+	}
+	retry := record.Request
+	retry.Runs = nil
+	for _, index := range indexes {
+		if index >= 0 && index < len(record.Request.Runs) {
+			retry.Runs = append(retry.Runs, record.Request.Runs[index])
+		}
+	}
+	if len(retry.Runs) == 0 {
+		return nil
+	}
+	title := record.BatchTitle
+	if title == "" {
+		title = record.BatchID
+	}
+	retry.BatchTitle = title + " (retry)"
+	return &retry
+}
+
+func bulkRunLabel(run dto.RunItem) string {
+	if run.Title != "" {
+		return run.Title
+	}
+	return utils.TruncateWithEllipsis(run.Prompt, 60)
+}
+
+// recordBulkBatch adds a submitted batch to the local ledger. A failure only
+// warns, since the runs were already created.
+func recordBulkBatch(req *dto.BulkRunRequest, resp *dto.BulkRunResponse, superBatchID, retryOf string) {
+	if resp.Data.BatchID == "" {
+		return
+	}
+	record := cache.NewBulkBatchRecord(req, resp)
+	record.SuperBatchID = superBatchID
+	record.RetryOf = retryOf
+	if err := cache.SaveBulkBatch(cache.BulkLedgerDir(), record); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+	}
+}
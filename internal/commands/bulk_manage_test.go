@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/config"
+)
+
+func configureBulkManageTest(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvEnvironment, "development")
+	t.Setenv(config.EnvEnableBulkRuns, "1")
+	t.Setenv(config.EnvAPIKey, "test-key")
+	t.Setenv(config.EnvAPIURL, server.URL)
+
+	originalFollow, originalDryRun, originalJSON := bulkFollow, bulkDryRun, jsonOutput
+	bulkFollow, bulkDryRun, jsonOutput = false, false, false
+	t.Cleanup(func() { bulkFollow, bulkDryRun, jsonOutput = originalFollow, originalDryRun, originalJSON })
+}
+
+func TestBulkRetryFailedResubmitsFailedRuns(t *testing.T) {
+	var retried dto.BulkRunRequest
+	configureBulkManageTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(dto.BulkStatusResponse{Data: dto.BulkStatusData{
+				BatchID: "batch-1",
+				Status:  "PARTIALLY_FAILED",
+				Runs:    []dto.RunStatusItem{{ID: 11, Status: "DONE"}, {ID: 12, Status: "FAILED"}},
+			}})
+		case http.MethodPost:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&retried))
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(dto.BulkRunResponse{Data: dto.BulkRunData{
+				BatchID:    "batch-2",
+				Successful: []dto.RunCreatedItem{{ID: 21, Title: "Split auth"}, {ID: 22, Title: "Add docs"}},
+			}})
+		}
+	})
+
+	record := cache.NewBulkBatchRecord(&dto.BulkRunRequest{
+		RepositoryName: "org/repo",
+		RunType:        "run",
+		BatchTitle:     "Nightly",
+		Runs: []dto.RunItem{
+			{Prompt: "Bump deps"},
+			{Prompt: "Split auth", BaseBranch: "develop", OpenCodeModel: "openrouter/strong/model"},
+			{Prompt: "Add docs"},
+		},
+	}, &dto.BulkRunResponse{Data: dto.BulkRunData{
+		BatchID:    "batch-1",
+		Successful: []dto.RunCreatedItem{{ID: 11, RequestIndex: 0}, {ID: 12, RequestIndex: 1}},
+		Failed:     []dto.RunError{{RequestIndex: 2, Message: "rate limited"}},
+	}})
+	require.NoError(t, cache.SaveBulkBatch(cache.BulkLedgerDir(), record))
+
+	output := captureBulkStdout(t, func() {
+		require.NoError(t, runBulkRetryFailed(nil, []string{"batch-1"}))
+	})
+	assert.Contains(t, output, "Resubmitting 2 failed runs from batch batch-1")
+
+	assert.Equal(t, "Nightly (retry)", retried.BatchTitle)
+	assert.Equal(t, "run", retried.RunType)
+	require.Len(t, retried.Runs, 2)
+	assert.Equal(t, "develop", retried.Runs[0].BaseBranch, "runs keep their settings")
+	assert.Equal(t, "openrouter/strong/model", retried.Runs[0].OpenCodeModel)
+	assert.Equal(t, "Add docs", retried.Runs[1].Prompt)
+
+	retry, err := cache.LoadBulkBatch(cache.BulkLedgerDir(), "batch-2")
+	require.NoError(t, err)
+	assert.Equal(t, "batch-1", retry.RetryOf)
+
+	var buf bytes.Buffer
+	records, err := cache.ListBulkBatches(cache.BulkLedgerDir())
+	require.NoError(t, err)
+	require.NoError(t, printBulkBatchList(&buf, records))
+	assert.Contains(t, buf.String(), "retry of batch-1")
+}
+
+func TestBulkStatusMergesSuperBatch(t *testing.T) {
+	configureBulkManageTest(t, func(w http.ResponseWriter, r *http.Request) {
+		batchID := r.URL.Path[len("/api/v1/runs/bulk/"):]
+		_ = json.NewEncoder(w).Encode(dto.BulkStatusResponse{Data: dto.BulkStatusData{
+			BatchID:  batchID,
+			Status:   "COMPLETED",
+			Runs:     []dto.RunStatusItem{{ID: len(batchID), Title: "Run in " + batchID, Status: "DONE"}},
+			Metadata: dto.BulkStatusMetadata{TotalRuns: 1, Completed: 1},
+		}})
+	})
+	manifest := cache.NewBulkManifest("org/repo", "Nightly", []string{"a", "b"}, 1)
+	manifest.RecordChunk(0, "batch-1", nil, nil)
+	manifest.RecordChunk(1, "batch-22", nil, nil)
+	require.NoError(t, cache.SaveBulkManifest(cache.BulkManifestDir(), manifest))
+
+	output := captureBulkStdout(t, func() {
+		require.NoError(t, runBulkStatus(NewBulkCommand(), []string{manifest.ID}))
+	})
+	assert.Contains(t, output, "batch-1, batch-22")
+	assert.Contains(t, output, "Run in batch-22")
+	assert.Contains(t, output, "Total: 2")
+}
@@ -8,10 +8,12 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/repobird/repobird-cli/internal/api/dto"
 	"github.com/repobird/repobird-cli/internal/bulk"
@@ -80,6 +82,28 @@ func TestPrintBulkDryRunSummaryJSONIsMachineReadable(t *testing.T) {
 	assert.Equal(t, "Run 2", second["title"])
 }
 
+func TestBulkDryRunJSONMatchesForCSVAndJSONL(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "tasks.csv")
+	jsonlPath := filepath.Join(dir, "tasks.jsonl")
+	require.NoError(t, os.WriteFile(csvPath, []byte("repository,title,prompt,target\n"+
+		"acme/webapp,Fix auth,\"Fix auth bug\nin the login flow\",fix/auth\n"+
+		"acme/webapp,,Add tests,test/auth\n"), 0600))
+	require.NoError(t, os.WriteFile(jsonlPath, []byte(
+		`{"repository":"acme/webapp","title":"Fix auth","prompt":"Fix auth bug\nin the login flow","target":"fix/auth"}`+"\n"+
+			`{"repository":"acme/webapp","prompt":"Add tests","target":"test/auth"}`+"\n"), 0600))
+
+	var outputs []string
+	for _, path := range []string{csvPath, jsonlPath} {
+		bulkConfig, err := bulk.ParseBulkConfig(path)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, printBulkDryRunJSON(&out, bulkConfig))
+		outputs = append(outputs, out.String())
+	}
+	assert.JSONEq(t, outputs[1], outputs[0])
+}
+
 func TestDisplayBulkSubmissionResultsJSONIsMachineReadable(t *testing.T) {
 	originalJSONOutput := jsonOutput
 	jsonOutput = true
@@ -104,7 +128,7 @@ func TestDisplayBulkSubmissionResultsJSONIsMachineReadable(t *testing.T) {
 					TotalFailed:     1,
 				},
 			},
-		})
+		}, nil)
 	})
 
 	assert.NotContains(t, output, "Partial success")
@@ -0,0 +1,392 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/campaign"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var (
+	campaignConcurrency int
+	campaignMarkdown    bool
+	campaignNoRefresh   bool
+)
+
+var campaignCmd = newCampaignCommand()
+
+func newCampaignCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "campaign",
+		Short: "Run one task across many repositories",
+		Long: `Run one task across many repositories and track the results.
+
+A campaign config lists repositories by name or glob and a prompt or template
+rendered for each repository. Progress is kept in a local campaign manifest,
+so starting the same campaign again only submits the repositories that do not
+have a run yet.`,
+	}
+
+	start := &cobra.Command{
+		Use:   "start <config-file>",
+		Short: "Create the runs of a campaign",
+		Example: `  repobird campaign start upgrade-ci.yaml
+  repobird campaign start upgrade-ci.yaml --concurrency 8
+  repobird campaign start upgrade-ci.yaml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCampaignStart,
+	}
+	start.Flags().IntVar(&campaignConcurrency, "concurrency", 0, fmt.Sprintf("maximum runs created at once (default from config, or %d)", campaign.DefaultConcurrency))
+	start.Flags().BoolVar(&dryRun, "dry-run", false, "list the repositories and prompts without creating runs")
+	start.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if the prompt or context contain suspected secrets")
+	start.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if a run configuration has lint errors")
+	start.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the runs would exceed a configured credit budget")
+
+	report := &cobra.Command{
+		Use:   "report <name>",
+		Short: "Show the per-repository status of a campaign",
+		Long: `Show the status, pull request URL and failure of every repository in a
+campaign. Statuses of unfinished runs are refreshed first unless --no-refresh
+is set.`,
+		Example: `  repobird campaign report upgrade-ci
+  repobird campaign report upgrade-ci --markdown > report.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCampaignReport,
+	}
+	report.Flags().BoolVar(&campaignMarkdown, "markdown", false, "output the report as a Markdown table")
+	report.Flags().BoolVar(&campaignNoRefresh, "no-refresh", false, "show the saved statuses without contacting the API")
+	report.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List campaigns started from this machine",
+		Args:  cobra.NoArgs,
+		RunE:  runCampaignList,
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	cmd.AddCommand(start, report, list)
+	return cmd
+}
+
+func runCampaignStart(_ *cobra.Command, args []string) error {
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+	campaignConfig, err := campaign.LoadConfig(args[0])
+	if err != nil {
+		return err
+	}
+	concurrency := campaignConfig.EffectiveConcurrency()
+	if campaignConcurrency != 0 {
+		if campaignConcurrency < 1 || campaignConcurrency > campaign.MaxConcurrency {
+			return fmt.Errorf("--concurrency must be between 1 and %d", campaign.MaxConcurrency)
+		}
+		concurrency = campaignConcurrency
+	}
+
+	var available []models.APIRepository
+	if campaignConfig.HasGlobs() {
+		client, err := newRepoAPIClient()
+		if err != nil {
+			return err
+		}
+		if available, err = client.ListRepositories(context.Background()); err != nil {
+			return fmt.Errorf("failed to list repositories: %s", errors.FormatUserError(err))
+		}
+	}
+	repositories, err := campaignConfig.ResolveRepositories(available)
+	if err != nil {
+		return err
+	}
+
+	var tmpl *templates.Template
+	if campaignConfig.Template != "" {
+		library, err := templates.LoadDefault()
+		if err != nil {
+			return err
+		}
+		if tmpl, err = library.Get(campaignConfig.Template); err != nil {
+			return err
+		}
+	}
+
+	dir := cache.CampaignDir()
+	// Only a campaign that was never started begins with a fresh manifest; an
+	// unreadable one would resubmit every repository.
+	manifest, err := cache.LoadCampaignManifest(dir, campaignConfig.Name)
+	if stderrors.Is(err, cache.ErrCampaignNotFound) {
+		manifest = cache.NewCampaignManifest(campaignConfig.Name, args[0])
+	} else if err != nil {
+		return err
+	}
+	manifest.AddRepositories(repositories)
+
+	// Every pending run is prepared before any is created, so a config error
+	// in one repository does not leave the campaign half submitted.
+	pending := manifest.Pending()
+	requests := make(map[int]domain.CreateRunRequest, len(pending))
+	for _, index := range pending {
+		runConfig, err := campaignConfig.Render(manifest.Repos[index].Repository, tmpl)
+		if err != nil {
+			return err
+		}
+		req, err := prepareRunRequest(runConfig)
+		if err != nil {
+			return fmt.Errorf("%s: %w", runConfig.Repository, err)
+		}
+		requests[index] = req
+	}
+
+	styler := stdoutStyle()
+	if dryRun {
+		fmt.Println(styler.Success(fmt.Sprintf("Validation successful. Campaign %s would create %d runs:", campaignConfig.Name, len(pending))))
+		for _, index := range pending {
+			req := requests[index]
+			fmt.Printf("  - %s: %s\n", req.RepositoryName, utils.TruncateWithEllipsis(strings.Join(strings.Fields(req.Prompt), " "), 70))
+		}
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", styler.Label("Campaign:"), campaignConfig.Name)
+	fmt.Printf("%s %d (%d already submitted)\n", styler.Label("Repositories:"), len(manifest.Repos), len(manifest.Repos)-len(pending))
+	if len(pending) == 0 {
+		fmt.Println(styler.Info("All repositories already have a run."))
+		fmt.Println("Use 'repobird campaign report " + campaignConfig.Name + "' to check progress")
+		return nil
+	}
+	if err := cache.SaveCampaignManifest(dir, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("Creating %d runs, %d at a time...\n\n", len(pending), concurrency)
+
+	failed := submitCampaignRuns(getContainer().RunService(), manifest, pending, requests, concurrency, func() {
+		if err := cache.SaveCampaignManifest(dir, manifest); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+	})
+
+	fmt.Println()
+	fmt.Println("Use 'repobird campaign report " + campaignConfig.Name + "' to track the runs")
+	if failed > 0 {
+		return fmt.Errorf("%d of %d runs could not be created; run the same command again to retry them", failed, len(pending))
+	}
+	return nil
+}
+
+// submitCampaignRuns creates the pending runs with at most concurrency
+// requests in flight, recording each outcome in the manifest and calling save
+// after it. It returns the number of runs that could not be created.
+func submitCampaignRuns(runService domain.RunService, manifest *cache.CampaignManifest, pending []int, requests map[int]domain.CreateRunRequest, concurrency int, save func()) int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	failed := 0
+	styler := stdoutStyle()
+
+	for _, index := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			req := requests[index]
+			run, err := runService.CreateRun(context.Background(), req)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				err = fmt.Errorf("%s", errors.FormatUserError(err))
+				manifest.RecordSubmission(index, "", "", err)
+				fmt.Printf("%s %s: %v\n", styler.Error("✗"), req.RepositoryName, err)
+			} else {
+				manifest.RecordSubmission(index, run.ID, run.Status, nil)
+				fmt.Printf("%s %s: run %s\n", styler.Success("✓"), req.RepositoryName, run.ID)
+			}
+			save()
+		}(index)
+	}
+	wg.Wait()
+	return failed
+}
+
+func runCampaignReport(cmd *cobra.Command, args []string) error {
+	dir := cache.CampaignDir()
+	manifest, err := cache.LoadCampaignManifest(dir, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !campaignNoRefresh {
+		if cfg.APIKey == "" {
+			return errors.NoAPIKeyError()
+		}
+		refreshCampaignRuns(getContainer().RunService(), manifest)
+		if err := cache.SaveCampaignManifest(dir, manifest); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	switch {
+	case jsonOutput:
+		submitted, failed := manifest.Counts()
+		return printJSON(out, campaignReportJSONOutput{
+			Schema:    "repobird.campaign.report.v1",
+			Operation: "campaign.report",
+			Name:      manifest.Name,
+			Total:     len(manifest.Repos),
+			Submitted: submitted,
+			Failed:    failed,
+			Repos:     manifest.Repos,
+		})
+	case campaignMarkdown:
+		return printCampaignMarkdown(out, manifest)
+	default:
+		return printCampaignTable(out, manifest)
+	}
+}
+
+// refreshCampaignRuns updates the repositories whose runs have not finished.
+// A run that cannot be fetched keeps its saved status.
+func refreshCampaignRuns(runService domain.RunService, manifest *cache.CampaignManifest) {
+	for i := range manifest.Repos {
+		repo := &manifest.Repos[i]
+		if repo.RunID == "" || campaignStatusFinal(repo.Status) {
+			continue
+		}
+		run, err := runService.GetRun(context.Background(), repo.RunID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s could not refresh run %s of %s: %s\n", stderrStyle().Warning("Warning:"), repo.RunID, repo.Repository, errors.FormatUserError(err))
+			continue
+		}
+		repo.Status = run.Status
+		repo.PullRequestURL = run.PullRequestURL
+		repo.Error = run.Error
+	}
+}
+
+func campaignStatusFinal(status string) bool {
+	run := domain.Run{Status: status}
+	return run.IsTerminal()
+}
+
+// campaignRepoStatus is the status shown for a repository: its run status,
+// or "not submitted" when creating the run failed or was not attempted.
+func campaignRepoStatus(repo cache.CampaignRepo) string {
+	if repo.RunID == "" {
+		return "not submitted"
+	}
+	if repo.Status == "" {
+		return "unknown"
+	}
+	return repo.Status
+}
+
+func printCampaignTable(out io.Writer, manifest *cache.CampaignManifest) error {
+	styler := styleFor(out)
+	submitted, failed := manifest.Counts()
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Campaign:"), manifest.Name)
+	_, _ = fmt.Fprintf(out, "%s %d repositories, %d submitted, %d failed\n\n", styler.Label("Progress:"), len(manifest.Repos), submitted, failed)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REPOSITORY\tRUN ID\tSTATUS\tPR URL\tERROR")
+	for _, repo := range manifest.Repos {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			repo.Repository,
+			valueOrDash(repo.RunID),
+			campaignRepoStatus(repo),
+			valueOrDash(repo.PullRequestURL),
+			valueOrDash(utils.TruncateWithEllipsis(firstLine(repo.Error), 60)),
+		)
+	}
+	return w.Flush()
+}
+
+func printCampaignMarkdown(out io.Writer, manifest *cache.CampaignManifest) error {
+	submitted, failed := manifest.Counts()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Campaign: %s\n\n", manifest.Name)
+	fmt.Fprintf(&b, "%d repositories, %d submitted, %d failed\n\n", len(manifest.Repos), submitted, failed)
+	b.WriteString("| Repository | Run | Status | Pull request | Error |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, repo := range manifest.Repos {
+		pr := "-"
+		if repo.PullRequestURL != "" {
+			pr = fmt.Sprintf("[PR](%s)", repo.PullRequestURL)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			markdownCell(repo.Repository),
+			markdownCell(valueOrDash(repo.RunID)),
+			markdownCell(campaignRepoStatus(repo)),
+			pr,
+			markdownCell(valueOrDash(firstLine(repo.Error))),
+		)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// markdownCell escapes pipes so a value cannot break the table row.
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(value), "\n")
+	return line
+}
+
+func runCampaignList(cmd *cobra.Command, _ []string) error {
+	manifests, err := cache.ListCampaignManifests(cache.CampaignDir())
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		if manifests == nil {
+			manifests = []*cache.CampaignManifest{}
+		}
+		return printJSON(out, campaignListJSONOutput{
+			Schema:    "repobird.campaign.list.v1",
+			Operation: "campaign.list",
+			Campaigns: manifests,
+		})
+	}
+	if len(manifests) == 0 {
+		_, err := fmt.Fprintln(out, styleFor(out).Muted("No campaigns have been started from this machine."))
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tCREATED\tREPOSITORIES\tSUBMITTED\tFAILED")
+	for _, manifest := range manifests {
+		submitted, failed := manifest.Counts()
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n",
+			manifest.Name,
+			manifest.CreatedAt.Local().Format("2006-01-02 15:04"),
+			len(manifest.Repos),
+			submitted,
+			failed,
+		)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+func TestCampaignStartResumesAndReports(t *testing.T) {
+	var mu sync.Mutex
+	created := map[string]int{}
+	rejectWeb := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repositories":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"id": 1, "repoOwner": "acme", "repoName": "api", "isEnabled": true},
+				{"id": 2, "repoOwner": "acme", "repoName": "web", "isEnabled": true},
+			}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/runs":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			repo := body["repositoryName"].(string)
+			assert.Equal(t, "Upgrade CI in "+strings.TrimPrefix(repo, "acme/"), body["prompt"])
+			if repo == "acme/web" && rejectWeb {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": "NOT_FOUND", "message": "Repository not found"})
+				return
+			}
+			created[repo]++
+			id := 100 + len(created)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"id": id, "status": "QUEUED", "repositoryName": repo,
+			}})
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/runs/"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"id":     strings.TrimPrefix(r.URL.Path, "/api/v1/runs/"),
+				"status": "DONE",
+				"prUrl":  "https://github.com/acme/pull/" + strings.TrimPrefix(r.URL.Path, "/api/v1/runs/"),
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	restore := configureRunWaitTest(t, server.URL)
+	defer restore()
+	jsonOutput = false
+	originalMarkdown, originalNoRefresh, originalConcurrency := campaignMarkdown, campaignNoRefresh, campaignConcurrency
+	t.Cleanup(func() {
+		campaignMarkdown, campaignNoRefresh, campaignConcurrency = originalMarkdown, originalNoRefresh, originalConcurrency
+	})
+
+	file := filepath.Join(t.TempDir(), "upgrade-ci.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`name: upgrade-ci
+repositories: ["acme/*"]
+prompt: "Upgrade CI in {{.name}}"
+concurrency: 2
+`), 0644))
+
+	captureRunStdout(t, func() {
+		err := runCampaignStart(campaignCmd, []string{file})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 2 runs could not be created")
+	})
+
+	manifest, err := cache.LoadCampaignManifest(cache.CampaignDir(), "upgrade-ci")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, manifest.Pending())
+	assert.Contains(t, manifest.Repos[1].Error, "Repository not found")
+
+	// Starting again only submits the repository that failed.
+	rejectWeb = false
+	captureRunStdout(t, func() {
+		require.NoError(t, runCampaignStart(campaignCmd, []string{file}))
+	})
+	assert.Equal(t, map[string]int{"acme/api": 1, "acme/web": 1}, created)
+
+	var out bytes.Buffer
+	campaignMarkdown = true
+	reportCmd, _, err := campaignCmd.Find([]string{"report"})
+	require.NoError(t, err)
+	reportCmd.SetOut(&out)
+	require.NoError(t, runCampaignReport(reportCmd, []string{"upgrade-ci"}))
+	assert.Contains(t, out.String(), "# Campaign: upgrade-ci")
+	assert.Contains(t, out.String(), "2 repositories, 2 submitted, 0 failed")
+	assert.Contains(t, out.String(), "| acme/api | 101 | completed | [PR](https://github.com/acme/pull/101) | - |")
+
+	// A corrupt manifest fails the start instead of resubmitting every
+	// repository.
+	require.NoError(t, os.WriteFile(filepath.Join(cache.CampaignDir(), "upgrade-ci.json"), []byte("{"), 0644))
+	captureRunStdout(t, func() {
+		err := runCampaignStart(campaignCmd, []string{file})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read campaign upgrade-ci")
+	})
+	assert.Equal(t, map[string]int{"acme/api": 1, "acme/web": 1}, created)
+}
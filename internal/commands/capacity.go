@@ -0,0 +1,238 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+)
+
+// Values of --when-full.
+const (
+	whenFullFail  = "fail"
+	whenFullWait  = "wait"
+	whenFullQueue = "queue"
+)
+
+const (
+	// activeRunsPageSize is how many runs each page lists when the active
+	// ones are counted.
+	activeRunsPageSize = 100
+	// activeRunMaxAge bounds how far back active runs are looked for. A run
+	// still queued or running after this long is taken to be stuck.
+	activeRunMaxAge = 24 * time.Hour
+)
+
+var (
+	// whenFull is what 'run' does when max_active_runs is reached.
+	whenFull = whenFullFail
+	// capacityPollInterval is how often --when-full=wait checks for a free
+	// slot.
+	capacityPollInterval = 30 * time.Second
+	// listActiveRuns returns the runs created within activeRunMaxAge, from
+	// which the active ones are counted. The run list has no status filter,
+	// so it is paged until the runs are older than that; older runs are left
+	// out.
+	listActiveRuns = func(ctx context.Context) ([]*models.RunResponse, error) {
+		client, err := newRepoAPIClient()
+		if err != nil {
+			return nil, err
+		}
+		since := time.Now().Add(-activeRunMaxAge)
+		var runs []*models.RunResponse
+		for page := 1; ; page++ {
+			resp, err := client.ListRuns(ctx, page, activeRunsPageSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, run := range resp.Data {
+				if run != nil && !run.CreatedAt.Before(since) {
+					runs = append(runs, run)
+				}
+			}
+			if len(resp.Data) < activeRunsPageSize {
+				return runs, nil
+			}
+			if last := resp.Data[len(resp.Data)-1]; last != nil && last.CreatedAt.Before(since) {
+				return runs, nil
+			}
+			if resp.Metadata != nil && page >= resp.Metadata.TotalPages {
+				return runs, nil
+			}
+		}
+	}
+)
+
+// runCapacity is the number of active runs against the configured caps.
+// A zero limit means no cap.
+type runCapacity struct {
+	Repository      string
+	Active          int
+	Limit           int
+	RepoActive      int
+	RepositoryLimit int
+}
+
+// full reports whether another run for the repository would exceed a cap.
+func (c runCapacity) full() bool {
+	return (c.Limit > 0 && c.Active >= c.Limit) ||
+		(c.RepositoryLimit > 0 && c.RepoActive >= c.RepositoryLimit)
+}
+
+// add counts a run submitted to repository.
+func (c *runCapacity) add(repository string) {
+	c.Active++
+	if strings.EqualFold(repository, c.Repository) {
+		c.RepoActive++
+	}
+}
+
+func (c runCapacity) String() string {
+	if c.RepositoryLimit > 0 && c.RepoActive >= c.RepositoryLimit {
+		return fmt.Sprintf("%d of %d active runs in %s", c.RepoActive, c.RepositoryLimit, c.Repository)
+	}
+	return fmt.Sprintf("%d of %d active runs", c.Active, c.Limit)
+}
+
+// activeRunLimits returns the configured global and per-repository caps.
+func activeRunLimits(repository string) (limit, repositoryLimit int) {
+	if cfg == nil || cfg.Config == nil {
+		return 0, 0
+	}
+	for _, entry := range cfg.Defaults.Repositories {
+		if strings.EqualFold(entry.Repository, repository) {
+			repositoryLimit = entry.MaxActiveRuns
+			break
+		}
+	}
+	return cfg.Defaults.MaxActiveRuns, repositoryLimit
+}
+
+// capacityLimited reports whether any cap applies to repository.
+func capacityLimited(repository string) bool {
+	limit, repositoryLimit := activeRunLimits(repository)
+	return limit > 0 || repositoryLimit > 0
+}
+
+// countActiveRuns counts the queued and running runs against the caps for
+// repository.
+func countActiveRuns(ctx context.Context, repository string) (runCapacity, error) {
+	runs, err := listActiveRuns(ctx)
+	if err != nil {
+		return runCapacity{}, fmt.Errorf("failed to count active runs: %w", err)
+	}
+	return tallyActiveRuns(runs, repository), nil
+}
+
+// tallyActiveRuns counts the active runs in runs against the caps for
+// repository. Runs older than activeRunMaxAge are stuck and not counted.
+func tallyActiveRuns(runs []*models.RunResponse, repository string) runCapacity {
+	limit, repositoryLimit := activeRunLimits(repository)
+	capacity := runCapacity{Repository: repository, Limit: limit, RepositoryLimit: repositoryLimit}
+	since := time.Now().Add(-activeRunMaxAge)
+	for _, run := range runs {
+		if models.IsActiveStatus(string(run.Status)) && !run.CreatedAt.Before(since) {
+			capacity.add(run.GetRepositoryName())
+		}
+	}
+	return capacity
+}
+
+// applyRunCapacity enforces max_active_runs before a run is created, as
+// chosen by --when-full. It reports whether the run was queued locally
+// instead of created.
+func applyRunCapacity(ctx context.Context, req domain.CreateRunRequest) (bool, error) {
+	capacity, err := countActiveRuns(ctx, req.RepositoryName)
+	if err != nil {
+		if queueOnNetworkError && errors.IsNetworkError(err) {
+			return true, queueCreateRequest(os.Stdout, req, "the API is unreachable", err)
+		}
+		return false, err
+	}
+	if !capacity.full() {
+		return false, nil
+	}
+	switch whenFull {
+	case whenFullWait:
+		out := io.Writer(os.Stdout)
+		if jsonOutput {
+			out = os.Stderr
+		}
+		return false, waitForRunSlot(ctx, out, capacity)
+	case whenFullQueue:
+		return true, queueCreateRequest(os.Stdout, req, fmt.Sprintf("max_active_runs is reached (%s)", capacity), nil)
+	default:
+		return false, fmt.Errorf("max_active_runs is reached (%s); pass --when-full=wait or --when-full=queue to submit the run later", capacity)
+	}
+}
+
+// capacityGate wraps submit so queued runs are held back while
+// max_active_runs is reached. The active runs are listed once; runs the
+// gate submits are added to the count.
+func capacityGate(ctx context.Context, submit outbox.Submitter) outbox.Submitter {
+	var active []*models.RunResponse
+	var submitted []string
+	listed := false
+	return func(req *models.APIRunRequest) (*models.RunResponse, error) {
+		if !capacityLimited(req.RepositoryName) {
+			return submit(req)
+		}
+		if !listed {
+			runs, err := listActiveRuns(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count active runs: %w", err)
+			}
+			active, listed = runs, true
+		}
+		capacity := tallyActiveRuns(active, req.RepositoryName)
+		for _, repository := range submitted {
+			capacity.add(repository)
+		}
+		if capacity.full() {
+			return nil, fmt.Errorf("%w: max_active_runs is reached (%s)", outbox.ErrHeld, capacity)
+		}
+		run, err := submit(req)
+		if err == nil {
+			submitted = append(submitted, req.RepositoryName)
+		}
+		return run, err
+	}
+}
+
+// validateWhenFull checks the --when-full value.
+func validateWhenFull(value string) error {
+	switch value {
+	case whenFullFail, whenFullWait, whenFullQueue:
+		return nil
+	default:
+		return fmt.Errorf("invalid --when-full %q: use wait, fail or queue", value)
+	}
+}
+
+// waitForRunSlot announces that capacity is full and polls the active runs
+// until the caps leave room for a run in its repository.
+func waitForRunSlot(ctx context.Context, out io.Writer, capacity runCapacity) error {
+	_, _ = fmt.Fprintf(out, "%s\n", styleFor(out).Info(fmt.Sprintf("Waiting for a free run slot (%s)...", capacity)))
+	for capacity.full() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(capacityPollInterval):
+		}
+		var err error
+		if capacity, err = countActiveRuns(ctx, capacity.Repository); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+)
+
+// capacityTestServer lists the active runs it is given and creates runs.
+type capacityTestServer struct {
+	mu      sync.Mutex
+	active  []map[string]any
+	lists   int
+	created []string
+	// onList, when set, changes the active runs after each list.
+	onList func(s *capacityTestServer)
+}
+
+func (s *capacityTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/runs":
+		s.lists++
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": s.active})
+		if s.onList != nil {
+			s.onList(s)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/runs":
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		repository, _ := body["repositoryName"].(string)
+		s.created = append(s.created, repository)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": 800 + len(s.created), "status": "QUEUED"}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func activeRun(id int, repository string) map[string]any {
+	return map[string]any{"id": id, "status": "PROCESSING", "repositoryName": repository, "createdAt": time.Now().UTC().Format(time.RFC3339)}
+}
+
+func configureCapacityTest(t *testing.T, defaults config.RunDefaults) *capacityTestServer {
+	t.Helper()
+	server := &capacityTestServer{}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(configureRunWaitTest(t, httpServer.URL))
+	wait = false
+	originalDefaults, originalWhenFull, originalInterval := cfg.Defaults, whenFull, capacityPollInterval
+	originalQueued, originalBackground := queuedThisCommand, queueBackground
+	t.Cleanup(func() {
+		cfg.Defaults, whenFull, capacityPollInterval = originalDefaults, originalWhenFull, originalInterval
+		queuedThisCommand, queueBackground = originalQueued, originalBackground
+	})
+	cfg.Defaults = defaults
+	capacityPollInterval = time.Millisecond
+	return server
+}
+
+func capacityRunConfig() *models.RunConfig {
+	return &models.RunConfig{Prompt: "Add pagination to the audit log", Repository: "acme/web", RunType: "run"}
+}
+
+func TestRunWhenFullFailsOrQueues(t *testing.T) {
+	server := configureCapacityTest(t, config.RunDefaults{MaxActiveRuns: 2})
+	server.active = []map[string]any{activeRun(1, "acme/web"), activeRun(2, "acme/api")}
+
+	var err error
+	captureRunStdout(t, func() { err = processSingleRun(capacityRunConfig(), "") })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_active_runs is reached (2 of 2 active runs)")
+	assert.Contains(t, err.Error(), "--when-full=wait")
+	assert.Empty(t, server.created)
+
+	whenFull = whenFullQueue
+	output := captureRunStdout(t, func() { require.NoError(t, processSingleRun(capacityRunConfig(), "")) })
+	var queued runQueueJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &queued))
+	assert.Equal(t, "max_active_runs is reached (2 of 2 active runs)", queued.Reason)
+	assert.Empty(t, server.created)
+
+	// A flush holds the run back while the cap is still reached.
+	var out bytes.Buffer
+	require.NoError(t, runQueueFlush(&out))
+	assert.Contains(t, out.String(), "still queued: max_active_runs is reached")
+	entries, err := outbox.New(outbox.DefaultDir()).List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 0, entries[0].Attempts)
+
+	server.active = server.active[:1]
+	out.Reset()
+	require.NoError(t, runQueueFlush(&out))
+	assert.Contains(t, out.String(), "submitted as run 801")
+	assert.Equal(t, []string{"acme/web"}, server.created)
+}
+
+func TestRunWhenFullWaitsForSlot(t *testing.T) {
+	server := configureCapacityTest(t, config.RunDefaults{
+		Repositories: []config.RepositoryDefaults{{Repository: "acme/web", MaxActiveRuns: 1}},
+	})
+	server.active = []map[string]any{activeRun(1, "acme/web"), activeRun(2, "acme/api"), activeRun(3, "acme/api")}
+	server.onList = func(s *capacityTestServer) {
+		if s.lists == 3 {
+			s.active = s.active[1:]
+		}
+	}
+	whenFull = whenFullWait
+	jsonOutput = false
+
+	require.NoError(t, validateWhenFull(whenFull))
+	output := captureRunStdout(t, func() { require.NoError(t, processSingleRun(capacityRunConfig(), "")) })
+	assert.Contains(t, output, "Waiting for a free run slot (1 of 1 active runs in acme/web)")
+	assert.Contains(t, output, "Run created successfully")
+	assert.Equal(t, 4, server.lists)
+	assert.Equal(t, []string{"acme/web"}, server.created)
+}
+
+func TestTallyActiveRuns(t *testing.T) {
+	ensureRunTestConfig()
+	originalDefaults := cfg.Defaults
+	defer func() { cfg.Defaults = originalDefaults }()
+	cfg.Defaults = config.RunDefaults{
+		MaxActiveRuns: 3,
+		Repositories:  []config.RepositoryDefaults{{Repository: "Acme/Web", MaxActiveRuns: 1}},
+	}
+
+	now := time.Now()
+	runs := []*models.RunResponse{
+		{ID: "1", Status: models.StatusProcessing, RepositoryName: "acme/api", CreatedAt: now},
+		{ID: "2", Status: models.StatusDone, RepositoryName: "acme/web", CreatedAt: now},
+		{ID: "3", Status: models.StatusQueued, RepositoryName: "acme/api", CreatedAt: now},
+		{ID: "4", Status: models.StatusProcessing, RepositoryName: "acme/web", CreatedAt: now.Add(-2 * activeRunMaxAge)},
+	}
+	capacity := tallyActiveRuns(runs, "acme/web")
+	assert.False(t, capacity.full())
+	capacity.add("acme/web")
+	assert.True(t, capacity.full())
+	assert.Equal(t, "1 of 1 active runs in acme/web", capacity.String())
+
+	assert.False(t, capacityLimited("acme/api") && tallyActiveRuns(runs, "acme/api").full())
+	assert.Error(t, validateWhenFull("later"))
+}
+
+func TestListActiveRunsPagesBackToOldestActiveRun(t *testing.T) {
+	now := time.Now()
+	runs := make([]map[string]any, 250)
+	for i := range runs {
+		createdAt := now.Add(-time.Duration(i) * time.Minute)
+		if i >= 150 {
+			createdAt = now.Add(-2 * activeRunMaxAge)
+		}
+		runs[i] = map[string]any{"id": i + 1, "status": "DONE", "repositoryName": "acme/web", "createdAt": createdAt.UTC().Format(time.RFC3339)}
+	}
+	runs[120]["status"] = "PROCESSING"
+	runs[220]["status"] = "PROCESSING"
+
+	var pages []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		pages = append(pages, page)
+		start := min((page-1)*limit, len(runs))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data":     runs[start:min(start+limit, len(runs))],
+			"metadata": map[string]any{"currentPage": page, "total": len(runs), "totalPages": 3},
+		})
+	}))
+	defer server.Close()
+	t.Cleanup(configureRunWaitTest(t, server.URL))
+
+	listed, err := listActiveRuns(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, pages)
+	require.Len(t, listed, 150)
+	assert.Equal(t, 1, tallyActiveRuns(listed, "acme/web").Active)
+}
+
+func TestStuckRunsDoNotFillRunSlots(t *testing.T) {
+	server := configureCapacityTest(t, config.RunDefaults{MaxActiveRuns: 1})
+	stuck := activeRun(5, "acme/web")
+	stuck["createdAt"] = time.Now().Add(-2 * activeRunMaxAge).UTC().Format(time.RFC3339)
+	server.active = []map[string]any{stuck}
+	whenFull = whenFullFail
+	jsonOutput = false
+
+	listed, err := listActiveRuns(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	output := captureRunStdout(t, func() { require.NoError(t, processSingleRun(capacityRunConfig(), "")) })
+	assert.Contains(t, output, "Run created successfully")
+	assert.Equal(t, []string{"acme/web"}, server.created)
+}
@@ -5,11 +5,16 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/repobird/repobird-cli/internal/config"
 	"github.com/repobird/repobird-cli/internal/output"
+	"github.com/repobird/repobird-cli/internal/project"
 	"github.com/repobird/repobird-cli/internal/utils"
 )
 
@@ -45,6 +50,7 @@ Storage locations:
 
 Examples:
   repobird config get                      # Show all configuration
+  repobird config show --resolved          # Show run defaults and their sources
   repobird config set api-key YOUR_KEY     # Set API key
   repobird config set api-url https://...  # Set custom API endpoint
   repobird config set color never          # Disable colored output
@@ -258,9 +264,130 @@ Note: Only the API key can be deleted. Other settings can be changed with 'confi
 	},
 }
 
+var configShowResolved bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show configuration and run defaults",
+	Long: `Show configuration and the run defaults that apply in this directory.
+
+Run settings are merged with this precedence, highest first:
+  flags > run file > project .repobird.yaml > user config defaults
+
+The project file is found by walking up from the current directory to the git
+root. User defaults live in the defaults section of the user config file.
+Use --resolved to print where each value came from.`,
+	Example: `  repobird config show
+  repobird config show --resolved
+  repobird config show --resolved --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		settings, err := resolvedConfigSettings()
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(cmd.OutOrStdout(), configResolvedJSONOutput{
+				Schema:    "repobird.config.resolved.v1",
+				Operation: "config.resolved",
+				Settings:  settings,
+			})
+		}
+		return printConfigSettings(cmd.OutOrStdout(), settings, configShowResolved)
+	},
+}
+
+// resolvedConfigSettings returns CLI settings, merged run defaults, lint
+// rule severities, and templates, each with the source that set it.
+func resolvedConfigSettings() ([]project.Setting, error) {
+	if cfg == nil || cfg.Config == nil {
+		return nil, fmt.Errorf("configuration not loaded")
+	}
+	userPath := config.FileUsed()
+	cliSetting := func(key, value, viperKey, envVar string) project.Setting {
+		setting := project.Setting{Key: key, Value: value, Source: config.SettingSource(viperKey, envVar)}
+		if setting.Source == config.SourceConfigFile {
+			setting.Path = userPath
+		}
+		return setting
+	}
+	settings := []project.Setting{
+		cliSetting(configKeyAPIURL, cfg.APIURL, "api_url", config.EnvAPIURL),
+		cliSetting(configKeyDebug, strconv.FormatBool(cfg.Debug), "debug", config.EnvDebug),
+		cliSetting(configKeyColor, cfg.Color, "color", config.EnvColor),
+	}
+
+	layers, err := loadRunDefaultLayers("")
+	if err != nil {
+		return nil, err
+	}
+	if merged, _ := project.Resolve(layers...); merged.Repository != "" {
+		if layers, err = loadRunDefaultLayers(merged.Repository); err != nil {
+			return nil, err
+		}
+	}
+	_, defaults := project.Resolve(layers...)
+	settings = append(settings, defaults...)
+
+	projectFile, projectPath, err := loadProjectFile()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range []struct {
+		prefix string
+		values map[string]string
+	}{
+		{"lint.rules.", projectFile.Lint.Rules},
+		{"templates.", projectFile.Templates},
+	} {
+		keys := make([]string, 0, len(group.values))
+		for key := range group.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			settings = append(settings, project.Setting{
+				Key:    group.prefix + key,
+				Value:  group.values[key],
+				Source: project.SourceProject,
+				Path:   projectPath,
+			})
+		}
+	}
+	return settings, nil
+}
+
+func printConfigSettings(out io.Writer, settings []project.Setting, withSource bool) error {
+	styler := styleFor(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if withSource {
+		_, _ = fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	} else {
+		_, _ = fmt.Fprintln(w, "KEY\tVALUE")
+	}
+	for _, setting := range settings {
+		value := setting.Value
+		if value == "" {
+			value = styler.Muted("(not set)")
+		}
+		if !withSource {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", setting.Key, value)
+			continue
+		}
+		source := setting.Source
+		if setting.Path != "" {
+			source = fmt.Sprintf("%s (%s)", source, setting.Path)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, value, source)
+	}
+	return w.Flush()
+}
+
 // InitConfigSubcommands adds subcommands to configCmd
 func InitConfigSubcommands() {
+	configShowCmd.Flags().BoolVar(&configShowResolved, "resolved", false, "print the source of each value")
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configDeleteCmd)
 }
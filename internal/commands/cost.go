@@ -0,0 +1,323 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/usage"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+const (
+	costFormatTable = "table"
+	costFormatCSV   = "csv"
+	costFormatJSON  = "json"
+
+	costGroupRepo = "repo"
+	costGroupRun  = "run"
+
+	costListPageSize = 50
+)
+
+type costOptions struct {
+	repo   string
+	since  time.Time
+	until  time.Time
+	limit  int
+	group  string
+	format string
+	output string
+}
+
+type costReportClient interface {
+	ListRuns(ctx context.Context, page, limit int) (*models.ListRunsResponse, error)
+	GetRunLogs(ctx context.Context, id string, afterSeq int) ([]models.RunLogMessage, error)
+}
+
+var costCmd = newCostCommand()
+
+func newCostCommand() *cobra.Command {
+	var since, until string
+	opts := costOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Report cost, token usage, and tool time across runs",
+		Long: `Report cost, token usage, and tool time derived from agent logs.
+
+Runs are listed newest first, filtered by repository and creation date, and
+their agent logs are summarized. Results are rolled up per repository (the
+default) or listed per run, and can be exported as CSV or JSON.
+
+Time bounds accept RFC3339 timestamps, YYYY-MM-DD dates, or ages such as 24h
+or 7d.`,
+		Example: `  repobird cost --since 7d
+  repobird cost --repo acme/webapp --since 2025-01-01 --until 2025-02-01
+  repobird cost --by run --format csv -o usage.csv
+  repobird cost --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			now := time.Now()
+			var err error
+			if opts.since, err = utils.ParseTimeBound(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if opts.until, err = utils.ParseTimeBound(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			if jsonOutput {
+				opts.format = costFormatJSON
+			}
+			return costCommand(cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "only include runs for this repository (owner/repo)")
+	cmd.Flags().StringVar(&since, "since", "", "only include runs created at or after this time")
+	cmd.Flags().StringVar(&until, "until", "", "only include runs created before this time")
+	cmd.Flags().IntVar(&opts.limit, "limit", 100, "maximum number of runs to summarize")
+	cmd.Flags().StringVar(&opts.group, "by", costGroupRepo, "group results by repo or run")
+	cmd.Flags().StringVar(&opts.format, "format", costFormatTable, "output format: table, csv, or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "write the report to a file instead of stdout")
+	return cmd
+}
+
+func costCommand(stdout io.Writer, opts costOptions) error {
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+	if err := validateCostOptions(opts); err != nil {
+		return err
+	}
+
+	client := api.NewClient(cfg.APIKey, utils.GetAPIURL(cfg.APIURL), cfg.Debug)
+	runs, err := collectRunUsage(context.Background(), client, opts, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if err := recordRunCosts(runs); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+	}
+
+	report := usage.BuildReport(runs)
+	if !opts.since.IsZero() {
+		report.Since = &opts.since
+	}
+	if !opts.until.IsZero() {
+		report.Until = &opts.until
+	}
+
+	if opts.output == "" {
+		return writeCostReport(stdout, report, opts)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCostReport(&buf, report, opts); err != nil {
+		return err
+	}
+	if err := utils.WriteFileWithError(opts.output, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "%s %s\n", styleFor(stdout).Success("✓ Cost report written to"), opts.output)
+	return nil
+}
+
+func validateCostOptions(opts costOptions) error {
+	switch opts.format {
+	case costFormatTable, costFormatCSV, costFormatJSON:
+	default:
+		return fmt.Errorf("invalid --format %q (use table, csv, or json)", opts.format)
+	}
+	switch opts.group {
+	case costGroupRepo, costGroupRun:
+	default:
+		return fmt.Errorf("invalid --by %q (use repo or run)", opts.group)
+	}
+	if opts.limit <= 0 {
+		return fmt.Errorf("--limit must be greater than zero")
+	}
+	if !opts.since.IsZero() && !opts.until.IsZero() && !opts.since.Before(opts.until) {
+		return fmt.Errorf("--since must be before --until")
+	}
+	return nil
+}
+
+// collectRunUsage pages through runs newest first and summarizes the agent
+// logs of every run that matches the filters. Runs whose logs cannot be
+// fetched are reported on warnOut and skipped.
+func collectRunUsage(ctx context.Context, client costReportClient, opts costOptions, warnOut io.Writer) ([]usage.RunUsage, error) {
+	results := make([]usage.RunUsage, 0)
+
+	for page := 1; len(results) < opts.limit; page++ {
+		resp, err := client.ListRuns(ctx, page, costListPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runs: %s", errors.FormatUserError(err))
+		}
+		if resp == nil || len(resp.Data) == 0 {
+			break
+		}
+
+		reachedSince := false
+		for _, run := range resp.Data {
+			if run == nil {
+				continue
+			}
+			if !opts.since.IsZero() && !run.CreatedAt.IsZero() && run.CreatedAt.Before(opts.since) {
+				reachedSince = true
+				continue
+			}
+			if !opts.until.IsZero() && !run.CreatedAt.Before(opts.until) {
+				continue
+			}
+			if opts.repo != "" && !strings.EqualFold(run.GetRepositoryName(), opts.repo) {
+				continue
+			}
+
+			messages, err := client.GetRunLogs(ctx, run.GetIDString(), 0)
+			if err != nil {
+				_, _ = fmt.Fprintf(warnOut, "%s run %s: %s\n",
+					styleFor(warnOut).Warning("Skipping"), run.GetIDString(), errors.FormatUserError(err))
+				continue
+			}
+			results = append(results, usage.RunUsage{
+				RunID:      run.GetIDString(),
+				Repository: run.GetRepositoryName(),
+				Title:      run.Title,
+				Status:     string(run.Status),
+				CreatedAt:  run.CreatedAt,
+				Summary:    usage.Summarize(messages),
+			})
+			if len(results) >= opts.limit {
+				break
+			}
+		}
+
+		if reachedSince {
+			break
+		}
+		if resp.Metadata != nil && page >= resp.Metadata.TotalPages {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func writeCostReport(out io.Writer, report usage.Report, opts costOptions) error {
+	switch opts.format {
+	case costFormatJSON:
+		return printJSON(out, costReportJSONOutput{
+			Schema:    "repobird.cost.report.v1",
+			Operation: "cost.report",
+			Report:    report,
+		})
+	case costFormatCSV:
+		if opts.group == costGroupRun {
+			return report.WriteRunsCSV(out)
+		}
+		return report.WriteRepositoriesCSV(out)
+	default:
+		printCostTable(out, report, opts.group)
+		return nil
+	}
+}
+
+func printCostTable(out io.Writer, report usage.Report, group string) {
+	styler := styleFor(out)
+	if len(report.Runs) == 0 {
+		_, _ = fmt.Fprintln(out, styler.Muted("No runs matched the filters."))
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if group == costGroupRun {
+		_, _ = fmt.Fprintln(w, "RUN\tREPOSITORY\tSTATUS\tCOST\tINPUT\tOUTPUT\tCACHE\tTOOLS\tTOOL TIME")
+		for _, run := range report.Runs {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.RunID, run.Repository, run.Status, costTableColumns(run.Summary))
+		}
+	} else {
+		_, _ = fmt.Fprintln(w, "REPOSITORY\tRUNS\tCOST\tINPUT\tOUTPUT\tCACHE\tTOOLS\tTOOL TIME")
+		for _, repo := range report.Repositories {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", repo.Repository, repo.Runs, costTableColumns(repo.Summary))
+		}
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintln(out)
+	writeUsageSummary(out, report.Total)
+}
+
+func costTableColumns(summary usage.Summary) string {
+	return fmt.Sprintf("%s\t%d\t%d\t%d\t%d\t%s",
+		formatCost(summary.Cost),
+		summary.Tokens.Input,
+		summary.Tokens.Output,
+		summary.Tokens.CacheRead+summary.Tokens.CacheWrite,
+		summary.ToolCalls,
+		utils.FormatDuration(summary.ToolDuration),
+	)
+}
+
+func renderRunLogSummary(out io.Writer, runID string, summary usage.Summary, asJSON bool) error {
+	if asJSON {
+		return printJSON(out, logsSummaryJSONOutput{
+			Schema:    "repobird.logs.summary.v1",
+			Operation: "logs.summary",
+			RunID:     runID,
+			Summary:   summary,
+		})
+	}
+	_, _ = fmt.Fprintf(out, "%s %s\n\n", styleFor(out).Heading("Usage for run"), runID)
+	writeUsageSummary(out, summary)
+	return nil
+}
+
+// writeUsageSummary prints totals followed by a per-tool breakdown.
+func writeUsageSummary(out io.Writer, summary usage.Summary) {
+	styler := styleFor(out)
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Cost:"), formatCost(summary.Cost))
+	_, _ = fmt.Fprintf(out, "%s %d input, %d output, %d cache read, %d cache write",
+		styler.Label("Tokens:"),
+		summary.Tokens.Input,
+		summary.Tokens.Output,
+		summary.Tokens.CacheRead,
+		summary.Tokens.CacheWrite,
+	)
+	if summary.Tokens.Reasoning > 0 {
+		_, _ = fmt.Fprintf(out, ", %d reasoning", summary.Tokens.Reasoning)
+	}
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintf(out, "%s %d calls, %s\n", styler.Label("Tools:"), summary.ToolCalls, utils.FormatDuration(summary.ToolDuration))
+	if summary.ToolErrors > 0 {
+		_, _ = fmt.Fprintf(out, "%s %d\n", styler.Label("Tool errors:"), summary.ToolErrors)
+	}
+
+	if len(summary.Tools) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TOOL\tCALLS\tERRORS\tTIME")
+	for _, tool := range summary.Tools {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", tool.Name, tool.Calls, tool.Errors, utils.FormatDuration(tool.Duration))
+	}
+	_ = w.Flush()
+}
+
+func formatCost(cost float64) string {
+	return fmt.Sprintf("$%.4f", cost)
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/usage"
+)
+
+type fakeCostClient struct {
+	runs []*models.RunResponse
+	logs map[string][]models.RunLogMessage
+}
+
+func (c *fakeCostClient) ListRuns(_ context.Context, page, limit int) (*models.ListRunsResponse, error) {
+	start := (page - 1) * limit
+	if start >= len(c.runs) {
+		return &models.ListRunsResponse{}, nil
+	}
+	end := start + limit
+	if end > len(c.runs) {
+		end = len(c.runs)
+	}
+	totalPages := (len(c.runs) + limit - 1) / limit
+	return &models.ListRunsResponse{
+		Data:     c.runs[start:end],
+		Metadata: &models.PaginationMetadata{CurrentPage: page, Total: len(c.runs), TotalPages: totalPages},
+	}, nil
+}
+
+func (c *fakeCostClient) GetRunLogs(_ context.Context, id string, _ int) ([]models.RunLogMessage, error) {
+	messages, ok := c.logs[id]
+	if !ok {
+		return nil, fmt.Errorf("logs unavailable")
+	}
+	return messages, nil
+}
+
+func TestCollectRunUsageFiltersByRepoAndDateRange(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cost := 1.25
+	client := &fakeCostClient{
+		runs: []*models.RunResponse{
+			{ID: "4", RepositoryName: "acme/web", CreatedAt: now.Add(-time.Hour)},
+			{ID: "3", RepositoryName: "acme/api", CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "2", RepositoryName: "acme/api", CreatedAt: now.Add(-3 * time.Hour)},
+			{ID: "1", RepositoryName: "acme/api", CreatedAt: now.Add(-72 * time.Hour)},
+		},
+		logs: map[string][]models.RunLogMessage{
+			"3": {{Type: "assistant", Cost: &cost}},
+			"1": {{Type: "assistant", Cost: &cost}},
+		},
+	}
+
+	var warnings bytes.Buffer
+	runs, err := collectRunUsage(context.Background(), client, costOptions{
+		repo:  "ACME/api",
+		since: now.Add(-24 * time.Hour),
+		limit: 10,
+	}, &warnings)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, "3", runs[0].RunID)
+	require.InDelta(t, 1.25, runs[0].Summary.Cost, 1e-9)
+	require.Contains(t, warnings.String(), "run 2")
+}
+
+func TestCollectRunUsageStopsAtLimit(t *testing.T) {
+	client := &fakeCostClient{logs: map[string][]models.RunLogMessage{}}
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("%d", i)
+		client.runs = append(client.runs, &models.RunResponse{ID: id, RepositoryName: "acme/api"})
+		client.logs[id] = nil
+	}
+
+	runs, err := collectRunUsage(context.Background(), client, costOptions{limit: 75}, io.Discard)
+	require.NoError(t, err)
+	require.Len(t, runs, 75)
+}
+
+func TestWriteCostReportJSON(t *testing.T) {
+	report := usage.BuildReport([]usage.RunUsage{{RunID: "1", Repository: "acme/api", Summary: usage.Summary{Cost: 2}}})
+
+	var out bytes.Buffer
+	require.NoError(t, writeCostReport(&out, report, costOptions{format: costFormatJSON, group: costGroupRepo}))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, "repobird.cost.report.v1", decoded["schema"])
+	require.Len(t, decoded["repositories"], 1)
+}
+
+func TestValidateCostOptionsRejectsUnknownFormat(t *testing.T) {
+	err := validateCostOptions(costOptions{format: "xml", group: costGroupRepo, limit: 1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid --format")
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
+)
+
+// loadProjectFile loads the project file for the working directory.
+func loadProjectFile() (*project.File, string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	return project.Load(cwd)
+}
+
+// loadRunDefaultLayers returns the project and user config default layers,
+// highest precedence first. Flags and run files sit above both and are
+// applied by filling only the fields they leave empty. When repository is
+// known, its user config model defaults sit between the two.
+func loadRunDefaultLayers(repository string) ([]project.Layer, error) {
+	projectFile, projectPath, err := loadProjectFile()
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []project.Layer{{Source: project.SourceProject, Path: projectPath, Defaults: projectFile.Defaults}}
+	if cfg != nil && cfg.Config != nil {
+		if layer, ok := userRepositoryLayer(cfg.Defaults.Repositories, repository, config.FileUsed()); ok {
+			layers = append(layers, layer)
+		}
+		layers = append(layers, userDefaultsLayer(cfg.Defaults, config.FileUsed()))
+	}
+	return layers, nil
+}
+
+// userRepositoryLayer returns the user config model defaults for repository.
+func userRepositoryLayer(entries []config.RepositoryDefaults, repository, path string) (project.Layer, bool) {
+	if repository == "" {
+		return project.Layer{}, false
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Repository, repository) {
+			return project.Layer{
+				Source:   project.SourceUserRepository,
+				Path:     path,
+				Defaults: project.Defaults{Model: entry.Model, Provider: entry.Provider},
+			}, true
+		}
+	}
+	return project.Layer{}, false
+}
+
+// userDefaultsLayer converts the user config defaults section. Relative
+// context files are resolved against the config file directory.
+func userDefaultsLayer(defaults config.RunDefaults, path string) project.Layer {
+	layer := project.Layer{
+		Source: project.SourceUser,
+		Path:   path,
+		Defaults: project.Defaults{
+			Repository:     defaults.Repository,
+			BaseBranch:     defaults.BaseBranch,
+			PRTargetBranch: defaults.PRTargetBranch,
+			OutputMode:     defaults.OutputMode,
+			Model:          defaults.Model,
+			Provider:       defaults.Provider,
+		},
+	}
+	for _, file := range defaults.ContextFiles {
+		if !filepath.IsAbs(file) && path != "" {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		layer.Defaults.ContextFiles = append(layer.Defaults.ContextFiles, file)
+	}
+	return layer
+}
+
+// resolveRunDefaults merges the project and user config defaults for
+// repository, which may be empty when it is not known yet.
+func resolveRunDefaults(repository string) (project.Defaults, error) {
+	layers, err := loadRunDefaultLayers(repository)
+	if err != nil {
+		return project.Defaults{}, err
+	}
+	defaults, _ := project.Resolve(layers...)
+	return defaults, nil
+}
+
+// hasDefaultRepository reports whether a project or user default names the
+// repository, so --repo can be omitted.
+func hasDefaultRepository() bool {
+	defaults, err := resolveRunDefaults("")
+	return err == nil && defaults.Repository != ""
+}
+
+// applyRunDefaults fills fields the run left empty. Branch defaults that do
+// not fit the run's output mode are skipped rather than creating conflicts,
+// as are those of another repository than the run's.
+func applyRunDefaults(runConfig *models.RunConfig, defaults project.Defaults) {
+	if runConfig.Repository == "" {
+		runConfig.Repository = defaults.Repository
+	}
+	if runConfig.OutputMode == "" && !runConfig.BranchOnly {
+		runConfig.OutputMode = defaults.OutputMode
+	}
+	if !repositoryDefaultsApply(defaults, runConfig.Repository) {
+		return
+	}
+	if runConfig.BaseBranch == "" && runConfig.Source == "" {
+		runConfig.BaseBranch = defaults.BaseBranch
+	}
+	branchOutput := runConfig.BranchOnly || runConfig.OutputMode == "branch"
+	if runConfig.PRTargetBranch == "" && runConfig.Target == "" && !branchOutput {
+		runConfig.PRTargetBranch = defaults.PRTargetBranch
+	}
+}
+
+// repositoryDefaultsApply reports whether the branch and context defaults,
+// which name branches and files of the default repository, fit a run in
+// repository. Without a default repository they apply to any run.
+func repositoryDefaultsApply(defaults project.Defaults, repository string) bool {
+	return defaults.Repository == "" || repository == "" || strings.EqualFold(defaults.Repository, repository)
+}
+
+// readDefaultContext reads and joins the default context files.
+func readDefaultContext(files []string) (string, error) {
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read default context file: %w", err)
+		}
+		if text := strings.TrimSpace(string(data)); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
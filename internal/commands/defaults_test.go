@@ -0,0 +1,181 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
+)
+
+// writeProjectCheckout creates a git checkout with a .repobird.yaml and
+// changes into it.
+func writeProjectCheckout(t *testing.T, projectYAML string) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".repobird.yaml"), []byte(projectYAML), 0644))
+	t.Chdir(root)
+	return root
+}
+
+func TestApplyRunDefaultsKeepsExplicitValues(t *testing.T) {
+	defaults := project.Defaults{
+		Repository:     "acme/default",
+		BaseBranch:     "develop",
+		PRTargetBranch: "main",
+		OutputMode:     "pull_request",
+	}
+
+	runConfig := &models.RunConfig{Repository: "acme/default", Source: "release"}
+	applyRunDefaults(runConfig, defaults)
+	assert.Equal(t, "acme/default", runConfig.Repository)
+	assert.Empty(t, runConfig.BaseBranch)
+	assert.Equal(t, "main", runConfig.PRTargetBranch)
+
+	// Branches of the default repository do not apply to another one.
+	otherRun := &models.RunConfig{Repository: "acme/webapp"}
+	applyRunDefaults(otherRun, defaults)
+	assert.Equal(t, "acme/webapp", otherRun.Repository)
+	assert.Empty(t, otherRun.BaseBranch)
+	assert.Empty(t, otherRun.PRTargetBranch)
+	assert.Equal(t, "pull_request", otherRun.OutputMode)
+
+	branchRun := &models.RunConfig{BranchOnly: true}
+	applyRunDefaults(branchRun, defaults)
+	assert.Equal(t, "acme/default", branchRun.Repository)
+	assert.Empty(t, branchRun.OutputMode)
+	assert.Empty(t, branchRun.PRTargetBranch)
+}
+
+func TestProcessSingleRunAppliesProjectDefaults(t *testing.T) {
+	root := writeProjectCheckout(t, `repository: acme/webapp
+baseBranch: develop
+prTargetBranch: main
+model: openrouter/example/model
+contextFiles:
+  - docs/agent.md
+`)
+	require.NoError(t, os.Mkdir(filepath.Join(root, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "agent.md"), []byte("Follow the style guide.\n"), 0644))
+
+	restore := configureRunWaitTest(t, "http://127.0.0.1:0")
+	defer restore()
+	dryRun = true
+
+	var runErr error
+	stdout := captureRunStdout(t, func() {
+		runErr = processSingleRun(&models.RunConfig{Prompt: "Add pagination to the runs endpoint", RunType: "run"}, "")
+	})
+	require.NoError(t, runErr)
+
+	var out runDryRunJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, "acme/webapp", out.Request.RepositoryName)
+	assert.Equal(t, "develop", out.Request.BaseBranch)
+	assert.Equal(t, "main", out.Request.PRTargetBranch)
+	assert.Equal(t, "openrouter/example/model", out.Request.OpenCodeModel)
+	assert.Equal(t, "Follow the style guide.", out.Request.Context)
+}
+
+func TestProcessSingleRunSkipsUserBranchesOfAnotherRepository(t *testing.T) {
+	writeProjectCheckout(t, "repository: acme/webapp\n")
+	restore := configureRunWaitTest(t, "http://127.0.0.1:0")
+	defer restore()
+	dryRun = true
+	originalDefaults := cfg.Defaults
+	defer func() { cfg.Defaults = originalDefaults }()
+	cfg.Defaults = config.RunDefaults{Repository: "acme/api", BaseBranch: "develop", PRTargetBranch: "release"}
+
+	var runErr error
+	stdout := captureRunStdout(t, func() {
+		runErr = processSingleRun(&models.RunConfig{Prompt: "Add pagination to the runs endpoint", RunType: "run"}, "")
+	})
+	require.NoError(t, runErr)
+
+	var out runDryRunJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, "acme/webapp", out.Request.RepositoryName)
+	assert.Empty(t, out.Request.BaseBranch)
+	assert.Empty(t, out.Request.PRTargetBranch)
+}
+
+func TestConfigShowResolvedReportsSources(t *testing.T) {
+	writeProjectCheckout(t, `baseBranch: develop
+lint:
+  rules:
+    prompt-too-short: error
+`)
+	ensureRunTestConfig()
+	originalDefaults := cfg.Defaults
+	defer func() { cfg.Defaults = originalDefaults }()
+	cfg.Defaults = config.RunDefaults{Repository: "acme/webapp", BaseBranch: "main"}
+
+	settings, err := resolvedConfigSettings()
+	require.NoError(t, err)
+	byKey := make(map[string]project.Setting)
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	assert.Equal(t, project.SourceUser, byKey["repository"].Source)
+	assert.Equal(t, "develop", byKey["baseBranch"].Value)
+	assert.Equal(t, project.SourceProject, byKey["baseBranch"].Source)
+	assert.Empty(t, byKey["model"].Source)
+	assert.Equal(t, "error", byKey["lint.rules.prompt-too-short"].Value)
+
+	var buf bytes.Buffer
+	require.NoError(t, printConfigSettings(&buf, settings, true))
+	assert.Contains(t, buf.String(), "SOURCE")
+	assert.Contains(t, buf.String(), "project (")
+}
+
+func TestRunFileTakesPrecedenceOverProjectDefaults(t *testing.T) {
+	root := writeProjectCheckout(t, `repository: acme/webapp
+baseBranch: develop
+prTargetBranch: main
+`)
+	restore := configureRunWaitTest(t, "http://127.0.0.1:0")
+	defer restore()
+	dryRun = true
+	originalBaseBranch := baseBranch
+	defer func() { baseBranch = originalBaseBranch }()
+
+	dryRunFile := func(content string) runDryRunJSONOutput {
+		t.Helper()
+		file := filepath.Join(root, "task.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		var runErr error
+		stdout := captureRunStdout(t, func() { runErr = runCommand(runCmd, []string{file}) })
+		require.NoError(t, runErr)
+		var out runDryRunJSONOutput
+		require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+		return out
+	}
+
+	out := dryRunFile("prompt: Add pagination to the runs endpoint\nbaseBranch: release\n")
+	assert.Equal(t, "acme/webapp", out.Request.RepositoryName)
+	assert.Equal(t, "release", out.Request.BaseBranch)
+	assert.Equal(t, "main", out.Request.PRTargetBranch)
+
+	// Flags take precedence over the run file.
+	baseBranch = "hotfix"
+	out = dryRunFile("prompt: Add pagination to the runs endpoint\nbaseBranch: release\n")
+	assert.Equal(t, "hotfix", out.Request.BaseBranch)
+	baseBranch = ""
+
+	// The project branches are not applied to a run in another repository.
+	out = dryRunFile("prompt: Add pagination to the runs endpoint\nrepository: acme/api\n")
+	assert.Equal(t, "acme/api", out.Request.RepositoryName)
+	assert.Empty(t, out.Request.BaseBranch)
+	assert.Empty(t, out.Request.PRTargetBranch)
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/events"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+const (
+	// eventsListLimit is how many of the newest runs each poll lists.
+	eventsListLimit = 100
+	// eventsBackoffFactor stretches the interval after each failed poll,
+	// as run polling does, up to utils.MaxPollInterval.
+	eventsBackoffFactor = 1.5
+)
+
+type eventsOptions struct {
+	repo     string
+	since    time.Time
+	runIDs   []string
+	interval time.Duration
+	cursor   string
+	reset    bool
+	once     bool
+}
+
+type eventsClient interface {
+	ListRuns(ctx context.Context, page, limit int) (*models.ListRunsResponse, error)
+	GetRunWithRetry(ctx context.Context, id string) (*models.RunResponse, error)
+}
+
+var (
+	eventsCmd = newEventsCommand()
+	// newEventsClient returns the API client 'events' polls with.
+	newEventsClient = func() (eventsClient, error) {
+		return newRepoAPIClient()
+	}
+)
+
+func newEventsCommand() *cobra.Command {
+	var since string
+	opts := eventsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "events [run-id...]",
+		Short: "Stream run state changes as NDJSON",
+		Long: `Poll the run list and write one JSON object per line to stdout for every
+change: run.created, run.status_changed, run.pr_opened, run.failed, and
+batch.progress for bulk batches submitted from this machine.
+
+Without run IDs every run is watched; --repo narrows the stream to one
+repository and --since to runs created after a time (an RFC3339 timestamp,
+a YYYY-MM-DD date, or an age such as 24h or 7d).
+
+The last seen state is saved in a named cursor in the cache directory, so a
+restarted stream reports what changed while it was stopped instead of
+starting over. A new cursor starts at --since, or at the current time when
+--since is not given.`,
+		Example: `  repobird events | jq -c 'select(.type == "run.failed")'
+  repobird events --repo acme/webapp --since 1h
+  repobird events 123 456 --cursor ci --once`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.since, err = parseEventsSince(since); err != nil {
+				return err
+			}
+			opts.runIDs = args
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return eventsCommand(ctx, cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "only watch runs for this repository (owner/repo)")
+	cmd.Flags().StringVar(&since, "since", "", "only watch runs created at or after this time")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 10*time.Second, "time between polls")
+	cmd.Flags().StringVar(&opts.cursor, "cursor", "default", "name of the saved cursor to resume from")
+	cmd.Flags().BoolVar(&opts.reset, "reset", false, "discard the saved cursor and start over")
+	cmd.Flags().BoolVar(&opts.once, "once", false, "poll once, print the changes since the cursor, and exit")
+	return cmd
+}
+
+func parseEventsSince(value string) (time.Time, error) {
+	since, err := utils.ParseTimeBound(value, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since: %w", err)
+	}
+	return since, nil
+}
+
+func eventsCommand(ctx context.Context, out io.Writer, opts eventsOptions) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("--interval must be greater than zero")
+	}
+	cursorPath, err := events.CursorPath(events.DefaultCursorDir(), opts.cursor)
+	if err != nil {
+		return err
+	}
+	client, err := newEventsClient()
+	if err != nil {
+		return err
+	}
+
+	tracker := events.NewTracker()
+	if !opts.reset {
+		if tracker, err = events.LoadTracker(cursorPath); err != nil {
+			return err
+		}
+	}
+	tracker.SetFilter(events.Filter{Repository: opts.repo, RunIDs: opts.runIDs, Since: opts.since})
+	tracker.Baseline = opts.since
+	if tracker.Baseline.IsZero() {
+		tracker.Baseline = time.Now()
+	}
+
+	encoder := json.NewEncoder(out)
+	backoff := &utils.GenericPollConfig{
+		Interval:      opts.interval,
+		MaxInterval:   max(opts.interval, utils.MaxPollInterval),
+		BackoffFactor: eventsBackoffFactor,
+	}
+	interval := opts.interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		listed, err := pollEvents(ctx, client, tracker, opts, encoder, cursorPath)
+		if err != nil {
+			return err
+		}
+		if opts.once {
+			return nil
+		}
+		if next := backoff.NextInterval(interval, !listed); next != interval {
+			interval = next
+			ticker.Reset(interval)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollEvents takes one snapshot, writes its events, saves the cursor and
+// reports whether the runs could be listed. A failed poll is reported on
+// stderr and retried after a backoff, unless it failed on authentication or
+// a missing run, or --once is set.
+func pollEvents(ctx context.Context, client eventsClient, tracker *events.Tracker, opts eventsOptions, encoder *json.Encoder, cursorPath string) (bool, error) {
+	runs, err := snapshotRuns(ctx, client, opts.runIDs)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, nil
+		}
+		if errors.IsAuthError(err) || errors.IsNotFound(err) || opts.once {
+			return false, fmt.Errorf("failed to list runs: %s", errors.FormatUserError(err))
+		}
+		fmt.Fprintf(os.Stderr, "%s failed to list runs: %s\n", stderrStyle().Warning("Warning:"), errors.FormatUserError(err))
+		return false, nil
+	}
+	batches, err := cache.ListBulkBatches(cache.BulkLedgerDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+	}
+
+	for _, event := range tracker.Observe(runs, batches, time.Now().UTC()) {
+		if err := encoder.Encode(event); err != nil {
+			return true, err
+		}
+	}
+	return true, tracker.Save(cursorPath)
+}
+
+// snapshotRuns lists the newest runs, or fetches the given runs.
+func snapshotRuns(ctx context.Context, client eventsClient, runIDs []string) ([]*models.RunResponse, error) {
+	if len(runIDs) == 0 {
+		resp, err := client.ListRuns(ctx, 1, eventsListLimit)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, nil
+		}
+		return resp.Data, nil
+	}
+	runs := make([]*models.RunResponse, 0, len(runIDs))
+	for _, id := range runIDs {
+		run, err := client.GetRunWithRetry(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("run %s: %w", id, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
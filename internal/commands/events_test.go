@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/events"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+type fakeEventsClient struct {
+	runs []*models.RunResponse
+}
+
+func (c *fakeEventsClient) ListRuns(_ context.Context, _, _ int) (*models.ListRunsResponse, error) {
+	return &models.ListRunsResponse{Data: c.runs}, nil
+}
+
+func (c *fakeEventsClient) GetRunWithRetry(_ context.Context, id string) (*models.RunResponse, error) {
+	for _, run := range c.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func decodeEvents(t *testing.T, out *bytes.Buffer) []events.Event {
+	t.Helper()
+	var decoded []events.Event
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var event events.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		decoded = append(decoded, event)
+	}
+	return decoded
+}
+
+func TestEventsResumesFromCursor(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	created := time.Now().Add(-time.Hour)
+	client := &fakeEventsClient{runs: []*models.RunResponse{
+		{ID: "1", Status: models.StatusProcessing, RepositoryName: "acme/api", CreatedAt: created},
+		{ID: "2", Status: models.StatusQueued, RepositoryName: "acme/web", CreatedAt: created},
+	}}
+	original := newEventsClient
+	newEventsClient = func() (eventsClient, error) { return client, nil }
+	t.Cleanup(func() { newEventsClient = original })
+
+	opts := eventsOptions{repo: "acme/api", since: created.Add(-time.Minute), interval: time.Second, cursor: "test", once: true}
+	var out bytes.Buffer
+	require.NoError(t, eventsCommand(context.Background(), &out, opts))
+	first := decodeEvents(t, &out)
+	require.Len(t, first, 1)
+	assert.Equal(t, events.RunCreated, first[0].Type)
+	assert.Equal(t, "1", first[0].RunID)
+
+	client.runs[0].Status = models.StatusFailed
+	client.runs[0].Error = "tests failed"
+	opts.since = time.Time{}
+	out.Reset()
+	require.NoError(t, eventsCommand(context.Background(), &out, opts))
+	second := decodeEvents(t, &out)
+	require.Len(t, second, 2)
+	assert.Equal(t, events.RunStatusChanged, second[0].Type)
+	assert.Equal(t, events.RunFailed, second[1].Type)
+	assert.Equal(t, "tests failed", second[1].Error)
+
+	out.Reset()
+	require.NoError(t, eventsCommand(context.Background(), &out, opts))
+	assert.Empty(t, out.String())
+
+	opts.runIDs = []string{"404"}
+	assert.Error(t, eventsCommand(context.Background(), &out, opts))
+}
@@ -124,6 +124,8 @@ func showRunSchema() {
 	fmt.Println("  • title       (string)  - Human-readable title for the run (default: auto-generated)")
 	fmt.Println("  • source      (string)  - Source branch (default: 'main', auto-detected in git repos)")
 	fmt.Println("  • runType     (string)  - Type: 'run' (default); 'plan' is development-only during the OpenCode migration")
+	fmt.Println("  • model       (string)  - OpenCode model ID or model profile name (see 'repobird models')")
+	fmt.Println("  • provider    (string)  - OpenCode provider for the model (for example: openrouter)")
 	fmt.Println("  • context     (string)  - Additional context or instructions")
 	fmt.Println("  • files       (array)   - List of specific files to include")
 	fmt.Println()
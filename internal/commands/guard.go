@@ -0,0 +1,182 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/idempotency"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var guardClearAll bool
+
+// guardKeyWidth is how much of a key 'guard list' shows; 'guard clear'
+// accepts any unique prefix.
+const guardKeyWidth = 16
+
+var guardCmd = newGuardCommand()
+
+func newGuardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "guard",
+		Short: "Inspect the local duplicate-submission guard",
+		Long: `Inspect the local guard that stops the same run from being submitted twice.
+Every run submission reserves its idempotency key before it is sent; an
+identical submission within 30 seconds, or a schedule slot within 7 days, is
+refused unless --force is passed. The guard remembers the run each key
+created or why its submission failed; a failed submission does not block a
+retry.`,
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List remembered run submissions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGuardList(cmd.OutOrStdout())
+		},
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	clear := &cobra.Command{
+		Use:   "clear [key...]",
+		Short: "Forget run submissions so they can be submitted again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGuardClear(cmd.OutOrStdout(), args)
+		},
+	}
+	clear.Flags().BoolVar(&guardClearAll, "all", false, "forget every run submission")
+
+	cmd.AddCommand(list, clear)
+	return cmd
+}
+
+// submissionGuard is one of the guards kept by the CLI.
+type submissionGuard struct {
+	source string
+	guard  *idempotency.RunGuard
+}
+
+func submissionGuards() []submissionGuard {
+	return []submissionGuard{
+		{source: "run", guard: runSubmissionGuard()},
+		{source: "schedule", guard: idempotency.NewRunGuard(scheduleGuardDir(), scheduleGuardWindow, scheduleNow)},
+	}
+}
+
+func runGuardList(out io.Writer) error {
+	var entries []guardListEntry
+	for _, g := range submissionGuards() {
+		submissions, err := g.guard.List()
+		if err != nil {
+			return err
+		}
+		for _, submission := range submissions {
+			entries = append(entries, guardListEntry{
+				Source:     g.source,
+				Key:        submission.Key,
+				ReservedAt: submission.ReservedAt,
+				RunID:      submission.RunID,
+				Error:      submission.Error,
+			})
+		}
+	}
+	if jsonOutput {
+		return printJSON(out, guardListJSONOutput{
+			Schema:      "repobird.guard.v1",
+			Operation:   "guard.list",
+			Submissions: entries,
+		})
+	}
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No run submissions remembered")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SOURCE\tKEY\tRESERVED\tRESULT")
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			entry.Source,
+			utils.TruncateWithEllipsis(entry.Key, guardKeyWidth),
+			entry.ReservedAt.Local().Format("2006-01-02 15:04:05"),
+			guardEntryResult(entry),
+		)
+	}
+	return w.Flush()
+}
+
+func guardEntryResult(entry guardListEntry) string {
+	switch {
+	case entry.RunID != "":
+		return "run " + entry.RunID
+	case entry.Error != "":
+		return "failed: " + utils.TruncateWithEllipsis(firstLine(entry.Error), 50)
+	default:
+		return "pending"
+	}
+}
+
+func runGuardClear(out io.Writer, prefixes []string) error {
+	switch {
+	case guardClearAll && len(prefixes) > 0:
+		return fmt.Errorf("pass submission keys or --all, not both")
+	case !guardClearAll && len(prefixes) == 0:
+		return fmt.Errorf("pass the keys of the submissions to forget (see 'repobird guard list'), or --all")
+	}
+
+	guards := submissionGuards()
+	removed := 0
+	if guardClearAll {
+		for _, g := range guards {
+			n, err := g.guard.Clear()
+			if err != nil {
+				return err
+			}
+			removed += n
+		}
+		_, _ = fmt.Fprintf(out, "%s Forgot %d run submission(s)\n", styleFor(out).Success("✓"), removed)
+		return nil
+	}
+
+	matches := make([][]string, len(guards))
+	for _, prefix := range prefixes {
+		found := 0
+		for i, g := range guards {
+			submissions, err := g.guard.List()
+			if err != nil {
+				return err
+			}
+			for _, submission := range submissions {
+				if strings.HasPrefix(submission.Key, prefix) {
+					matches[i] = append(matches[i], submission.Key)
+					found++
+				}
+			}
+		}
+		switch {
+		case found == 0:
+			return fmt.Errorf("no run submission matches %q", prefix)
+		case found > 1:
+			return fmt.Errorf("%q matches %d run submissions; use a longer key", prefix, found)
+		}
+	}
+	for i, g := range guards {
+		if len(matches[i]) == 0 {
+			continue
+		}
+		n, err := g.guard.Clear(matches[i]...)
+		if err != nil {
+			return err
+		}
+		removed += n
+	}
+	_, _ = fmt.Fprintf(out, "%s Forgot %d run submission(s)\n", styleFor(out).Success("✓"), removed)
+	return nil
+}
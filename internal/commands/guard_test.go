@@ -0,0 +1,83 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/idempotency"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func TestRunDuplicateNamesCreatedRun(t *testing.T) {
+	created := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": 123, "status": "QUEUED"}})
+	}))
+	defer server.Close()
+	t.Cleanup(configureRunWaitTest(t, server.URL))
+	wait = false
+	forceRun = false
+
+	runConfig := func() *models.RunConfig {
+		return &models.RunConfig{Prompt: "Fix the login redirect loop", Repository: "acme/web", RunType: "run"}
+	}
+	captureRunStdout(t, func() { require.NoError(t, processSingleRun(runConfig(), "")) })
+
+	var err error
+	captureRunStdout(t, func() { err = processSingleRun(runConfig(), "") })
+	require.ErrorIs(t, err, idempotency.ErrDuplicate)
+	assert.Contains(t, err.Error(), "already created as run 123")
+	assert.Equal(t, 1, created)
+
+	jsonOutput = true
+	var out bytes.Buffer
+	require.NoError(t, runGuardList(&out))
+	var listed guardListJSONOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &listed))
+	require.Len(t, listed.Submissions, 1)
+	assert.Equal(t, "run", listed.Submissions[0].Source)
+	assert.Equal(t, "123", listed.Submissions[0].RunID)
+
+	out.Reset()
+	require.NoError(t, runGuardClear(&out, []string{listed.Submissions[0].Key[:8]}))
+	assert.Contains(t, out.String(), "Forgot 1 run submission(s)")
+	captureRunStdout(t, func() { require.NoError(t, processSingleRun(runConfig(), "")) })
+	assert.Equal(t, 2, created)
+}
+
+func TestGuardClearValidatesKeys(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	originalJSONOutput := jsonOutput
+	jsonOutput = false
+	t.Cleanup(func() { jsonOutput = originalJSONOutput })
+	guard := runSubmissionGuard()
+	require.NoError(t, guard.Reserve("abc-1", false))
+	require.NoError(t, guard.Fail("abc-1", "repository not found"))
+	require.NoError(t, guard.Reserve("abc-2", false))
+
+	var out bytes.Buffer
+	require.NoError(t, runGuardList(&out))
+	assert.Contains(t, out.String(), "failed: repository not found")
+	assert.Contains(t, out.String(), "pending")
+
+	assert.Error(t, runGuardClear(&out, nil))
+	assert.ErrorContains(t, runGuardClear(&out, []string{"abc"}), "matches 2 run submissions")
+	assert.ErrorContains(t, runGuardClear(&out, []string{"xyz"}), "no run submission matches")
+
+	guardClearAll = true
+	t.Cleanup(func() { guardClearAll = false })
+	out.Reset()
+	require.NoError(t, runGuardClear(&out, nil))
+	assert.Contains(t, out.String(), "Forgot 2 run submission(s)")
+}
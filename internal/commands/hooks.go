@@ -0,0 +1,125 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/hooks"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var (
+	// hookFlags are the hooks passed on the command line. They run in
+	// addition to the hooks in the config file.
+	hookFlags config.Hooks
+	// newHookRunner returns the runner completion hooks are fired with.
+	newHookRunner = func(secret string) *hooks.Runner {
+		return hooks.NewRunner(secret, os.Stderr)
+	}
+)
+
+// addHookFlags registers --on-success, --on-failure and --on-complete.
+func addHookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&hookFlags.OnSuccess, "on-success", nil, "shell command or webhook URL to run when a run completes (repeatable)")
+	cmd.Flags().StringArrayVar(&hookFlags.OnFailure, "on-failure", nil, "shell command or webhook URL to run when a run fails or is cancelled (repeatable)")
+	cmd.Flags().StringArrayVar(&hookFlags.OnComplete, "on-complete", nil, "shell command or webhook URL to run when a run finishes either way (repeatable)")
+}
+
+// requireHookFollow rejects hook flags on a command that is not going to see
+// its runs finish.
+func requireHookFollow(following bool, flags string) error {
+	if following || hooks.Empty(hookFlags) {
+		return nil
+	}
+	return fmt.Errorf("--on-success, --on-failure and --on-complete require %s", flags)
+}
+
+// completionHooks returns the hooks from the config file and the flags.
+func completionHooks() config.Hooks {
+	var configured config.Hooks
+	if cfg != nil && cfg.Config != nil {
+		configured = cfg.Hooks
+	}
+	return hooks.Merge(configured, hookFlags)
+}
+
+// fireCompletionHooks runs the hooks for a finished run. A hook that fails
+// is reported as a warning; it does not change the command's exit code.
+func fireCompletionHooks(payload hooks.Payload) {
+	set := completionHooks()
+	targets := hooks.Targets(set, payload.Success)
+	if len(targets) == 0 {
+		return
+	}
+	runner := newHookRunner(set.Secret)
+	if set.Timeout > 0 {
+		runner.CommandTimeout = set.Timeout
+	}
+	for _, delivery := range runner.Fire(context.Background(), targets, payload) {
+		if delivery.OK {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s %s hook %s for run %s failed: %s\n",
+			stderrStyle().Warning("Warning:"), delivery.Kind, delivery.Target, delivery.RunID, delivery.Error)
+	}
+}
+
+// runHookPayload describes a finished run to its hooks.
+func runHookPayload(run *domain.Run) hooks.Payload {
+	payload := hooks.Payload{
+		Event:          hooks.EventFailed,
+		RunID:          run.ID,
+		Status:         formatStatusForDisplay(run.Status),
+		Success:        run.IsSuccess(),
+		Repository:     run.RepositoryName,
+		Title:          run.Title,
+		PullRequestURL: run.PullRequestURL,
+		RunURL:         utils.GenerateRepoBirdURL(createdRunURLID(run)),
+		Error:          run.Error,
+	}
+	if payload.Success {
+		payload.Event = hooks.EventSucceeded
+	}
+	return payload
+}
+
+// bulkRunHookPayload describes a finished run of a batch to its hooks.
+func bulkRunHookPayload(batchID string, run dto.RunStatusItem) hooks.Payload {
+	id := strconv.Itoa(run.ID)
+	payload := hooks.Payload{
+		Event:   hooks.EventFailed,
+		RunID:   id,
+		Status:  run.Status,
+		Success: run.Status == "DONE",
+		Title:   run.Title,
+		RunURL:  utils.GenerateRepoBirdURL(id),
+		BatchID: batchID,
+	}
+	if run.PRURL != nil {
+		payload.PullRequestURL = *run.PRURL
+	}
+	if payload.Success {
+		payload.Event = hooks.EventSucceeded
+	}
+	return payload
+}
+
+// fireBulkCompletionHooks runs the hooks for every finished run of a batch.
+func fireBulkCompletionHooks(status dto.BulkStatusData) {
+	for _, run := range status.Runs {
+		switch run.Status {
+		case "DONE", "FAILED", "CANCELLED":
+			fireCompletionHooks(bulkRunHookPayload(status.BatchID, run))
+		}
+	}
+}
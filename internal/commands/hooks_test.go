@@ -0,0 +1,62 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/hooks"
+)
+
+func TestWaitFiresCompletionHooks(t *testing.T) {
+	var mu sync.Mutex
+	var received []hooks.Payload
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload hooks.Payload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+	server := newWaitTestServer(t, map[string][]string{
+		"1": {"PROCESSING", "DONE"},
+		"2": {"FAILED"},
+	})
+	defer server.Close()
+
+	t.Cleanup(func() { hookFlags = config.Hooks{} })
+	hookFlags = config.Hooks{OnFailure: []string{webhook.URL}, OnComplete: []string{webhook.URL + "/all"}}
+
+	_, err := runWaitTest(t, server, "", "1", "2")
+	require.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	assert.Equal(t, "2", received[0].RunID)
+	assert.Equal(t, hooks.EventFailed, received[0].Event)
+	assert.Equal(t, "FAILED", received[0].Status)
+	assert.Equal(t, "tests failed", received[0].Error)
+	assert.Equal(t, "1", received[2].RunID)
+	assert.True(t, received[2].Success)
+	assert.Equal(t, hooks.EventSucceeded, received[2].Event)
+}
+
+func TestHookFlagsRequireFollowing(t *testing.T) {
+	t.Cleanup(func() { hookFlags = config.Hooks{} })
+	assert.NoError(t, requireHookFollow(false, "--follow"))
+
+	hookFlags.OnSuccess = []string{"make test"}
+	assert.ErrorContains(t, requireHookFollow(false, "--wait or --follow"), "require --wait or --follow")
+	assert.NoError(t, requireHookFollow(true, "--follow"))
+}
@@ -0,0 +1,262 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/importer"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+type importOptions struct {
+	from           string
+	repo           string
+	source         string
+	template       string
+	vars           []string
+	labels         []string
+	output         string
+	submit         bool
+	includeHandled bool
+}
+
+var importCmd = newImportCommand()
+
+func newImportCommand() *cobra.Command {
+	var opts importOptions
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Turn issue-tracker exports into runs",
+		Long: `Read an issue-tracker export and turn every issue into a run whose prompt
+holds the issue title, body and acceptance criteria.
+
+Supported exports:
+  github  JSON from 'gh issue list --json number,title,body,labels,url' or the REST API
+  jira    CSV export or JSON search results
+  linear  CSV export
+
+By default the runs are printed as a bulk YAML config. Use --output to write
+the config for review, or --submit to create the runs now. Issues written with
+--output or submitted are remembered, and later imports skip them unless
+--include-handled is set.`,
+		Example: `  repobird import issues.json --from github --output tasks.yaml
+  repobird import jira.csv --from jira --repo acme/webapp --label ready --submit
+  repobird import linear.csv --from linear --repo acme/webapp --template fix-issue --var team=web`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd.OutOrStdout(), args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.from, "from", "", "issue tracker of the export: github, jira or linear (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "repository to run the issues in (default from issue URLs, project defaults or the git remote)")
+	cmd.Flags().StringVarP(&opts.source, "source", "s", "", "source branch for the runs")
+	cmd.Flags().StringVar(&opts.template, "template", "", "render prompts with a named template (see 'repobird templates list')")
+	cmd.Flags().StringArrayVar(&opts.vars, "var", nil, "template variable as name=value (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.labels, "label", nil, "only import issues with this label (repeatable)")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "write the bulk YAML config to a file")
+	cmd.Flags().BoolVar(&opts.submit, "submit", false, "create the runs instead of writing a config")
+	cmd.Flags().BoolVar(&opts.includeHandled, "include-handled", false, "also import issues that were already exported or submitted")
+	cmd.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if an issue contains suspected secrets")
+	cmd.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if a run configuration has lint errors")
+	cmd.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the runs would exceed a configured credit budget")
+	_ = cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+func runImport(out io.Writer, file string, opts importOptions) error {
+	source, err := importer.ParseSource(opts.from)
+	if err != nil {
+		return err
+	}
+	if opts.submit && opts.output != "" {
+		return fmt.Errorf("--submit and --output cannot be used together")
+	}
+	if len(opts.vars) > 0 && opts.template == "" {
+		return fmt.Errorf("--var requires --template")
+	}
+
+	issues, err := importer.ParseFile(source, file)
+	if err != nil {
+		return err
+	}
+	issues = importer.FilterLabels(issues, opts.labels)
+
+	repository, err := importRepository(opts.repo, issues)
+	if err != nil {
+		return err
+	}
+
+	ledgerPath := cache.ImportLedgerPath()
+	ledger, err := cache.LoadImportLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+	var pending []importer.Issue
+	skipped, unkeyed := 0, 0
+	for _, issue := range issues {
+		if issue.Key == "" {
+			unkeyed++
+		}
+		if _, handled := ledger.Lookup(string(source), repository, issue.Key); handled && !opts.includeHandled {
+			skipped++
+			continue
+		}
+		pending = append(pending, issue)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s skipping %d issue(s) already imported into %s (use --include-handled to import them again)\n",
+			stderrStyle().Info("Note:"), skipped, repository)
+	}
+	if unkeyed > 0 {
+		fmt.Fprintf(os.Stderr, "%s %d issue(s) have no key and will not be remembered, so importing again repeats them\n",
+			stderrStyle().Info("Note:"), unkeyed)
+	}
+	if len(pending) == 0 {
+		return fmt.Errorf("no issues to import")
+	}
+
+	builder := &importer.Builder{}
+	if opts.template != "" {
+		if builder.Vars, err = templates.ParseVars(opts.vars); err != nil {
+			return err
+		}
+		library, err := templates.LoadDefault()
+		if err != nil {
+			return err
+		}
+		if builder.Template, err = library.Get(opts.template); err != nil {
+			return err
+		}
+	}
+	bulkConfig := &bulk.BulkConfig{
+		Repository: repository,
+		Source:     opts.source,
+		RunType:    "run",
+		BatchTitle: fmt.Sprintf("Imported %s issues", source),
+	}
+	for _, issue := range pending {
+		run, err := builder.Run(issue)
+		if err != nil {
+			return err
+		}
+		bulkConfig.Runs = append(bulkConfig.Runs, run)
+	}
+
+	if opts.submit {
+		return submitImportedRuns(bulkConfig, pending, source, ledger, ledgerPath)
+	}
+
+	data, err := yaml.Marshal(bulkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encode bulk config: %w", err)
+	}
+	if opts.output == "" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.output, err)
+	}
+	for _, issue := range pending {
+		ledger.Record(cache.ImportRecord{
+			Source:     string(source),
+			Repository: repository,
+			IssueKey:   issue.Key,
+			Title:      issue.Title,
+			State:      cache.ImportStateExported,
+			File:       opts.output,
+		})
+	}
+	if err := cache.SaveImportLedger(ledgerPath, ledger); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s Wrote %d runs to %s\nReview the file, then submit it with 'repobird bulk %s'\n",
+		styleFor(out).Success("✓"), len(bulkConfig.Runs), opts.output, opts.output)
+	return err
+}
+
+// importRepository picks the repository from --repo, the issue URLs, the
+// project defaults or the git remote, in that order.
+func importRepository(flag string, issues []importer.Issue) (string, error) {
+	if flag == "" {
+		if repository := importer.Repository(issues); repository != "" {
+			return repository, nil
+		}
+	}
+	return taskRepository(flag)
+}
+
+// taskRepository picks the repository for generated runs from the --repo
+// flag, the project defaults or the git remote, in that order.
+func taskRepository(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if defaults, err := resolveRunDefaults(""); err == nil && defaults.Repository != "" {
+		return defaults.Repository, nil
+	}
+	if gitService := getContainer().GitService(); gitService.IsGitRepository() {
+		if repository, err := gitService.GetRepositoryName(); err == nil {
+			return repository, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the repository; pass --repo owner/name")
+}
+
+// submitImportedRuns creates one run per issue and records each created run
+// in the ledger, so an interrupted submission can be repeated.
+func submitImportedRuns(bulkConfig *bulk.BulkConfig, issues []importer.Issue, source importer.Source, ledger *cache.ImportLedger, ledgerPath string) error {
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+
+	runService := getContainer().RunService()
+	styler := stdoutStyle()
+	fmt.Printf("Creating %d runs in %s...\n", len(bulkConfig.Runs), bulkConfig.Repository)
+	failed := 0
+	for i, bulkRun := range bulkConfig.Runs {
+		run, err := createImportedRun(runService, bulkRun.RunConfig(bulkConfig))
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %s\n", styler.Error("✗"), bulkRun.Title, errors.FormatUserError(err))
+			continue
+		}
+		ledger.Record(cache.ImportRecord{
+			Source:     string(source),
+			Repository: bulkConfig.Repository,
+			IssueKey:   issues[i].Key,
+			Title:      issues[i].Title,
+			State:      cache.ImportStateSubmitted,
+			RunID:      run.ID,
+		})
+		if err := cache.SaveImportLedger(ledgerPath, ledger); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+		fmt.Printf("%s %s: run %s\n", styler.Success("✓"), bulkRun.Title, run.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d runs could not be created; import again to retry them", failed, len(bulkConfig.Runs))
+	}
+	return nil
+}
+
+func createImportedRun(runService domain.RunService, runConfig *models.RunConfig) (*domain.Run, error) {
+	req, err := prepareRunRequest(runConfig)
+	if err != nil {
+		return nil, err
+	}
+	return runService.CreateRun(context.Background(), req)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+const importGitHubExport = `[
+  {"number": 42, "title": "Fix login redirect", "body": "Users land on /home.\n\n## Acceptance criteria\n- Redirect back",
+   "labels": [{"name": "ready"}], "html_url": "https://github.com/acme/webapp/issues/42"},
+  {"number": 43, "title": "Bump deps", "labels": [{"name": "chore"}], "html_url": "https://github.com/acme/webapp/issues/43"}
+]`
+
+func TestImportWritesBulkConfigAndSkipsHandledIssues(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	export := filepath.Join(dir, "issues.json")
+	require.NoError(t, os.WriteFile(export, []byte(importGitHubExport), 0644))
+	output := filepath.Join(dir, "tasks.yaml")
+
+	var out bytes.Buffer
+	require.NoError(t, runImport(&out, export, importOptions{from: "github", labels: []string{"ready"}, output: output}))
+	assert.Contains(t, out.String(), "Wrote 1 runs to "+output)
+
+	config, err := bulk.LoadBulkConfig([]string{output})
+	require.NoError(t, err)
+	assert.Equal(t, "acme/webapp", config.Repository)
+	require.Len(t, config.Runs, 1)
+	assert.Equal(t, "#42: Fix login redirect", config.Runs[0].Title)
+	assert.Contains(t, config.Runs[0].Prompt, "Acceptance criteria:\n- Redirect back")
+
+	ledger, err := cache.LoadImportLedger(cache.ImportLedgerPath())
+	require.NoError(t, err)
+	record, ok := ledger.Lookup("github", "acme/webapp", "42")
+	require.True(t, ok)
+	assert.Equal(t, cache.ImportStateExported, record.State)
+	assert.Equal(t, output, record.File)
+
+	// A second import only prints the issue that was not handled yet.
+	out.Reset()
+	require.NoError(t, runImport(&out, export, importOptions{from: "github"}))
+	assert.NotContains(t, out.String(), "Fix login redirect")
+	assert.Contains(t, out.String(), "#43: Bump deps")
+
+	out.Reset()
+	err = runImport(&out, export, importOptions{from: "github", labels: []string{"ready"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no issues to import")
+
+	out.Reset()
+	require.NoError(t, runImport(&out, export, importOptions{from: "github", labels: []string{"ready"}, includeHandled: true}))
+	assert.Contains(t, out.String(), "Fix login redirect")
+}
+
+func TestImportSubmitRecordsRuns(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/runs" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "acme/webapp", body["repositoryName"])
+		prompts = append(prompts, body["prompt"].(string))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"id": 100 + len(prompts), "status": "QUEUED", "repositoryName": "acme/webapp",
+		}})
+	}))
+	defer server.Close()
+
+	restore := configureRunWaitTest(t, server.URL)
+	defer restore()
+
+	export := filepath.Join(t.TempDir(), "issues.json")
+	require.NoError(t, os.WriteFile(export, []byte(importGitHubExport), 0644))
+
+	output := captureRunStdout(t, func() {
+		require.NoError(t, runImport(os.Stdout, export, importOptions{from: "github", submit: true}))
+	})
+	assert.Contains(t, output, "#42: Fix login redirect: run 101")
+	assert.Contains(t, output, "#43: Bump deps: run 102")
+	require.Len(t, prompts, 2)
+	assert.True(t, strings.HasPrefix(prompts[0], "Fix login redirect\n\nUsers land on /home."))
+
+	ledger, err := cache.LoadImportLedger(cache.ImportLedgerPath())
+	require.NoError(t, err)
+	record, ok := ledger.Lookup("github", "acme/webapp", "43")
+	require.True(t, ok)
+	assert.Equal(t, cache.ImportStateSubmitted, record.State)
+	assert.Equal(t, "102", record.RunID)
+}
+
+func TestImportSubmitKeepsTemplateRunSettings(t *testing.T) {
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/runs" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"id": 100 + len(bodies), "status": "QUEUED", "repositoryName": "acme/webapp",
+		}})
+	}))
+	defer server.Close()
+
+	restore := configureRunWaitTest(t, server.URL)
+	defer restore()
+
+	root := writeProjectCheckout(t, "")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".repobird", "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".repobird", "templates", "branch-issue.md"), []byte(`---
+baseBranch: develop
+prTargetBranch: release
+variables:
+  - name: title
+---
+Fix {{.title}}
+`), 0644))
+
+	export := filepath.Join(t.TempDir(), "issues.json")
+	require.NoError(t, os.WriteFile(export, []byte(`[{"number": 42, "title": "Fix login redirect", "html_url": "https://github.com/acme/webapp/issues/42"}]`), 0644))
+
+	captureRunStdout(t, func() {
+		require.NoError(t, runImport(os.Stdout, export, importOptions{from: "github", submit: true, template: "branch-issue"}))
+	})
+	require.Len(t, bodies, 1)
+	assert.Equal(t, "Fix Fix login redirect", bodies[0]["prompt"])
+	assert.Equal(t, "develop", bodies[0]["baseBranch"])
+	assert.Equal(t, "release", bodies[0]["prTargetBranch"])
+}
@@ -0,0 +1,285 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/lint"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/output"
+	"github.com/repobird/repobird-cli/internal/project"
+	"github.com/repobird/repobird-cli/internal/utils"
+	pkgutils "github.com/repobird/repobird-cli/pkg/utils"
+)
+
+const (
+	lintFailOnError   = "error"
+	lintFailOnWarning = "warning"
+	lintFailOnNever   = "never"
+)
+
+// skipLint disables the pre-submit lint check on run commands.
+var skipLint bool
+
+type lintOptions struct {
+	prompt  string
+	context string
+	repo    string
+	format  string
+	failOn  string
+}
+
+var lintCmd = newLintCommand()
+
+func newLintCommand() *cobra.Command {
+	opts := lintOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint [file]",
+		Short: "Check run configurations for common prompt and branch problems",
+		Long: `Check a run configuration for problems before submitting it.
+
+Rules:
+  prompt-too-short             prompt has only a few words
+  prompt-vague                 prompt is a generic request such as "fix the bug"
+  missing-acceptance-criteria  nothing says how to verify the change
+  missing-file                 a referenced path does not exist in the local checkout
+  conflicting-branches         branch settings contradict each other
+  oversized-context            context is larger than 64 KiB
+
+Bulk configuration files are linted run by run. Rule severities can be
+changed or rules turned off in the lint.rules section of .repobird.yaml:
+
+  lint:
+    rules:
+      missing-acceptance-criteria: off
+      prompt-too-short: error
+
+The same rules run before every 'repobird run'; error-level issues block the
+submission unless --skip-lint is passed.`,
+		Example: `  repobird lint task.yaml
+  repobird lint tasks.json --format sarif > repobird-lint.sarif
+  repobird lint -p "Fix the bug"
+  repobird lint task.md --fail-on warning`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonOutput {
+				opts.format = lint.FormatJSON
+			}
+			file := ""
+			if len(args) == 1 {
+				file = args[0]
+			}
+			return lintCommand(cmd.OutOrStdout(), file, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.prompt, "prompt", "p", "", "lint a prompt instead of a file (use @file to read from file, - for stdin)")
+	cmd.Flags().StringVar(&opts.context, "context", "", "context to lint with --prompt (use @file to read from file)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "repository to lint with --prompt (owner/repo)")
+	cmd.Flags().StringVar(&opts.format, "format", lint.FormatText, "output format: text, json, or sarif")
+	cmd.Flags().StringVar(&opts.failOn, "fail-on", lintFailOnError, "exit non-zero when issues reach this severity: error, warning, or never")
+	return cmd
+}
+
+func lintCommand(stdout io.Writer, file string, opts lintOptions) error {
+	if err := lint.ValidateFormat(opts.format); err != nil {
+		return err
+	}
+	threshold, err := lintFailThreshold(opts.failOn)
+	if err != nil {
+		return err
+	}
+
+	targets, err := lintTargets(file, opts)
+	if err != nil {
+		return err
+	}
+	linter, err := newProjectLinter()
+	if err != nil {
+		return err
+	}
+	issues := linter.Lint(targets...)
+
+	switch opts.format {
+	case lint.FormatJSON:
+		err = lint.WriteJSON(stdout, issues)
+	case lint.FormatSARIF:
+		err = lint.WriteSARIF(stdout, linter.Rules(), issues)
+	default:
+		err = writeLintText(stdout, issues)
+	}
+	if err != nil {
+		return err
+	}
+
+	if threshold != "" {
+		if count := lint.Count(issues, threshold); count > 0 {
+			return newExitError(ExitCodeGeneric, fmt.Sprintf("lint failed: %d issue(s) at %s level or above", count, threshold))
+		}
+	}
+	return nil
+}
+
+func lintFailThreshold(failOn string) (lint.Severity, error) {
+	switch failOn {
+	case lintFailOnError:
+		return lint.SeverityError, nil
+	case lintFailOnWarning:
+		return lint.SeverityWarning, nil
+	case lintFailOnNever:
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid --fail-on %q (use error, warning, or never)", failOn)
+	}
+}
+
+func lintTargets(file string, opts lintOptions) ([]lint.Target, error) {
+	if file != "" && opts.prompt != "" {
+		return nil, fmt.Errorf("provide either a configuration file or --prompt, not both")
+	}
+	if file == "" {
+		if opts.prompt == "" {
+			return nil, fmt.Errorf("provide a configuration file or --prompt to lint")
+		}
+		promptText, err := utils.ReadPromptInput(opts.prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt: %w", err)
+		}
+		contextText := ""
+		if opts.context != "" {
+			if contextText, err = utils.ReadPromptInput(opts.context); err != nil {
+				return nil, fmt.Errorf("failed to read context: %w", err)
+			}
+		}
+		return []lint.Target{{
+			Name:   "prompt",
+			Config: &models.RunConfig{Prompt: promptText, Context: contextText, Repository: opts.repo},
+		}}, nil
+	}
+
+	isBulk, err := bulk.IsBulkConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	if isBulk {
+		bulkConfig, err := bulk.ParseBulkConfig(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bulk configuration: %w", err)
+		}
+		return bulkLintTargets(file, bulkConfig), nil
+	}
+
+	runConfig, additionalContext, err := utils.LoadConfigFromFileNoPrompts(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration file: %w", err)
+	}
+	return []lint.Target{{Name: file, Config: runConfig, AdditionalContext: additionalContext}}, nil
+}
+
+// bulkLintTargets expands a bulk configuration into one target per run, with
+// the batch-level settings and the run's overrides applied.
+func bulkLintTargets(file string, config *bulk.BulkConfig) []lint.Target {
+	targets := make([]lint.Target, 0, len(config.Runs))
+	for i, run := range config.Runs {
+		targets = append(targets, lint.Target{
+			Name:   fmt.Sprintf("%s#runs[%d]", file, i),
+			Config: run.RunConfig(config),
+		})
+	}
+	return targets
+}
+
+// newProjectLinter creates a linter for the current directory, applying rule
+// severities from .repobird.yaml and checking referenced paths against the
+// enclosing git checkout, for runs targeting the repository of its remote.
+func newProjectLinter() (*lint.Linter, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	projectFile, projectPath, err := project.Load(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	env := lint.Env{}
+	if root := project.GitRoot(cwd); root != "" {
+		env.RepoRoot = root
+		env.FileExists = func(path string) bool {
+			_, err := os.Stat(filepath.Join(root, filepath.FromSlash(path)))
+			return err == nil
+		}
+		env.Repository, _ = pkgutils.DetectRepository()
+	}
+
+	linter, err := lint.New(env, projectFile.Lint.Rules)
+	if err != nil && projectPath != "" {
+		return nil, fmt.Errorf("%s: %w", projectPath, err)
+	}
+	return linter, err
+}
+
+func writeLintText(out io.Writer, issues []lint.Issue) error {
+	styler := styleFor(out)
+	for _, issue := range issues {
+		if _, err := fmt.Fprintf(out, "%s  %s  %s %s\n",
+			issue.Location(), lintSeverityLabel(styler, issue.Severity), issue.Message, styler.Muted("["+issue.RuleID+"]")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(out, lint.Summary(issues))
+	return err
+}
+
+func lintSeverityLabel(styler output.Styler, severity lint.Severity) string {
+	label := string(severity)
+	switch severity {
+	case lint.SeverityError:
+		return styler.Error(label)
+	case lint.SeverityWarning:
+		return styler.Warning(label)
+	default:
+		return styler.Info(label)
+	}
+}
+
+// checkSubmissionLint runs the lint rules before a run is submitted. Warnings
+// and errors are printed to stderr; error-level issues block the submission
+// unless skip is set.
+func checkSubmissionLint(runConfig *models.RunConfig, additionalContext string, skip bool) error {
+	if skip {
+		return nil
+	}
+	linter, err := newProjectLinter()
+	if err != nil {
+		return err
+	}
+	issues := linter.Lint(lint.Target{Config: runConfig, AdditionalContext: additionalContext})
+
+	var shown []string
+	for _, issue := range issues {
+		if issue.Severity.AtLeast(lint.SeverityWarning) {
+			shown = append(shown, fmt.Sprintf("  %s  %s  %s [%s]", issue.Location(), issue.Severity, issue.Message, issue.RuleID))
+		}
+	}
+	if len(shown) == 0 {
+		return nil
+	}
+
+	errorCount := lint.Count(issues, lint.SeverityError)
+	if errorCount == 0 {
+		fmt.Fprintf(os.Stderr, "%s run configuration has lint warnings:\n%s\n", stderrStyle().Warning("Warning:"), strings.Join(shown, "\n"))
+		return nil
+	}
+	return fmt.Errorf("submission blocked: %d lint error(s)\n%s\n\nFix the configuration or pass --skip-lint to submit anyway", errorCount, strings.Join(shown, "\n"))
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintCommandBulkFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`repository: acme/webapp
+runs:
+  - prompt: "Add pagination to the runs endpoint. Acceptance criteria: tests pass."
+  - prompt: fix the bug
+`), 0644))
+
+	var out bytes.Buffer
+	err := lintCommand(&out, path, lintOptions{format: "text", failOn: lintFailOnWarning})
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeGeneric, exitCodeForError(err))
+	assert.Contains(t, out.String(), path+"#runs[1]:prompt:1")
+	assert.Contains(t, out.String(), "[prompt-vague]")
+	assert.NotContains(t, out.String(), "#runs[0]")
+
+	out.Reset()
+	require.NoError(t, lintCommand(&out, path, lintOptions{format: "text", failOn: lintFailOnError}))
+}
+
+func TestLintCommandBulkFileAppliesRunOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`repository: acme/webapp
+runs:
+  - prompt: "Add pagination to the runs endpoint. Acceptance criteria: tests pass."
+    branchOnly: true
+    prTargetBranch: main
+`), 0644))
+
+	var out bytes.Buffer
+	err := lintCommand(&out, path, lintOptions{format: "text", failOn: lintFailOnError})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), path+"#runs[0]")
+	assert.Contains(t, out.String(), "[conflicting-branches]")
+}
+
+func TestProcessSingleRunBlocksLintErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	restore := configureRunWaitTest(t, server.URL)
+	defer restore()
+	originalSkipLint := skipLint
+	defer func() { skipLint = originalSkipLint }()
+	skipLint = false
+
+	runConfig := waitTestConfig()
+	runConfig.BranchOnly = true
+	runConfig.PRTargetBranch = "main"
+
+	err := processSingleRun(runConfig, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting-branches")
+	assert.Contains(t, err.Error(), "--skip-lint")
+	assert.Zero(t, atomic.LoadInt32(&requests))
+}
@@ -5,6 +5,7 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -19,12 +20,17 @@ import (
 	"github.com/repobird/repobird-cli/internal/api"
 	"github.com/repobird/repobird-cli/internal/errors"
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/transcript"
+	"github.com/repobird/repobird-cli/internal/usage"
 	"github.com/repobird/repobird-cli/internal/utils"
 )
 
 var (
-	logsJSON   bool
-	logsFollow bool
+	logsJSON    bool
+	logsFollow  bool
+	logsSummary bool
+	logsExport  string
+	logsOutput  string
 )
 
 var logsCmd = &cobra.Command{
@@ -34,7 +40,20 @@ var logsCmd = &cobra.Command{
 
 The current RepoBird API exposes logs as NDJSON through the agent-logs endpoint.
 Without --follow, the CLI fetches the current snapshot once. With --follow, the
-CLI polls for newer messages and writes NDJSON lines as they arrive.`,
+CLI polls for newer messages and writes NDJSON lines as they arrive.
+
+With --summary, the CLI prints total cost, token usage (input, output, and
+cache), and per-tool call counts and time instead of the messages.
+
+With --export md|html, the CLI writes a shareable transcript with the run
+header, prompt, context, and every message. Tool parameters and results are
+collapsible, secrets are redacted, and HTML exports embed their own styles so
+they open offline.`,
+	Example: `  repobird logs 123
+  repobird logs 123 --follow
+  repobird logs 123 --summary
+  repobird logs 123 --export md -o run-123.md
+  repobird logs 123 --export html -o run-123.html`,
 	Args: cobra.ExactArgs(1),
 	RunE: logsCommand,
 }
@@ -43,6 +62,10 @@ CLI polls for newer messages and writes NDJSON lines as they arrive.`,
 func init() {
 	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "output the current log snapshot as JSON")
 	logsCmd.Flags().BoolVar(&logsFollow, "follow", false, "poll for new log messages and output NDJSON")
+	logsCmd.Flags().BoolVar(&logsSummary, "summary", false, "print cost, token, and tool timing totals")
+	logsCmd.Flags().StringVar(&logsExport, "export", "", "export a transcript as md or html")
+	logsCmd.Flags().StringVarP(&logsOutput, "output", "o", "", "write the export to a file instead of stdout")
+	logsCmd.MarkFlagsMutuallyExclusive("follow", "summary", "export")
 }
 
 func logsCommand(_ *cobra.Command, args []string) error {
@@ -50,6 +73,14 @@ func logsCommand(_ *cobra.Command, args []string) error {
 		return errors.NoAPIKeyError()
 	}
 
+	if logsExport != "" {
+		if err := transcript.ValidateFormat(logsExport); err != nil {
+			return err
+		}
+	} else if logsOutput != "" {
+		return fmt.Errorf("--output requires --export")
+	}
+
 	client := api.NewClient(cfg.APIKey, utils.GetAPIURL(cfg.APIURL), cfg.Debug)
 	runID := args[0]
 	if logsFollow {
@@ -60,9 +91,35 @@ func logsCommand(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get run logs: %s", errors.FormatUserError(err))
 	}
+	if logsExport != "" {
+		run, err := client.GetRun(runID)
+		if err != nil {
+			return fmt.Errorf("failed to get run: %s", errors.FormatUserError(err))
+		}
+		return exportRunTranscript(os.Stdout, transcript.New(run, messages, time.Now()), logsExport, logsOutput)
+	}
+	if logsSummary {
+		return renderRunLogSummary(os.Stdout, runID, usage.Summarize(messages), logsJSON || jsonOutput)
+	}
 	return renderRunLogs(os.Stdout, messages, logsJSON)
 }
 
+func exportRunTranscript(stdout io.Writer, t transcript.Transcript, format, outputPath string) error {
+	if outputPath == "" {
+		return transcript.Write(stdout, t, format)
+	}
+
+	var buf bytes.Buffer
+	if err := transcript.Write(&buf, t, format); err != nil {
+		return err
+	}
+	if err := utils.WriteFileWithError(outputPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "%s %s\n", styleFor(stdout).Success("✓ Transcript written to"), outputPath)
+	return nil
+}
+
 func renderRunLogs(out io.Writer, messages []models.RunLogMessage, asJSON bool) error {
 	if asJSON {
 		encoder := json.NewEncoder(out)
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/usage"
 )
 
 func TestLogsCommandRequiresRunID(t *testing.T) {
@@ -132,3 +133,24 @@ func (c *staticRunLogClient) OpenRunLogs(context.Context, string, int) (io.ReadC
 func (c *staticRunLogClient) GetRunWithRetry(context.Context, string) (*models.RunResponse, error) {
 	return &models.RunResponse{Status: "DONE"}, nil
 }
+
+func TestRenderRunLogSummaryHumanOutput(t *testing.T) {
+	cost := 0.5
+	duration := 1200.0
+	messages := []models.RunLogMessage{
+		{Type: "assistant", Cost: &cost, Tokens: map[string]any{"input": float64(100), "output": float64(20)}},
+		{Type: "tool_call", ToolName: "bash", Duration: &duration},
+	}
+
+	var out bytes.Buffer
+	if err := renderRunLogSummary(&out, "123", usage.Summarize(messages), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"Usage for run", "$0.5000", "100 input, 20 output", "1 calls", "bash"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
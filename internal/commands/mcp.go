@@ -0,0 +1,505 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/mcp"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/utils"
+	"github.com/repobird/repobird-cli/pkg/version"
+)
+
+const (
+	mcpDefaultListLimit   = 20
+	mcpMaxListLimit       = 100
+	mcpDefaultLogTail     = 100
+	mcpDefaultWaitSeconds = 600
+	mcpMaxWaitSeconds     = 3600
+)
+
+const mcpInstructions = `RepoBird runs cloud coding agents that work on a GitHub repository and open a
+pull request or push a branch. Runs are asynchronous: create_run returns at once
+with the run ID; use wait_for_run or get_run to follow it, get_logs to see what
+the agent is doing, and get_diff to review its changes.
+
+Call create_run with dryRun first to validate a run and see its cost estimate.
+Submissions are checked against the user's credit budgets, the duplicate
+submission guard and the secret scanner; a blocked submission returns an error
+explaining why and must not be retried unchanged.`
+
+// runConfigDescriptions describe the create_run properties derived from
+// models.RunConfig.
+var runConfigDescriptions = map[string]string{
+	"prompt":                            "Task for the agent: what to change and how to verify it",
+	"repository":                        "Repository as owner/repo; defaults to the configured default repository",
+	"source":                            "Legacy name of baseBranch",
+	"target":                            "Legacy name of outputBranch",
+	"baseBranch":                        "Branch the agent starts from; defaults to the repository default branch",
+	"outputMode":                        "pull_request opens a pull request; branch only pushes the output branch",
+	"outputBranch":                      "Branch the agent pushes to; generated when empty",
+	"prTargetBranch":                    "Branch the pull request targets; defaults to baseBranch",
+	"outputBranchPolicy":                "What to do when outputBranch already exists",
+	"runType":                           "run (default), basic or pro; plan is only available where plan runs are enabled",
+	"model":                             "OpenCode model ID or model profile name",
+	"provider":                          "OpenCode provider for the model",
+	"title":                             "Run title; generated when empty",
+	"context":                           "Additional context or instructions for the agent",
+	"files":                             "Repository files the agent should focus on",
+	"providerCredentialId":              "ID of a stored provider credential to run with",
+	"providerMode":                      "How the provider credential is used",
+	"gitlabCredential":                  "GitLab token reference for GitLab repositories",
+	"gitlabCredential.tokenReferenceId": "ID of the stored GitLab token",
+	"branchOnly":                        "Push the output branch without opening a pull request",
+	"acknowledgePromptRisk":             "Submit even if the server flags the prompt as risky",
+	"idempotencyKey":                    "Key that makes retried submissions return the same run",
+}
+
+// mcpClient is the API the MCP tools use beyond the run service.
+type mcpClient interface {
+	ListRepositories(ctx context.Context) ([]models.APIRepository, error)
+	GetRunLogs(ctx context.Context, id string, afterSeq int) ([]models.RunLogMessage, error)
+	GetRunDiff(ctx context.Context, id string) (string, error)
+	CancelRun(ctx context.Context, id string) error
+}
+
+var (
+	mcpCmd = newMCPCommand()
+	// newMCPClient returns the API client the MCP tools use.
+	newMCPClient = func() (mcpClient, error) {
+		return newRepoAPIClient()
+	}
+)
+
+func newMCPCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve RepoBird tools to coding agents over the Model Context Protocol",
+		Long: `Speak the Model Context Protocol over stdin and stdout, so coding agents can
+create and follow RepoBird runs through tools instead of shelling out.
+
+Tools: create_run (with dryRun), get_run, list_runs, get_logs, wait_for_run,
+cancel_run, list_repositories and get_diff.
+
+Runs created through MCP go through the same checks as 'repobird run':
+validation, lint errors, the secret scanner, credit budgets, max_active_runs
+and the duplicate submission guard. There is no way to override them from a
+tool call. Diagnostics are written to stderr; stdout carries only protocol
+messages.`,
+		Example: `  # Register with an MCP client configuration
+  {"mcpServers": {"repobird": {"command": "repobird", "args": ["mcp"]}}}`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if cfg.APIKey == "" {
+				return errors.NoAPIKeyError()
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// Protocol messages own stdout; anything else printed on the way
+			// goes to stderr.
+			protocolOut := os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = protocolOut }()
+			return newMCPServer().Serve(ctx, cmd.InOrStdin(), protocolOut)
+		},
+	}
+}
+
+// newMCPServer returns the MCP server with the RepoBird tools.
+func newMCPServer() *mcp.Server {
+	server := mcp.NewServer("repobird", version.GetVersion(), mcpInstructions)
+
+	createSchema := mcp.SchemaFor(models.RunConfig{}, runConfigDescriptions)
+	createSchema.Required = []string{"prompt"}
+	createSchema.Properties["runType"].Enum = []string{"run", "basic", "pro", "plan"}
+	createSchema.Properties["outputMode"].Enum = []string{"pull_request", "branch"}
+	createSchema.Properties["dryRun"] = mcp.Boolean("Validate the run and estimate its cost without creating it")
+
+	runID := func() map[string]*mcp.Schema {
+		return map[string]*mcp.Schema{"runId": mcp.String("Run ID")}
+	}
+	waitProperties := runID()
+	waitProperties["timeoutSeconds"] = mcp.Integer(fmt.Sprintf("How long to wait, at most %d seconds (default %d)", mcpMaxWaitSeconds, mcpDefaultWaitSeconds), 1)
+	logProperties := runID()
+	logProperties["afterSeq"] = mcp.Integer("Only return log messages after this sequence number", 0)
+	logProperties["tail"] = mcp.Integer(fmt.Sprintf("Return at most this many of the newest messages (default %d)", mcpDefaultLogTail), 1)
+
+	server.AddTool(mcp.Tool{
+		Name:        "create_run",
+		Title:       "Create run",
+		Description: "Submit a task to a RepoBird cloud agent, or validate it with dryRun. Returns the created run.",
+		InputSchema: createSchema,
+		Handler:     mcpCreateRun,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_run",
+		Title:       "Get run",
+		Description: "Get the current status, branches, pull request URL and error of a run.",
+		InputSchema: mcp.Object(runID(), "runId"),
+		Handler:     mcpGetRun,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "list_runs",
+		Title:       "List runs",
+		Description: "List the newest runs, optionally for one repository or status.",
+		InputSchema: mcp.Object(map[string]*mcp.Schema{
+			"repository": mcp.String("Only runs for this repository (owner/repo)"),
+			"status":     mcp.String("Only runs with this status, such as QUEUED, PROCESSING, DONE or FAILED"),
+			"limit":      mcp.Integer(fmt.Sprintf("Number of runs, at most %d (default %d)", mcpMaxListLimit, mcpDefaultListLimit), 1),
+		}),
+		Handler: mcpListRuns,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_logs",
+		Title:       "Get run logs",
+		Description: "Get the agent log of a run: messages, tool calls and their results.",
+		InputSchema: mcp.Object(logProperties, "runId"),
+		Handler:     mcpGetLogs,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "wait_for_run",
+		Title:       "Wait for run",
+		Description: "Wait until a run finishes or the timeout passes, and return its final or last seen state.",
+		InputSchema: mcp.Object(waitProperties, "runId"),
+		Handler:     mcpWaitForRun,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "cancel_run",
+		Title:       "Cancel run",
+		Description: "Cancel a run that has not finished.",
+		InputSchema: mcp.Object(runID(), "runId"),
+		Handler:     mcpCancelRun,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "list_repositories",
+		Title:       "List repositories",
+		Description: "List the repositories RepoBird can run on.",
+		InputSchema: mcp.Object(map[string]*mcp.Schema{
+			"query": mcp.String("Only repositories whose name contains this text"),
+		}),
+		Handler: mcpListRepositories,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_diff",
+		Title:       "Get run diff",
+		Description: "Get the unified diff of the changes a run made.",
+		InputSchema: mcp.Object(runID(), "runId"),
+		Handler:     mcpGetDiff,
+	})
+	return server
+}
+
+// decodeMCPArgs decodes tool arguments, rejecting unknown properties.
+func decodeMCPArgs(args json.RawMessage, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return mcp.InvalidParams("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+type mcpRunArgs struct {
+	RunID string `json:"runId"`
+}
+
+func decodeMCPRunID(args json.RawMessage, v any, runID *string) error {
+	if err := decodeMCPArgs(args, v); err != nil {
+		return err
+	}
+	*runID = strings.TrimSpace(*runID)
+	if *runID == "" {
+		return mcp.InvalidParams("runId is required")
+	}
+	return nil
+}
+
+// mcpRunOutput is a run returned by the MCP tools.
+type mcpRunOutput struct {
+	Run runJSON `json:"run"`
+	URL string  `json:"url,omitempty"`
+}
+
+func makeMCPRunOutput(run *domain.Run) mcpRunOutput {
+	output := mcpRunOutput{Run: makeRunJSON(run)}
+	if urlID := createdRunURLID(run); urlID != "" {
+		output.URL = utils.GenerateRepoBirdURL(urlID)
+	}
+	return output
+}
+
+func mcpCreateRun(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in struct {
+		models.RunConfig
+		DryRun bool `json:"dryRun"`
+	}
+	if err := decodeMCPArgs(args, &in); err != nil {
+		return nil, err
+	}
+	runConfig := in.RunConfig
+	if runConfig.RunType == "" {
+		runConfig.RunType = string(models.RunTypeRun)
+	}
+	if runConfig.RunType == string(models.RunTypePlan) && !config.IsPlanRunsEnabled() {
+		return nil, fmt.Errorf("%s", config.PlanRunsUnavailableMessage())
+	}
+
+	// Validation, lint errors and the secret scanner; a dry run only
+	// estimates the cost, a real one is also checked against the budgets
+	if in.DryRun {
+		req, err := validateRunRequest(&runConfig, true)
+		if err != nil {
+			return nil, err
+		}
+		decision, err := estimateRunBudget(ctx, req.RepositoryName)
+		if err != nil {
+			printBudgetWarning(fmt.Errorf("could not estimate the run cost: %s", errors.FormatUserError(err)))
+		}
+		return mcp.JSONResult(makeRunDryRunJSON(req, decision))
+	}
+	req, err := prepareRunRequest(&runConfig)
+	if err != nil {
+		return nil, err
+	}
+	if capacityLimited(req.RepositoryName) {
+		if _, err := applyRunCapacity(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	if err := reserveRunSubmission(req, false); err != nil {
+		return nil, err
+	}
+	run, err := getContainer().RunService().CreateRun(ctx, req)
+	recordRunSubmission(runSubmissionGuard(), req.IdempotencyKey, run, err)
+	if err != nil {
+		return nil, fmt.Errorf("%s", errors.FormatUserError(err))
+	}
+	return mcp.JSONResult(makeRunCreateJSON(run, req))
+}
+
+func mcpGetRun(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in mcpRunArgs
+	if err := decodeMCPRunID(args, &in, &in.RunID); err != nil {
+		return nil, err
+	}
+	run, err := getContainer().RunService().GetRun(ctx, in.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %s: %s", in.RunID, errors.FormatUserError(err))
+	}
+	return mcp.JSONResult(makeMCPRunOutput(run))
+}
+
+func mcpListRuns(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in struct {
+		Repository string `json:"repository"`
+		Status     string `json:"status"`
+		Limit      int    `json:"limit"`
+	}
+	if err := decodeMCPArgs(args, &in); err != nil {
+		return nil, err
+	}
+	limit := in.Limit
+	if limit <= 0 {
+		limit = mcpDefaultListLimit
+	}
+	limit = min(limit, mcpMaxListLimit)
+	fetch := limit
+	if in.Repository != "" || in.Status != "" {
+		fetch = mcpMaxListLimit
+	}
+
+	runs, err := getContainer().RunService().ListRuns(ctx, domain.ListOptions{Limit: fetch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %s", errors.FormatUserError(err))
+	}
+	output := struct {
+		Runs []mcpRunOutput `json:"runs"`
+	}{Runs: []mcpRunOutput{}}
+	for _, run := range runs {
+		if len(output.Runs) == limit {
+			break
+		}
+		if in.Repository != "" && !strings.EqualFold(run.RepositoryName, in.Repository) {
+			continue
+		}
+		if in.Status != "" && !strings.EqualFold(run.Status, in.Status) && !strings.EqualFold(formatStatusForDisplay(run.Status), in.Status) {
+			continue
+		}
+		output.Runs = append(output.Runs, makeMCPRunOutput(run))
+	}
+	return mcp.JSONResult(output)
+}
+
+func mcpGetLogs(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in struct {
+		RunID    string `json:"runId"`
+		AfterSeq int    `json:"afterSeq"`
+		Tail     int    `json:"tail"`
+	}
+	if err := decodeMCPRunID(args, &in, &in.RunID); err != nil {
+		return nil, err
+	}
+	client, err := newMCPClient()
+	if err != nil {
+		return nil, err
+	}
+	messages, err := client.GetRunLogs(ctx, in.RunID, in.AfterSeq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs for run %s: %s", in.RunID, errors.FormatUserError(err))
+	}
+	tail := in.Tail
+	if tail <= 0 {
+		tail = mcpDefaultLogTail
+	}
+	output := struct {
+		RunID    string                 `json:"runId"`
+		Total    int                    `json:"total"`
+		Messages []models.RunLogMessage `json:"messages"`
+	}{RunID: in.RunID, Total: len(messages), Messages: messages}
+	if len(messages) > tail {
+		output.Messages = messages[len(messages)-tail:]
+	}
+	if output.Messages == nil {
+		output.Messages = []models.RunLogMessage{}
+	}
+	return mcp.JSONResult(output)
+}
+
+func mcpWaitForRun(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in struct {
+		RunID          string `json:"runId"`
+		TimeoutSeconds int    `json:"timeoutSeconds"`
+	}
+	if err := decodeMCPRunID(args, &in, &in.RunID); err != nil {
+		return nil, err
+	}
+	seconds := in.TimeoutSeconds
+	if seconds <= 0 {
+		seconds = mcpDefaultWaitSeconds
+	}
+	seconds = min(seconds, mcpMaxWaitSeconds)
+
+	run, timedOut, err := waitForRunTerminal(ctx, getContainer().RunService(), in.RunID, time.Duration(seconds)*time.Second)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil && !timedOut {
+		return nil, fmt.Errorf("failed to wait for run %s: %s", in.RunID, errors.FormatUserError(err))
+	}
+
+	output := runWaitJSONOutput{
+		Schema:    "repobird.run.wait.v1",
+		Operation: "run.wait",
+		Run:       makeRunJSON(run),
+		ExitCode:  exitCodeForFinalRun(run),
+		TimedOut:  timedOut,
+	}
+	if run != nil {
+		output.Status = run.Status
+		output.Error = run.Error
+		if urlID := createdRunURLID(run); urlID != "" {
+			output.URL = utils.GenerateRepoBirdURL(urlID)
+		}
+		if run.IsTerminal() {
+			fireCompletionHooks(runHookPayload(run))
+		}
+	}
+	if timedOut {
+		output.ExitCode = ExitCodeTimeout
+		output.Error = fmt.Sprintf("timed out waiting for run %s after %ds", in.RunID, seconds)
+	}
+	output.Success = output.ExitCode == ExitCodeSuccess
+	return mcp.JSONResult(output)
+}
+
+func mcpCancelRun(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in mcpRunArgs
+	if err := decodeMCPRunID(args, &in, &in.RunID); err != nil {
+		return nil, err
+	}
+	client, err := newMCPClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.CancelRun(ctx, in.RunID); err != nil {
+		return nil, fmt.Errorf("failed to cancel run %s: %s", in.RunID, errors.FormatUserError(err))
+	}
+	return mcp.JSONResult(map[string]any{"runId": in.RunID, "cancelled": true})
+}
+
+func mcpListRepositories(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in struct {
+		Query string `json:"query"`
+	}
+	if err := decodeMCPArgs(args, &in); err != nil {
+		return nil, err
+	}
+	client, err := newMCPClient()
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := client.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %s", errors.FormatUserError(err))
+	}
+
+	type repositoryOutput struct {
+		ID            int    `json:"id"`
+		Name          string `json:"name"`
+		DefaultBranch string `json:"defaultBranch,omitempty"`
+		Enabled       bool   `json:"enabled"`
+	}
+	output := struct {
+		Repositories []repositoryOutput `json:"repositories"`
+	}{Repositories: []repositoryOutput{}}
+	query := strings.ToLower(in.Query)
+	for _, repository := range repositories {
+		name := repository.FullName()
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		output.Repositories = append(output.Repositories, repositoryOutput{
+			ID:            repository.ID,
+			Name:          name,
+			DefaultBranch: repository.DefaultBranch,
+			Enabled:       repository.IsEnabled,
+		})
+	}
+	return mcp.JSONResult(output)
+}
+
+func mcpGetDiff(ctx context.Context, args json.RawMessage) (*mcp.Result, error) {
+	var in mcpRunArgs
+	if err := decodeMCPRunID(args, &in, &in.RunID); err != nil {
+		return nil, err
+	}
+	client, err := newMCPClient()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := client.GetRunDiff(ctx, in.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the diff of run %s: %s", in.RunID, errors.FormatUserError(err))
+	}
+	if strings.TrimSpace(diff) == "" {
+		return mcp.TextResult(fmt.Sprintf("Run %s has no changes.", in.RunID)), nil
+	}
+	return mcp.TextResult(diff), nil
+}
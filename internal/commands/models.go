@@ -0,0 +1,287 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// Profile sources shown by `repobird models`.
+const profileSourceBuiltin = "built-in"
+
+var (
+	runModel      string
+	runProvider   string
+	modelsRefresh bool
+)
+
+// fetchModelList fetches the allowed models from the API. Tests replace it.
+var fetchModelList = func() ([]models.ModelInfo, error) {
+	client := api.NewClient(cfg.APIKey, utils.GetAPIURL(cfg.APIURL), cfg.Debug)
+	return client.ListModels()
+}
+
+// modelProfile is a named model selection and where it was defined.
+type modelProfile struct {
+	Name string `json:"name"`
+	project.ModelProfile
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+}
+
+// loadModelProfiles returns the built-in basic and pro profiles overlaid with
+// user config profiles, then project profiles. Names are case-insensitive.
+func loadModelProfiles() (map[string]modelProfile, error) {
+	profiles := make(map[string]modelProfile)
+	for name, preset := range runPresets {
+		profiles[name] = modelProfile{
+			Name:         name,
+			ModelProfile: project.ModelProfile{Model: preset.Model, Provider: preset.Provider},
+			Source:       profileSourceBuiltin,
+		}
+	}
+	if cfg != nil && cfg.Config != nil {
+		for name, profile := range cfg.ModelProfiles {
+			name = strings.ToLower(name)
+			profiles[name] = modelProfile{
+				Name:         name,
+				ModelProfile: project.ModelProfile{Model: profile.Model, Provider: profile.Provider},
+				Source:       project.SourceUser,
+				Path:         config.FileUsed(),
+			}
+		}
+	}
+
+	projectFile, projectPath, err := loadProjectFile()
+	if err != nil {
+		return nil, err
+	}
+	for name, profile := range projectFile.ModelProfiles {
+		name = strings.ToLower(name)
+		profiles[name] = modelProfile{Name: name, ModelProfile: profile, Source: project.SourceProject, Path: projectPath}
+	}
+	return profiles, nil
+}
+
+// selectRunModel returns the model and provider for a run. A model or
+// provider set on the run wins, then the --basic or --pro profile, then the
+// project and user defaults. Profile names are expanded at every level.
+func selectRunModel(runConfig *models.RunConfig, defaults project.Defaults, profiles map[string]modelProfile) project.ModelProfile {
+	selection := project.ModelProfile{Model: runConfig.Model, Provider: runConfig.Provider}
+	if selection.Model == "" && selection.Provider == "" {
+		if _, ok := runPresets[runConfig.RunType]; ok {
+			selection = profiles[runConfig.RunType].ModelProfile
+		} else {
+			selection = project.ModelProfile{Model: defaults.Model, Provider: defaults.Provider}
+		}
+	}
+	if profile, ok := profiles[strings.ToLower(selection.Model)]; ok {
+		selection.Model = profile.Model
+		if selection.Provider == "" {
+			selection.Provider = profile.Provider
+		}
+	}
+	return selection
+}
+
+// modelCatalog is the allowed model list used for validation. It is not
+// authoritative when the list could not be fetched; unknown models then only
+// produce a warning against a stale cached list, and are allowed without one
+// when the list was never fetched.
+type modelCatalog struct {
+	Models        []models.ModelInfo
+	FetchedAt     time.Time
+	Authoritative bool
+}
+
+// loadModelCatalog returns the cached model list, fetching it when the cache
+// is older than cache.ModelListTTL or refresh is set. A failed fetch, such as
+// a 404 from an API without the model list, is not repeated for
+// cache.ModelListFailureTTL.
+func loadModelCatalog(refresh bool) modelCatalog {
+	var cached *cache.ModelList
+	path, err := cache.ModelListPath()
+	if err == nil {
+		if cached, err = cache.LoadModelList(path); err != nil {
+			cached = nil
+		}
+	}
+	now := time.Now()
+	if !refresh && cached.Fresh(now) {
+		return modelCatalog{Models: cached.Models, FetchedAt: cached.FetchedAt, Authoritative: true}
+	}
+
+	if refresh || !cached.RecentlyFailed(now) {
+		fetched, err := fetchModelList()
+		if err == nil && len(fetched) > 0 {
+			list := &cache.ModelList{Models: fetched, FetchedAt: now}
+			if path != "" {
+				_ = cache.SaveModelList(path, list)
+			}
+			return modelCatalog{Models: fetched, FetchedAt: now, Authoritative: true}
+		}
+		failed := &cache.ModelList{FailedAt: now}
+		if cached != nil {
+			failed.Models, failed.FetchedAt = cached.Models, cached.FetchedAt
+		}
+		if path != "" {
+			_ = cache.SaveModelList(path, failed)
+		}
+	}
+	if cached != nil && len(cached.Models) > 0 {
+		return modelCatalog{Models: cached.Models, FetchedAt: cached.FetchedAt}
+	}
+
+	var builtin []models.ModelInfo
+	for _, name := range []string{"basic", "pro"} {
+		preset := runPresets[name]
+		builtin = append(builtin, models.ModelInfo{ID: preset.Model, Provider: preset.Provider, Name: modelDisplayName(preset.Model)})
+	}
+	return modelCatalog{Models: builtin}
+}
+
+// validate checks a model selection against the catalog. Problems are
+// errors when the catalog is authoritative and warnings otherwise.
+func (c modelCatalog) validate(selection project.ModelProfile) (warning string, err error) {
+	problem := c.check(selection)
+	if problem == "" || (!c.Authoritative && c.FetchedAt.IsZero()) {
+		return "", nil
+	}
+	if c.Authoritative {
+		return "", fmt.Errorf("%s; run 'repobird models' to list allowed models", problem)
+	}
+	return problem + " (could not verify against the current model list)", nil
+}
+
+func (c modelCatalog) check(selection project.ModelProfile) string {
+	if selection.Model == "" {
+		if selection.Provider == "" {
+			return ""
+		}
+		for _, info := range c.Models {
+			if info.Provider == selection.Provider {
+				return ""
+			}
+		}
+		return fmt.Sprintf("unknown provider %q", selection.Provider)
+	}
+
+	ids := make([]string, 0, len(c.Models))
+	for _, info := range c.Models {
+		if info.ID != selection.Model {
+			ids = append(ids, info.ID)
+			continue
+		}
+		if selection.Provider != "" && info.Provider != "" && selection.Provider != info.Provider {
+			return fmt.Sprintf("model %q is served by provider %q, not %q", info.ID, info.Provider, selection.Provider)
+		}
+		return ""
+	}
+	problem := fmt.Sprintf("unknown model %q", selection.Model)
+	if suggestion := utils.SuggestFieldName(selection.Model, ids); suggestion != "" {
+		problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return problem
+}
+
+// checkRunModel validates the selected model before submission.
+func checkRunModel(selection project.ModelProfile) error {
+	if selection.Model == "" && selection.Provider == "" {
+		return nil
+	}
+	warning, err := loadModelCatalog(false).validate(selection)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "%s %s\n", stderrStyle().Warning("Warning:"), warning)
+	}
+	return nil
+}
+
+var modelsCmd = newModelsCommand()
+
+func newModelsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List allowed models and model profiles",
+		Long: `List the OpenCode models runs may select with --model, and the model
+profiles defined in the user config and project .repobird.yaml.
+
+The model list is cached for 24 hours, and a failed fetch is not retried for
+24 hours either; use --refresh to fetch it now.`,
+		Example: `  repobird models
+  repobird models --refresh --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.APIKey == "" {
+				return errors.NoAPIKeyError()
+			}
+			catalog := loadModelCatalog(modelsRefresh)
+			profiles, err := loadModelProfiles()
+			if err != nil {
+				return err
+			}
+			profileList := make([]modelProfile, 0, len(profiles))
+			for _, profile := range profiles {
+				profileList = append(profileList, profile)
+			}
+			sort.Slice(profileList, func(i, j int) bool { return profileList[i].Name < profileList[j].Name })
+
+			if jsonOutput {
+				return printModelsJSON(cmd.OutOrStdout(), catalog, profileList)
+			}
+			return printModels(cmd.OutOrStdout(), catalog, profileList)
+		},
+	}
+	cmd.Flags().BoolVar(&modelsRefresh, "refresh", false, "fetch the model list instead of using the cache")
+	return cmd
+}
+
+func printModels(out io.Writer, catalog modelCatalog, profiles []modelProfile) error {
+	styler := styleFor(out)
+	_, _ = fmt.Fprintln(out, styler.Heading("Models:"))
+	if !catalog.Authoritative {
+		_, _ = fmt.Fprintf(out, "%s\n", styler.Warning("Could not fetch the model list; showing cached or built-in models."))
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MODEL\tPROVIDER\tNAME")
+	for _, info := range catalog.Models {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", info.ID, valueOrDash(info.Provider), valueOrDash(info.Name))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%s\n", styler.Heading("Profiles:"))
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tMODEL\tPROVIDER\tSOURCE")
+	for _, profile := range profiles {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", profile.Name, profile.Model, valueOrDash(profile.Provider), profile.Source)
+	}
+	return w.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
+)
+
+func stubModelList(t *testing.T, list []models.ModelInfo, err error) *int {
+	t.Helper()
+	isolateUserCache(t)
+	calls := 0
+	original := fetchModelList
+	fetchModelList = func() ([]models.ModelInfo, error) {
+		calls++
+		return list, err
+	}
+	t.Cleanup(func() { fetchModelList = original })
+	return &calls
+}
+
+func TestSelectRunModelPrecedenceAndProfiles(t *testing.T) {
+	writeProjectCheckout(t, `modelProfiles:
+  chore:
+    model: openrouter/cheap/model
+    provider: openrouter
+`)
+	ensureRunTestConfig()
+	originalProfiles := cfg.ModelProfiles
+	defer func() { cfg.ModelProfiles = originalProfiles }()
+	cfg.ModelProfiles = map[string]config.ModelProfile{"refactor": {Model: "openrouter/strong/model"}}
+
+	profiles, err := loadModelProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, project.SourceProject, profiles["chore"].Source)
+	assert.Equal(t, project.SourceUser, profiles["refactor"].Source)
+	assert.Equal(t, profileSourceBuiltin, profiles["pro"].Source)
+
+	defaults := project.Defaults{Model: "chore"}
+	tests := []struct {
+		name      string
+		runConfig models.RunConfig
+		want      project.ModelProfile
+	}{
+		{"default profile", models.RunConfig{RunType: "run"}, project.ModelProfile{Model: "openrouter/cheap/model", Provider: "openrouter"}},
+		{"run profile", models.RunConfig{RunType: "run", Model: "Refactor"}, project.ModelProfile{Model: "openrouter/strong/model"}},
+		{"explicit model", models.RunConfig{RunType: "run", Model: "openrouter/x/y", Provider: "other"}, project.ModelProfile{Model: "openrouter/x/y", Provider: "other"}},
+		{"preset", models.RunConfig{RunType: "pro"}, profiles["pro"].ModelProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selectRunModel(&tt.runConfig, defaults, profiles))
+		})
+	}
+}
+
+func TestModelCatalogValidation(t *testing.T) {
+	allowed := []models.ModelInfo{{ID: "openrouter/z-ai/glm-5.2", Provider: "openrouter"}}
+
+	t.Run("authoritative list rejects unknown models", func(t *testing.T) {
+		calls := stubModelList(t, allowed, nil)
+		catalog := loadModelCatalog(false)
+		require.True(t, catalog.Authoritative)
+
+		_, err := catalog.validate(project.ModelProfile{Model: "openrouter/z-ai/glm-5.3"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `did you mean "openrouter/z-ai/glm-5.2"`)
+
+		_, err = catalog.validate(project.ModelProfile{Model: "openrouter/z-ai/glm-5.2", Provider: "anthropic"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "served by provider")
+
+		warning, err := catalog.validate(project.ModelProfile{Model: "openrouter/z-ai/glm-5.2"})
+		require.NoError(t, err)
+		assert.Empty(t, warning)
+
+		// The second load is served from the cache.
+		loadModelCatalog(false)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("fetch failure allows unknown models and is not retried", func(t *testing.T) {
+		calls := stubModelList(t, nil, errors.New("HTTP 404"))
+		catalog := loadModelCatalog(false)
+		assert.False(t, catalog.Authoritative)
+
+		warning, err := catalog.validate(project.ModelProfile{Model: "openrouter/new/model"})
+		require.NoError(t, err)
+		assert.Empty(t, warning)
+
+		loadModelCatalog(false)
+		assert.Equal(t, 1, *calls)
+		loadModelCatalog(true)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("stale cache is used when the fetch fails", func(t *testing.T) {
+		calls := stubModelList(t, nil, errors.New("offline"))
+		stale := &cache.ModelList{Models: allowed, FetchedAt: time.Now().Add(-2 * cache.ModelListTTL)}
+		path, err := cache.ModelListPath()
+		require.NoError(t, err)
+		require.NoError(t, cache.SaveModelList(path, stale))
+
+		catalog := loadModelCatalog(false)
+		assert.False(t, catalog.Authoritative)
+		assert.Equal(t, allowed, catalog.Models)
+		warning, err := catalog.validate(project.ModelProfile{Model: "openrouter/new/model"})
+		require.NoError(t, err)
+		assert.Contains(t, warning, "could not verify")
+
+		catalog = loadModelCatalog(false)
+		assert.Equal(t, allowed, catalog.Models)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("nothing is cached without a home directory", func(t *testing.T) {
+		calls := stubModelList(t, allowed, nil)
+		t.Setenv("XDG_CACHE_HOME", "")
+		t.Setenv("HOME", "")
+		dir := t.TempDir()
+		t.Chdir(dir)
+
+		_, err := cache.ModelListPath()
+		require.Error(t, err)
+		assert.True(t, loadModelCatalog(false).Authoritative)
+		assert.NoDirExists(t, filepath.Join(dir, ".cache"))
+		loadModelCatalog(false)
+		assert.Equal(t, 2, *calls)
+	})
+}
+
+func TestProcessSingleRunRejectsUnknownModel(t *testing.T) {
+	restore := configureRunWaitTest(t, "http://127.0.0.1:0")
+	defer restore()
+	stubModelList(t, []models.ModelInfo{{ID: "openrouter/z-ai/glm-5.2", Provider: "openrouter"}}, nil)
+	dryRun = true
+
+	runConfig := waitTestConfig()
+	runConfig.Model = "openrouter/unknown/model"
+	err := processSingleRun(runConfig, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown model "openrouter/unknown/model"`)
+}
+
+func TestUserRepositoryDefaultsApplyToMatchingRepository(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ensureRunTestConfig()
+	originalDefaults := cfg.Defaults
+	defer func() { cfg.Defaults = originalDefaults }()
+	cfg.Defaults = config.RunDefaults{
+		Model: "basic",
+		Repositories: []config.RepositoryDefaults{
+			{Repository: "acme/monorepo", Model: "pro"},
+		},
+	}
+
+	defaults, err := resolveRunDefaults("Acme/Monorepo")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", defaults.Model)
+
+	defaults, err = resolveRunDefaults("acme/webapp")
+	require.NoError(t, err)
+	assert.Equal(t, "basic", defaults.Model)
+}
+
+func TestPrintModelsJSON(t *testing.T) {
+	catalog := modelCatalog{Models: []models.ModelInfo{{ID: "openrouter/z-ai/glm-5.2"}}, Authoritative: true, FetchedAt: time.Now()}
+	profiles := []modelProfile{{Name: "chore", ModelProfile: project.ModelProfile{Model: "openrouter/z-ai/glm-5.2"}, Source: project.SourceProject}}
+
+	var buf bytes.Buffer
+	require.NoError(t, printModelsJSON(&buf, catalog, profiles))
+	var out modelsJSONOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, "repobird.models.v1", out.Schema)
+	assert.True(t, out.Authoritative)
+	require.Len(t, out.Profiles, 1)
+	assert.Equal(t, "openrouter/z-ai/glm-5.2", out.Profiles[0].Model)
+}
@@ -7,12 +7,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/budget"
 	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
 	configpkg "github.com/repobird/repobird-cli/internal/config"
 	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
 	"github.com/repobird/repobird-cli/internal/output"
+	"github.com/repobird/repobird-cli/internal/project"
+	"github.com/repobird/repobird-cli/internal/usage"
 	"github.com/repobird/repobird-cli/internal/utils"
 )
 
@@ -44,10 +51,11 @@ type runCreateJSONOutput struct {
 }
 
 type runDryRunJSONOutput struct {
-	Schema    string     `json:"schema"`
-	Operation string     `json:"operation"`
-	Valid     bool       `json:"valid"`
-	Request   runRequest `json:"request"`
+	Schema    string          `json:"schema"`
+	Operation string          `json:"operation"`
+	Valid     bool            `json:"valid"`
+	Request   runRequest      `json:"request"`
+	Budget    budget.Decision `json:"budget"`
 }
 
 type runWaitJSONOutput struct {
@@ -63,6 +71,28 @@ type runWaitJSONOutput struct {
 	Error     string      `json:"error,omitempty"`
 }
 
+type waitJSONOutput struct {
+	Schema    string        `json:"schema"`
+	Operation string        `json:"operation"`
+	Mode      string        `json:"mode"`
+	Success   bool          `json:"success"`
+	ExitCode  int           `json:"exitCode"`
+	TimedOut  bool          `json:"timedOut"`
+	Completed int           `json:"completed"`
+	Failed    int           `json:"failed"`
+	Pending   int           `json:"pending"`
+	Runs      []waitRunJSON `json:"runs"`
+}
+
+type waitRunJSON struct {
+	ID       string  `json:"id"`
+	Status   string  `json:"status"`
+	Finished bool    `json:"finished"`
+	Run      runJSON `json:"run,omitempty"`
+	URL      string  `json:"url,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
 type runJSON struct {
 	ID                 string  `json:"id"`
 	PublicID           string  `json:"publicId,omitempty"`
@@ -124,6 +154,8 @@ type bulkDryRunJSONOutput struct {
 	RepoID         int                `json:"repoId,omitempty"`
 	SourceBranch   string             `json:"sourceBranch,omitempty"`
 	RunType        string             `json:"runType,omitempty"`
+	Model          string             `json:"model,omitempty"`
+	Provider       string             `json:"provider,omitempty"`
 	BatchTitle     string             `json:"batchTitle,omitempty"`
 	TotalRuns      int                `json:"totalRuns"`
 	Runs           []bulkRunConfigOut `json:"runs"`
@@ -135,6 +167,7 @@ type bulkRunConfigOut struct {
 	Prompt       string `json:"prompt,omitempty"`
 	TargetBranch string `json:"targetBranch,omitempty"`
 	Context      string `json:"context,omitempty"`
+	bulk.RunOverrides
 }
 
 type bulkCreateJSONOutput struct {
@@ -148,6 +181,8 @@ type bulkCreateJSONOutput struct {
 	TotalRequested  int               `json:"totalRequested"`
 	TotalSuccessful int               `json:"totalSuccessful"`
 	TotalFailed     int               `json:"totalFailed"`
+	SuperBatchID    string            `json:"superBatchId,omitempty"`
+	BatchIDs        []string          `json:"batchIds,omitempty"`
 }
 
 type bulkRunJSON struct {
@@ -166,16 +201,148 @@ type bulkFailureJSON struct {
 	ExistingRunID int    `json:"existingRunId,omitempty"`
 }
 
-func printRunDryRunJSON(out io.Writer, req domain.CreateRunRequest) error {
-	return printJSON(out, runDryRunJSONOutput{
+type logsSummaryJSONOutput struct {
+	Schema    string        `json:"schema"`
+	Operation string        `json:"operation"`
+	RunID     string        `json:"runId"`
+	Summary   usage.Summary `json:"summary"`
+}
+
+type configResolvedJSONOutput struct {
+	Schema    string            `json:"schema"`
+	Operation string            `json:"operation"`
+	Settings  []project.Setting `json:"settings"`
+}
+
+type modelsJSONOutput struct {
+	Schema        string             `json:"schema"`
+	Operation     string             `json:"operation"`
+	Authoritative bool               `json:"authoritative"`
+	FetchedAt     *time.Time         `json:"fetchedAt,omitempty"`
+	Models        []models.ModelInfo `json:"models"`
+	Profiles      []modelProfile     `json:"profiles"`
+}
+
+type bulkStatusJSONOutput struct {
+	Schema    string                 `json:"schema"`
+	Operation string                 `json:"operation"`
+	BatchID   string                 `json:"batchId"`
+	BatchIDs  []string               `json:"batchIds,omitempty"`
+	Status    string                 `json:"status"`
+	Runs      []dto.RunStatusItem    `json:"runs"`
+	Metadata  dto.BulkStatusMetadata `json:"metadata"`
+}
+
+type bulkCancelJSONOutput struct {
+	Schema    string   `json:"schema"`
+	Operation string   `json:"operation"`
+	Success   bool     `json:"success"`
+	BatchIDs  []string `json:"batchIds"`
+}
+
+type bulkListJSONOutput struct {
+	Schema    string                   `json:"schema"`
+	Operation string                   `json:"operation"`
+	Batches   []*cache.BulkBatchRecord `json:"batches"`
+}
+
+type campaignReportJSONOutput struct {
+	Schema    string               `json:"schema"`
+	Operation string               `json:"operation"`
+	Name      string               `json:"name"`
+	Total     int                  `json:"total"`
+	Submitted int                  `json:"submitted"`
+	Failed    int                  `json:"failed"`
+	Repos     []cache.CampaignRepo `json:"repos"`
+}
+
+type campaignListJSONOutput struct {
+	Schema    string                    `json:"schema"`
+	Operation string                    `json:"operation"`
+	Campaigns []*cache.CampaignManifest `json:"campaigns"`
+}
+
+type workflowStatusJSONOutput struct {
+	Schema    string `json:"schema"`
+	Operation string `json:"operation"`
+	*cache.WorkflowState
+}
+
+type scheduleListJSONOutput struct {
+	Schema    string              `json:"schema"`
+	Operation string              `json:"operation"`
+	Schedules []scheduleListEntry `json:"schedules"`
+}
+
+type runQueueJSONOutput struct {
+	Schema    string      `json:"schema"`
+	Operation string      `json:"operation"`
+	Queued    bool        `json:"queued"`
+	QueueID   string      `json:"queueId"`
+	Reason    string      `json:"reason"`
+	Error     string      `json:"error,omitempty"`
+	Request   *runRequest `json:"request,omitempty"`
+}
+
+type queueListJSONOutput struct {
+	Schema    string          `json:"schema"`
+	Operation string          `json:"operation"`
+	Entries   []*outbox.Entry `json:"entries"`
+}
+
+type guardListJSONOutput struct {
+	Schema      string           `json:"schema"`
+	Operation   string           `json:"operation"`
+	Submissions []guardListEntry `json:"submissions"`
+}
+
+type guardListEntry struct {
+	Source     string    `json:"source"`
+	Key        string    `json:"key"`
+	ReservedAt time.Time `json:"reserved_at"`
+	RunID      string    `json:"run_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type costReportJSONOutput struct {
+	Schema    string `json:"schema"`
+	Operation string `json:"operation"`
+	usage.Report
+}
+
+func printModelsJSON(out io.Writer, catalog modelCatalog, profiles []modelProfile) error {
+	output := modelsJSONOutput{
+		Schema:        "repobird.models.v1",
+		Operation:     "models.list",
+		Authoritative: catalog.Authoritative,
+		Models:        catalog.Models,
+		Profiles:      profiles,
+	}
+	if !catalog.FetchedAt.IsZero() {
+		output.FetchedAt = &catalog.FetchedAt
+	}
+	return printJSON(out, output)
+}
+
+func printRunDryRunJSON(out io.Writer, req domain.CreateRunRequest, decision budget.Decision) error {
+	return printJSON(out, makeRunDryRunJSON(req, decision))
+}
+
+func makeRunDryRunJSON(req domain.CreateRunRequest, decision budget.Decision) runDryRunJSONOutput {
+	return runDryRunJSONOutput{
 		Schema:    "repobird.run.dry_run.v1",
 		Operation: "run.dry_run",
 		Valid:     true,
 		Request:   makeRunRequestJSON(req),
-	})
+		Budget:    decision,
+	}
 }
 
 func printRunCreateJSON(out io.Writer, run *domain.Run, req domain.CreateRunRequest) error {
+	return printJSON(out, makeRunCreateJSON(run, req))
+}
+
+func makeRunCreateJSON(run *domain.Run, req domain.CreateRunRequest) runCreateJSONOutput {
 	urlID := createdRunURLID(run)
 	output := runCreateJSONOutput{
 		Schema:    "repobird.run.create.v1",
@@ -188,7 +355,7 @@ func printRunCreateJSON(out io.Writer, run *domain.Run, req domain.CreateRunRequ
 	}
 	request := makeRunRequestJSON(req)
 	output.Request = &request
-	return printJSON(out, output)
+	return output
 }
 
 func printRunWaitJSON(out io.Writer, run *domain.Run, req domain.CreateRunRequest, exitCode int, timedOut bool, message string) error {
@@ -213,6 +380,46 @@ func printRunWaitJSON(out io.Writer, run *domain.Run, req domain.CreateRunReques
 	return printJSON(out, output)
 }
 
+func printWaitJSON(out io.Writer, runs []*waitedRun, exitCode int, timedOut bool) error {
+	output := waitJSONOutput{
+		Schema:    "repobird.wait.v1",
+		Operation: "wait",
+		Mode:      "all",
+		Success:   exitCode == ExitCodeSuccess,
+		ExitCode:  exitCode,
+		TimedOut:  timedOut,
+		Runs:      make([]waitRunJSON, 0, len(runs)),
+	}
+	if waitAny {
+		output.Mode = "any"
+	}
+	for _, w := range runs {
+		entry := waitRunJSON{
+			ID:       w.id,
+			Status:   w.status(),
+			Finished: w.finished(),
+			Run:      makeRunJSON(w.run),
+		}
+		switch {
+		case !w.finished():
+			output.Pending++
+		case w.run.IsSuccess():
+			output.Completed++
+		default:
+			output.Failed++
+		}
+		if w.run != nil {
+			entry.URL = utils.GenerateRepoBirdURL(createdRunURLID(w.run))
+			entry.Error = w.run.Error
+		}
+		if w.err != nil {
+			entry.Error = w.err.Error()
+		}
+		output.Runs = append(output.Runs, entry)
+	}
+	return printJSON(out, output)
+}
+
 func makeRunJSON(run *domain.Run) runJSON {
 	if run == nil {
 		return runJSON{}
@@ -291,6 +498,7 @@ func printBulkDryRunJSON(out io.Writer, bulkConfig *bulk.BulkConfig) error {
 			Prompt:       run.Prompt,
 			TargetBranch: run.Target,
 			Context:      run.Context,
+			RunOverrides: run.RunOverrides,
 		})
 	}
 	return printJSON(out, bulkDryRunJSONOutput{
@@ -301,13 +509,15 @@ func printBulkDryRunJSON(out io.Writer, bulkConfig *bulk.BulkConfig) error {
 		RepoID:         bulkConfig.RepoID,
 		SourceBranch:   bulkConfig.Source,
 		RunType:        bulkConfig.RunType,
+		Model:          bulkConfig.Model,
+		Provider:       bulkConfig.Provider,
 		BatchTitle:     bulkConfig.BatchTitle,
 		TotalRuns:      len(bulkConfig.Runs),
 		Runs:           runs,
 	})
 }
 
-func printBulkCreateJSON(out io.Writer, bulkResp *dto.BulkRunResponse) error {
+func printBulkCreateJSON(out io.Writer, bulkResp *dto.BulkRunResponse, manifest *cache.BulkManifest) error {
 	runs := make([]bulkRunJSON, 0, len(bulkResp.Data.Successful))
 	for _, run := range bulkResp.Data.Successful {
 		runs = append(runs, bulkRunJSON{
@@ -328,7 +538,7 @@ func printBulkCreateJSON(out io.Writer, bulkResp *dto.BulkRunResponse) error {
 			ExistingRunID: failure.ExistingRunId,
 		})
 	}
-	return printJSON(out, bulkCreateJSONOutput{
+	output := bulkCreateJSONOutput{
 		Schema:          "repobird.bulk.create.v1",
 		Operation:       "bulk.create",
 		Success:         len(bulkResp.Data.Failed) == 0,
@@ -339,7 +549,28 @@ func printBulkCreateJSON(out io.Writer, bulkResp *dto.BulkRunResponse) error {
 		TotalRequested:  bulkResp.Data.Metadata.TotalRequested,
 		TotalSuccessful: bulkResp.Data.Metadata.TotalSuccessful,
 		TotalFailed:     bulkResp.Data.Metadata.TotalFailed,
-	})
+	}
+	if manifest != nil {
+		// A chunked submission reports every batch of the super-batch.
+		output.SuperBatchID = manifest.ID
+		output.BatchIDs = manifest.BatchIDs()
+	}
+	return printJSON(out, output)
+}
+
+func printBulkStatusJSON(out io.Writer, id string, batchIDs []string, status dto.BulkStatusData) error {
+	output := bulkStatusJSONOutput{
+		Schema:    "repobird.bulk.status.v1",
+		Operation: "bulk.status",
+		BatchID:   id,
+		Status:    status.Status,
+		Runs:      status.Runs,
+		Metadata:  status.Metadata,
+	}
+	if len(batchIDs) > 1 {
+		output.BatchIDs = batchIDs
+	}
+	return printJSON(out, output)
 }
 
 func fallbackRunTitle(index int) string {
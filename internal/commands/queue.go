@@ -0,0 +1,352 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var (
+	// queueOnNetworkError makes 'run' keep a request that cannot reach the API
+	// in the outbox instead of failing.
+	queueOnNetworkError bool
+	// queuedThisCommand is set once the current command queued a run, so the
+	// background flush does not retry it while the API is still unreachable.
+	queuedThisCommand bool
+	queueDropAll      bool
+	queueBackground   bool
+)
+
+// startOutboxFlush runs 'repobird queue flush --background' in a process that
+// outlives the current command.
+var startOutboxFlush = func() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"queue", "flush", "--background"}
+	if cfgFile != "" {
+		args = append(args, "--config", cfgFile)
+	}
+	cmd := exec.Command(executable, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+var queueCmd = newQueueCommand()
+
+func newQueueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage runs queued while the API was unreachable",
+		Long: `Manage the outbox of runs queued with 'repobird run --queue' or from the TUI
+while the API could not be reached. Queued runs are submitted in the
+background after the next successful command, or now with 'queue flush'.
+Each run keeps its idempotency key, so a run is created at most once even if
+an earlier attempt reached the API.`,
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List queued runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runQueueList(cmd.OutOrStdout())
+		},
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	flush := &cobra.Command{
+		Use:   "flush",
+		Short: "Submit queued runs now",
+		Long: `Submit the queued runs in the order they were queued. The flush stops at the
+first network, authentication or server error and leaves the remaining runs
+queued. Runs the API rejected stay queued, marked rejected, until they are
+dropped; an explicit flush retries them. Runs held back by max_active_runs or
+a credit budget stay queued for a later flush.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runQueueFlush(cmd.OutOrStdout())
+		},
+	}
+	flush.Flags().BoolVar(&queueBackground, "background", false, "flush quietly, skipping rejected runs")
+	flush.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the runs would exceed a configured credit budget")
+	_ = flush.Flags().MarkHidden("background")
+
+	drop := &cobra.Command{
+		Use:   "drop [id...]",
+		Short: "Remove queued runs without submitting them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQueueDrop(cmd.OutOrStdout(), args)
+		},
+	}
+	drop.Flags().BoolVar(&queueDropAll, "all", false, "drop every queued run")
+
+	cmd.AddCommand(list, flush, drop)
+	return cmd
+}
+
+func runQueueList(out io.Writer) error {
+	entries, err := outbox.New(outbox.DefaultDir()).List()
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(out, queueListJSONOutput{
+			Schema:    "repobird.queue.v1",
+			Operation: "queue.list",
+			Entries:   entries,
+		})
+	}
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No queued runs")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tQUEUED\tREPOSITORY\tTITLE\tSTATUS")
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			entry.QueuedAt.Local().Format("2006-01-02 15:04"),
+			valueOrDash(entry.Request.RepositoryName),
+			utils.TruncateWithEllipsis(queuedRunTitle(entry), 40),
+			queueEntryStatus(entry),
+		)
+	}
+	return w.Flush()
+}
+
+// printLocallyQueuedRuns lists the runs waiting in the outbox below the
+// runs 'status' shows.
+func printLocallyQueuedRuns(out io.Writer) error {
+	entries, err := outbox.New(outbox.DefaultDir()).List()
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "\n%s\n", styleFor(out).Label(fmt.Sprintf("Queued locally (%d):", len(entries))))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			entry.ID,
+			valueOrDash(entry.Request.RepositoryName),
+			utils.TruncateWithEllipsis(queuedRunTitle(entry), 40),
+			queueEntryStatus(entry),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush output: %w", err)
+	}
+	return nil
+}
+
+func queuedRunTitle(entry *outbox.Entry) string {
+	if entry.Request.Title != "" {
+		return entry.Request.Title
+	}
+	return firstLine(entry.Request.Prompt)
+}
+
+func queueEntryStatus(entry *outbox.Entry) string {
+	status := "pending"
+	if entry.Rejected {
+		status = "rejected"
+	}
+	switch {
+	case entry.LastError == "":
+	case entry.Attempts == 0:
+		status += fmt.Sprintf(" (%s)", utils.TruncateWithEllipsis(firstLine(entry.LastError), 50))
+	default:
+		status += fmt.Sprintf(" (%d attempt(s): %s)", entry.Attempts, utils.TruncateWithEllipsis(firstLine(entry.LastError), 50))
+	}
+	return status
+}
+
+func runQueueFlush(out io.Writer) error {
+	box := outbox.New(outbox.DefaultDir())
+	if queueBackground {
+		// Nobody reads the output of a background flush; outcomes are kept on
+		// the entries for 'queue list'.
+		out = io.Discard
+	}
+	client, err := newRepoAPIClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	results, err := box.Flush(capacityGate(ctx, budgetGate(ctx, client.CreateRunAPI)), !queueBackground)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(out, "No queued runs to submit")
+		return nil
+	}
+
+	styler := styleFor(out)
+	submitted := 0
+	for _, result := range results {
+		title := utils.TruncateWithEllipsis(queuedRunTitle(result.Entry), 60)
+		switch {
+		case result.Run != nil:
+			submitted++
+			_, _ = fmt.Fprintf(out, "%s %s submitted as run %s\n", styler.Success("✓"), title, result.Run.GetIDString())
+		case stderrors.Is(result.Err, outbox.ErrHeld):
+			_, _ = fmt.Fprintf(out, "%s %s still queued: %s\n", styler.Info("•"), title, result.Entry.LastError)
+		case result.Entry.Rejected:
+			_, _ = fmt.Fprintf(out, "%s %s rejected: %s\n", styler.Error("✗"), title, firstLine(result.Entry.LastError))
+		default:
+			_, _ = fmt.Fprintf(out, "%s %s not submitted: %s\n", styler.Warning("!"), title, firstLine(result.Entry.LastError))
+		}
+	}
+	remaining, err := box.List()
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "\nSubmitted %d queued run(s), %d still queued\n", submitted, len(remaining))
+	return nil
+}
+
+func runQueueDrop(out io.Writer, ids []string) error {
+	box := outbox.New(outbox.DefaultDir())
+	switch {
+	case queueDropAll && len(ids) > 0:
+		return fmt.Errorf("pass queued run IDs or --all, not both")
+	case queueDropAll:
+		entries, err := box.List()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+	case len(ids) == 0:
+		return fmt.Errorf("pass the IDs of the queued runs to drop (see 'repobird queue list'), or --all")
+	}
+	for _, id := range ids {
+		if err := box.Remove(id); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintf(out, "%s Dropped %d queued run(s)\n", styleFor(out).Success("✓"), len(ids))
+	return nil
+}
+
+// queueCreateRequest keeps a run request in the outbox instead of creating
+// it. reason says why; cause is the error that stopped the submission, if
+// any.
+func queueCreateRequest(out io.Writer, req domain.CreateRunRequest, reason string, cause error) error {
+	box := outbox.New(outbox.DefaultDir())
+	entry, existing, err := box.Add(apiRunRequestFromDomain(req))
+	if err != nil {
+		if cause == nil {
+			return fmt.Errorf("%s and the run could not be queued: %w", reason, err)
+		}
+		return fmt.Errorf("%w (and the run could not be queued: %v)", cause, err)
+	}
+	if cause != nil {
+		reason = fmt.Sprintf("%s (%s)", reason, errors.FormatUserError(cause))
+	} else if !existing {
+		entry.LastError = reason
+		if err := box.Save(entry); err != nil {
+			return err
+		}
+	}
+	queuedThisCommand = true
+	if jsonOutput {
+		request := makeRunRequestJSON(req)
+		output := runQueueJSONOutput{
+			Schema:    "repobird.run.queue.v1",
+			Operation: "run.queue",
+			Queued:    true,
+			QueueID:   entry.ID,
+			Reason:    reason,
+			Request:   &request,
+		}
+		if cause != nil {
+			output.Error = errors.FormatUserError(cause)
+		}
+		return printJSON(out, output)
+	}
+	styler := styleFor(out)
+	note := "Queued run"
+	if existing {
+		note = "Run already queued"
+	}
+	_, _ = fmt.Fprintf(out, "%s %s %s: %s\n", styler.Warning("!"), note, entry.ID, reason)
+	_, _ = fmt.Fprintln(out, "It is submitted after the next successful command, or with 'repobird queue flush'")
+	return nil
+}
+
+// apiRunRequestFromDomain converts a normalized create request to the API
+// request kept in the outbox.
+func apiRunRequestFromDomain(req domain.CreateRunRequest) models.APIRunRequest {
+	agent := req.Agent
+	if agent == "" {
+		agent = "opencode"
+	}
+	apiReq := models.APIRunRequest{
+		Prompt:                req.Prompt,
+		RepositoryName:        req.RepositoryName,
+		SourceBranch:          req.SourceBranch,
+		TargetBranch:          req.TargetBranch,
+		BaseBranch:            req.BaseBranch,
+		OutputMode:            req.OutputMode,
+		OutputBranch:          req.OutputBranch,
+		PRTargetBranch:        req.PRTargetBranch,
+		OutputBranchPolicy:    req.OutputBranchPolicy,
+		RunType:               models.RunType(req.RunType),
+		Agent:                 agent,
+		OpenCodeModel:         req.OpenCodeModel,
+		OpenCodeProvider:      req.OpenCodeProvider,
+		Title:                 req.Title,
+		Context:               req.Context,
+		Files:                 req.Files,
+		ProviderCredentialID:  req.ProviderCredentialID,
+		ProviderMode:          req.ProviderMode,
+		BranchOnly:            req.BranchOnly,
+		AcknowledgePromptRisk: req.AcknowledgePromptRisk,
+		IdempotencyKey:        req.IdempotencyKey,
+	}
+	if req.GitLabCredential != nil {
+		apiReq.GitLabCredential = &models.GitLabCredentialRequest{
+			Mode:             req.GitLabCredential.Mode,
+			TokenReferenceID: req.GitLabCredential.TokenReferenceID,
+		}
+	}
+	return apiReq
+}
+
+// flushOutboxInBackground starts a background flush after a successful
+// command when runs are queued.
+func flushOutboxInBackground(cmd *cobra.Command) {
+	if queuedThisCommand || dryRun || cfg == nil || cfg.APIKey == "" {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == queueCmd {
+			return
+		}
+	}
+	pending, err := outbox.New(outbox.DefaultDir()).Pending()
+	if err != nil || pending == 0 {
+		return
+	}
+	_ = startOutboxFlush()
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+)
+
+// configureQueueTest points the commands at an API that is down and returns
+// a func that brings it up, recording the idempotency keys it receives.
+func configureQueueTest(t *testing.T) (up func() *[]string) {
+	t.Helper()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	t.Cleanup(configureRunWaitTest(t, down.URL))
+	wait = false
+	originalQueue, originalQueued, originalBackground := queueOnNetworkError, queuedThisCommand, queueBackground
+	t.Cleanup(func() {
+		queueOnNetworkError, queuedThisCommand, queueBackground = originalQueue, originalQueued, originalBackground
+	})
+
+	return func() *[]string {
+		var mu sync.Mutex
+		keys := &[]string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			*keys = append(*keys, r.Header.Get("Idempotency-Key"))
+			id := 700 + len(*keys)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": id, "status": "QUEUED"}})
+		}))
+		t.Cleanup(server.Close)
+		cfg.APIURL = server.URL
+		resetContainer()
+		return keys
+	}
+}
+
+func TestRunQueueWhenOffline(t *testing.T) {
+	up := configureQueueTest(t)
+
+	runConfig := func() *models.RunConfig {
+		return &models.RunConfig{Prompt: "Fix the login redirect loop on expired sessions", Repository: "acme/web", RunType: "run"}
+	}
+	var runErr error
+	captureRunStdout(t, func() { runErr = processSingleRun(runConfig(), "") })
+	require.Error(t, runErr, "without --queue a network error fails the run")
+
+	queueOnNetworkError = true
+	output := captureRunStdout(t, func() { require.NoError(t, processSingleRun(runConfig(), "")) })
+	var queued runQueueJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &queued))
+	assert.Equal(t, "run.queue", queued.Operation)
+	assert.True(t, queued.Queued)
+	assert.True(t, queuedThisCommand)
+
+	box := outbox.New(outbox.DefaultDir())
+	entries, err := box.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, queued.QueueID, entries[0].ID)
+	key := entries[0].Request.IdempotencyKey
+	require.NotEmpty(t, key)
+	assert.Equal(t, "acme/web", entries[0].Request.RepositoryName)
+	assert.Equal(t, "opencode", entries[0].Request.Agent)
+
+	// Queuing the same run again keeps one entry.
+	captureRunStdout(t, func() { require.NoError(t, processSingleRun(runConfig(), "")) })
+	entries, err = box.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	keys := up()
+	var out bytes.Buffer
+	require.NoError(t, runQueueFlush(&out))
+	assert.Contains(t, out.String(), "submitted as run 701")
+	assert.Contains(t, out.String(), "Submitted 1 queued run(s), 0 still queued")
+	assert.Equal(t, []string{key}, *keys, "the flush reuses the idempotency key")
+}
+
+func TestQueueFlushInBackground(t *testing.T) {
+	configureQueueTest(t)
+	started := 0
+	originalStart := startOutboxFlush
+	startOutboxFlush = func() error { started++; return nil }
+	t.Cleanup(func() { startOutboxFlush = originalStart })
+
+	flushOutboxInBackground(rootCmd)
+	assert.Equal(t, 0, started, "an empty outbox needs no flush")
+
+	_, _, err := outbox.New(outbox.DefaultDir()).Add(models.APIRunRequest{Prompt: "Add rate limiting", IdempotencyKey: "key-1"})
+	require.NoError(t, err)
+	flushOutboxInBackground(rootCmd)
+	assert.Equal(t, 1, started)
+
+	flushOutboxInBackground(queueCmd.Commands()[0])
+	assert.Equal(t, 1, started, "queue commands do not start a flush")
+
+	queuedThisCommand = true
+	flushOutboxInBackground(rootCmd)
+	assert.Equal(t, 1, started, "a command that just queued a run does not retry it")
+}
+
+func TestQueueListAndDrop(t *testing.T) {
+	configureQueueTest(t)
+	jsonOutput = false
+	box := outbox.New(outbox.DefaultDir())
+	first, _, err := box.Add(models.APIRunRequest{Prompt: "Add rate limiting", RepositoryName: "acme/api", IdempotencyKey: "key-1"})
+	require.NoError(t, err)
+	_, _, err = box.Add(models.APIRunRequest{Prompt: "Document the API", Title: "API docs", RepositoryName: "acme/api", IdempotencyKey: "key-2"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runQueueList(&out))
+	assert.Contains(t, out.String(), first.ID)
+	assert.Contains(t, out.String(), "API docs")
+	assert.Contains(t, out.String(), "pending")
+
+	queueDropAll = false
+	require.Error(t, runQueueDrop(&out, nil))
+	require.NoError(t, runQueueDrop(&out, []string{first.ID}))
+	queueDropAll = true
+	t.Cleanup(func() { queueDropAll = false })
+	out.Reset()
+	require.NoError(t, runQueueDrop(&out, nil))
+	assert.Contains(t, out.String(), "Dropped 1 queued run(s)")
+
+	out.Reset()
+	require.NoError(t, runQueueList(&out))
+	assert.Contains(t, out.String(), "No queued runs")
+}
@@ -51,6 +51,9 @@ Get API Key: %s`, config.GetURLs().BaseURL, config.GetAPIKeysURL()),
 
 		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+		flushOutboxInBackground(cmd)
+	},
 }
 
 func Execute() {
@@ -99,7 +102,15 @@ func init() {
 	rootCmd.AddCommand(newRunPresetCommand("basic"))
 	rootCmd.AddCommand(newRunPresetCommand("pro"))
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(waitCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(costCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(repoCmd)
 	InitConfigSubcommands() // Initialize config subcommands
 	rootCmd.AddCommand(configCmd)
@@ -108,6 +119,13 @@ func init() {
 	rootCmd.AddCommand(verifyCmd)
 	rootCmd.AddCommand(infoCmd)
 	rootCmd.AddCommand(NewBulkCommand())
+	rootCmd.AddCommand(campaignCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(todoCmd)
+	rootCmd.AddCommand(workflowCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(queueCmd)
+	rootCmd.AddCommand(guardCmd)
 	rootCmd.AddCommand(examplesCmd)
 	rootCmd.AddCommand(completionCmd)
 }
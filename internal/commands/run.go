@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	netstderrors "errors"
 	"fmt"
-	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -16,14 +15,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/repobird/repobird-cli/internal/api"
-	"github.com/repobird/repobird-cli/internal/api/dto"
 	"github.com/repobird/repobird-cli/internal/bulk"
-	"github.com/repobird/repobird-cli/internal/cache"
 	"github.com/repobird/repobird-cli/internal/config"
 	"github.com/repobird/repobird-cli/internal/domain"
 	"github.com/repobird/repobird-cli/internal/errors"
 	"github.com/repobird/repobird-cli/internal/idempotency"
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/project"
 	"github.com/repobird/repobird-cli/internal/prompts"
 	"github.com/repobird/repobird-cli/internal/utils"
 )
@@ -113,6 +111,9 @@ Examples:
   repobird run -r owner/repo -p "Refactor" --context @context.md
   repobird run -r owner/repo -p @task.txt --context @requirements.md
 
+  # Using a prompt template (see 'repobird templates list')
+  repobird run --template add-tests --var package=internal/api
+
 For configuration examples and field descriptions:
   repobird examples                         # View all examples
   repobird examples generate run -o task.json`,
@@ -149,10 +150,20 @@ func init() {
 	runCmd.Flags().BoolVar(&acknowledgePromptRisk, "acknowledge-prompt-risk", false, "acknowledge prompt-risk warning and create the run")
 	runCmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "stable key for safely retrying run creation")
 	runCmd.Flags().BoolVar(&forceRun, "force", false, "bypass the local duplicate-submission guard")
+	runCmd.Flags().BoolVar(&queueOnNetworkError, "queue", false, "queue the run in the local outbox if the API cannot be reached")
+	runCmd.Flags().StringVar(&whenFull, "when-full", whenFullFail, "when max_active_runs is reached: fail, wait for a free slot, or queue the run locally")
+	runCmd.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the run would exceed a configured credit budget")
+	addHookFlags(runCmd)
+	runCmd.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if the prompt, context, or file paths contain suspected secrets")
+	runCmd.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if the run configuration has lint errors")
 	runCmd.Flags().StringVar(&contextFlag, "context", "", "additional context (use @file to read from file, - for stdin)")
 	runCmd.Flags().StringVar(&providerCredentialID, "provider-credential-id", "", "provider credential ID for BYOK or enterprise provider routing")
 	runCmd.Flags().StringVar(&providerMode, "provider-mode", "", "provider mode: bundled, byok-user, or enterprise-gateway")
+	runCmd.Flags().StringVar(&runModel, "model", "", "OpenCode model ID or model profile name (see 'repobird models')")
+	runCmd.Flags().StringVar(&runProvider, "provider", "", "OpenCode provider for --model (for example: openrouter)")
 	runCmd.Flags().StringVar(&gitlabTokenReferenceID, "gitlab-token-reference-id", "", "stored GitLab token reference ID for self-managed GitLab repositories")
+	runCmd.Flags().StringVar(&templateName, "template", "", "render the prompt from a named template (see 'repobird templates list')")
+	runCmd.Flags().StringArrayVar(&templateVars, "var", nil, "template variable as name=value (repeatable)")
 }
 
 func runCommand(cmd *cobra.Command, args []string) error {
@@ -172,6 +183,9 @@ func runCommandWithPreset(cmd *cobra.Command, args []string, presetName string)
 	if waitTimeout <= 0 {
 		return fmt.Errorf("--timeout must be greater than zero")
 	}
+	if err := requireHookFollow(wait || follow, "--wait or --follow"); err != nil {
+		return err
+	}
 
 	selectedPreset, err := resolveRunPreset(presetName)
 	if err != nil {
@@ -182,13 +196,27 @@ func runCommandWithPreset(cmd *cobra.Command, args []string, presetName string)
 		return fmt.Errorf("--run-type cannot be used with --%s", selectedPreset.RunType)
 	}
 
+	if templateName != "" {
+		if prompt != "" || len(args) > 0 {
+			return fmt.Errorf("--template cannot be combined with --prompt or a configuration file")
+		}
+		runConfig, err := runConfigFromTemplate(templateName, templateVars, selectedPreset)
+		if err != nil {
+			return err
+		}
+		return processSingleRun(runConfig, "")
+	}
+	if len(templateVars) > 0 {
+		return fmt.Errorf("--var requires --template")
+	}
+
 	if prompt == "" && len(args) == 1 && (repo != "" || presetName != "") {
 		prompt = args[0]
 		args = nil
 	}
 
 	// Check if run is being created with flags
-	if prompt != "" && (repo != "" || selectedPreset != nil) {
+	if prompt != "" && (repo != "" || selectedPreset != nil || hasDefaultRepository()) {
 		// Process prompt input (handles @file, -, or literal string)
 		processedPrompt, err := utils.ReadPromptInput(prompt)
 		if err != nil {
@@ -217,6 +245,8 @@ func runCommandWithPreset(cmd *cobra.Command, args []string, presetName string)
 			OutputBranchPolicy:    outputBranchPolicy,
 			Title:                 title,
 			RunType:               selectedRunType(selectedPreset),
+			Model:                 runModel,
+			Provider:              runProvider,
 			Context:               processedContext,
 			ProviderCredentialID:  providerCredentialID,
 			ProviderMode:          providerMode,
@@ -274,7 +304,10 @@ func runCommandWithPreset(cmd *cobra.Command, args []string, presetName string)
 			}
 		}
 
-		applyRunPreset(runConfig, selectedPreset)
+		// Flags take precedence over the config
+		if err := applyRunFlags(runConfig, selectedPreset); err != nil {
+			return err
+		}
 		return processSingleRun(runConfig, "")
 	}
 
@@ -321,11 +354,25 @@ func runCommandWithPreset(cmd *cobra.Command, args []string, presetName string)
 		}
 	}
 
-	applyRunPreset(runConfig, selectedPreset)
+	// Flags take precedence over the run file
+	if err := applyRunFlags(runConfig, selectedPreset); err != nil {
+		return err
+	}
 	return processSingleRun(runConfig, additionalContext)
 }
 
 func processSingleRun(runConfig *models.RunConfig, additionalContext string) error {
+	defaults, err := resolveRunDefaults("")
+	if err != nil {
+		return err
+	}
+	applyRunDefaults(runConfig, defaults)
+	if runConfig.Context == "" && additionalContext == "" && len(defaults.ContextFiles) > 0 && repositoryDefaultsApply(defaults, runConfig.Repository) {
+		if runConfig.Context, err = readDefaultContext(defaults.ContextFiles); err != nil {
+			return err
+		}
+	}
+
 	if runConfig.Repository == "" {
 		container := getContainer()
 		gitService := container.GitService()
@@ -346,6 +393,16 @@ func processSingleRun(runConfig *models.RunConfig, additionalContext string) err
 
 	runConfig.NormalizeBranchOutput()
 
+	// Per-repository model defaults need the final repository
+	if defaults, err = resolveRunDefaults(runConfig.Repository); err != nil {
+		return err
+	}
+	profiles, err := loadModelProfiles()
+	if err != nil {
+		return err
+	}
+	modelSelection := selectRunModel(runConfig, defaults, profiles)
+
 	// Validate the configuration
 	if runConfig.RunType == string(models.RunTypePlan) && !config.IsPlanRunsEnabled() {
 		return netstderrors.New(config.PlanRunsUnavailableMessage())
@@ -355,49 +412,36 @@ func processSingleRun(runConfig *models.RunConfig, additionalContext string) err
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Convert to domain request
-	createReq := domain.CreateRunRequest{
-		Prompt:                runConfig.Prompt,
-		RepositoryName:        runConfig.Repository,
-		SourceBranch:          runConfig.Source,
-		TargetBranch:          runConfig.Target,
-		BaseBranch:            runConfig.BaseBranch,
-		OutputMode:            runConfig.OutputMode,
-		OutputBranch:          runConfig.OutputBranch,
-		PRTargetBranch:        runConfig.PRTargetBranch,
-		OutputBranchPolicy:    runConfig.OutputBranchPolicy,
-		RunType:               runConfig.RunType,
-		Agent:                 "opencode",
-		OpenCodeModel:         modelForRunType(runConfig.RunType),
-		OpenCodeProvider:      providerForRunType(runConfig.RunType),
-		Title:                 runConfig.Title,
-		Context:               runConfig.Context,
-		Files:                 runConfig.Files,
-		ProviderCredentialID:  runConfig.ProviderCredentialID,
-		ProviderMode:          runConfig.ProviderMode,
-		GitLabCredential:      domainGitLabCredential(runConfig.GitLabCredential),
-		BranchOnly:            runConfig.BranchOnly,
-		AcknowledgePromptRisk: runConfig.AcknowledgePromptRisk,
-		IdempotencyKey:        selectedIdempotencyKey(runConfig),
+	if err := checkSubmissionLint(runConfig, additionalContext, skipLint); err != nil {
+		return err
+	}
+	if err := checkRunModel(modelSelection); err != nil {
+		return err
 	}
 
-	// Append additional markdown context if present
-	if additionalContext != "" {
-		if createReq.Context != "" {
-			createReq.Context = createReq.Context + "\n\n" + additionalContext
-		} else {
-			createReq.Context = additionalContext
-		}
+	createReq := newCreateRunRequest(runConfig, modelSelection, additionalContext)
+
+	if err := checkSubmissionSecrets(utils.ScanRunInputs(createReq.Prompt, createReq.Context, createReq.Files), allowSecrets, dryRun); err != nil {
+		return err
+	}
+
+	if err := validateWhenFull(whenFull); err != nil {
+		return err
 	}
 
 	if dryRun {
+		decision, err := estimateRunBudget(context.Background(), createReq.RepositoryName)
+		if err != nil {
+			printBudgetWarning(fmt.Errorf("could not estimate the run cost: %s", errors.FormatUserError(err)))
+		}
 		if jsonOutput {
-			return printRunDryRunJSON(os.Stdout, createReq)
+			return printRunDryRunJSON(os.Stdout, createReq, decision)
 		}
 		fmt.Println(stdoutStyle().Success("Validation successful. Run would be created with:"))
 		printRunSelection(createReq)
 		b, _ := json.MarshalIndent(createReq, "", "  ")
 		fmt.Println(string(b))
+		printBudgetEstimate(os.Stdout, decision)
 		return nil
 	}
 
@@ -410,6 +454,14 @@ func processSingleRun(runConfig *models.RunConfig, additionalContext string) err
 	runService := container.RunService()
 	ctx := context.Background()
 
+	if err := checkRunBudget(ctx, createReq.RepositoryName); err != nil {
+		return err
+	}
+	if capacityLimited(createReq.RepositoryName) {
+		if queued, err := applyRunCapacity(ctx, createReq); queued || err != nil {
+			return err
+		}
+	}
 	if !jsonOutput {
 		printRunSelection(createReq)
 		fmt.Println(stdoutStyle().Info("Creating run..."))
@@ -418,7 +470,11 @@ func processSingleRun(runConfig *models.RunConfig, additionalContext string) err
 		return err
 	}
 	run, err := runService.CreateRun(ctx, createReq)
+	recordRunSubmission(runSubmissionGuard(), createReq.IdempotencyKey, run, err)
 	if err != nil {
+		if queueOnNetworkError && errors.IsNetworkError(err) {
+			return queueCreateRequest(os.Stdout, createReq, "the API is unreachable", err)
+		}
 		return wrapExitError(exitCodeForError(err), err)
 	}
 
@@ -470,7 +526,7 @@ func waitForCreatedRun(ctx context.Context, runService domain.RunService, create
 		fmt.Printf("\n%s\n", stdoutStyle().Info("Waiting for run to finish..."))
 	}
 
-	finalRun, timedOut, err := waitForRunTerminal(ctx, runService, createdRun.ID)
+	finalRun, timedOut, err := waitForRunTerminal(ctx, runService, createdRun.ID, waitTimeout)
 	if err != nil {
 		exitCode := exitCodeForError(err)
 		timedOut := exitCode == ExitCodeTimeout
@@ -495,6 +551,9 @@ func waitForCreatedRun(ctx context.Context, runService domain.RunService, create
 	} else {
 		printRunWaitHuman(finalRun, timedOut, message)
 	}
+	if finalRun.IsTerminal() {
+		fireCompletionHooks(runHookPayload(finalRun))
+	}
 
 	if exitCode == ExitCodeSuccess {
 		return nil
@@ -502,8 +561,8 @@ func waitForCreatedRun(ctx context.Context, runService domain.RunService, create
 	return wrapExitError(exitCode, netstderrors.New(messageOrDefault(message, "run did not complete successfully")))
 }
 
-func waitForRunTerminal(ctx context.Context, runService domain.RunService, runID string) (*domain.Run, bool, error) {
-	pollCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+func waitForRunTerminal(ctx context.Context, runService domain.RunService, runID string, timeout time.Duration) (*domain.Run, bool, error) {
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	ticker := time.NewTicker(waitPollInterval)
@@ -519,7 +578,7 @@ func waitForRunTerminal(ctx context.Context, runService domain.RunService, runID
 				return lastRun, false, wrapExitError(code, err)
 			}
 			if pollCtx.Err() != nil {
-				return lastRun, true, newExitError(ExitCodeTimeout, fmt.Sprintf("timed out waiting for run %s after %s", runID, waitTimeout))
+				return lastRun, true, newExitError(ExitCodeTimeout, fmt.Sprintf("timed out waiting for run %s after %s", runID, timeout))
 			}
 		} else {
 			lastRun = run
@@ -530,7 +589,7 @@ func waitForRunTerminal(ctx context.Context, runService domain.RunService, runID
 
 		select {
 		case <-pollCtx.Done():
-			return lastRun, true, newExitError(ExitCodeTimeout, fmt.Sprintf("timed out waiting for run %s after %s", runID, waitTimeout))
+			return lastRun, true, newExitError(ExitCodeTimeout, fmt.Sprintf("timed out waiting for run %s after %s", runID, timeout))
 		case <-ticker.C:
 		}
 	}
@@ -626,13 +685,107 @@ func newRunPresetCommand(presetName string) *cobra.Command {
 	cmd.Flags().BoolVar(&acknowledgePromptRisk, "acknowledge-prompt-risk", false, "acknowledge prompt-risk warning and create the run")
 	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "stable key for safely retrying run creation")
 	cmd.Flags().BoolVar(&forceRun, "force", false, "bypass the local duplicate-submission guard")
+	cmd.Flags().BoolVar(&queueOnNetworkError, "queue", false, "queue the run in the local outbox if the API cannot be reached")
+	cmd.Flags().StringVar(&whenFull, "when-full", whenFullFail, "when max_active_runs is reached: fail, wait for a free slot, or queue the run locally")
+	cmd.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the run would exceed a configured credit budget")
+	addHookFlags(cmd)
+	cmd.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if the prompt, context, or file paths contain suspected secrets")
+	cmd.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if the run configuration has lint errors")
 	cmd.Flags().StringVar(&contextFlag, "context", "", "additional context (use @file to read from file, - for stdin)")
 	cmd.Flags().StringVar(&providerCredentialID, "provider-credential-id", "", "provider credential ID for BYOK or enterprise provider routing")
 	cmd.Flags().StringVar(&providerMode, "provider-mode", "", "provider mode: bundled, byok-user, or enterprise-gateway")
+	cmd.Flags().StringVar(&runModel, "model", "", "OpenCode model ID or model profile name (see 'repobird models')")
+	cmd.Flags().StringVar(&runProvider, "provider", "", "OpenCode provider for --model (for example: openrouter)")
 	cmd.Flags().StringVar(&gitlabTokenReferenceID, "gitlab-token-reference-id", "", "stored GitLab token reference ID for self-managed GitLab repositories")
 	return cmd
 }
 
+// prepareRunRequest applies the run defaults of the repository, validates
+// the run and converts it to a create request without printing progress, for
+// commands that create many runs. Unless --dry-run is set, the run is also
+// checked against the credit budgets.
+func prepareRunRequest(runConfig *models.RunConfig) (domain.CreateRunRequest, error) {
+	req, err := validateRunRequest(runConfig, dryRun)
+	if err != nil || dryRun {
+		return req, err
+	}
+	if err := checkRunBudget(context.Background(), req.RepositoryName); err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+	return req, nil
+}
+
+// validateRunRequest is prepareRunRequest without the budget check. A dry
+// run only warns about suspected secrets.
+func validateRunRequest(runConfig *models.RunConfig, dry bool) (domain.CreateRunRequest, error) {
+	defaults, err := resolveRunDefaults(runConfig.Repository)
+	if err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+	applyRunDefaults(runConfig, defaults)
+	runConfig.NormalizeBranchOutput()
+
+	profiles, err := loadModelProfiles()
+	if err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+	modelSelection := selectRunModel(runConfig, defaults, profiles)
+	if err := utils.ValidateRunConfig(runConfig); err != nil {
+		return domain.CreateRunRequest{}, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := checkSubmissionLint(runConfig, "", skipLint); err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+	if err := checkRunModel(modelSelection); err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+
+	req := newCreateRunRequest(runConfig, modelSelection, "")
+	if err := checkSubmissionSecrets(utils.ScanRunInputs(req.Prompt, req.Context, req.Files), allowSecrets, dry); err != nil {
+		return domain.CreateRunRequest{}, err
+	}
+	return req, nil
+}
+
+// newCreateRunRequest converts a validated run configuration to the domain
+// request, appending additional markdown context when present.
+func newCreateRunRequest(runConfig *models.RunConfig, modelSelection project.ModelProfile, additionalContext string) domain.CreateRunRequest {
+	req := domain.CreateRunRequest{
+		Prompt:                runConfig.Prompt,
+		RepositoryName:        runConfig.Repository,
+		SourceBranch:          runConfig.Source,
+		TargetBranch:          runConfig.Target,
+		BaseBranch:            runConfig.BaseBranch,
+		OutputMode:            runConfig.OutputMode,
+		OutputBranch:          runConfig.OutputBranch,
+		PRTargetBranch:        runConfig.PRTargetBranch,
+		OutputBranchPolicy:    runConfig.OutputBranchPolicy,
+		RunType:               runConfig.RunType,
+		Agent:                 "opencode",
+		OpenCodeModel:         modelSelection.Model,
+		OpenCodeProvider:      modelSelection.Provider,
+		Title:                 runConfig.Title,
+		Context:               runConfig.Context,
+		Files:                 runConfig.Files,
+		ProviderCredentialID:  runConfig.ProviderCredentialID,
+		ProviderMode:          runConfig.ProviderMode,
+		GitLabCredential:      domainGitLabCredential(runConfig.GitLabCredential),
+		BranchOnly:            runConfig.BranchOnly,
+		AcknowledgePromptRisk: runConfig.AcknowledgePromptRisk,
+		IdempotencyKey:        selectedIdempotencyKey(runConfig),
+	}
+
+	// Append additional markdown context if present
+	if additionalContext != "" {
+		if req.Context != "" {
+			req.Context = req.Context + "\n\n" + additionalContext
+		} else {
+			req.Context = additionalContext
+		}
+	}
+	return req
+}
+
 func selectedIdempotencyKey(runConfig *models.RunConfig) string {
 	if idempotencyKey != "" {
 		return strings.TrimSpace(idempotencyKey)
@@ -647,9 +800,26 @@ func selectedIdempotencyKey(runConfig *models.RunConfig) string {
 	})
 }
 
+func runSubmissionGuard() *idempotency.RunGuard {
+	return idempotency.NewRunGuard(idempotency.DefaultCacheDir(), 30*time.Second, time.Now)
+}
+
 func reserveRunSubmission(req domain.CreateRunRequest, force bool) error {
-	guard := idempotency.NewRunGuard(idempotency.DefaultCacheDir(), 30*time.Second, time.Now)
-	return guard.Reserve(req.IdempotencyKey, force)
+	return runSubmissionGuard().Reserve(req.IdempotencyKey, force)
+}
+
+// recordRunSubmission stores what a reserved submission became, so a
+// duplicate attempt can name the run it created.
+func recordRunSubmission(guard *idempotency.RunGuard, key string, run *domain.Run, err error) {
+	var recordErr error
+	if err != nil {
+		recordErr = guard.Fail(key, errors.FormatUserError(err))
+	} else if run != nil {
+		recordErr = guard.Complete(key, run.ID)
+	}
+	if recordErr != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), recordErr)
+	}
 }
 
 func resolveRunPreset(presetName string) (*runPreset, error) {
@@ -681,12 +851,6 @@ func selectedRunType(preset *runPreset) string {
 	return runType
 }
 
-func applyRunPreset(runConfig *models.RunConfig, preset *runPreset) {
-	if preset != nil {
-		runConfig.RunType = preset.RunType
-	}
-}
-
 func gitLabCredentialFromFlag(tokenReferenceID string) *models.GitLabCredentialRequest {
 	tokenReferenceID = strings.TrimSpace(tokenReferenceID)
 	if tokenReferenceID == "" {
@@ -708,33 +872,21 @@ func domainGitLabCredential(credential *models.GitLabCredentialRequest) *domain.
 	}
 }
 
-func modelForRunType(runType string) string {
-	preset, ok := runPresets[runType]
-	if !ok {
-		return ""
-	}
-	return preset.Model
-}
-
-func providerForRunType(runType string) string {
-	preset, ok := runPresets[runType]
-	if !ok {
-		return ""
-	}
-	return preset.Provider
-}
-
 func printRunSelection(req domain.CreateRunRequest) {
-	preset, ok := runPresets[req.RunType]
-	if !ok {
+	preset, isPreset := runPresets[req.RunType]
+	if !isPreset && req.OpenCodeModel == "" {
 		return
 	}
 	styler := stdoutStyle()
 	if req.RepositoryName != "" {
 		fmt.Printf("%s %s\n", styler.Label("Repository:"), req.RepositoryName)
 	}
-	fmt.Printf("%s %s\n", styler.Label("Run type:"), preset.Label)
-	fmt.Printf("%s %s (%s)\n", styler.Label("Model:"), modelDisplayName(preset.Model), preset.Model)
+	if isPreset {
+		fmt.Printf("%s %s\n", styler.Label("Run type:"), preset.Label)
+	}
+	if req.OpenCodeModel != "" {
+		fmt.Printf("%s %s (%s)\n", styler.Label("Model:"), modelDisplayName(req.OpenCodeModel), req.OpenCodeModel)
+	}
 }
 
 func modelDisplayName(model string) string {
@@ -761,6 +913,13 @@ func processBulkRuns(filename string) error {
 		return fmt.Errorf("failed to load bulk configuration: %w", err)
 	}
 
+	if err := checkSubmissionSecrets(bulk.ScanSecrets(bulkConfig), allowSecrets, dryRun); err != nil {
+		return err
+	}
+	if err := resolveBulkModel(bulkConfig); err != nil {
+		return err
+	}
+
 	// Auto-detection disabled for now
 	// TODO: Enable when feature is ready
 	// container := getContainer()
@@ -802,6 +961,9 @@ func processBulkRuns(filename string) error {
 				title = fmt.Sprintf("Run %d", i+1)
 			}
 			fmt.Printf("  - %s\n", title)
+			if overrides := run.Summary(); len(overrides) > 0 {
+				fmt.Printf("    %s\n", styler.Muted(strings.Join(overrides, " ")))
+			}
 		}
 		return nil
 	}
@@ -911,6 +1073,9 @@ func followRunStatus(runService domain.RunService, runID string) error {
 			fmt.Printf("DEBUG: Run completed but no PR URL available (PullRequestURL='%s')\n", finalRun.PullRequestURL)
 		}
 	}
+	if finalRun.IsTerminal() {
+		fireCompletionHooks(runHookPayload(finalRun))
+	}
 	return nil
 }
 
@@ -940,169 +1105,27 @@ func executeBulkRuns(bulkConfig *bulk.BulkConfig) error {
 	apiURL := utils.GetAPIURL(cfg.APIURL)
 	client := api.NewClient(cfg.APIKey, apiURL, debug)
 
-	// Generate file hashes for tracking purposes
-	var runHashes []string
-	fileHashCache := cache.NewFileHashCache()
-	for i, run := range bulkConfig.Runs {
-		// Create a hash based on the run content for tracking
-		hashContent := fmt.Sprintf("%s-%s-%s-%s",
-			bulkConfig.Repository,
-			run.Prompt,
-			run.Target,
-			run.Context,
-		)
-		hash := cache.CalculateStringHash(hashContent)
-		runHashes = append(runHashes, hash)
-		fileHashCache.Set(fmt.Sprintf("bulk-run-%d", i), hash)
-	}
-
-	// Convert to API request format
-	bulkRequest := &dto.BulkRunRequest{
-		RepositoryName: bulkConfig.Repository,
-		RepoID:         bulkConfig.RepoID,
-		RunType:        bulkConfig.RunType,
-		SourceBranch:   bulkConfig.Source,
-		BatchTitle:     bulkConfig.BatchTitle,
-		Force:          false,
-		Runs:           make([]dto.RunItem, len(bulkConfig.Runs)),
-		Options:        dto.BulkOptions{},
-	}
-
-	for i, run := range bulkConfig.Runs {
-		item := dto.RunItem{
-			Prompt:  run.Prompt,
-			Title:   run.Title,
-			Target:  run.Target,
-			Context: run.Context,
-		}
-		// Always include file hash for tracking purposes
-		if i < len(runHashes) {
-			item.FileHash = runHashes[i]
-		}
-		bulkRequest.Runs[i] = item
-	}
-
-	// Submit bulk runs
-	ctx := context.Background()
-
-	// Display submission info
-	styler := stdoutStyle()
-	if !jsonOutput {
-		fmt.Println(styler.Heading("Submitting bulk runs..."))
-		fmt.Printf("%s %s\n", styler.Label("Repository:"), bulkConfig.Repository)
-		fmt.Printf("%s %d\n", styler.Label("Total runs:"), len(bulkConfig.Runs))
-		fmt.Println("\nThis may take up to 5 minutes. Please wait...")
-	}
-
-	done := make(chan bool, 1) // Buffered to prevent goroutine leak
-
-	if !jsonOutput && stdoutIsTerminal() {
-		// Show a progress indicator with elapsed time
-		startTime := time.Now()
-		spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-		spinnerIdx := 0
-
-		// Start spinner in background
-		go func() {
-			ticker := time.NewTicker(100 * time.Millisecond)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-done:
-					clearLiveOutput(os.Stdout, true)
-					return
-				case <-ticker.C:
-					elapsed := time.Since(startTime)
-					printLiveUpdate(os.Stdout, true, "%s Processing... (%.0fs)", spinner[spinnerIdx], elapsed.Seconds())
-					_ = os.Stdout.Sync() // Force flush to ensure animation
-					spinnerIdx = (spinnerIdx + 1) % len(spinner)
-				}
-			}
-		}()
-	}
-
-	bulkResp, err := client.CreateBulkRuns(ctx, bulkRequest)
-	done <- true // Stop spinner
-	close(done)  // Clean up channel
-
+	bulkRequest := prepareBulkRequest(bulkConfig)
+	bulkResp, manifest, err := submitBulk(client, bulkRequest, bulkConfig, bulk.MaxBulkBatchSize, 0)
 	if err != nil {
-		// Check for timeout error
-		if ctx.Err() == context.DeadlineExceeded || netstderrors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("bulk submission timed out after 5 minutes. The server may still be processing your runs.\nTry checking the status later with 'repobird status'")
-		}
-
-		// Check if this is a 403 error which might indicate duplicate runs or quota issues
-		var authErr *errors.AuthError
-		if netstderrors.As(err, &authErr) {
-			errMsg := errors.FormatUserError(err)
-			// Check for quota-related messages
-			if strings.Contains(strings.ToLower(errMsg), "insufficient run") ||
-				strings.Contains(strings.ToLower(errMsg), "no runs remaining") {
-				return fmt.Errorf("%s\n\nUpgrade your plan at %s", errMsg, config.GetPricingURL())
-			}
-			// For other 403 errors, just return the error message
-			if !bulkConfig.Force {
-				return fmt.Errorf("%s", errMsg)
-			}
-		}
-		return fmt.Errorf("%s", errors.FormatUserError(err))
+		return err
 	}
 
+	displayBulkSubmissionResults(bulkResp, manifest)
 	if jsonOutput {
-		_ = printBulkCreateJSON(os.Stdout, bulkResp)
 		return nil
 	}
 
-	// Handle different status codes
-	if bulkResp.StatusCode == http.StatusMultiStatus {
-		// 207 Multi-Status: Some runs still processing
-		fmt.Println("\n" + styler.Warning("⚠ Bulk submission in progress:"))
-		fmt.Printf("The server is still processing your runs. This is normal for large batches.\n")
-		fmt.Printf("Created: %d/%d runs so far\n", bulkResp.Data.Metadata.TotalSuccessful, bulkResp.Data.Metadata.TotalRequested)
-
-		if len(bulkResp.Data.Failed) > 0 {
-			fmt.Println("\n" + styler.Error("Failed runs:"))
-			for _, runErr := range bulkResp.Data.Failed {
-				fmt.Printf("  %s Run %d: %s\n", styler.Error("✗"), runErr.RequestIndex+1, runErr.Message)
-			}
-		}
-
-		fmt.Println("\n" + styler.Info("ℹ  The remaining runs are being processed in the background."))
-		fmt.Println("Use --follow or check status to monitor progress.")
-	} else if len(bulkResp.Data.Failed) > 0 {
-		// Some runs failed
-		fmt.Println("\n" + styler.Warning("⚠ Partial success:"))
-		fmt.Printf("Created: %d/%d runs\n", bulkResp.Data.Metadata.TotalSuccessful, bulkResp.Data.Metadata.TotalRequested)
-
-		// Check if failures are due to duplicates
-		for _, runErr := range bulkResp.Data.Failed {
-			fmt.Printf("  %s Run %d: %s\n", styler.Error("✗"), runErr.RequestIndex+1, runErr.Message)
-			// Note: Duplicates are no longer blocked as --force is deprecated
-		}
-	} else {
-		// All runs created successfully
-		fmt.Println("\n" + styler.Success("✓ All runs created successfully"))
-	}
-
-	// Display created runs
-	if len(bulkResp.Data.Successful) > 0 {
-		fmt.Println("\nCreated runs:")
-		for _, run := range bulkResp.Data.Successful {
-			fmt.Printf("  • %s (ID: %d)\n", run.Title, run.ID)
-		}
-	}
-
 	// Follow progress if requested
-	if follow && len(bulkResp.Data.Successful) > 0 {
-		fmt.Println("\n" + styler.Info("Following batch progress..."))
+	if batchIDs := bulkBatchIDs(bulkResp, manifest); follow && len(batchIDs) > 0 {
+		fmt.Println("\n" + stdoutStyle().Info("Following batch progress..."))
 		// Create context with 1h 30m timeout
 		followCtx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
 		defer cancel()
-		return followBulkProgress(followCtx, client, bulkResp.Data.BatchID)
+		return followBulkProgress(followCtx, client, batchIDs)
 	}
 
-	fmt.Printf("\n%s %s\n", styler.Label("Batch ID:"), bulkResp.Data.BatchID)
-	fmt.Println("Use 'repobird bulk status " + bulkResp.Data.BatchID + "' to check progress")
+	printBulkStatusHint(bulkResp, manifest)
 
 	return nil
 }
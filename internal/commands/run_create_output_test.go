@@ -99,6 +99,7 @@ func TestProcessSingleRunJSONCreateOutputIsMachineReadable(t *testing.T) {
 }
 
 func TestProcessSingleRunJSONDryRunOutputIsMachineReadable(t *testing.T) {
+	isolateUserCache(t)
 	ensureRunTestConfig()
 
 	originalDryRun := dryRun
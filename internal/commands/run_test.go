@@ -26,6 +26,14 @@ func ensureRunTestConfig() {
 	}
 }
 
+// isolateUserCache points the user cache and home directories at a temp
+// dir, so caches written by the command under test stay out of the tree.
+func isolateUserCache(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
 func TestRunCommand_WithFlags(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -181,6 +189,7 @@ func TestRunCommand_WithFlags(t *testing.T) {
 }
 
 func TestRunCommand_ValidationWithFlags(t *testing.T) {
+	isolateUserCache(t)
 	tests := []struct {
 		name         string
 		args         []string
@@ -553,6 +562,7 @@ func TestRunCommand_BranchOutputFields(t *testing.T) {
 }
 
 func TestRunPresetCommand_BranchOnlyFlag(t *testing.T) {
+	isolateUserCache(t)
 	ensureRunTestConfig()
 	originalAPIKey := cfg.APIKey
 	cfg.APIKey = "test-key"
@@ -636,6 +646,7 @@ func TestRunCommand_RejectsConflictingPresetFlags(t *testing.T) {
 }
 
 func TestRunPresetCommand_UsesPromptArgument(t *testing.T) {
+	isolateUserCache(t)
 	ensureRunTestConfig()
 	originalAPIKey := cfg.APIKey
 	cfg.APIKey = "test-key"
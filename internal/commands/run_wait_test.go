@@ -181,7 +181,9 @@ func configureRunWaitTest(t *testing.T, apiURL string) func() {
 	originalWaitPollInterval := waitPollInterval
 	originalForceRun := forceRun
 	originalIdempotencyKey := idempotencyKey
+	originalFetchModelList := fetchModelList
 
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
 	cfg.APIKey = "test-key"
 	cfg.APIURL = apiURL
 	dryRun = false
@@ -192,6 +194,9 @@ func configureRunWaitTest(t *testing.T, apiURL string) func() {
 	waitPollInterval = 10 * time.Millisecond
 	forceRun = true
 	idempotencyKey = ""
+	fetchModelList = func() ([]models.ModelInfo, error) {
+		return nil, errors.New("model list unavailable")
+	}
 	resetContainer()
 
 	return func() {
@@ -205,6 +210,7 @@ func configureRunWaitTest(t *testing.T, apiURL string) func() {
 		waitPollInterval = originalWaitPollInterval
 		forceRun = originalForceRun
 		idempotencyKey = originalIdempotencyKey
+		fetchModelList = originalFetchModelList
 		resetContainer()
 	}
 }
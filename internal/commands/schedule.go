@@ -0,0 +1,447 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/idempotency"
+	"github.com/repobird/repobird-cli/internal/schedule"
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+const (
+	// scheduleGuardWindow is how long a fired slot stays in the submission
+	// guard. The schedule state already prevents refiring; the guard covers a
+	// run that was created but whose outcome could not be saved.
+	scheduleGuardWindow = 7 * 24 * time.Hour
+	// scheduleLockStale is the age after which a lock file left by a crashed
+	// process is replaced.
+	scheduleLockStale = 10 * time.Minute
+)
+
+// scheduleNow is the clock used to find due slots.
+var scheduleNow = time.Now
+
+type scheduleAddOptions struct {
+	cron          string
+	config        string
+	template      string
+	vars          []string
+	repo          string
+	timezone      string
+	catchUpWindow string
+	replace       bool
+}
+
+var scheduleCmd = newScheduleCommand()
+
+func newScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Create runs on a recurring schedule",
+		Long: `Create runs on a schedule, without external CI. A schedule pairs a cron
+expression with a run config file or a template and is kept in the config dir.
+
+Schedules fire from 'repobird schedule run-due', which can be called from cron
+or a system timer, or from 'repobird schedule daemon', which stays in the
+foreground and checks every minute. Slots missed while neither was running
+are caught up once, with the latest missed slot, unless they are older than
+the catch-up window of the schedule.`,
+	}
+
+	var addOpts scheduleAddOptions
+	add := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a schedule",
+		Long: `Add a schedule. The cron expression has five fields: minute, hour, day of
+month, month and day of week. The macros @hourly, @daily, @weekly, @monthly
+and @yearly are also accepted.`,
+		Example: `  repobird schedule add dependency-bump --cron "0 9 * * mon" --file deps.yaml
+  repobird schedule add flaky-tests --cron @daily --template triage --var suite=e2e --repo acme/api`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleAdd(cmd.OutOrStdout(), args[0], addOpts)
+		},
+	}
+	add.Flags().StringVar(&addOpts.cron, "cron", "", "cron expression of the schedule (required)")
+	add.Flags().StringVarP(&addOpts.config, "file", "f", "", "run config file (YAML, JSON or Markdown) to submit")
+	add.Flags().StringVarP(&addOpts.template, "template", "t", "", "template to render for each run")
+	add.Flags().StringArrayVar(&addOpts.vars, "var", nil, "template variable as name=value (repeatable)")
+	add.Flags().StringVarP(&addOpts.repo, "repo", "r", "", "repository to run in, replacing the one of the config or template")
+	add.Flags().StringVar(&addOpts.timezone, "timezone", "", "IANA time zone of the cron expression (default local time)")
+	add.Flags().StringVar(&addOpts.catchUpWindow, "catch-up-window", "", "only catch up missed slots this recent, e.g. 24h (default any age)")
+	add.Flags().BoolVar(&addOpts.replace, "replace", false, "replace an existing schedule with the same name")
+	_ = add.MarkFlagRequired("cron")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List schedules with their next and last runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runScheduleList(cmd.OutOrStdout())
+		},
+	}
+	list.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	remove := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a schedule and its history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleRemove(cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	runDue := &cobra.Command{
+		Use:   "run-due",
+		Short: "Submit the runs of schedules that are due",
+		Long: `Submit one run for each schedule that is due and record the outcome. Run it
+from cron or a system timer, for example every five minutes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runScheduleRunDue(cmd.OutOrStdout())
+		},
+	}
+	runDue.Flags().BoolVar(&dryRun, "dry-run", false, "show the schedules that are due without submitting runs")
+
+	var interval time.Duration
+	daemon := &cobra.Command{
+		Use:   "daemon",
+		Short: "Fire due schedules in the foreground until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.APIKey == "" {
+				return errors.NoAPIKeyError()
+			}
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runScheduleDaemon(ctx, cmd.OutOrStdout(), interval)
+		},
+	}
+	daemon.Flags().DurationVar(&interval, "interval", time.Minute, "how often to check for due schedules")
+
+	for _, c := range []*cobra.Command{runDue, daemon} {
+		c.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if a run contains suspected secrets")
+		c.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if a run has lint errors")
+		c.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if a run would exceed a configured credit budget")
+	}
+
+	cmd.AddCommand(add, list, remove, runDue, daemon)
+	return cmd
+}
+
+func runScheduleAdd(out io.Writer, name string, opts scheduleAddOptions) error {
+	switch {
+	case opts.config == "" && opts.template == "":
+		return fmt.Errorf("either --file or --template is required")
+	case opts.config != "" && opts.template != "":
+		return fmt.Errorf("--file and --template cannot be used together")
+	case len(opts.vars) > 0 && opts.template == "":
+		return fmt.Errorf("--var requires --template")
+	}
+	dir := schedule.Dir()
+	if _, err := schedule.Load(dir, name); err == nil && !opts.replace {
+		return fmt.Errorf("schedule %s already exists; use --replace to overwrite it", name)
+	}
+
+	s := &schedule.Schedule{
+		Name:          name,
+		Cron:          opts.cron,
+		Timezone:      opts.timezone,
+		Template:      opts.template,
+		Repository:    opts.repo,
+		CatchUpWindow: opts.catchUpWindow,
+		CreatedAt:     scheduleNow().UTC(),
+	}
+	if opts.config != "" {
+		configPath, err := filepath.Abs(opts.config)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", opts.config, err)
+		}
+		s.Config = configPath
+	}
+	if len(opts.vars) > 0 {
+		vars, err := templates.ParseVars(opts.vars)
+		if err != nil {
+			return err
+		}
+		s.Vars = vars
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	// Load the run now, so a broken config or template shows up here rather
+	// than when the schedule first fires.
+	if _, err := s.RunConfig(); err != nil {
+		return err
+	}
+	if err := schedule.Save(dir, s); err != nil {
+		return err
+	}
+
+	styler := styleFor(out)
+	_, _ = fmt.Fprintf(out, "%s Added schedule %s (%s)\n", styler.Success("✓"), s.Name, s.Cron)
+	if next, err := s.Next(scheduleNow()); err == nil && !next.IsZero() {
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Next run:"), formatScheduleTime(next))
+	}
+	_, _ = fmt.Fprintln(out, "Runs fire from 'repobird schedule run-due' or 'repobird schedule daemon'")
+	return nil
+}
+
+// scheduleListEntry is a schedule with its next and last runs.
+type scheduleListEntry struct {
+	*schedule.Schedule
+	NextRun *time.Time         `json:"nextRun,omitempty"`
+	LastRun *cache.ScheduleRun `json:"lastRun,omitempty"`
+}
+
+func runScheduleList(out io.Writer) error {
+	schedules, err := schedule.List(schedule.Dir())
+	if err != nil {
+		return err
+	}
+	now := scheduleNow()
+	entries := make([]scheduleListEntry, 0, len(schedules))
+	for _, s := range schedules {
+		entry := scheduleListEntry{Schedule: s}
+		if next, err := s.Next(now); err == nil && !next.IsZero() {
+			entry.NextRun = &next
+		}
+		if state, err := cache.LoadScheduleState(cache.ScheduleDir(), s.Name); err == nil {
+			entry.LastRun = state.Last()
+		}
+		entries = append(entries, entry)
+	}
+
+	if jsonOutput {
+		return printJSON(out, scheduleListJSONOutput{
+			Schema:    "repobird.schedule.v1",
+			Operation: "schedule.list",
+			Schedules: entries,
+		})
+	}
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No schedules. Add one with 'repobird schedule add <name> --cron <expr> --file <file>'")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tCRON\tSOURCE\tNEXT RUN\tLAST RUN")
+	for _, entry := range entries {
+		next := "-"
+		if entry.NextRun != nil {
+			next = formatScheduleTime(*entry.NextRun)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Name, entry.Cron, entry.Source(), next, describeScheduleRun(entry.LastRun))
+	}
+	return w.Flush()
+}
+
+func describeScheduleRun(run *cache.ScheduleRun) string {
+	if run == nil {
+		return "-"
+	}
+	text := fmt.Sprintf("%s %s", formatScheduleTime(run.Slot), run.Status)
+	if run.RunID != "" {
+		text += " (run " + run.RunID + ")"
+	}
+	return text
+}
+
+func runScheduleRemove(out io.Writer, name string) error {
+	if err := schedule.Remove(schedule.Dir(), name); err != nil {
+		return err
+	}
+	if err := cache.RemoveScheduleState(cache.ScheduleDir(), name); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+	}
+	_, _ = fmt.Fprintf(out, "%s Removed schedule %s\n", styleFor(out).Success("✓"), name)
+	return nil
+}
+
+func runScheduleRunDue(out io.Writer) error {
+	if !dryRun && cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+	handled, err := fireDueSchedules(out, scheduleNow())
+	if err != nil {
+		return err
+	}
+	if handled == 0 {
+		_, _ = fmt.Fprintln(out, "No schedules are due")
+	}
+	return nil
+}
+
+// runScheduleDaemon fires due schedules every interval until ctx is done.
+// Errors of one pass are reported and the next pass tries again.
+func runScheduleDaemon(ctx context.Context, out io.Writer, interval time.Duration) error {
+	_, _ = fmt.Fprintf(out, "Checking schedules every %s; press Ctrl+C to stop\n", interval)
+	for {
+		if _, err := fireDueSchedules(out, scheduleNow()); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+		select {
+		case <-ctx.Done():
+			_, _ = fmt.Fprintln(out, "Schedule daemon stopped")
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fireDueSchedules handles the due slot of every schedule and returns how
+// many slots were handled. A failed submission is recorded and reported in
+// the error once every schedule was handled; its slot stays due, so the next
+// pass tries it again. A lock file keeps two processes from firing the same
+// slot; each slot is also reserved in a submission guard under a key scoped
+// to the slot, and that key is sent as the idempotency key of the run.
+func fireDueSchedules(out io.Writer, now time.Time) (int, error) {
+	schedules, err := schedule.List(schedule.Dir())
+	if err != nil || len(schedules) == 0 {
+		return 0, err
+	}
+	stateDir := cache.ScheduleDir()
+	if !dryRun {
+		unlock, err := cache.LockFile(filepath.Join(stateDir, "run-due.lock"), scheduleLockStale)
+		if err != nil {
+			if stderrors.Is(err, cache.ErrLocked) {
+				return 0, fmt.Errorf("schedules are already being fired by another process (%w)", err)
+			}
+			return 0, err
+		}
+		defer unlock()
+	}
+
+	var runService domain.RunService
+	handled, failed := 0, 0
+	for _, s := range schedules {
+		state, err := cache.LoadScheduleState(stateDir, s.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+			continue
+		}
+		slot, due, err := s.Due(state.LastSlot, now)
+		if err != nil || !due {
+			continue
+		}
+		handled++
+		if dryRun {
+			printScheduleDue(out, s, slot)
+			continue
+		}
+
+		outcome := cache.ScheduleRun{Slot: slot.Time.UTC(), Skipped: slot.Missed}
+		if slot.Late {
+			outcome.Status = cache.ScheduleRunMissed
+			outcome.Error = "past the catch-up window"
+		} else {
+			if runService == nil {
+				runService = getContainer().RunService()
+			}
+			run, err := fireScheduleSlot(runService, s, slot.Time)
+			switch {
+			case stderrors.Is(err, idempotency.ErrDuplicate):
+				outcome.Status = cache.ScheduleRunDuplicate
+				outcome.Error = "slot was already submitted"
+			case err != nil:
+				outcome.Status = cache.ScheduleRunFailed
+				outcome.Error = errors.FormatUserError(err)
+				failed++
+			default:
+				outcome.Status = cache.ScheduleRunSubmitted
+				outcome.RunID = run.ID
+			}
+		}
+		state.Record(outcome)
+		if err := cache.SaveScheduleState(stateDir, state); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+		printScheduleOutcome(out, s, outcome)
+	}
+	if failed > 0 {
+		return handled, fmt.Errorf("%d scheduled run(s) could not be submitted", failed)
+	}
+	return handled, nil
+}
+
+// fireScheduleSlot creates the run of one slot.
+func fireScheduleSlot(runService domain.RunService, s *schedule.Schedule, slot time.Time) (*domain.Run, error) {
+	runConfig, err := s.RunConfig()
+	if err != nil {
+		return nil, err
+	}
+	if runConfig.Title == "" {
+		runConfig.Title = fmt.Sprintf("%s (%s)", s.Name, slot.Format("2006-01-02 15:04"))
+	}
+	runConfig.IdempotencyKey = schedule.SlotKey(s.Name, slot)
+	req, err := prepareRunRequest(runConfig)
+	if err != nil {
+		return nil, err
+	}
+	guard := idempotency.NewRunGuard(scheduleGuardDir(), scheduleGuardWindow, scheduleNow)
+	if err := guard.Reserve(req.IdempotencyKey, false); err != nil {
+		return nil, err
+	}
+	run, err := runService.CreateRun(context.Background(), req)
+	recordRunSubmission(guard, req.IdempotencyKey, run, err)
+	return run, err
+}
+
+// scheduleGuardDir keeps slot keys apart from the short-lived keys of
+// interactive submissions, which are pruned after seconds.
+func scheduleGuardDir() string {
+	return filepath.Join(idempotency.DefaultCacheDir(), "schedules")
+}
+
+func printScheduleDue(out io.Writer, s *schedule.Schedule, slot schedule.Slot) {
+	styler := styleFor(out)
+	action := "would submit a run"
+	if slot.Late {
+		action = "would pass over the slot, which is past the catch-up window"
+	}
+	_, _ = fmt.Fprintf(out, "%s %s: %s for %s%s\n", styler.Info("•"), s.Name, action, formatScheduleTime(slot.Time), missedNote(slot.Missed))
+}
+
+func printScheduleOutcome(out io.Writer, s *schedule.Schedule, outcome cache.ScheduleRun) {
+	styler := styleFor(out)
+	slot := formatScheduleTime(outcome.Slot) + missedNote(outcome.Skipped)
+	switch outcome.Status {
+	case cache.ScheduleRunSubmitted:
+		_, _ = fmt.Fprintf(out, "%s %s: submitted run %s for %s\n", styler.Success("✓"), s.Name, outcome.RunID, slot)
+	case cache.ScheduleRunFailed:
+		_, _ = fmt.Fprintf(out, "%s %s: failed to submit the run for %s: %s\n", styler.Error("✗"), s.Name, slot, firstLine(outcome.Error))
+	default:
+		_, _ = fmt.Fprintf(out, "%s %s: %s for %s (%s)\n", styler.Warning("!"), s.Name, outcome.Status, slot, outcome.Error)
+	}
+}
+
+func missedNote(missed int) string {
+	if missed == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (after %d missed slot(s))", missed)
+}
+
+func formatScheduleTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04 MST")
+}
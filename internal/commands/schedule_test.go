@@ -0,0 +1,220 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/schedule"
+)
+
+type scheduleTestServer struct {
+	mu      sync.Mutex
+	created []map[string]any
+}
+
+func (s *scheduleTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v1/runs" {
+		http.NotFound(w, r)
+		return
+	}
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.created = append(s.created, body)
+	id := 500 + len(s.created)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+		"id": id, "status": "QUEUED", "repositoryName": body["repositoryName"],
+	}})
+}
+
+func (s *scheduleTestServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.created)
+}
+
+// configureScheduleTest adds a nightly schedule created at start and sets
+// the schedule clock to now.
+func configureScheduleTest(t *testing.T, start time.Time, now *time.Time) *scheduleTestServer {
+	t.Helper()
+	server := &scheduleTestServer{}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(configureRunWaitTest(t, httpServer.URL))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	jsonOutput = false
+
+	originalNow := scheduleNow
+	scheduleNow = func() time.Time { return *now }
+	t.Cleanup(func() { scheduleNow = originalNow })
+
+	file := filepath.Join(t.TempDir(), "triage.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("prompt: Triage the flaky tests reported by the nightly build\n"), 0644))
+	*now = start
+	var out bytes.Buffer
+	require.NoError(t, runScheduleAdd(&out, "nightly", scheduleAddOptions{
+		cron: "0 2 * * *", config: file, repo: "acme/api", timezone: "UTC",
+	}))
+	assert.Contains(t, out.String(), "Added schedule nightly (0 2 * * *)")
+	return server
+}
+
+func TestScheduleRunDueFiresEachSlotOnce(t *testing.T) {
+	now := time.Time{}
+	server := configureScheduleTest(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), &now)
+
+	var out bytes.Buffer
+	now = time.Date(2026, 10, 11, 1, 0, 0, 0, time.UTC)
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Contains(t, out.String(), "No schedules are due")
+	assert.Equal(t, 0, server.count())
+
+	// The first night was missed; one run catches up with the latest slot.
+	out.Reset()
+	now = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Contains(t, out.String(), "nightly: submitted run 501")
+	assert.Contains(t, out.String(), "after 1 missed slot(s)")
+	require.Equal(t, 1, server.count())
+	assert.Equal(t, "acme/api", server.created[0]["repositoryName"])
+	assert.Equal(t, "schedule:nightly:2026-10-12T02:00:00Z", server.created[0]["idempotencyKey"])
+
+	out.Reset()
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Contains(t, out.String(), "No schedules are due")
+	assert.Equal(t, 1, server.count())
+
+	state, err := cache.LoadScheduleState(cache.ScheduleDir(), "nightly")
+	require.NoError(t, err)
+	require.Len(t, state.Runs, 1)
+	assert.Equal(t, cache.ScheduleRun{
+		Slot: time.Date(2026, 10, 12, 2, 0, 0, 0, time.UTC), Status: cache.ScheduleRunSubmitted,
+		RunID: "501", Skipped: 1, FiredAt: state.Runs[0].FiredAt,
+	}, state.Runs[0])
+}
+
+func TestScheduleRunDueSkipsSlotInGuard(t *testing.T) {
+	now := time.Time{}
+	server := configureScheduleTest(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), &now)
+	now = time.Date(2026, 10, 11, 2, 1, 0, 0, time.UTC)
+
+	// A run created by a process that could not save the schedule state is
+	// still in the submission guard.
+	require.NoError(t, os.MkdirAll(scheduleGuardDir(), 0755))
+	key := schedule.SlotKey("nightly", time.Date(2026, 10, 11, 2, 0, 0, 0, time.UTC))
+	data, err := json.Marshal(map[string]any{"submissions": map[string]time.Time{key: now.Add(-time.Minute)}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(scheduleGuardDir(), "run_submissions.json"), data, 0644))
+
+	var out bytes.Buffer
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Contains(t, out.String(), "nightly: duplicate")
+	assert.Equal(t, 0, server.count())
+}
+
+func TestScheduleRunDueHonorsLock(t *testing.T) {
+	now := time.Time{}
+	server := configureScheduleTest(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), &now)
+	now = time.Date(2026, 10, 11, 2, 1, 0, 0, time.UTC)
+
+	unlock, err := cache.LockFile(filepath.Join(cache.ScheduleDir(), "run-due.lock"), time.Hour)
+	require.NoError(t, err)
+	err = runScheduleRunDue(&bytes.Buffer{})
+	require.ErrorIs(t, err, cache.ErrLocked)
+	assert.Equal(t, 0, server.count())
+
+	unlock()
+	dryRun = true
+	var out bytes.Buffer
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Contains(t, out.String(), "nightly: would submit a run")
+	assert.Equal(t, 0, server.count())
+}
+
+func TestScheduleDaemonAndList(t *testing.T) {
+	now := time.Time{}
+	server := configureScheduleTest(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), &now)
+	now = time.Date(2026, 10, 11, 2, 1, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	require.NoError(t, runScheduleDaemon(ctx, &out, time.Minute))
+	assert.Contains(t, out.String(), "nightly: submitted run 501")
+	assert.Contains(t, out.String(), "Schedule daemon stopped")
+	assert.Equal(t, 1, server.count())
+
+	jsonOutput = true
+	out.Reset()
+	require.NoError(t, runScheduleList(&out))
+	var listed scheduleListJSONOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &listed))
+	require.Len(t, listed.Schedules, 1)
+	entry := listed.Schedules[0]
+	assert.Equal(t, "nightly", entry.Name)
+	assert.Equal(t, time.Date(2026, 10, 12, 2, 0, 0, 0, time.UTC), entry.NextRun.UTC())
+	assert.Equal(t, "501", entry.LastRun.RunID)
+
+	out.Reset()
+	require.NoError(t, runScheduleRemove(&out, "nightly"))
+	_, err := os.Stat(filepath.Join(cache.ScheduleDir(), "nightly.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestScheduleAddValidates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var out bytes.Buffer
+	assert.EqualError(t, runScheduleAdd(&out, "weekly", scheduleAddOptions{cron: "@weekly"}), "either --file or --template is required")
+	assert.Error(t, runScheduleAdd(&out, "weekly", scheduleAddOptions{cron: "@weekly", config: filepath.Join(t.TempDir(), "missing.yaml")}))
+	assert.Error(t, runScheduleAdd(&out, "weekly", scheduleAddOptions{cron: "every week", template: "triage"}))
+}
+
+func TestScheduleRunDueRecordsFailures(t *testing.T) {
+	now := time.Time{}
+	server := configureScheduleTest(t, time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), &now)
+	now = time.Date(2026, 10, 11, 2, 1, 0, 0, time.UTC)
+
+	s, err := schedule.Load(schedule.Dir(), "nightly")
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(s.Config))
+
+	var out bytes.Buffer
+	require.EqualError(t, runScheduleRunDue(&out), "1 scheduled run(s) could not be submitted")
+	assert.Contains(t, out.String(), "nightly: failed to submit the run")
+	assert.Equal(t, 0, server.count())
+
+	state, err := cache.LoadScheduleState(cache.ScheduleDir(), "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, cache.ScheduleRunFailed, state.Last().Status)
+	assert.True(t, state.LastSlot.IsZero())
+
+	// The failed slot is tried again on the next pass.
+	require.NoError(t, os.WriteFile(s.Config, []byte("prompt: Triage the flaky tests reported by the nightly build\n"), 0644))
+	out.Reset()
+	require.NoError(t, runScheduleRunDue(&out))
+	assert.Equal(t, 1, server.count())
+	state, err = cache.LoadScheduleState(cache.ScheduleDir(), "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, cache.ScheduleRunSubmitted, state.Last().Status)
+	assert.Equal(t, time.Date(2026, 10, 11, 2, 0, 0, 0, time.UTC), state.LastSlot)
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var allowSecrets bool
+
+// checkSubmissionSecrets blocks a submission when the scanner found suspected
+// secrets, unless --allow-secrets was passed. Allowed findings are still
+// listed on stderr so they do not go unnoticed, as are the findings of a dry
+// run, which only reports what the submission would do.
+func checkSubmissionSecrets(findings []utils.SecretFinding, allow, dry bool) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	styler := stderrStyle()
+	switch {
+	case dry && !allow:
+		fmt.Fprintf(os.Stderr, "%s %d possible secret(s) would block the submission without --allow-secrets\n%s\n",
+			styler.Warning("Warning:"), len(findings), utils.FormatSecretFindings(findings))
+		return nil
+	case allow:
+		fmt.Fprintf(os.Stderr, "%s submitting with %d possible secret(s) (--allow-secrets)\n%s\n",
+			styler.Warning("Warning:"), len(findings), utils.FormatSecretFindings(findings))
+		return nil
+	}
+	return fmt.Errorf("%w\n\nRemove the secrets or pass --allow-secrets to submit anyway", &utils.SecretsDetectedError{Findings: findings})
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/daemon"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/services"
+	tuicache "github.com/repobird/repobird-cli/internal/tui/cache"
+	"github.com/repobird/repobird-cli/internal/utils"
+	"github.com/repobird/repobird-cli/pkg/version"
+)
+
+type serveOptions struct {
+	listen   string
+	interval time.Duration
+	limit    int
+	dir      string
+}
+
+var serveCmd = newServeCommand()
+
+func newServeCommand() *cobra.Command {
+	opts := serveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local daemon that shares API polling between clients",
+		Long: `Run a local daemon that owns authentication and polling for every RepoBird
+client on this machine. Terminals, the TUI, editor plugins and scripts then
+share one poll of the run list instead of each polling the API.
+
+While the daemon runs, the CLI and TUI send their API requests through it
+automatically when they use the same API URL and key. Set REPOBIRD_NO_DAEMON=1
+to bypass it. Run lists, run details and auth checks are answered from a cache
+refreshed every --interval; other requests, including logs and new runs, are
+forwarded to the API.
+
+The daemon listens on a Unix socket in the cache directory by default, or on
+a localhost port with --listen. Clients authenticate with the token in
+daemon.json next to the socket, sent in the X-RepoBird-Daemon-Token header
+(or a token query parameter for WebSockets).
+
+Endpoints:
+  GET /health     daemon status (no token needed)
+  GET /events     WebSocket feed of run events, as in 'repobird events';
+                  ?repo=owner/repo narrows it to one repository
+  /api/v1/...     the RepoBird API`,
+		Example: `  repobird serve
+  repobird serve --listen 127.0.0.1:7457 --interval 30s
+  REPOBIRD_NO_DAEMON=1 repobird status`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return serveCommand(ctx, cmd.ErrOrStderr(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.listen, "listen", "", "localhost host:port or unix:PATH to listen on (default: a Unix socket in the cache directory)")
+	cmd.Flags().DurationVar(&opts.interval, "interval", daemon.DefaultInterval, "time between polls of the run list")
+	cmd.Flags().IntVar(&opts.limit, "limit", daemon.DefaultListLimit, "number of recent runs each poll lists")
+	return cmd
+}
+
+func serveCommand(ctx context.Context, out io.Writer, opts serveOptions) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("--interval must be greater than zero")
+	}
+	// The daemon's own requests must go to the API, not to itself.
+	daemon.Disable()
+
+	secureConfig := cfg
+	if secureConfig == nil {
+		loaded, err := config.LoadSecureConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		secureConfig = loaded
+	}
+	if secureConfig.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+	apiURL := utils.GetAPIURL(secureConfig.APIURL)
+
+	dir := opts.dir
+	if dir == "" {
+		dir = daemon.DefaultDir()
+	}
+	statePath := daemon.StatePath(dir)
+	if existing, err := daemon.ReadState(statePath); err == nil && existing != nil && existing.Alive() {
+		return fmt.Errorf("a daemon is already running (pid %d) on %s", existing.PID, existing.Address)
+	}
+
+	client := api.NewClient(secureConfig.APIKey, apiURL, secureConfig.Debug)
+	userInfo, err := client.GetUserInfoWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate: %s", errors.FormatUserError(err))
+	}
+	services.SetCurrentUser(userInfo)
+	runCache := tuicache.NewSimpleCache()
+
+	token, err := daemon.NewToken()
+	if err != nil {
+		return err
+	}
+	server, err := daemon.NewServer(daemon.Config{
+		APIURL:    apiURL,
+		APIKey:    secureConfig.APIKey,
+		Token:     token,
+		Interval:  opts.interval,
+		ListLimit: opts.limit,
+		Warm: func(runs []*models.RunResponse) {
+			warmed := make([]models.RunResponse, 0, len(runs))
+			for _, run := range runs {
+				if run != nil {
+					warmed = append(warmed, *run)
+				}
+			}
+			runCache.SetRuns(warmed)
+		},
+		Batches: func() ([]*cache.BulkBatchRecord, error) {
+			return cache.ListBulkBatches(cache.BulkLedgerDir())
+		},
+		Logf: func(format string, args ...any) {
+			fmt.Fprintf(out, "%s %s\n", stderrStyle().Warning("Warning:"), fmt.Sprintf(format, args...))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	listener, network, address, err := daemon.Listen(opts.listen, dir)
+	if err != nil {
+		return err
+	}
+	state := &daemon.State{
+		Network:   network,
+		Address:   address,
+		Token:     token,
+		PID:       os.Getpid(),
+		APIURL:    apiURL,
+		KeyID:     daemon.KeyID(secureConfig.APIKey),
+		Version:   version.GetVersion(),
+		StartedAt: time.Now().UTC(),
+	}
+	if err := state.Save(statePath); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	defer func() {
+		_ = os.Remove(statePath)
+		if network == "unix" {
+			_ = os.Remove(address)
+		}
+	}()
+
+	httpServer := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go server.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		server.Close()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(out, "%s RepoBird daemon listening on %s %s (pid %d, polling every %s)\n",
+		stderrStyle().Success("✓"), network, address, state.PID, opts.interval)
+	if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("daemon stopped: %w", err)
+	}
+	return nil
+}
@@ -170,7 +170,7 @@ func listRuns(client *api.Client) error {
 
 	if len(runs) == 0 {
 		fmt.Println(styler.Muted("No runs found"))
-		return nil
+		return printLocallyQueuedRuns(os.Stdout)
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -199,7 +199,7 @@ func listRuns(client *api.Client) error {
 	if err := w.Flush(); err != nil {
 		return fmt.Errorf("failed to flush output: %w", err)
 	}
-	return nil
+	return printLocallyQueuedRuns(os.Stdout)
 }
 
 func followSingleRun(client *api.Client, runID string) error {
@@ -0,0 +1,208 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+var (
+	templateName string
+	templateVars []string
+)
+
+var templatesCmd = newTemplatesCommand()
+
+func newTemplatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "List and inspect prompt templates",
+		Long: `List and inspect prompt templates used with 'repobird run --template'.
+
+Templates are Markdown files whose body is a Go text/template. They are loaded
+from, in increasing precedence:
+  ~/.config/repobird/templates/     personal templates
+  .repobird/templates/              project templates at the repository root
+  templates: in .repobird.yaml      named project template files
+
+Frontmatter declares variables and default run settings:
+
+  ---
+  description: Add unit tests for a package
+  title: "Add tests for {{.package}}"
+  variables:
+    - name: package
+      required: true
+    - name: framework
+      default: testify
+  ---
+  Add table-driven unit tests for {{.package}} using {{.framework}}.`,
+		Example: `  repobird templates list
+  repobird templates show add-tests
+  repobird run --template add-tests --var package=internal/api`,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List available templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			library, err := templates.LoadDefault()
+			if err != nil {
+				return err
+			}
+			return printTemplateList(cmd.OutOrStdout(), library.List())
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a template's variables and rendered preview",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			library, err := templates.LoadDefault()
+			if err != nil {
+				return err
+			}
+			tmpl, err := library.Get(args[0])
+			if err != nil {
+				return err
+			}
+			return printTemplateDetails(cmd.OutOrStdout(), tmpl)
+		},
+	})
+	return cmd
+}
+
+func printTemplateList(out io.Writer, list []*templates.Template) error {
+	styler := styleFor(out)
+	if len(list) == 0 {
+		_, err := fmt.Fprintf(out, "%s add Markdown templates to %s or %s\n",
+			styler.Muted("No templates found;"), templates.UserDir(), templates.ProjectDir)
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSOURCE\tREQUIRED\tDESCRIPTION")
+	for _, tmpl := range list {
+		required := strings.Join(tmpl.RequiredVariables(), ", ")
+		if required == "" {
+			required = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tmpl.Name, tmpl.Source, required, tmpl.Description)
+	}
+	return w.Flush()
+}
+
+func printTemplateDetails(out io.Writer, tmpl *templates.Template) error {
+	styler := styleFor(out)
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Template:"), tmpl.Name)
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Path:"), tmpl.Path)
+	if tmpl.Description != "" {
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Description:"), tmpl.Description)
+	}
+	if len(tmpl.Variables) > 0 {
+		_, _ = fmt.Fprintf(out, "\n%s\n", styler.Heading("Variables:"))
+		for _, variable := range tmpl.Variables {
+			detail := "optional"
+			switch {
+			case variable.Required:
+				detail = "required"
+			case variable.Default != "":
+				detail = "default: " + variable.Default
+			}
+			line := fmt.Sprintf("  %s (%s)", variable.Name, detail)
+			if variable.Description != "" {
+				line += " - " + variable.Description
+			}
+			_, _ = fmt.Fprintln(out, line)
+		}
+	}
+
+	preview, err := tmpl.Preview()
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "\n%s\n%s\n", styler.Heading("Prompt preview:"), preview.Prompt)
+	return nil
+}
+
+// runConfigFromTemplate renders the --template selection and applies any run
+// flags on top, since flags take precedence over template defaults.
+func runConfigFromTemplate(name string, pairs []string, preset *runPreset) (*models.RunConfig, error) {
+	vars, err := templates.ParseVars(pairs)
+	if err != nil {
+		return nil, err
+	}
+	library, err := templates.LoadDefault()
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := library.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	runConfig, err := tmpl.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRunFlags(runConfig, preset); err != nil {
+		return nil, err
+	}
+	if runConfig.RunType == "" {
+		runConfig.RunType = "run"
+	}
+	return runConfig, nil
+}
+
+// applyRunFlags overrides the fields of a template, run file or stdin config
+// with the run flags that were set.
+func applyRunFlags(runConfig *models.RunConfig, preset *runPreset) error {
+	for _, field := range []struct {
+		flag  string
+		value *string
+	}{
+		{repo, &runConfig.Repository},
+		{source, &runConfig.Source},
+		{target, &runConfig.Target},
+		{baseBranch, &runConfig.BaseBranch},
+		{outputMode, &runConfig.OutputMode},
+		{outputBranch, &runConfig.OutputBranch},
+		{prTargetBranch, &runConfig.PRTargetBranch},
+		{outputBranchPolicy, &runConfig.OutputBranchPolicy},
+		{title, &runConfig.Title},
+		{selectedRunType(preset), &runConfig.RunType},
+		{providerCredentialID, &runConfig.ProviderCredentialID},
+		{providerMode, &runConfig.ProviderMode},
+		{runModel, &runConfig.Model},
+		{runProvider, &runConfig.Provider},
+	} {
+		if field.flag != "" {
+			*field.value = field.flag
+		}
+	}
+	if contextFlag != "" {
+		processedContext, err := utils.ReadPromptInput(contextFlag)
+		if err != nil {
+			return fmt.Errorf("failed to process context: %w", err)
+		}
+		runConfig.Context = processedContext
+	}
+	if credential := gitLabCredentialFromFlag(gitlabTokenReferenceID); credential != nil {
+		runConfig.GitLabCredential = credential
+	}
+	if branchOnly {
+		runConfig.BranchOnly = true
+	}
+	if acknowledgePromptRisk {
+		runConfig.AcknowledgePromptRisk = true
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectTemplate(t *testing.T, name, content string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := writeProjectCheckout(t, "repository: acme/webapp\n")
+	dir := filepath.Join(root, ".repobird", "templates")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".md"), []byte(content), 0644))
+}
+
+func TestRunConfigFromTemplateAppliesFlags(t *testing.T) {
+	writeProjectTemplate(t, "add-tests", `---
+description: Add unit tests
+title: "Tests for {{.package}}"
+baseBranch: develop
+variables:
+  - name: package
+    required: true
+---
+Add unit tests for {{.package}}.`)
+
+	originalTitle, originalBase := title, baseBranch
+	defer func() { title, baseBranch = originalTitle, originalBase }()
+	title, baseBranch = "", "release"
+
+	runConfig, err := runConfigFromTemplate("add-tests", []string{"package=internal/api"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Add unit tests for internal/api.", runConfig.Prompt)
+	assert.Equal(t, "Tests for internal/api", runConfig.Title)
+	assert.Equal(t, "release", runConfig.BaseBranch)
+	assert.Equal(t, "run", runConfig.RunType)
+
+	_, err = runConfigFromTemplate("add-tests", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required variable(s): package")
+}
+
+func TestTemplatesListAndShow(t *testing.T) {
+	writeProjectTemplate(t, "fix-issue", `---
+description: Fix a tracked issue
+variables:
+  - name: issue
+    required: true
+  - name: area
+    default: api
+---
+Fix issue {{.issue}} in {{.area}}.`)
+
+	var list bytes.Buffer
+	cmd := newTemplatesCommand()
+	cmd.SetOut(&list)
+	cmd.SetArgs([]string{"list"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, list.String(), "fix-issue")
+	assert.Contains(t, list.String(), "Fix a tracked issue")
+
+	var show bytes.Buffer
+	cmd = newTemplatesCommand()
+	cmd.SetOut(&show)
+	cmd.SetArgs([]string{"show", "fix-issue"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, show.String(), "area (default: api)")
+	assert.Contains(t, show.String(), "Fix issue <issue> in api.")
+}
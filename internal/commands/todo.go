@@ -0,0 +1,205 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/todo"
+)
+
+// todoLedgerSource is the ledger source of scanned TODO markers, which are
+// keyed by fingerprint.
+const todoLedgerSource = "todo"
+
+type todoScanOptions struct {
+	repo           string
+	source         string
+	output         string
+	submit         bool
+	contextLines   int
+	includeHandled bool
+}
+
+var todoCmd = newTodoCommand()
+
+func newTodoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "todo",
+		Short: "Turn TODO(repobird) comments into runs",
+	}
+
+	var opts todoScanOptions
+	scan := &cobra.Command{
+		Use:   "scan [path]",
+		Short: "Collect TODO(repobird) comments into a bulk config",
+		Long: `Walk a checkout for TODO(repobird) comments and turn each one into a bulk
+run. The comment text, including the lines that continue it, becomes the
+prompt, and the surrounding code becomes the context.
+
+  // TODO(repobird): retry failed uploads with exponential backoff
+  //   and give up after five attempts.
+
+Paths ignored by .gitignore and dependency or build directories are skipped.
+By default the bulk YAML config is printed. Use --output to write it for
+review, or --submit to send the runs to bulk now. Markers written with
+--output or submitted are remembered, and later scans skip them unless
+--include-handled is set.`,
+		Example: `  repobird todo scan --output todos.yaml
+  repobird todo scan ./services/api --repo acme/api --submit`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := "."
+			if len(args) == 1 {
+				root = args[0]
+			}
+			return runTodoScan(cmd.OutOrStdout(), root, opts)
+		},
+	}
+	scan.Flags().StringVarP(&opts.repo, "repo", "r", "", "repository to run the tasks in (default from project defaults or the git remote)")
+	scan.Flags().StringVarP(&opts.source, "source", "s", "", "source branch for the runs")
+	scan.Flags().StringVarP(&opts.output, "output", "o", "", "write the bulk YAML config to a file")
+	scan.Flags().BoolVar(&opts.submit, "submit", false, "submit the runs with bulk instead of writing a config")
+	scan.Flags().IntVar(&opts.contextLines, "context-lines", todo.DefaultContextLines, "lines of code kept above and below each comment")
+	scan.Flags().BoolVar(&opts.includeHandled, "include-handled", false, "also include comments that were already exported or submitted")
+	scan.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if the comments or code contain suspected secrets")
+	scan.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if the runs would exceed a configured credit budget")
+
+	cmd.AddCommand(scan)
+	return cmd
+}
+
+func runTodoScan(out io.Writer, root string, opts todoScanOptions) error {
+	if opts.submit && opts.output != "" {
+		return fmt.Errorf("--submit and --output cannot be used together")
+	}
+	contextLines := opts.contextLines
+	if contextLines == 0 {
+		contextLines = -1
+	}
+	markers, err := todo.Scan(root, todo.Options{ContextLines: contextLines})
+	if err != nil {
+		return err
+	}
+	if len(markers) == 0 {
+		return fmt.Errorf("no TODO(repobird) comments found in %s", root)
+	}
+
+	repository, err := taskRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	ledgerPath := cache.ImportLedgerPath()
+	ledger, err := cache.LoadImportLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+	var pending []todo.Marker
+	skipped := 0
+	for _, marker := range markers {
+		if _, handled := ledger.Lookup(todoLedgerSource, repository, marker.Fingerprint); handled && !opts.includeHandled {
+			skipped++
+			continue
+		}
+		pending = append(pending, marker)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s skipping %d comment(s) already handled for %s (use --include-handled to include them)\n",
+			stderrStyle().Info("Note:"), skipped, repository)
+	}
+	if len(pending) == 0 {
+		return fmt.Errorf("no new TODO(repobird) comments to submit")
+	}
+
+	bulkConfig := &bulk.BulkConfig{
+		Repository: repository,
+		Source:     opts.source,
+		RunType:    "run",
+		BatchTitle: fmt.Sprintf("TODO(repobird) comments (%d)", len(pending)),
+	}
+	for _, marker := range pending {
+		bulkConfig.Runs = append(bulkConfig.Runs, marker.Run())
+	}
+
+	if opts.submit {
+		return submitTodoRuns(bulkConfig, pending, ledger, ledgerPath)
+	}
+
+	data, err := yaml.Marshal(bulkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encode bulk config: %w", err)
+	}
+	if opts.output == "" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.output, err)
+	}
+	for _, marker := range pending {
+		record := todoRecord(repository, marker, cache.ImportStateExported, "")
+		record.File = opts.output
+		ledger.Record(record)
+	}
+	if err := cache.SaveImportLedger(ledgerPath, ledger); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s Wrote %d runs to %s\nReview the file, then submit it with 'repobird bulk %s'\n",
+		styleFor(out).Success("✓"), len(bulkConfig.Runs), opts.output, opts.output)
+	return err
+}
+
+// submitTodoRuns sends the runs to bulk and records each created run in the
+// ledger.
+func submitTodoRuns(bulkConfig *bulk.BulkConfig, markers []todo.Marker, ledger *cache.ImportLedger, ledgerPath string) error {
+	client, err := bulkManageClient()
+	if err != nil {
+		return fmt.Errorf("%w, or write the runs to a file with --output", err)
+	}
+	if err := checkSubmissionSecrets(bulk.ScanSecrets(bulkConfig), allowSecrets, false); err != nil {
+		return err
+	}
+	if err := resolveBulkModel(bulkConfig); err != nil {
+		return err
+	}
+
+	bulkRequest := prepareBulkRequest(bulkConfig)
+	bulkResp, manifest, err := submitBulk(client, bulkRequest, bulkConfig, bulk.MaxBulkBatchSize, 0)
+	if err != nil {
+		return err
+	}
+	for _, created := range bulkResp.Data.Successful {
+		if created.RequestIndex < 0 || created.RequestIndex >= len(markers) {
+			continue
+		}
+		marker := markers[created.RequestIndex]
+		ledger.Record(todoRecord(bulkConfig.Repository, marker, cache.ImportStateSubmitted, fmt.Sprint(created.ID)))
+	}
+	if err := cache.SaveImportLedger(ledgerPath, ledger); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+	}
+
+	displayBulkSubmissionResults(bulkResp, manifest)
+	printBulkStatusHint(bulkResp, manifest)
+	return nil
+}
+
+func todoRecord(repository string, marker todo.Marker, state, runID string) cache.ImportRecord {
+	return cache.ImportRecord{
+		Source:     todoLedgerSource,
+		Repository: repository,
+		IssueKey:   marker.Fingerprint,
+		Title:      fmt.Sprintf("%s:%d %s", marker.Path, marker.Line, marker.Run().Title),
+		State:      state,
+		RunID:      runID,
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+func writeTodoCheckout(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\n// TODO(repobird): log the exit code\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# Tool\n\n<!-- TODO(repobird): document the flags -->\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "build.sh"), []byte("#!/bin/sh\n# TODO(repobird): fail on errors\n"), 0644))
+	return root
+}
+
+func TestTodoScanWritesBulkConfigAndSkipsHandled(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := writeTodoCheckout(t)
+	output := filepath.Join(t.TempDir(), "todos.yaml")
+
+	var out bytes.Buffer
+	require.NoError(t, runTodoScan(&out, root, todoScanOptions{repo: "acme/tool", output: output, contextLines: 2}))
+	assert.Contains(t, out.String(), "Wrote 3 runs to "+output)
+
+	config, err := bulk.LoadBulkConfig([]string{output})
+	require.NoError(t, err)
+	assert.Equal(t, "acme/tool", config.Repository)
+	require.Len(t, config.Runs, 3)
+	assert.Equal(t, "document the flags", config.Runs[0].Title)
+	assert.Equal(t, []string{"README.md"}, config.Runs[0].Files)
+
+	// New comments are picked up; handled ones are skipped.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "util.go"), []byte("// TODO(repobird): add a helper\n"), 0644))
+	out.Reset()
+	require.NoError(t, runTodoScan(&out, root, todoScanOptions{repo: "acme/tool"}))
+	assert.Contains(t, out.String(), "add a helper")
+	assert.NotContains(t, out.String(), "fail on errors")
+
+	err = runTodoScan(&out, root, todoScanOptions{repo: "acme/tool", output: output})
+	require.NoError(t, err)
+	err = runTodoScan(&out, root, todoScanOptions{repo: "acme/tool"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no new TODO(repobird) comments")
+}
+
+func TestTodoScanSubmitsWithBulk(t *testing.T) {
+	var submitted dto.BulkRunRequest
+	configureBulkManageTest(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(dto.BulkRunResponse{Data: dto.BulkRunData{
+			BatchID: "batch-7",
+			Successful: []dto.RunCreatedItem{
+				{ID: 71, Status: "QUEUED", RequestIndex: 0},
+				{ID: 72, Status: "QUEUED", RequestIndex: 2},
+			},
+			Failed: []dto.RunError{{RequestIndex: 1, Error: "DUPLICATE", Message: "duplicate run"}},
+		}})
+	})
+	root := writeTodoCheckout(t)
+
+	captureBulkStdout(t, func() {
+		require.NoError(t, runTodoScan(os.Stdout, root, todoScanOptions{repo: "acme/tool", submit: true}))
+	})
+	assert.Equal(t, "acme/tool", submitted.RepositoryName)
+	require.Len(t, submitted.Runs, 3)
+
+	ledger, err := cache.LoadImportLedger(cache.ImportLedgerPath())
+	require.NoError(t, err)
+	assert.Len(t, ledger.Records, 2)
+
+	// The run that failed is offered again.
+	var out bytes.Buffer
+	require.NoError(t, runTodoScan(&out, root, todoScanOptions{repo: "acme/tool"}))
+	config := out.String()
+	assert.Contains(t, config, submitted.Runs[1].Title)
+	assert.NotContains(t, config, submitted.Runs[0].Title)
+}
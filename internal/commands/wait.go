@@ -0,0 +1,380 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+)
+
+var (
+	waitAny         bool
+	waitAll         bool
+	waitBatch       string
+	waitRunsTimeout time.Duration
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait [run-id...]",
+	Short: "Wait for runs to reach a terminal state",
+	Long: `Wait for one or more runs to finish. Run IDs are taken from the arguments,
+from stdin when the only argument is "-" or stdin is piped, or from the runs
+of a bulk batch with --batch.
+
+With --all (the default) wait returns once every run has finished; with --any
+it returns as soon as the first run finishes. A line is printed whenever a run
+changes status.
+
+Exit codes:
+  0  every run completed (--any: the first finished run completed)
+  4  a run failed or was cancelled
+  5  the timeout was reached before the runs finished`,
+	Example: `  repobird wait 123 456            # Wait for both runs
+  repobird wait --any 123 456      # Return when either run finishes
+  repobird wait --batch batch_abc  # Wait for every run of a batch
+  repobird run task.json --json | jq -r .run.id | repobird wait --json`,
+	RunE: runWaitCommand,
+}
+
+//nolint:gochecknoinits // Required for CLI command registration
+func init() {
+	waitCmd.Flags().BoolVar(&waitAll, "all", false, "wait until every run has finished (default)")
+	waitCmd.Flags().BoolVar(&waitAny, "any", false, "wait until the first run finishes")
+	waitCmd.Flags().StringVar(&waitBatch, "batch", "", "wait for the runs of a bulk batch or super-batch")
+	waitCmd.Flags().DurationVar(&waitRunsTimeout, "timeout", 90*time.Minute, "maximum time to wait (for example: 45m, 1h30m)")
+	waitCmd.Flags().BoolVar(&jsonOutput, "json", false, "print a JSON summary instead of progress lines")
+	addHookFlags(waitCmd)
+}
+
+// waitedRun is the state of one run being waited for.
+type waitedRun struct {
+	id  string
+	run *domain.Run
+	err error
+	// finishOrder counts from 1 in the order finished runs were seen.
+	finishOrder int
+}
+
+func (w *waitedRun) finished() bool {
+	return w.run != nil && w.run.IsTerminal()
+}
+
+func (w *waitedRun) status() string {
+	if w.run == nil {
+		return "unknown"
+	}
+	return w.run.Status
+}
+
+func runWaitCommand(cmd *cobra.Command, args []string) error {
+	if waitAny && waitAll {
+		return fmt.Errorf("--any and --all cannot be used together")
+	}
+	if waitRunsTimeout <= 0 {
+		return fmt.Errorf("--timeout must be greater than zero")
+	}
+
+	runIDs, err := collectWaitRunIDs(cmd.InOrStdin(), args)
+	if err != nil {
+		return err
+	}
+	if len(runIDs) == 0 {
+		return fmt.Errorf("no run IDs to wait for; pass run IDs, pipe them on stdin, or use --batch")
+	}
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+
+	runs := make([]*waitedRun, len(runIDs))
+	for i, id := range runIDs {
+		runs[i] = &waitedRun{id: id}
+	}
+	out := cmd.OutOrStdout()
+	timedOut, err := waitForRuns(context.Background(), getContainer().RunService(), runs, out)
+	if err != nil {
+		return err
+	}
+
+	exitCode := waitExitCode(runs, timedOut)
+	if jsonOutput {
+		if err := printWaitJSON(out, runs, exitCode, timedOut); err != nil {
+			return err
+		}
+	} else {
+		printWaitSummary(out, runs, exitCode)
+	}
+	if exitCode == ExitCodeSuccess {
+		return nil
+	}
+	return wrapExitError(exitCode, fmt.Errorf("%s", waitFailureMessage(runs, exitCode)))
+}
+
+// collectWaitRunIDs gathers the run IDs from args, stdin and --batch,
+// dropping duplicates.
+func collectWaitRunIDs(stdin io.Reader, args []string) ([]string, error) {
+	var ids []string
+	readStdin := len(args) == 1 && args[0] == "-"
+	if len(args) == 0 && waitBatch == "" {
+		if f, ok := stdin.(*os.File); ok {
+			stat, err := f.Stat()
+			readStdin = err == nil && stat.Mode()&os.ModeCharDevice == 0
+		} else {
+			readStdin = stdin != nil
+		}
+	}
+	if readStdin {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			ids = append(ids, strings.Fields(line)...)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read run IDs from stdin: %w", err)
+		}
+	} else {
+		ids = append(ids, args...)
+	}
+
+	if waitBatch != "" {
+		batchRunIDs, err := bulkBatchRunIDs(waitBatch)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, batchRunIDs...)
+	}
+
+	seen := make(map[string]bool, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// bulkBatchRunIDs returns the IDs of the runs of a batch or super-batch.
+func bulkBatchRunIDs(id string) ([]string, error) {
+	client, err := bulkManageClient()
+	if err != nil {
+		return nil, err
+	}
+	batchIDs, err := resolveBulkBatchIDs(id)
+	if err != nil {
+		return nil, err
+	}
+	status, err := fetchBulkStatus(context.Background(), client, batchIDs)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(status.Runs))
+	for _, run := range status.Runs {
+		ids = append(ids, strconv.Itoa(run.ID))
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("batch %s has no runs", id)
+	}
+	return ids, nil
+}
+
+// waitForRuns polls the runs until every run (or, with --any, one run) has
+// finished or the timeout is reached, printing a line whenever a run changes
+// status. It reports whether the timeout was reached.
+func waitForRuns(ctx context.Context, runService domain.RunService, runs []*waitedRun, out io.Writer) (bool, error) {
+	pollCtx, cancel := context.WithTimeout(ctx, waitRunsTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	finishedRuns := 0
+	for {
+		for _, w := range runs {
+			if w.finished() {
+				continue
+			}
+			run, err := runService.GetRun(pollCtx, w.id)
+			if err != nil {
+				code := exitCodeForError(err)
+				if code == ExitCodeAuth || code == ExitCodeQuota {
+					return false, wrapExitError(code, err)
+				}
+				if errors.IsNotFound(err) {
+					return false, fmt.Errorf("run %s not found", w.id)
+				}
+				if pollCtx.Err() != nil {
+					return true, nil
+				}
+				w.err = err
+				continue
+			}
+			w.err = nil
+			if w.run == nil || w.run.Status != run.Status {
+				printWaitProgress(out, w.id, run)
+			}
+			w.run = run
+			if w.finished() {
+				finishedRuns++
+				w.finishOrder = finishedRuns
+				fireCompletionHooks(runHookPayload(run))
+			}
+		}
+
+		if waitDone(runs) {
+			return false, nil
+		}
+
+		select {
+		case <-pollCtx.Done():
+			return true, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitDone reports whether every run has finished, or with --any whether
+// one has.
+func waitDone(runs []*waitedRun) bool {
+	finished := 0
+	for _, w := range runs {
+		if w.finished() {
+			finished++
+		}
+	}
+	if waitAny {
+		return finished > 0
+	}
+	return finished == len(runs)
+}
+
+// waitExitCode aggregates the outcome of the runs. With --any the first run
+// that finished decides; otherwise a failed run outranks a timeout.
+func waitExitCode(runs []*waitedRun, timedOut bool) int {
+	if waitAny {
+		if first := firstFinishedRun(runs); first != nil {
+			return exitCodeForFinalRun(first.run)
+		}
+		return ExitCodeTimeout
+	}
+	for _, w := range runs {
+		if w.finished() && !w.run.IsSuccess() {
+			return ExitCodeRunFailed
+		}
+	}
+	if timedOut {
+		return ExitCodeTimeout
+	}
+	return ExitCodeSuccess
+}
+
+// firstFinishedRun returns the run that finished first, by the time the API
+// reports it finished, or by the order the finishes were seen when runs
+// finished at the same time or report no time.
+func firstFinishedRun(runs []*waitedRun) *waitedRun {
+	var first *waitedRun
+	for _, w := range runs {
+		if !w.finished() {
+			continue
+		}
+		if first == nil {
+			first = w
+			continue
+		}
+		at, firstAt := w.finishedAt(), first.finishedAt()
+		switch {
+		case !at.IsZero() && !firstAt.IsZero() && !at.Equal(firstAt):
+			if at.Before(firstAt) {
+				first = w
+			}
+		case w.finishOrder < first.finishOrder:
+			first = w
+		}
+	}
+	return first
+}
+
+// finishedAt returns when the API reports the run finished, or the zero
+// time when it does not.
+func (w *waitedRun) finishedAt() time.Time {
+	if w.run.CompletedAt != nil {
+		return *w.run.CompletedAt
+	}
+	return w.run.UpdatedAt
+}
+
+func waitFailureMessage(runs []*waitedRun, exitCode int) string {
+	if exitCode == ExitCodeTimeout {
+		pending := 0
+		for _, w := range runs {
+			if !w.finished() {
+				pending++
+			}
+		}
+		return fmt.Sprintf("timed out after %s with %d of %d run(s) unfinished", waitRunsTimeout, pending, len(runs))
+	}
+	var failed []string
+	for _, w := range runs {
+		if w.finished() && !w.run.IsSuccess() {
+			failed = append(failed, w.id)
+		}
+	}
+	return fmt.Sprintf("%d run(s) did not complete successfully: %s", len(failed), strings.Join(failed, ", "))
+}
+
+func printWaitProgress(out io.Writer, id string, run *domain.Run) {
+	if jsonOutput {
+		return
+	}
+	styler := styleFor(out)
+	line := fmt.Sprintf("%s %s %s", time.Now().Format("15:04:05"), styler.Label("Run "+id+":"), styler.Status(formatStatusForDisplay(run.Status)))
+	if run.Status == domain.StatusFailed && run.Error != "" {
+		line += " - " + firstLine(run.Error)
+	}
+	_, _ = fmt.Fprintln(out, line)
+}
+
+func printWaitSummary(out io.Writer, runs []*waitedRun, exitCode int) {
+	styler := styleFor(out)
+	completed, failed, pending := 0, 0, 0
+	for _, w := range runs {
+		switch {
+		case !w.finished():
+			pending++
+		case w.run.IsSuccess():
+			completed++
+		default:
+			failed++
+		}
+	}
+	_, _ = fmt.Fprintln(out)
+	summary := fmt.Sprintf("%d completed, %d failed, %d unfinished", completed, failed, pending)
+	switch exitCode {
+	case ExitCodeTimeout:
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Error("Timed out:"), summary)
+	case ExitCodeRunFailed:
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Error("Runs finished:"), summary)
+	default:
+		_, _ = fmt.Fprintf(out, "%s %s\n", styler.Success("Runs finished:"), summary)
+	}
+	for _, w := range runs {
+		if w.finished() && w.run.IsSuccess() && w.run.PullRequestURL != "" {
+			_, _ = fmt.Fprintf(out, "%s %s\n", styler.Label("Run "+w.id+":"), styler.URL(w.run.PullRequestURL))
+		}
+	}
+}
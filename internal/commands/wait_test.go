@@ -0,0 +1,151 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWaitTestServer serves each run's statuses in turn, repeating the last.
+func newWaitTestServer(t *testing.T, statuses map[string][]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	polls := make(map[string]int)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/runs/")
+		sequence, ok := statuses[id]
+		if r.Method != http.MethodGet || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		index := polls[id]
+		if index >= len(sequence) {
+			index = len(sequence) - 1
+		}
+		polls[id]++
+		mu.Unlock()
+		run := map[string]any{"id": id, "status": sequence[index], "repositoryName": "acme/webapp"}
+		if sequence[index] == "FAILED" {
+			run["error"] = "tests failed"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": run})
+	}))
+}
+
+func runWaitTest(t *testing.T, server *httptest.Server, stdin string, args ...string) (string, error) {
+	t.Helper()
+	t.Cleanup(configureRunWaitTest(t, server.URL))
+	originalAny, originalTimeout := waitAny, waitRunsTimeout
+	t.Cleanup(func() { waitAny, waitRunsTimeout = originalAny, originalTimeout })
+	waitRunsTimeout = time.Second
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	err := runWaitCommand(cmd, args)
+	return out.String(), err
+}
+
+func TestWaitAllAggregatesRunFailures(t *testing.T) {
+	server := newWaitTestServer(t, map[string][]string{
+		"1": {"PROCESSING", "DONE"},
+		"2": {"QUEUED", "PROCESSING", "FAILED"},
+	})
+	defer server.Close()
+
+	output, err := runWaitTest(t, server, "", "1", "2")
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeRunFailed, exitCodeForError(err))
+
+	var result waitJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "repobird.wait.v1", result.Schema)
+	assert.Equal(t, "all", result.Mode)
+	assert.False(t, result.Success)
+	assert.Equal(t, 1, result.Completed)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Runs, 2)
+	assert.Equal(t, "completed", result.Runs[0].Status)
+	assert.Equal(t, "tests failed", result.Runs[1].Error)
+}
+
+func TestWaitAnyReturnsFirstFinishedRun(t *testing.T) {
+	server := newWaitTestServer(t, map[string][]string{
+		"1": {"PROCESSING"},
+		"2": {"PROCESSING", "DONE"},
+	})
+	defer server.Close()
+	t.Cleanup(func() { waitAny = false })
+	waitAny = true
+
+	output, err := runWaitTest(t, server, "1\n# comment\n2 2\n")
+	require.NoError(t, err)
+
+	var result waitJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "any", result.Mode)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, result.Pending)
+	require.Len(t, result.Runs, 2, "duplicate IDs are waited for once")
+	assert.True(t, result.Runs[1].Finished)
+}
+
+func TestWaitAnyUsesTheRunThatFinishedFirst(t *testing.T) {
+	finished := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		run := map[string]any{"id": "1", "status": "FAILED", "repositoryName": "acme/webapp", "completedAt": finished.Add(time.Minute)}
+		if strings.TrimPrefix(r.URL.Path, "/api/v1/runs/") == "2" {
+			run = map[string]any{"id": "2", "status": "DONE", "repositoryName": "acme/webapp", "completedAt": finished}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": run})
+	}))
+	defer server.Close()
+	t.Cleanup(func() { waitAny = false })
+	waitAny = true
+
+	// Both runs finish in the same poll; run 2, listed second, finished first.
+	_, err := runWaitTest(t, server, "", "1", "2")
+	require.NoError(t, err)
+}
+
+func TestWaitTimesOutWithUnfinishedRuns(t *testing.T) {
+	server := newWaitTestServer(t, map[string][]string{"1": {"DONE"}, "2": {"PROCESSING"}})
+	defer server.Close()
+	t.Cleanup(configureRunWaitTest(t, server.URL))
+	jsonOutput = false
+	originalTimeout := waitRunsTimeout
+	t.Cleanup(func() { waitRunsTimeout = originalTimeout })
+	waitRunsTimeout = 30 * time.Millisecond
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := runWaitCommand(cmd, []string{"1", "2"})
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeTimeout, exitCodeForError(err))
+	assert.Contains(t, err.Error(), "1 of 2 run(s) unfinished")
+	assert.Contains(t, out.String(), "Run 2:")
+	assert.Contains(t, out.String(), "1 completed, 0 failed, 1 unfinished")
+}
+
+func TestWaitRejectsUnknownRun(t *testing.T) {
+	server := newWaitTestServer(t, map[string][]string{"1": {"DONE"}})
+	defer server.Close()
+
+	_, err := runWaitTest(t, server, "", "1", "404")
+	assert.ErrorContains(t, err, "run 404 not found")
+}
@@ -0,0 +1,456 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/repobird/repobird-cli/internal/api"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/domain"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/output"
+	"github.com/repobird/repobird-cli/internal/utils"
+	"github.com/repobird/repobird-cli/internal/workflow"
+)
+
+var (
+	workflowOnFailure   string
+	workflowRestart     bool
+	workflowStepTimeout time.Duration
+	// workflowPollInterval is how often running steps are polled.
+	workflowPollInterval = utils.DefaultPollInterval
+)
+
+var workflowCmd = newWorkflowCommand()
+
+func newWorkflowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workflow",
+		Short: "Run chained runs that build on each other",
+		Long: `Run a workflow: a set of steps, each a run config, where a step starts once
+the steps listed in its needs have succeeded. A step can build on the branch an
+earlier step produced by setting
+
+  baseBranch: ${{ steps.<id>.outputBranch }}
+
+Progress is kept in a local workflow state, so an interrupted or failed
+workflow can be continued with 'repobird workflow resume <name>'.`,
+	}
+
+	run := &cobra.Command{
+		Use:   "run <workflow-file>",
+		Short: "Start a workflow and wait for its steps",
+		Example: `  repobird workflow run profiles.yaml
+  repobird workflow run profiles.yaml --on-failure continue
+  repobird workflow run profiles.yaml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runWorkflowRun,
+	}
+	run.Flags().BoolVar(&workflowRestart, "restart", false, "start over even if an earlier run of the workflow is unfinished")
+	run.Flags().BoolVar(&dryRun, "dry-run", false, "validate the workflow and show its steps without creating runs")
+
+	resume := &cobra.Command{
+		Use:   "resume <name>",
+		Short: "Continue a workflow, retrying failed and skipped steps",
+		Long: `Continue a workflow from its saved state. Steps that are still running are
+waited for, steps that failed or were skipped are started again, and the
+workflow file is re-read, so fixes to failed steps take effect.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runWorkflowResume,
+	}
+
+	for _, c := range []*cobra.Command{run, resume} {
+		c.Flags().StringVar(&workflowOnFailure, "on-failure", "", "stop or continue when a step fails (default from the workflow, or stop)")
+		c.Flags().DurationVar(&workflowStepTimeout, "step-timeout", 2*time.Hour, "maximum time to wait for one step")
+		c.Flags().BoolVar(&allowSecrets, "allow-secrets", false, "submit even if a step contains suspected secrets")
+		c.Flags().BoolVar(&skipLint, "skip-lint", false, "submit even if a step has lint errors")
+		c.Flags().BoolVar(&overrideBudget, "override-budget", false, "submit even if a step would exceed a configured credit budget")
+	}
+
+	status := &cobra.Command{
+		Use:   "status <name>",
+		Short: "Show the steps of a workflow as a tree",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWorkflowStatus,
+	}
+	status.Flags().BoolVar(&jsonOutput, "json", false, "output in JSON format")
+
+	cmd.AddCommand(run, resume, status)
+	return cmd
+}
+
+func runWorkflowRun(_ *cobra.Command, args []string) error {
+	config, err := loadWorkflowConfig(args[0])
+	if err != nil {
+		return err
+	}
+	ids, needs := workflowStepIDs(config)
+	if dryRun {
+		state := cache.NewWorkflowState(config.Name, args[0], ids, needs)
+		printWorkflowTree(os.Stdout, state)
+		fmt.Printf("\n%s %s\n", stdoutStyle().Label("On failure:"), config.Policy())
+		return nil
+	}
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+
+	dir := cache.WorkflowDir()
+	if previous, err := cache.LoadWorkflowState(dir, config.Name); err == nil && !previous.Finished() && !workflowRestart {
+		return fmt.Errorf("workflow %s has unfinished steps from an earlier run; continue it with 'repobird workflow resume %s' or start over with --restart", config.Name, config.Name)
+	}
+	configPath, err := filepath.Abs(args[0])
+	if err != nil {
+		configPath = args[0]
+	}
+	return executeWorkflow(config, cache.NewWorkflowState(config.Name, configPath, ids, needs), dir)
+}
+
+func runWorkflowResume(_ *cobra.Command, args []string) error {
+	if cfg.APIKey == "" {
+		return errors.NoAPIKeyError()
+	}
+	dir := cache.WorkflowDir()
+	state, err := cache.LoadWorkflowState(dir, args[0])
+	if err != nil {
+		return err
+	}
+	if state.Finished() {
+		fmt.Printf("Workflow %s already completed\n", state.Name)
+		return nil
+	}
+	config, err := loadWorkflowConfig(state.ConfigPath)
+	if err != nil {
+		return err
+	}
+	state.SyncSteps(workflowStepIDs(config))
+	state.ResetUnfinished()
+	return executeWorkflow(config, state, dir)
+}
+
+func runWorkflowStatus(_ *cobra.Command, args []string) error {
+	state, err := cache.LoadWorkflowState(cache.WorkflowDir(), args[0])
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(os.Stdout, workflowStatusJSONOutput{
+			Schema:        "repobird.workflow.v1",
+			Operation:     "workflow.status",
+			WorkflowState: state,
+		})
+	}
+	printWorkflowTree(os.Stdout, state)
+	return nil
+}
+
+// loadWorkflowConfig reads a workflow file and applies --on-failure.
+func loadWorkflowConfig(file string) (*workflow.Config, error) {
+	config, err := workflow.LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if workflowOnFailure != "" {
+		if workflowOnFailure != workflow.OnFailureStop && workflowOnFailure != workflow.OnFailureContinue {
+			return nil, fmt.Errorf("--on-failure must be %q or %q", workflow.OnFailureStop, workflow.OnFailureContinue)
+		}
+		config.OnFailure = workflowOnFailure
+	}
+	return config, nil
+}
+
+func workflowStepIDs(config *workflow.Config) ([]string, map[string][]string) {
+	ids := make([]string, len(config.Steps))
+	needs := make(map[string][]string, len(config.Steps))
+	for i, step := range config.Steps {
+		ids[i] = step.ID
+		needs[step.ID] = step.Needs
+	}
+	return ids, needs
+}
+
+type workflowPollResult struct {
+	index int
+	run   *models.RunResponse
+	err   error
+}
+
+// executeWorkflow starts every step whose dependencies succeeded, waits for
+// running steps and repeats until no step can start. The state is saved
+// after every change.
+func executeWorkflow(config *workflow.Config, state *cache.WorkflowState, dir string) error {
+	client, err := newRepoAPIClient()
+	if err != nil {
+		return err
+	}
+	runService := getContainer().RunService()
+	ctx := context.Background()
+	save := func() {
+		if err := cache.SaveWorkflowState(dir, state); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", stderrStyle().Warning("Warning:"), err)
+		}
+	}
+
+	results := make(chan workflowPollResult)
+	running := 0
+	poll := func(index int) {
+		running++
+		go func(runID string) {
+			run, err := pollWorkflowStep(ctx, client, runID)
+			results <- workflowPollResult{index: index, run: run, err: err}
+		}(state.Steps[index].RunID)
+	}
+	for i := range state.Steps {
+		if state.Steps[i].Status == cache.WorkflowStepRunning {
+			poll(i)
+		}
+	}
+
+	stopOnFailure := config.Policy() == workflow.OnFailureStop
+	progress := &workflowProgress{out: os.Stdout, live: stdoutIsTerminal()}
+	var pollErr error
+	for {
+		if pollErr == nil {
+			state.SkipBlocked()
+			for _, index := range state.Ready() {
+				if stopOnFailure && state.HasFailed() {
+					break
+				}
+				startWorkflowStep(ctx, runService, config, state, index)
+				save()
+				if state.Steps[index].Status == cache.WorkflowStepRunning {
+					poll(index)
+				}
+			}
+		}
+		progress.update(state)
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		if result.err != nil {
+			// The run keeps going on the server; resume waits for it again.
+			pollErr = result.err
+			continue
+		}
+		finishWorkflowStep(&state.Steps[result.index], result.run)
+		save()
+	}
+
+	state.SkipBlocked()
+	if stopOnFailure && state.HasFailed() {
+		state.SkipPending("not started because an earlier step failed")
+	}
+	save()
+	progress.finish(state)
+
+	switch {
+	case pollErr != nil:
+		return fmt.Errorf("stopped waiting for workflow %s: %s\nRun 'repobird workflow resume %s' to continue", state.Name, errors.FormatUserError(pollErr), state.Name)
+	case !state.Finished():
+		counts := state.Counts()
+		return fmt.Errorf("workflow %s finished with %d failed and %d skipped steps\nFix the failures and run 'repobird workflow resume %s' to retry them",
+			state.Name, counts[cache.WorkflowStepFailed], counts[cache.WorkflowStepSkipped], state.Name)
+	}
+	fmt.Printf("%s Workflow %s completed\n", stdoutStyle().Success("✓"), state.Name)
+	return nil
+}
+
+// startWorkflowStep creates the run of a step, building on the output
+// branches of the steps it needs.
+func startWorkflowStep(ctx context.Context, runService domain.RunService, config *workflow.Config, state *cache.WorkflowState, index int) {
+	step := &state.Steps[index]
+	now := time.Now().UTC()
+	step.StartedAt = &now
+
+	outputBranches := make(map[string]string)
+	for _, other := range state.Steps {
+		if other.Status == cache.WorkflowStepSucceeded {
+			outputBranches[other.ID] = other.OutputBranch
+		}
+	}
+	run, req, err := createWorkflowRun(ctx, runService, config, config.Step(step.ID), outputBranches)
+	if err != nil {
+		step.Status = cache.WorkflowStepFailed
+		step.Error = errors.FormatUserError(err)
+		step.FinishedAt = &now
+		return
+	}
+	step.Status = cache.WorkflowStepRunning
+	step.RunID = run.ID
+	step.RunStatus = run.Status
+	step.BaseBranch = req.BaseBranch
+	step.OutputBranch = run.OutputBranch
+	if step.OutputBranch == "" {
+		step.OutputBranch = req.OutputBranch
+	}
+}
+
+func createWorkflowRun(ctx context.Context, runService domain.RunService, config *workflow.Config, step *workflow.Step, outputBranches map[string]string) (*domain.Run, domain.CreateRunRequest, error) {
+	runConfig, err := config.RunConfig(step, outputBranches)
+	if err != nil {
+		return nil, domain.CreateRunRequest{}, err
+	}
+	req, err := prepareRunRequest(runConfig)
+	if err != nil {
+		return nil, req, err
+	}
+	run, err := runService.CreateRun(ctx, req)
+	return run, req, err
+}
+
+// pollWorkflowStep waits for a run to finish.
+func pollWorkflowStep(ctx context.Context, client *api.Client, runID string) (*models.RunResponse, error) {
+	poller := utils.NewPoller(&utils.PollConfig{
+		Interval:    workflowPollInterval,
+		MaxDuration: workflowStepTimeout,
+		Debug:       cfg.Debug,
+	})
+	return poller.Poll(ctx, func(ctx context.Context) (*models.RunResponse, error) {
+		return client.GetRunWithRetry(ctx, runID)
+	}, nil)
+}
+
+// finishWorkflowStep records the final state of a step's run.
+func finishWorkflowStep(step *cache.WorkflowStep, run *models.RunResponse) {
+	now := time.Now().UTC()
+	step.FinishedAt = &now
+	step.RunStatus = string(run.Status)
+	if run.OutputBranch != "" {
+		step.OutputBranch = run.OutputBranch
+	}
+	if run.PullRequestURL != nil {
+		step.PullRequestURL = *run.PullRequestURL
+	}
+	if run.Status == models.StatusDone {
+		step.Status = cache.WorkflowStepSucceeded
+		step.Error = ""
+		return
+	}
+	step.Status = cache.WorkflowStepFailed
+	step.Error = messageOrDefault(run.Error, "run ended with status "+string(run.Status))
+}
+
+// workflowProgress redraws the step tree on a terminal and prints one line
+// per step change otherwise.
+type workflowProgress struct {
+	out   io.Writer
+	live  bool
+	lines int
+	seen  map[string]string
+}
+
+func (p *workflowProgress) update(state *cache.WorkflowState) {
+	if p.live {
+		clearPreviousLiveLines(p.out, true, p.lines)
+		p.lines = printWorkflowTree(p.out, state)
+		return
+	}
+	if p.seen == nil {
+		p.seen = make(map[string]string, len(state.Steps))
+		printWorkflowTree(p.out, state)
+		_, _ = fmt.Fprintln(p.out)
+	}
+	styler := styleFor(p.out)
+	for _, step := range state.Steps {
+		if p.seen[step.ID] == step.Status {
+			continue
+		}
+		if p.seen[step.ID] != "" || step.Status != cache.WorkflowStepPending {
+			line := fmt.Sprintf("%s %s: %s", workflowStepIcon(styler, step.Status), step.ID, step.Status)
+			if step.RunID != "" {
+				line += " (run " + step.RunID + ")"
+			}
+			_, _ = fmt.Fprintln(p.out, line)
+		}
+		p.seen[step.ID] = step.Status
+	}
+}
+
+// finish shows the final state, ending with the full tree.
+func (p *workflowProgress) finish(state *cache.WorkflowState) {
+	p.update(state)
+	if !p.live {
+		_, _ = fmt.Fprintln(p.out)
+		printWorkflowTree(p.out, state)
+	}
+}
+
+// printWorkflowTree prints the steps under the first step each one needs and
+// returns the number of lines written.
+func printWorkflowTree(out io.Writer, state *cache.WorkflowState) int {
+	styler := styleFor(out)
+	children := make(map[string][]cache.WorkflowStep)
+	var roots []cache.WorkflowStep
+	for _, step := range state.Steps {
+		if len(step.Needs) > 0 && state.Step(step.Needs[0]) != nil {
+			children[step.Needs[0]] = append(children[step.Needs[0]], step)
+		} else {
+			roots = append(roots, step)
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "%s %s\n", styler.Heading("Workflow"), state.Name)
+	lines := 1
+	var walk func(steps []cache.WorkflowStep, prefix string)
+	walk = func(steps []cache.WorkflowStep, prefix string) {
+		for i, step := range steps {
+			connector, indent := "├─ ", "│  "
+			if i == len(steps)-1 {
+				connector, indent = "└─ ", "   "
+			}
+			_, _ = fmt.Fprintf(out, "%s%s%s %s %s\n", prefix, connector, workflowStepIcon(styler, step.Status), step.ID, workflowStepDetails(styler, step))
+			lines++
+			walk(children[step.ID], prefix+indent)
+		}
+	}
+	walk(roots, "")
+	return lines
+}
+
+func workflowStepIcon(styler output.Styler, status string) string {
+	switch status {
+	case cache.WorkflowStepSucceeded:
+		return styler.Success("✓")
+	case cache.WorkflowStepFailed:
+		return styler.Error("✗")
+	case cache.WorkflowStepRunning:
+		return styler.Info("●")
+	case cache.WorkflowStepSkipped:
+		return styler.Muted("-")
+	}
+	return styler.Muted("○")
+}
+
+func workflowStepDetails(styler output.Styler, step cache.WorkflowStep) string {
+	details := []string{styler.Status(step.Status)}
+	if step.RunID != "" {
+		details = append(details, "run "+step.RunID)
+	}
+	if step.Status == cache.WorkflowStepSucceeded && step.OutputBranch != "" {
+		details = append(details, "→ "+step.OutputBranch)
+	}
+	if step.PullRequestURL != "" {
+		details = append(details, step.PullRequestURL)
+	}
+	if len(step.Needs) > 1 {
+		details = append(details, styler.Muted("(also needs "+strings.Join(step.Needs[1:], ", ")+")"))
+	}
+	if step.Error != "" {
+		details = append(details, styler.Error(firstLine(step.Error)))
+	}
+	return strings.Join(details, "  ")
+}
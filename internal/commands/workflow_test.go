@@ -0,0 +1,156 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+)
+
+type workflowTestServer struct {
+	mu        sync.Mutex
+	created   map[string]map[string]any // prompt -> request body
+	ids       map[string]string         // run ID -> prompt
+	failFirst map[string]bool           // prompts whose first run fails
+}
+
+func (s *workflowTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/runs":
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prompt := body["prompt"].(string)
+		s.created[prompt] = body
+		id := 100 + len(s.ids) + 1
+		s.ids[jsonNumber(id)] = prompt
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"id": id, "status": "QUEUED", "repositoryName": body["repositoryName"],
+		}})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/runs/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/runs/")
+		prompt := s.ids[id]
+		run := map[string]any{"id": id, "status": "DONE", "outputBranch": "repobird/run-" + id}
+		if s.failFirst[prompt] {
+			s.failFirst[prompt] = false
+			run["status"], run["error"] = "FAILED", "tests failed"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": run})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func jsonNumber(id int) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
+
+func configureWorkflowTest(t *testing.T, failFirst ...string) (*workflowTestServer, string) {
+	t.Helper()
+	server := &workflowTestServer{created: map[string]map[string]any{}, ids: map[string]string{}, failFirst: map[string]bool{}}
+	for _, prompt := range failFirst {
+		server.failFirst[prompt] = true
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	restore := configureRunWaitTest(t, httpServer.URL)
+	t.Cleanup(restore)
+	jsonOutput = false
+	originalInterval, originalTimeout := workflowPollInterval, workflowStepTimeout
+	originalPolicy, originalRestart := workflowOnFailure, workflowRestart
+	workflowPollInterval, workflowStepTimeout = 10*time.Millisecond, 5*time.Second
+	workflowOnFailure, workflowRestart = "", false
+	t.Cleanup(func() {
+		workflowPollInterval, workflowStepTimeout = originalInterval, originalTimeout
+		workflowOnFailure, workflowRestart = originalPolicy, originalRestart
+	})
+
+	file := filepath.Join(t.TempDir(), "profiles.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`name: profiles
+repository: acme/webapp
+steps:
+  - id: schema
+    prompt: Add a profiles table
+  - id: api
+    prompt: Add profile endpoints
+    baseBranch: ${{ steps.schema.outputBranch }}
+  - id: docs
+    prompt: Document profiles
+  - id: ui
+    needs: [api, docs]
+    prompt: Add the profile page
+`), 0644))
+	return server, file
+}
+
+func TestWorkflowRunChainsOutputBranches(t *testing.T) {
+	server, file := configureWorkflowTest(t)
+
+	output := captureRunStdout(t, func() {
+		require.NoError(t, runWorkflowRun(workflowCmd, []string{file}))
+	})
+	assert.Contains(t, output, "Workflow profiles completed")
+	assert.Contains(t, output, "└─ ✓ ui")
+
+	require.Len(t, server.created, 4)
+	schemaState, err := cache.LoadWorkflowState(cache.WorkflowDir(), "profiles")
+	require.NoError(t, err)
+	assert.True(t, schemaState.Finished())
+	schema := schemaState.Step("schema")
+	assert.Equal(t, schema.OutputBranch, server.created["Add profile endpoints"]["baseBranch"])
+	assert.Equal(t, schema.OutputBranch, schemaState.Step("api").BaseBranch)
+	assert.NotContains(t, server.created["Add the profile page"], "baseBranch")
+}
+
+func TestWorkflowStopsOnFailureAndResumes(t *testing.T) {
+	server, file := configureWorkflowTest(t, "Add a profiles table")
+
+	output := captureRunStdout(t, func() {
+		err := runWorkflowRun(workflowCmd, []string{file})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "finished with 1 failed and 2 skipped steps")
+	})
+	assert.Contains(t, output, "✗ schema")
+	assert.Contains(t, output, "tests failed")
+
+	state, err := cache.LoadWorkflowState(cache.WorkflowDir(), "profiles")
+	require.NoError(t, err)
+	assert.Equal(t, cache.WorkflowStepSucceeded, state.Step("docs").Status)
+	assert.Equal(t, cache.WorkflowStepSkipped, state.Step("api").Status)
+
+	err = runWorkflowRun(workflowCmd, []string{file})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow resume profiles")
+
+	captureRunStdout(t, func() {
+		require.NoError(t, runWorkflowResume(workflowCmd, []string{"profiles"}))
+	})
+	// docs is not submitted again; schema, api and ui are.
+	assert.Len(t, server.ids, 5)
+
+	output = captureRunStdout(t, func() {
+		require.NoError(t, runWorkflowStatus(workflowCmd, []string{"profiles"}))
+	})
+	assert.Contains(t, output, "├─ ✓ schema")
+	assert.Contains(t, output, "(also needs docs)")
+}
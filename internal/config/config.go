@@ -8,15 +8,83 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	APIKey string `mapstructure:"api_key"`
-	APIURL string `mapstructure:"api_url"`
-	Debug  bool   `mapstructure:"debug"`
-	Color  string `mapstructure:"color"`
+	APIKey   string      `mapstructure:"api_key"`
+	APIURL   string      `mapstructure:"api_url"`
+	Debug    bool        `mapstructure:"debug"`
+	Color    string      `mapstructure:"color"`
+	Defaults RunDefaults `mapstructure:"defaults"`
+	// ModelProfiles names model and provider pairs, such as a cheap model for
+	// chores and a strong one for refactors, that --model can refer to.
+	ModelProfiles map[string]ModelProfile `mapstructure:"model_profiles"`
+	Budget        Budget                  `mapstructure:"budget"`
+	Hooks         Hooks                   `mapstructure:"hooks"`
+}
+
+// Hooks are shell commands or webhook URLs run when a run that the CLI is
+// waiting for or following reaches a terminal state. A hook that starts with
+// http:// or https:// is a webhook; anything else is a shell command.
+type Hooks struct {
+	OnSuccess  []string `mapstructure:"on_success"`
+	OnFailure  []string `mapstructure:"on_failure"`
+	OnComplete []string `mapstructure:"on_complete"`
+	// Secret signs webhook payloads with HMAC-SHA256. REPOBIRD_HOOK_SECRET
+	// takes precedence.
+	Secret string `mapstructure:"secret"`
+	// Timeout bounds each hook command, such as "30s". Zero uses the
+	// default of hooks.DefaultCommandTimeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Budget sets credit guardrails checked before a run is submitted. Zero
+// disables a setting.
+type Budget struct {
+	// DailyCap and WeeklyCap cap the credits spent by runs created in the
+	// current calendar day and week, starting Monday.
+	DailyCap  float64 `mapstructure:"daily_cap"`
+	WeeklyCap float64 `mapstructure:"weekly_cap"`
+	// MinReserve is the credit balance a run may not dip below.
+	MinReserve float64 `mapstructure:"min_reserve"`
+	// WarnAbove warns when a run is estimated to cost more than this.
+	WarnAbove float64 `mapstructure:"warn_above"`
+}
+
+// ModelProfile is an OpenCode model and the provider that serves it.
+type ModelProfile struct {
+	Model    string `mapstructure:"model"`
+	Provider string `mapstructure:"provider"`
+}
+
+// RepositoryDefaults overrides the default model and the active run cap for
+// one repository.
+type RepositoryDefaults struct {
+	Repository    string `mapstructure:"repository"`
+	Model         string `mapstructure:"model"`
+	Provider      string `mapstructure:"provider"`
+	MaxActiveRuns int    `mapstructure:"max_active_runs"`
+}
+
+// RunDefaults are user-wide run settings. They have the lowest precedence and
+// are overridden by a project .repobird.yaml, the run file, and flags.
+type RunDefaults struct {
+	Repository     string   `mapstructure:"repository"`
+	BaseBranch     string   `mapstructure:"base_branch"`
+	PRTargetBranch string   `mapstructure:"pr_target_branch"`
+	OutputMode     string   `mapstructure:"output_mode"`
+	Model          string   `mapstructure:"model"`
+	Provider       string   `mapstructure:"provider"`
+	ContextFiles   []string `mapstructure:"context_files"`
+	// MaxActiveRuns caps the runs that may be queued or running at once
+	// before 'run' submits another. Zero means no cap.
+	MaxActiveRuns int `mapstructure:"max_active_runs"`
+	// Repositories sets the model per repository. It is a list rather than a
+	// map because repository names may contain dots.
+	Repositories []RepositoryDefaults `mapstructure:"repositories"`
 }
 
 var (
@@ -106,6 +174,31 @@ func SaveConfig(config *Config) error {
 	return nil
 }
 
+// Sources reported by SettingSource.
+const (
+	SourceEnv        = "env"
+	SourceConfigFile = "user config"
+	SourceDefault    = "default"
+)
+
+// SettingSource reports whether a top-level setting such as api_url came from
+// its environment variable, the user config file, or the built-in default.
+func SettingSource(key, envVar string) string {
+	if envVar != "" && os.Getenv(envVar) != "" {
+		return SourceEnv
+	}
+	if viper.InConfig(key) {
+		return SourceConfigFile
+	}
+	return SourceDefault
+}
+
+// FileUsed returns the path of the loaded user config file, or an empty
+// string when no config file was found.
+func FileUsed() string {
+	return viper.ConfigFileUsed()
+}
+
 func SetConfigFile(path string) {
 	configFileOverride = path
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,8 @@ api_url: https://custom.api.com
 api_key: test-key-123
 debug: true
 color: never
+hooks:
+  timeout: 45s
 `
 	err = os.WriteFile(configFile, []byte(configContent), 0644)
 	require.NoError(t, err)
@@ -54,6 +57,7 @@ color: never
 	assert.Equal(t, "test-key-123", config.APIKey)
 	assert.True(t, config.Debug)
 	assert.Equal(t, "never", config.Color)
+	assert.Equal(t, 45*time.Second, config.Hooks.Timeout)
 }
 
 func TestLoadConfig_EnvironmentVariables(t *testing.T) {
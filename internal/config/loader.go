@@ -39,6 +39,8 @@ func (c *ConfigLoader) LoadConfig(filePath string) (*models.RunRequest, error) {
 		Source:                runConfig.Source,
 		Target:                runConfig.Target,
 		RunType:               models.RunType(runConfig.RunType),
+		Model:                 runConfig.Model,
+		Provider:              runConfig.Provider,
 		Title:                 runConfig.Title,
 		Context:               runConfig.Context,
 		Files:                 runConfig.Files,
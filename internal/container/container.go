@@ -9,6 +9,7 @@ import (
 
 	"github.com/repobird/repobird-cli/internal/cache"
 	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/daemon"
 	"github.com/repobird/repobird-cli/internal/domain"
 	"github.com/repobird/repobird-cli/internal/repository"
 	"github.com/repobird/repobird-cli/internal/services"
@@ -27,8 +28,10 @@ type Container struct {
 // NewContainer creates a new dependency injection container
 func NewContainer(cfg *config.Config) *Container {
 	// Create HTTP client
+	// Route through the local daemon when one is serving this API key
 	httpClient := &http.Client{
-		Timeout: 45 * time.Minute,
+		Timeout:   45 * time.Minute,
+		Transport: daemon.Transport(cfg.APIURL, cfg.APIKey),
 	}
 
 	// Create services
@@ -0,0 +1,176 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// dialTimeout bounds how long a client waits on a daemon that is not
+	// answering before it goes to the API directly.
+	dialTimeout = 250 * time.Millisecond
+	// redialAfter is how long requests go to the API directly after the
+	// daemon could not be reached, before it is tried again.
+	redialAfter = 30 * time.Second
+)
+
+var (
+	disabled atomic.Bool
+
+	// transports caches Transport results, so the daemon is discovered once
+	// per process rather than for every API client.
+	transportsMu sync.Mutex
+	transports   = make(map[string]http.RoundTripper)
+)
+
+// Disable stops this process from routing through a daemon. The daemon calls
+// it so its own requests go to the API.
+func Disable() {
+	disabled.Store(true)
+}
+
+// Transport returns a RoundTripper that sends requests for apiURL through the
+// daemon when one is serving apiURL with apiKey, and nil otherwise, which an
+// http.Client treats as the default transport. The result is cached for the
+// process.
+func Transport(apiURL, apiKey string) http.RoundTripper {
+	if disabled.Load() || os.Getenv(DisableEnv) != "" || apiKey == "" {
+		return nil
+	}
+	dir := DefaultDir()
+	key := strings.Join([]string{dir, strings.TrimRight(apiURL, "/"), KeyID(apiKey)}, "\x00")
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+	var transport http.RoundTripper
+	if state := Discover(dir, apiURL, apiKey); state != nil {
+		transport = NewTransport(state, http.DefaultTransport)
+	}
+	transports[key] = transport
+	return transport
+}
+
+// Discover returns the state of the daemon in dir if it serves apiURL with
+// apiKey and accepts connections.
+func Discover(dir, apiURL, apiKey string) *State {
+	if disabled.Load() || os.Getenv(DisableEnv) != "" || apiKey == "" {
+		return nil
+	}
+	state, err := ReadState(StatePath(dir))
+	if err != nil || state == nil || state.PID == os.Getpid() {
+		return nil
+	}
+	if !sameAPI(state.APIURL, apiURL) || state.KeyID != KeyID(apiKey) {
+		return nil
+	}
+	if !state.Alive() {
+		return nil
+	}
+	return state
+}
+
+// Alive reports whether the daemon accepts connections.
+func (s *State) Alive() bool {
+	conn, err := net.DialTimeout(s.Network, s.Address, dialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func sameAPI(a, b string) bool {
+	return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+}
+
+// dialError marks a failure to reach the daemon, as opposed to a failure of
+// the request it forwarded.
+type dialError struct{ err error }
+
+func (e *dialError) Error() string { return fmt.Sprintf("daemon unreachable: %v", e.err) }
+func (e *dialError) Unwrap() error { return e.err }
+
+type routingTransport struct {
+	state    *State
+	upstream *url.URL
+	daemon   http.RoundTripper
+	base     http.RoundTripper
+	// downUntil is when, in Unix nanoseconds, the daemon is tried again
+	// after it could not be reached.
+	downUntil atomic.Int64
+}
+
+// NewTransport returns a RoundTripper that rewrites requests for the daemon's
+// API URL to the daemon and sends everything else with base. A request the
+// daemon cannot be reached for is sent with base instead, as are the
+// requests of the next redialAfter.
+func NewTransport(state *State, base http.RoundTripper) http.RoundTripper {
+	upstream, _ := url.Parse(state.APIURL)
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	daemon := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, state.Network, state.Address)
+			if err != nil {
+				return nil, &dialError{err: err}
+			}
+			return conn, nil
+		},
+		// A fresh connection per request means a daemon that went away is
+		// noticed at dial time, where the request can still go direct.
+		DisableKeepAlives: true,
+	}
+	return &routingTransport{state: state, upstream: upstream, daemon: daemon, base: base}
+}
+
+func (t *routingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.upstream == nil || req.URL.Scheme != t.upstream.Scheme || req.URL.Host != t.upstream.Host {
+		return t.base.RoundTrip(req)
+	}
+	if time.Now().UnixNano() < t.downUntil.Load() {
+		return t.base.RoundTrip(req)
+	}
+
+	out := req.Clone(req.Context())
+	out.URL.Scheme = "http"
+	out.URL.Host = "repobird-daemon"
+	if t.state.Network == "tcp" {
+		out.URL.Host = t.state.Address
+	}
+	out.Host = ""
+	out.Header.Del("Authorization")
+	out.Header.Set(TokenHeader, t.state.Token)
+
+	resp, err := t.daemon.RoundTrip(out)
+	var unreachable *dialError
+	if err == nil || !errors.As(err, &unreachable) {
+		return resp, err
+	}
+	t.downUntil.Store(time.Now().Add(redialAfter).UnixNano())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, err
+		}
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package daemon
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportRoutesAPIRequestsThroughDaemon(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "direct")
+	}))
+	defer upstream.Close()
+	var seen *http.Request
+	front := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+		_, _ = io.WriteString(w, "daemon")
+	}))
+	defer front.Close()
+
+	state := &State{Network: "tcp", Address: strings.TrimPrefix(front.URL, "http://"), Token: "tok", APIURL: upstream.URL}
+	client := &http.Client{Transport: NewTransport(state, http.DefaultTransport)}
+
+	req, err := http.NewRequest(http.MethodGet, upstream.URL+"/api/v1/runs/7", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer key")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "daemon", string(body))
+	require.NotNil(t, seen)
+	assert.Equal(t, "/api/v1/runs/7", seen.URL.Path)
+	assert.Equal(t, "tok", seen.Header.Get(TokenHeader))
+	assert.Empty(t, seen.Header.Get("Authorization"))
+
+	// With the daemon gone, requests go to the API directly.
+	front.Close()
+	resp, err = client.Post(upstream.URL+"/api/v1/runs", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "direct", string(body))
+	assert.Greater(t, client.Transport.(*routingTransport).downUntil.Load(), time.Now().UnixNano(),
+		"later requests skip the daemon until it is retried")
+}
+
+func TestTransportDiscoversTheDaemonOncePerProcess(t *testing.T) {
+	t.Setenv(DisableEnv, "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Cleanup(func() {
+		transportsMu.Lock()
+		clear(transports)
+		transportsMu.Unlock()
+	})
+	front := httptest.NewServer(http.NotFoundHandler())
+	defer front.Close()
+	state := &State{
+		Network: "tcp",
+		Address: strings.TrimPrefix(front.URL, "http://"),
+		Token:   "tok",
+		PID:     os.Getpid() + 1,
+		APIURL:  "https://api.repobird.ai",
+		KeyID:   KeyID("key"),
+	}
+	require.NoError(t, state.Save(StatePath(DefaultDir())))
+
+	transport := Transport("https://api.repobird.ai", "key")
+	require.NotNil(t, transport)
+	assert.Nil(t, Transport("https://api.repobird.ai", "other-key"))
+
+	// The daemon is not discovered again, so a stopped one is not dialed.
+	front.Close()
+	assert.Same(t, transport, Transport("https://api.repobird.ai/", "key"))
+}
+
+func TestDiscoverMatchesAPIAndKey(t *testing.T) {
+	t.Setenv(DisableEnv, "")
+	front := httptest.NewServer(http.NotFoundHandler())
+	defer front.Close()
+	dir := t.TempDir()
+	state := &State{
+		Network: "tcp",
+		Address: strings.TrimPrefix(front.URL, "http://"),
+		Token:   "tok",
+		PID:     os.Getpid() + 1,
+		APIURL:  "https://api.repobird.ai",
+		KeyID:   KeyID("key"),
+	}
+	require.NoError(t, state.Save(StatePath(dir)))
+
+	assert.NotNil(t, Discover(dir, "https://api.repobird.ai/", "key"))
+	assert.Nil(t, Discover(dir, "https://api.repobird.ai", "other-key"))
+	assert.Nil(t, Discover(dir, "http://localhost:3000", "key"))
+
+	t.Setenv(DisableEnv, "1")
+	assert.Nil(t, Discover(dir, "https://api.repobird.ai", "key"))
+
+	t.Setenv(DisableEnv, "")
+	front.Close()
+	assert.Nil(t, Discover(dir, "https://api.repobird.ai", "key"))
+}
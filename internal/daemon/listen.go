@@ -0,0 +1,63 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Listen opens the daemon listener. An empty address is the Unix socket in
+// dir, "unix:PATH" is a Unix socket at PATH, and anything else is a TCP
+// host:port, which must be on the loopback interface. It returns the network
+// and the address clients connect to.
+func Listen(address, dir string) (net.Listener, string, string, error) {
+	if address == "" || strings.HasPrefix(address, "unix:") {
+		path := strings.TrimPrefix(address, "unix:")
+		if path == "" {
+			path = SocketPath(dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, "", "", fmt.Errorf("failed to create socket directory: %w", err)
+		}
+		// A socket left behind by a daemon that did not shut down cleanly
+		// would make the listen fail.
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, "", "", fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to listen on %s: %w", path, err)
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			_ = listener.Close()
+			return nil, "", "", fmt.Errorf("failed to restrict socket permissions: %w", err)
+		}
+		return listener, "unix", path, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("invalid listen address %q: %w", address, err)
+	}
+	if !isLoopback(host) {
+		return nil, "", "", fmt.Errorf("listen address %q is not on the loopback interface", address)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return listener, "tcp", listener.Addr().String(), nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,477 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/events"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/pkg/version"
+)
+
+const (
+	// DefaultInterval is how often the daemon polls the run list, and how
+	// long it serves a cached response.
+	DefaultInterval = 15 * time.Second
+	// DefaultListLimit is how many of the newest runs each poll lists. It
+	// matches the page the TUI lists, so the TUI is answered from the poll.
+	DefaultListLimit = 1000
+
+	runsPath       = "/api/v1/runs"
+	authVerifyPath = "/api/v1/auth/verify"
+	fetchTimeout   = 30 * time.Second
+	subscriberSend = 64
+)
+
+// Config configures a Server.
+type Config struct {
+	APIURL string
+	APIKey string
+	// Token is required from clients on every endpoint but /health.
+	Token     string
+	Interval  time.Duration
+	ListLimit int
+	// Upstream sends the daemon's own requests to the API. Nil is the
+	// default transport.
+	Upstream http.RoundTripper
+	// Warm, when set, is given the runs each poll lists, to keep the local
+	// run cache warm.
+	Warm func(runs []*models.RunResponse)
+	// Batches, when set, returns the local bulk batches for batch.progress
+	// events.
+	Batches func() ([]*cache.BulkBatchRecord, error)
+	// Logf, when set, reports failed polls.
+	Logf func(format string, args ...any)
+}
+
+// Health is the body of GET /health.
+type Health struct {
+	Status      string     `json:"status"`
+	Version     string     `json:"version"`
+	PID         int        `json:"pid"`
+	APIURL      string     `json:"apiUrl"`
+	StartedAt   time.Time  `json:"startedAt"`
+	LastPoll    *time.Time `json:"lastPoll,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	Subscribers int        `json:"subscribers"`
+	Cached      int        `json:"cachedResponses"`
+}
+
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	fetched     time.Time
+}
+
+type subscriber struct {
+	repository string
+	send       chan []byte
+}
+
+// Server serves the daemon API: GET /health, the /events WebSocket feed, and
+// /api/v1/..., which mirrors the RepoBird API. GET requests for the run list,
+// run details and auth verification are answered from a cache; the run list
+// is refreshed by polling, and a smaller first page is cut from the polled
+// list. Everything else is forwarded to the API with the daemon's key, and a
+// successful change drops the cached responses it affects.
+type Server struct {
+	cfg      Config
+	upstream *url.URL
+	client   *http.Client
+	proxy    *httputil.ReverseProxy
+	started  time.Time
+
+	mu          sync.Mutex
+	responses   map[string]cachedResponse
+	tracker     *events.Tracker
+	lastPoll    time.Time
+	lastError   string
+	subscribers map[*subscriber]struct{}
+}
+
+// NewServer returns a server for cfg.
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("the daemon needs an API key")
+	}
+	upstream, err := url.Parse(cfg.APIURL)
+	if err != nil || upstream.Scheme == "" || upstream.Host == "" {
+		return nil, fmt.Errorf("invalid API URL %q", cfg.APIURL)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.ListLimit <= 0 {
+		cfg.ListLimit = DefaultListLimit
+	}
+	if cfg.Upstream == nil {
+		cfg.Upstream = http.DefaultTransport
+	}
+
+	tracker := events.NewTracker()
+	tracker.Baseline = time.Now()
+	s := &Server{
+		cfg:         cfg,
+		upstream:    upstream,
+		client:      &http.Client{Transport: cfg.Upstream, Timeout: fetchTimeout},
+		started:     time.Now(),
+		responses:   make(map[string]cachedResponse),
+		tracker:     tracker,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	s.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			pr.Out.Header.Del(TokenHeader)
+			pr.Out.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+		},
+		Transport:     cfg.Upstream,
+		FlushInterval: -1,
+		ModifyResponse: func(resp *http.Response) error {
+			if resp.Request.Method != http.MethodGet && resp.StatusCode < http.StatusMultipleChoices {
+				s.invalidate(resp.Request.URL.Path)
+			}
+			return nil
+		},
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler of the daemon API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/events", s.authorized(s.handleEvents))
+	mux.HandleFunc("/api/", s.authorized(s.handleAPI))
+	return mux
+}
+
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(TokenHeader)
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		if s.cfg.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			http.Error(w, "missing or invalid daemon token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	health := Health{
+		Status:      "ok",
+		Version:     version.GetVersion(),
+		PID:         os.Getpid(),
+		APIURL:      s.cfg.APIURL,
+		StartedAt:   s.started.UTC(),
+		LastError:   s.lastError,
+		Subscribers: len(s.subscribers),
+		Cached:      len(s.responses),
+	}
+	if !s.lastPoll.IsZero() {
+		lastPoll := s.lastPoll.UTC()
+		health.LastPoll = &lastPoll
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(health)
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !cacheable(r.URL.Path) {
+		s.proxy.ServeHTTP(w, r)
+		return
+	}
+	key := r.URL.RequestURI()
+	resp, ok := s.cached(key)
+	if !ok && r.URL.Path == runsPath {
+		resp, ok = s.firstPage(r.URL.Query())
+	}
+	if !ok {
+		var err error
+		if resp, err = s.fetch(r.Context(), key); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		if resp.status == http.StatusOK {
+			s.mu.Lock()
+			s.responses[key] = resp
+			s.mu.Unlock()
+		}
+	}
+	if resp.contentType != "" {
+		w.Header().Set("Content-Type", resp.contentType)
+	}
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+// cacheable reports whether GETs of path are served from the cache: the run
+// list, the details of one run, and auth verification.
+func cacheable(path string) bool {
+	if path == runsPath || path == authVerifyPath {
+		return true
+	}
+	id := strings.TrimPrefix(path, runsPath+"/")
+	return id != path && id != "" && !strings.Contains(id, "/")
+}
+
+// invalidate drops the cached run lists and, for a path under one run, the
+// cached details of that run.
+func (s *Server) invalidate(path string) {
+	path = strings.TrimPrefix(path, strings.TrimRight(s.upstream.Path, "/"))
+	if path != runsPath && !strings.HasPrefix(path, runsPath+"/") {
+		return
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(path, runsPath+"/"), "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.responses {
+		keyPath, _, _ := strings.Cut(key, "?")
+		if keyPath == runsPath || (id != "" && keyPath == runsPath+"/"+id) {
+			delete(s.responses, key)
+		}
+	}
+}
+
+func (s *Server) listURI() string {
+	return fmt.Sprintf("%s?page=1&limit=%d", runsPath, s.cfg.ListLimit)
+}
+
+// firstPage answers a request for the first page of fewer runs than each
+// poll lists from the polled list. The runs are passed through unchanged.
+func (s *Server) firstPage(query url.Values) (cachedResponse, bool) {
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 || limit >= s.cfg.ListLimit || query.Get("page") != "1" || len(query) != 2 {
+		return cachedResponse{}, false
+	}
+	polled, ok := s.cached(s.listURI())
+	if !ok {
+		return cachedResponse{}, false
+	}
+	var list struct {
+		Data     []json.RawMessage          `json:"data"`
+		Metadata *models.PaginationMetadata `json:"metadata,omitempty"`
+	}
+	if err := json.Unmarshal(polled.body, &list); err != nil || list.Data == nil {
+		return cachedResponse{}, false
+	}
+	if len(list.Data) > limit {
+		list.Data = list.Data[:limit]
+	}
+	if list.Metadata != nil {
+		list.Metadata.CurrentPage = 1
+		list.Metadata.TotalPages = (list.Metadata.Total + limit - 1) / limit
+	}
+	body, err := json.Marshal(list)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	polled.body = body
+	return polled, true
+}
+
+func (s *Server) cached(key string) (cachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, ok := s.responses[key]
+	if !ok || time.Since(resp.fetched) >= s.cfg.Interval {
+		return cachedResponse{}, false
+	}
+	return resp, true
+}
+
+// fetch GETs requestURI from the API with the daemon's key.
+func (s *Server) fetch(ctx context.Context, requestURI string) (cachedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(s.cfg.APIURL, "/")+requestURI, nil)
+	if err != nil {
+		return cachedResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("repobird-cli/%s (daemon)", version.GetVersion()))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return cachedResponse{}, fmt.Errorf("GET %s: %w", requestURI, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cachedResponse{}, fmt.Errorf("GET %s: %w", requestURI, err)
+	}
+	return cachedResponse{
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        body,
+		fetched:     time.Now(),
+	}, nil
+}
+
+// Run polls until ctx is done.
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Poll(ctx); err != nil && ctx.Err() == nil && s.cfg.Logf != nil {
+			s.cfg.Logf("poll failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll lists the newest runs once. It refreshes the cached run list and
+// sends the resulting events to the subscribers. Run details are not taken
+// from the list, whose entries may carry fewer fields than GET /runs/{id}.
+func (s *Server) Poll(ctx context.Context) error {
+	listURI := s.listURI()
+	resp, err := s.fetch(ctx, listURI)
+	if err == nil && resp.status != http.StatusOK {
+		err = fmt.Errorf("listing runs returned HTTP %d", resp.status)
+	}
+	var runs []*models.RunResponse
+	if err == nil {
+		runs, err = decodeRuns(resp.body)
+	}
+	if err != nil {
+		s.mu.Lock()
+		s.lastError = err.Error()
+		s.mu.Unlock()
+		return err
+	}
+
+	var batches []*cache.BulkBatchRecord
+	if s.cfg.Batches != nil {
+		if batches, err = s.cfg.Batches(); err != nil && s.cfg.Logf != nil {
+			s.cfg.Logf("failed to read bulk batches: %v", err)
+		}
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.responses[listURI] = resp
+	changes := s.tracker.Observe(runs, batches, now.UTC())
+	s.lastPoll = now
+	s.lastError = ""
+	s.mu.Unlock()
+
+	s.broadcast(changes)
+	if s.cfg.Warm != nil {
+		s.cfg.Warm(runs)
+	}
+	return nil
+}
+
+// decodeRuns accepts the paginated run list and the legacy bare array, like
+// the API client does.
+func decodeRuns(body []byte) ([]*models.RunResponse, error) {
+	var list models.ListRunsResponse
+	if err := json.Unmarshal(body, &list); err == nil {
+		return list.Data, nil
+	}
+	var runs []*models.RunResponse
+	if err := json.Unmarshal(body, &runs); err != nil {
+		return nil, fmt.Errorf("failed to decode run list: %w", err)
+	}
+	return runs, nil
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := acceptWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := s.subscribe(r.URL.Query().Get("repo"))
+	go func() {
+		_ = conn.readLoop()
+		s.unsubscribe(sub)
+	}()
+	for data := range sub.send {
+		if err := conn.WriteText(data); err != nil {
+			s.unsubscribe(sub)
+			return
+		}
+	}
+}
+
+func (s *Server) subscribe(repository string) *subscriber {
+	sub := &subscriber{repository: repository, send: make(chan []byte, subscriberSend)}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropLocked(sub)
+}
+
+func (s *Server) dropLocked(sub *subscriber) {
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.send)
+	}
+}
+
+// broadcast sends events to the subscribers. A subscriber too slow to keep
+// up is disconnected rather than allowed to hold up the others.
+func (s *Server) broadcast(changes []events.Event) {
+	if len(changes) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range changes {
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		for sub := range s.subscribers {
+			if sub.repository != "" && !strings.EqualFold(sub.repository, event.Repository) {
+				continue
+			}
+			select {
+			case sub.send <- data:
+			default:
+				s.dropLocked(sub)
+			}
+		}
+	}
+}
+
+// Close disconnects the event subscribers.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		s.dropLocked(sub)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/events"
+)
+
+type fakeAPI struct {
+	mu       sync.Mutex
+	status   string
+	lists    int
+	details  int
+	requests []*http.Request
+}
+
+func (f *fakeAPI) setStatus(status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+func (f *fakeAPI) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r.Clone(context.Background()))
+		assert.Equal(t, "Bearer daemon-key", r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get(TokenHeader))
+		switch {
+		case r.URL.Path == runsPath && r.Method == http.MethodGet:
+			f.lists++
+			fmt.Fprintf(w, `{"data":[{"id":"7","status":%q,"repositoryName":"acme/api","title":"Fix","createdAt":%q}]}`,
+				f.status, time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+		case r.URL.Path == runsPath && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"data":{"id":"8"}}`)
+		case r.URL.Path == runsPath+"/7" && r.Method == http.MethodGet:
+			f.details++
+			fmt.Fprintf(w, `{"data":{"id":"7","status":%q,"repositoryName":"acme/api","plan":"Fix it"}}`, f.status)
+		case r.URL.Path == runsPath+"/7" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func newTestServer(t *testing.T) (*Server, *fakeAPI, *httptest.Server) {
+	t.Helper()
+	api := &fakeAPI{status: "QUEUED"}
+	upstream := httptest.NewServer(api.handler(t))
+	t.Cleanup(upstream.Close)
+	server, err := NewServer(Config{APIURL: upstream.URL, APIKey: "daemon-key", Token: "tok", Interval: time.Minute})
+	require.NoError(t, err)
+	front := httptest.NewServer(server.Handler())
+	t.Cleanup(front.Close)
+	t.Cleanup(server.Close)
+	return server, api, front
+}
+
+func get(t *testing.T, url, token string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set(TokenHeader, token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestServerServesPolledRunsFromCache(t *testing.T) {
+	server, api, front := newTestServer(t)
+	require.NoError(t, server.Poll(context.Background()))
+
+	// The TUI's page and smaller first pages are answered from the poll.
+	resp, body := get(t, front.URL+"/api/v1/runs?page=1&limit=1000", "tok")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"repositoryName":"acme/api"`)
+	resp, body = get(t, front.URL+"/api/v1/runs?page=1&limit=10", "tok")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"repositoryName":"acme/api"`)
+
+	// Run details are fetched, not copied from the list, then cached.
+	for i := 0; i < 2; i++ {
+		resp, body = get(t, front.URL+"/api/v1/runs/7", "tok")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, `"plan":"Fix it"`)
+	}
+
+	api.mu.Lock()
+	assert.Equal(t, 1, api.lists)
+	assert.Equal(t, 1, api.details)
+	api.mu.Unlock()
+
+	resp, _ = get(t, front.URL+"/api/v1/runs/7", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, body = get(t, front.URL+"/health", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"status":"ok"`)
+}
+
+func TestServerForwardsOtherRequestsWithItsKey(t *testing.T) {
+	_, api, front := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodPost, front.URL+"/api/v1/runs", strings.NewReader(`{"prompt":"x"}`))
+	require.NoError(t, err)
+	req.Header.Set(TokenHeader, "tok")
+	req.Header.Set("Authorization", "Bearer client-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	require.Len(t, api.requests, 1)
+	assert.Equal(t, http.MethodPost, api.requests[0].Method)
+}
+
+func TestServerDropsCachedRunsAfterChanges(t *testing.T) {
+	server, api, front := newTestServer(t)
+	require.NoError(t, server.Poll(context.Background()))
+	get(t, front.URL+"/api/v1/runs/7", "tok")
+
+	send := func(method, path string) {
+		req, err := http.NewRequest(method, front.URL+path, strings.NewReader(`{"prompt":"x"}`))
+		require.NoError(t, err)
+		req.Header.Set(TokenHeader, "tok")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	counts := func() (int, int) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		return api.lists, api.details
+	}
+
+	// A created run drops the cached list.
+	send(http.MethodPost, runsPath)
+	get(t, front.URL+"/api/v1/runs?page=1&limit=1000", "tok")
+	get(t, front.URL+"/api/v1/runs/7", "tok")
+	lists, details := counts()
+	assert.Equal(t, 2, lists)
+	assert.Equal(t, 1, details)
+
+	// A deleted run drops the list and its details.
+	send(http.MethodDelete, runsPath+"/7")
+	get(t, front.URL+"/api/v1/runs?page=1&limit=1000", "tok")
+	get(t, front.URL+"/api/v1/runs/7", "tok")
+	lists, details = counts()
+	assert.Equal(t, 3, lists)
+	assert.Equal(t, 2, details)
+
+	// A failed change keeps the cache.
+	send(http.MethodDelete, runsPath+"/9")
+	get(t, front.URL+"/api/v1/runs?page=1&limit=1000", "tok")
+	lists, _ = counts()
+	assert.Equal(t, 3, lists)
+}
+
+// dialEvents opens the WebSocket event feed and returns a reader of its
+// text messages.
+func dialEvents(t *testing.T, front *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(front.URL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	fmt.Fprintf(conn, "GET /events?token=tok HTTP/1.1\r\nHost: daemon\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", key)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+	return conn, reader
+}
+
+func readMessage(t *testing.T, conn net.Conn, reader *bufio.Reader) events.Event {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var head [2]byte
+	_, err := io.ReadFull(reader, head[:])
+	require.NoError(t, err)
+	require.Equal(t, byte(0x80|opText), head[0])
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, err = io.ReadFull(reader, ext[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+	var event events.Event
+	require.NoError(t, json.Unmarshal(payload, &event))
+	return event
+}
+
+func TestServerStreamsEventsOverWebSocket(t *testing.T) {
+	server, api, front := newTestServer(t)
+	conn, reader := dialEvents(t, front)
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, server.Poll(context.Background()))
+	created := readMessage(t, conn, reader)
+	assert.Equal(t, events.RunCreated, created.Type)
+	assert.Equal(t, "7", created.RunID)
+
+	api.setStatus("DONE")
+	require.NoError(t, server.Poll(context.Background()))
+	changed := readMessage(t, conn, reader)
+	assert.Equal(t, events.RunStatusChanged, changed.Type)
+	assert.Equal(t, "QUEUED", changed.PreviousStatus)
+	assert.Equal(t, "DONE", changed.Status)
+
+	// A masked close frame from the client ends the subscription.
+	_, err := conn.Write([]byte{0x80 | opClose, 0x80, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.subscribers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package daemon is the local daemon started by 'repobird serve' and the
+// client side that routes API requests through it. The daemon owns the API
+// key and the polling of the run list; clients on the same machine share its
+// cached responses instead of each polling the API themselves.
+package daemon
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// TokenHeader carries the daemon token on every request but /health.
+	TokenHeader = "X-RepoBird-Daemon-Token"
+	// DisableEnv set to a non-empty value stops clients from using a daemon.
+	DisableEnv = "REPOBIRD_NO_DAEMON"
+)
+
+// State describes a running daemon. It is written to daemon.json in the
+// daemon dir while the daemon is serving, and is how clients find it.
+type State struct {
+	// Network is "unix" or "tcp".
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Token   string `json:"token"`
+	PID     int    `json:"pid"`
+	APIURL  string `json:"api_url"`
+	// KeyID identifies the API key the daemon authenticates with, so a
+	// client configured with another key does not borrow its identity.
+	KeyID     string    `json:"key_id"`
+	Version   string    `json:"version"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// DefaultDir returns the directory the state file and socket live in, in the
+// user cache dir.
+func DefaultDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "repobird", "daemon")
+}
+
+// StatePath returns the state file in dir.
+func StatePath(dir string) string {
+	return filepath.Join(dir, "daemon.json")
+}
+
+// SocketPath returns the default Unix socket in dir.
+func SocketPath(dir string) string {
+	return filepath.Join(dir, "repobird.sock")
+}
+
+// KeyID returns a short fingerprint of apiKey.
+func KeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewToken returns a random token for a new daemon.
+func NewToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate daemon token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ReadState reads the state file at path. A missing file is nil state.
+func ReadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read daemon state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Save writes the state to path, readable only by the current user since it
+// holds the token.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create daemon directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal daemon state: %w", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write daemon state: %w", err)
+	}
+	return os.Rename(tempPath, path)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var cursorNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// DefaultCursorDir returns the directory cursors are kept in, in the user
+// cache dir.
+func DefaultCursorDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "repobird", "events")
+}
+
+// CursorPath returns the file of the named cursor in dir.
+func CursorPath(dir, name string) (string, error) {
+	if !cursorNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid cursor name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// LoadTracker reads the tracker saved at path. A missing file is a fresh
+// tracker.
+func LoadTracker(path string) (*Tracker, error) {
+	tracker := NewTracker()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tracker, nil
+		}
+		return nil, fmt.Errorf("failed to read event cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, tracker); err != nil {
+		return nil, fmt.Errorf("failed to parse event cursor %s: %w", path, err)
+	}
+	if tracker.Runs == nil {
+		tracker.Runs = make(map[string]RunState)
+	}
+	if tracker.Batches == nil {
+		tracker.Batches = make(map[string]BatchCounts)
+	}
+	return tracker, nil
+}
+
+// Save writes the tracker to path.
+func (t *Tracker) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create event cursor directory: %w", err)
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode event cursor: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write event cursor: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write event cursor: %w", err)
+	}
+	return nil
+}
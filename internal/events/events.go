@@ -0,0 +1,273 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package events turns successive snapshots of the run list into typed
+// events. A Tracker holds the last snapshot and the progress of the local
+// bulk batches; each Observe compares a new snapshot with it and returns what
+// changed. The tracker state is the cursor that lets a stream resume where
+// it stopped.
+package events
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Event types.
+const (
+	RunCreated       = "run.created"
+	RunStatusChanged = "run.status_changed"
+	RunPROpened      = "run.pr_opened"
+	RunFailed        = "run.failed"
+	BatchProgress    = "batch.progress"
+)
+
+// Event is one change, written as one NDJSON line.
+type Event struct {
+	Type           string       `json:"type"`
+	Time           time.Time    `json:"time"`
+	RunID          string       `json:"runId,omitempty"`
+	Repository     string       `json:"repository,omitempty"`
+	Title          string       `json:"title,omitempty"`
+	Status         string       `json:"status,omitempty"`
+	PreviousStatus string       `json:"previousStatus,omitempty"`
+	PullRequestURL string       `json:"prUrl,omitempty"`
+	Error          string       `json:"error,omitempty"`
+	Batch          *BatchCounts `json:"batch,omitempty"`
+	CreatedAt      *time.Time   `json:"createdAt,omitempty"`
+}
+
+// RunState is what the tracker remembers of a run.
+type RunState struct {
+	Status         string    `json:"status"`
+	Repository     string    `json:"repository,omitempty"`
+	PullRequestURL string    `json:"pr_url,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BatchCounts is the progress of a bulk batch. Total is the number of runs
+// submitted; the other counts are of the runs the tracker has seen, with runs
+// the server rejected counted as failed.
+type BatchCounts struct {
+	BatchID   string `json:"batchId"`
+	Title     string `json:"title,omitempty"`
+	Total     int    `json:"total"`
+	Queued    int    `json:"queued"`
+	Running   int    `json:"running"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+}
+
+// Filter selects the runs a tracker reports on. Empty fields select all.
+type Filter struct {
+	Repository string
+	RunIDs     []string
+	// Since drops runs created before it.
+	Since time.Time
+}
+
+func (f Filter) matches(run *models.RunResponse) bool {
+	if f.Repository != "" && !strings.EqualFold(run.GetRepositoryName(), f.Repository) {
+		return false
+	}
+	if len(f.RunIDs) > 0 {
+		id := run.GetIDString()
+		found := false
+		for _, want := range f.RunIDs {
+			if want == id || (run.PublicID != "" && want == run.PublicID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return f.Since.IsZero() || !run.CreatedAt.Before(f.Since)
+}
+
+// Tracker diffs run snapshots. Its exported fields are the persisted cursor.
+type Tracker struct {
+	Runs    map[string]RunState    `json:"runs"`
+	Batches map[string]BatchCounts `json:"batches"`
+	// Primed is set once a snapshot has been observed. In the first snapshot
+	// of a fresh tracker, runs created before Baseline are recorded without
+	// events.
+	Primed    bool      `json:"primed"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Baseline is when an unprimed tracker starts reporting.
+	Baseline time.Time `json:"-"`
+	filter   Filter
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{Runs: make(map[string]RunState), Batches: make(map[string]BatchCounts)}
+}
+
+// SetFilter selects the runs the tracker reports on.
+func (t *Tracker) SetFilter(filter Filter) {
+	t.filter = filter
+}
+
+// Observe compares a snapshot of the run list and the local batch ledger
+// with the previous one and returns the events, in a stable order.
+func (t *Tracker) Observe(runs []*models.RunResponse, batches []*cache.BulkBatchRecord, now time.Time) []Event {
+	var out []Event
+	selected := make([]*models.RunResponse, 0, len(runs))
+	for _, run := range runs {
+		if run != nil && run.GetIDString() != "" && t.filter.matches(run) {
+			selected = append(selected, run)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].CreatedAt.Before(selected[j].CreatedAt) })
+
+	for _, run := range selected {
+		id := run.GetIDString()
+		current := RunState{
+			Status:     string(run.Status),
+			Repository: run.GetRepositoryName(),
+			CreatedAt:  run.CreatedAt,
+		}
+		if run.PullRequestURL != nil {
+			current.PullRequestURL = *run.PullRequestURL
+		}
+		previous, known := t.Runs[id]
+		t.Runs[id] = current
+		if !known && !t.Primed && run.CreatedAt.Before(t.Baseline) {
+			continue
+		}
+
+		base := Event{
+			Time:           now,
+			RunID:          id,
+			Repository:     current.Repository,
+			Title:          run.Title,
+			Status:         current.Status,
+			PullRequestURL: current.PullRequestURL,
+		}
+		if !known {
+			created := base
+			created.Type = RunCreated
+			createdAt := run.CreatedAt
+			created.CreatedAt = &createdAt
+			out = append(out, created)
+		} else if previous.Status != current.Status {
+			changed := base
+			changed.Type = RunStatusChanged
+			changed.PreviousStatus = previous.Status
+			out = append(out, changed)
+		}
+		if current.PullRequestURL != "" && (!known || previous.PullRequestURL == "") {
+			opened := base
+			opened.Type = RunPROpened
+			out = append(out, opened)
+		}
+		if isFailed(current.Status) && (!known || !isFailed(previous.Status)) {
+			failed := base
+			failed.Type = RunFailed
+			failed.Error = run.Error
+			out = append(out, failed)
+		}
+	}
+
+	t.prune(selected)
+	out = append(out, t.observeBatches(batches, now)...)
+	t.Primed = true
+	t.UpdatedAt = now
+	return out
+}
+
+// prune forgets runs that have dropped off the snapshot because newer runs
+// pushed them off the listed page, so the cursor does not grow without
+// bound. Runs missing for another reason, such as a failed fetch, are kept.
+func (t *Tracker) prune(selected []*models.RunResponse) {
+	if len(selected) == 0 || len(t.filter.RunIDs) > 0 {
+		return
+	}
+	oldest := selected[0].CreatedAt
+	seen := make(map[string]bool, len(selected))
+	for _, run := range selected {
+		seen[run.GetIDString()] = true
+	}
+	for id, state := range t.Runs {
+		if !seen[id] && state.CreatedAt.Before(oldest) {
+			delete(t.Runs, id)
+		}
+	}
+}
+
+func (t *Tracker) observeBatches(batches []*cache.BulkBatchRecord, now time.Time) []Event {
+	var out []Event
+	listed := make(map[string]bool, len(batches))
+	for _, batch := range batches {
+		if batch != nil {
+			listed[batch.BatchID] = true
+		}
+	}
+	for id := range t.Batches {
+		if !listed[id] {
+			delete(t.Batches, id)
+		}
+	}
+	sorted := append([]*cache.BulkBatchRecord(nil), batches...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+	for _, batch := range sorted {
+		if batch == nil || (t.filter.Repository != "" && !strings.EqualFold(batch.Repository, t.filter.Repository)) {
+			continue
+		}
+		counts := BatchCounts{
+			BatchID: batch.BatchID,
+			Title:   batch.BatchTitle,
+			Total:   max(len(batch.Request.Runs), len(batch.RunIDs)+len(batch.CreateErrors)),
+			Failed:  len(batch.CreateErrors),
+		}
+		seen := 0
+		for _, runID := range batch.RunIDs {
+			state, ok := t.Runs[strconv.Itoa(runID)]
+			if !ok {
+				continue
+			}
+			seen++
+			switch {
+			case state.Status == string(models.StatusDone):
+				counts.Completed++
+			case isFailed(state.Status):
+				counts.Failed++
+			case state.Status == string(models.StatusQueued):
+				counts.Queued++
+			default:
+				counts.Running++
+			}
+		}
+		if seen == 0 {
+			continue
+		}
+		previous, known := t.Batches[batch.BatchID]
+		t.Batches[batch.BatchID] = counts
+		if known && previous == counts {
+			continue
+		}
+		if !known && !t.Primed && batch.CreatedAt.Before(t.Baseline) {
+			continue
+		}
+		progress := counts
+		out = append(out, Event{Type: BatchProgress, Time: now, Repository: batch.Repository, Batch: &progress})
+	}
+	return out
+}
+
+func isFailed(status string) bool {
+	switch status {
+	case string(models.StatusFailed), "CANCELLED", "ERROR":
+		return true
+	default:
+		return false
+	}
+}
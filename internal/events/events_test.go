@@ -0,0 +1,138 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+var start = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+func snapshotRun(id string, status models.RunStatus, created time.Duration, prURL string) *models.RunResponse {
+	run := &models.RunResponse{ID: id, Status: status, RepositoryName: "acme/api", CreatedAt: start.Add(created)}
+	if prURL != "" {
+		run.PullRequestURL = &prURL
+	}
+	return run
+}
+
+func eventTypes(events []Event) []string {
+	types := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.Type + ":" + event.RunID
+	}
+	return types
+}
+
+func TestTrackerObserve(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Baseline = start
+
+	events := tracker.Observe([]*models.RunResponse{
+		snapshotRun("1", models.StatusProcessing, -time.Hour, ""),
+		snapshotRun("2", models.StatusQueued, time.Minute, ""),
+	}, nil, start.Add(time.Minute))
+	assert.Equal(t, []string{"run.created:2"}, eventTypes(events), "runs older than the baseline are recorded silently")
+
+	events = tracker.Observe([]*models.RunResponse{
+		snapshotRun("1", models.StatusDone, -time.Hour, "https://github.com/acme/api/pull/1"),
+		snapshotRun("2", models.StatusFailed, time.Minute, ""),
+		snapshotRun("3", models.StatusQueued, 2*time.Minute, ""),
+	}, nil, start.Add(2*time.Minute))
+	assert.Equal(t, []string{
+		"run.status_changed:1", "run.pr_opened:1",
+		"run.status_changed:2", "run.failed:2",
+		"run.created:3",
+	}, eventTypes(events))
+	assert.Equal(t, "PROCESSING", events[0].PreviousStatus)
+	assert.Equal(t, "DONE", events[0].Status)
+
+	events = tracker.Observe([]*models.RunResponse{
+		snapshotRun("1", models.StatusDone, -time.Hour, "https://github.com/acme/api/pull/1"),
+		snapshotRun("2", models.StatusFailed, time.Minute, ""),
+		snapshotRun("3", models.StatusQueued, 2*time.Minute, ""),
+	}, nil, start.Add(3*time.Minute))
+	assert.Empty(t, events)
+}
+
+func TestTrackerFilterAndBatchProgress(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Baseline = start
+	tracker.SetFilter(Filter{Repository: "ACME/api"})
+	batches := []*cache.BulkBatchRecord{{BatchID: "batch_1", Repository: "acme/api", RunIDs: map[int]int{0: 10, 1: 11}, CreatedAt: start}}
+	other := snapshotRun("20", models.StatusQueued, time.Minute, "")
+	other.RepositoryName = "acme/web"
+
+	events := tracker.Observe([]*models.RunResponse{
+		snapshotRun("10", models.StatusQueued, time.Minute, ""),
+		snapshotRun("11", models.StatusQueued, time.Minute, ""),
+		other,
+	}, batches, start.Add(time.Minute))
+	require.Len(t, events, 3)
+	assert.Equal(t, BatchProgress, events[2].Type)
+	assert.Equal(t, BatchCounts{BatchID: "batch_1", Total: 2, Queued: 2}, *events[2].Batch)
+
+	events = tracker.Observe([]*models.RunResponse{
+		snapshotRun("10", models.StatusDone, time.Minute, ""),
+		snapshotRun("11", models.StatusProcessing, time.Minute, ""),
+	}, batches, start.Add(2*time.Minute))
+	require.Len(t, events, 3)
+	assert.Equal(t, BatchCounts{BatchID: "batch_1", Total: 2, Running: 1, Completed: 1}, *events[2].Batch)
+}
+
+func TestTrackerBatchTotalIsTheSubmittedRunCount(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Baseline = start
+	batches := []*cache.BulkBatchRecord{{
+		BatchID:      "batch_1",
+		Repository:   "acme/api",
+		Request:      dto.BulkRunRequest{Runs: make([]dto.RunItem, 4)},
+		RunIDs:       map[int]int{0: 10, 1: 11, 2: 12},
+		CreateErrors: []int{3},
+		CreatedAt:    start,
+	}}
+
+	events := tracker.Observe([]*models.RunResponse{
+		snapshotRun("10", models.StatusDone, time.Minute, ""),
+		snapshotRun("11", models.StatusDone, time.Minute, ""),
+	}, batches, start.Add(time.Minute))
+	require.NotEmpty(t, events)
+	progress := events[len(events)-1]
+	require.Equal(t, BatchProgress, progress.Type)
+	assert.Equal(t, BatchCounts{BatchID: "batch_1", Total: 4, Completed: 2, Failed: 1}, *progress.Batch)
+}
+
+func TestTrackerCursorRoundTrip(t *testing.T) {
+	path, err := CursorPath(t.TempDir(), "ci")
+	require.NoError(t, err)
+	_, err = CursorPath(t.TempDir(), "../escape")
+	assert.Error(t, err)
+
+	tracker, err := LoadTracker(path)
+	require.NoError(t, err)
+	assert.False(t, tracker.Primed)
+	tracker.Baseline = start
+	tracker.Observe([]*models.RunResponse{snapshotRun("1", models.StatusQueued, time.Minute, "")}, nil, start)
+	require.NoError(t, tracker.Save(path))
+
+	resumed, err := LoadTracker(path)
+	require.NoError(t, err)
+	assert.True(t, resumed.Primed)
+	events := resumed.Observe([]*models.RunResponse{
+		snapshotRun("1", models.StatusProcessing, time.Minute, ""),
+		snapshotRun("0", models.StatusQueued, -time.Hour, ""),
+	}, nil, start.Add(time.Minute))
+	assert.Equal(t, []string{"run.created:0", "run.status_changed:1"}, eventTypes(events),
+		"a primed cursor reports every run it has not seen")
+	assert.FileExists(t, filepath.Join(filepath.Dir(path), "ci.json"))
+}
@@ -0,0 +1,368 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package hooks runs the completion hooks of a finished run: shell commands
+// with the run described in REPOBIRD_* environment variables, and webhooks
+// that receive the run as a JSON payload signed with HMAC-SHA256. Webhook
+// deliveries are retried; commands run once, within a timeout. Every attempt
+// is appended to a log in the cache directory.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/config"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC of the request body.
+	SignatureHeader = "X-RepoBird-Signature"
+	// EventHeader carries the payload's event.
+	EventHeader = "X-RepoBird-Event"
+	// SecretEnv overrides the configured webhook secret.
+	SecretEnv = "REPOBIRD_HOOK_SECRET"
+
+	// DefaultCommandTimeout bounds a hook command unless hooks.timeout is
+	// set.
+	DefaultCommandTimeout = 30 * time.Second
+
+	defaultAttempts = 3
+	webhookTimeout  = 10 * time.Second
+	// commandWaitDelay bounds how long a timed-out command's output is
+	// waited for, in case a process it started still holds it open.
+	commandWaitDelay = time.Second
+)
+
+// Events of a payload.
+const (
+	EventSucceeded = "run.succeeded"
+	EventFailed    = "run.failed"
+)
+
+// Payload describes a finished run. It is the webhook body, and its fields
+// are the environment of hook commands.
+type Payload struct {
+	Event          string    `json:"event"`
+	RunID          string    `json:"runId"`
+	Status         string    `json:"status"`
+	Success        bool      `json:"success"`
+	Repository     string    `json:"repository,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	PullRequestURL string    `json:"prUrl,omitempty"`
+	RunURL         string    `json:"runUrl,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	BatchID        string    `json:"batchId,omitempty"`
+	FinishedAt     time.Time `json:"finishedAt"`
+}
+
+// Env returns the REPOBIRD_* variables a hook command is run with.
+func (p Payload) Env() []string {
+	return []string{
+		"REPOBIRD_EVENT=" + p.Event,
+		"REPOBIRD_RUN_ID=" + p.RunID,
+		"REPOBIRD_STATUS=" + p.Status,
+		"REPOBIRD_SUCCESS=" + strconv.FormatBool(p.Success),
+		"REPOBIRD_REPOSITORY=" + p.Repository,
+		"REPOBIRD_TITLE=" + p.Title,
+		"REPOBIRD_PR_URL=" + p.PullRequestURL,
+		"REPOBIRD_RUN_URL=" + p.RunURL,
+		"REPOBIRD_ERROR=" + p.Error,
+		"REPOBIRD_BATCH_ID=" + p.BatchID,
+	}
+}
+
+// Targets returns the hooks to run for a run that succeeded or not: the
+// success or failure hooks, then the completion hooks.
+func Targets(hooks config.Hooks, success bool) []string {
+	var targets []string
+	if success {
+		targets = append(targets, hooks.OnSuccess...)
+	} else {
+		targets = append(targets, hooks.OnFailure...)
+	}
+	return append(targets, hooks.OnComplete...)
+}
+
+// Merge returns the hooks of both sets. The secret and timeout of extra win
+// when set.
+func Merge(base, extra config.Hooks) config.Hooks {
+	merged := config.Hooks{
+		OnSuccess:  append(append([]string(nil), base.OnSuccess...), extra.OnSuccess...),
+		OnFailure:  append(append([]string(nil), base.OnFailure...), extra.OnFailure...),
+		OnComplete: append(append([]string(nil), base.OnComplete...), extra.OnComplete...),
+		Secret:     base.Secret,
+		Timeout:    base.Timeout,
+	}
+	if extra.Secret != "" {
+		merged.Secret = extra.Secret
+	}
+	if extra.Timeout > 0 {
+		merged.Timeout = extra.Timeout
+	}
+	return merged
+}
+
+// Empty reports whether no hook is set.
+func Empty(hooks config.Hooks) bool {
+	return len(hooks.OnSuccess) == 0 && len(hooks.OnFailure) == 0 && len(hooks.OnComplete) == 0
+}
+
+// IsWebhook reports whether target is a webhook URL rather than a command.
+func IsWebhook(target string) bool {
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
+
+// Sign returns the signature header value of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Delivery is the outcome of one attempt to run a hook.
+type Delivery struct {
+	Time       time.Time `json:"time"`
+	RunID      string    `json:"run_id"`
+	Event      string    `json:"event"`
+	Kind       string    `json:"kind"`
+	Target     string    `json:"target"`
+	Attempt    int       `json:"attempt"`
+	OK         bool      `json:"ok"`
+	StatusCode int       `json:"status_code,omitempty"`
+	ExitCode   int       `json:"exit_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Runner runs hooks and logs their deliveries.
+type Runner struct {
+	// Secret signs webhook payloads; they are sent unsigned when it is empty.
+	Secret string
+	// LogPath is the file deliveries are appended to, one JSON object per
+	// line. Nothing is logged when it is empty.
+	LogPath string
+	// Output receives the output of hook commands.
+	Output io.Writer
+	// Attempts bounds the deliveries of one webhook, and Backoff is the wait
+	// before the second one, doubled before each further attempt.
+	Attempts int
+	Backoff  time.Duration
+	// CommandTimeout bounds each hook command; a command still running after
+	// it is killed and reported as failed.
+	CommandTimeout time.Duration
+
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewRunner returns a runner that signs webhooks with secret, or with
+// REPOBIRD_HOOK_SECRET when it is set, and logs to the default log.
+func NewRunner(secret string, output io.Writer) *Runner {
+	if env := os.Getenv(SecretEnv); env != "" {
+		secret = env
+	}
+	return &Runner{
+		Secret:         secret,
+		LogPath:        DefaultLogPath(),
+		Output:         output,
+		Attempts:       defaultAttempts,
+		Backoff:        time.Second,
+		CommandTimeout: DefaultCommandTimeout,
+		client:         &http.Client{Timeout: webhookTimeout},
+		now:            time.Now,
+	}
+}
+
+// DefaultLogPath returns the delivery log in the user cache dir.
+func DefaultLogPath() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "repobird", "hooks.log")
+}
+
+// Fire runs targets for payload in order and returns the last delivery of
+// each. A hook that fails does not stop the ones after it.
+func (r *Runner) Fire(ctx context.Context, targets []string, payload Payload) []Delivery {
+	if payload.FinishedAt.IsZero() {
+		payload.FinishedAt = r.now().UTC()
+	}
+	deliveries := make([]Delivery, 0, len(targets))
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		var delivery Delivery
+		if IsWebhook(target) {
+			delivery = r.post(ctx, target, payload)
+		} else {
+			delivery = r.exec(ctx, target, payload)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (r *Runner) exec(ctx context.Context, command string, payload Payload) Delivery {
+	if r.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.CommandTimeout)
+		defer cancel()
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), payload.Env()...)
+	cmd.WaitDelay = commandWaitDelay
+	if r.Output != nil {
+		cmd.Stdout = r.Output
+		cmd.Stderr = r.Output
+	}
+
+	delivery := r.newDelivery("command", command, payload, 1)
+	if err := cmd.Run(); err != nil {
+		delivery.Error = err.Error()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			delivery.Error = fmt.Sprintf("timed out after %s", r.CommandTimeout)
+		} else if exitErr, ok := err.(*exec.ExitError); ok {
+			delivery.ExitCode = exitErr.ExitCode()
+		}
+	} else {
+		delivery.OK = true
+	}
+	r.log(delivery)
+	return delivery
+}
+
+func (r *Runner) post(ctx context.Context, target string, payload Payload) Delivery {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		delivery := r.newDelivery("webhook", redactURL(target), payload, 1)
+		delivery.Error = err.Error()
+		r.log(delivery)
+		return delivery
+	}
+
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := r.Backoff
+	var delivery Delivery
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return delivery
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		var retry bool
+		delivery, retry = r.postOnce(ctx, target, payload, body, attempt)
+		r.log(delivery)
+		if delivery.OK || !retry {
+			break
+		}
+	}
+	return delivery
+}
+
+// postOnce sends one webhook request and reports whether a failure is worth
+// retrying: network errors, 429 and 5xx responses are.
+func (r *Runner) postOnce(ctx context.Context, target string, payload Payload, body []byte, attempt int) (Delivery, bool) {
+	delivery := r.newDelivery("webhook", redactURL(target), payload, attempt)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, payload.Event)
+	if r.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(r.Secret, body))
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery, ctx.Err() == nil
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.OK = true
+		return delivery, false
+	}
+	delivery.Error = fmt.Sprintf("webhook returned %s", resp.Status)
+	return delivery, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (r *Runner) newDelivery(kind, target string, payload Payload, attempt int) Delivery {
+	return Delivery{
+		Time:    r.now().UTC(),
+		RunID:   payload.RunID,
+		Event:   payload.Event,
+		Kind:    kind,
+		Target:  target,
+		Attempt: attempt,
+	}
+}
+
+// log appends delivery to the log. Logging is best effort: a hook is not
+// reported as failed because its log line could not be written.
+func (r *Runner) log(delivery Delivery) {
+	if r.LogPath == "" {
+		return
+	}
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.LogPath), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(r.LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(line, '\n'))
+}
+
+// redactURL keeps the scheme and host of a webhook URL for the log, since
+// the path and query of chat webhooks are often the credential.
+func redactURL(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return "webhook"
+	}
+	if parsed.Path == "" && parsed.RawQuery == "" {
+		return parsed.Scheme + "://" + parsed.Host
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/…"
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/config"
+)
+
+func testRunner(t *testing.T, output io.Writer) *Runner {
+	t.Helper()
+	t.Setenv(SecretEnv, "")
+	runner := NewRunner("s3cret", output)
+	runner.LogPath = filepath.Join(t.TempDir(), "hooks.log")
+	runner.Backoff = time.Millisecond
+	return runner
+}
+
+func readLog(t *testing.T, path string) []Delivery {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var delivery Delivery
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &delivery))
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func TestTargets(t *testing.T) {
+	hooks := Merge(
+		config.Hooks{OnSuccess: []string{"make test"}, OnComplete: []string{"notify"}, Secret: "a", Timeout: time.Minute},
+		config.Hooks{OnFailure: []string{"page"}, OnComplete: []string{"https://example.com/hook"}},
+	)
+	assert.Equal(t, "a", hooks.Secret)
+	assert.Equal(t, time.Minute, hooks.Timeout)
+	assert.Equal(t, []string{"make test", "notify", "https://example.com/hook"}, Targets(hooks, true))
+	assert.Equal(t, []string{"page", "notify", "https://example.com/hook"}, Targets(hooks, false))
+	assert.True(t, Empty(config.Hooks{Secret: "a"}))
+}
+
+func TestFireWebhookSignsAndRetries(t *testing.T) {
+	var requests int
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		assert.Equal(t, EventFailed, r.Header.Get(EventHeader))
+	}))
+	defer server.Close()
+
+	runner := testRunner(t, nil)
+	deliveries := runner.Fire(context.Background(), []string{server.URL + "/hooks/secret-token"}, Payload{
+		Event: EventFailed, RunID: "42", Status: "FAILED", Error: "tests failed",
+	})
+	require.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].OK)
+	assert.Equal(t, 2, deliveries[0].Attempt)
+	assert.Equal(t, Sign("s3cret", body), signature)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "42", payload.RunID)
+	assert.False(t, payload.FinishedAt.IsZero())
+
+	logged := readLog(t, runner.LogPath)
+	require.Len(t, logged, 2)
+	assert.False(t, logged[0].OK)
+	assert.Equal(t, http.StatusBadGateway, logged[0].StatusCode)
+	assert.NotContains(t, logged[1].Target, "secret-token")
+}
+
+func TestFireWebhookDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	deliveries := testRunner(t, nil).Fire(context.Background(), []string{server.URL}, Payload{Event: EventSucceeded, RunID: "1"})
+	require.Len(t, deliveries, 1)
+	assert.False(t, deliveries[0].OK)
+	assert.Equal(t, 1, requests)
+}
+
+func TestFireCommandSetsRunEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	var output bytes.Buffer
+	runner := testRunner(t, &output)
+	deliveries := runner.Fire(context.Background(), []string{
+		`echo "$REPOBIRD_RUN_ID $REPOBIRD_STATUS $REPOBIRD_PR_URL"`,
+		"exit 3",
+	}, Payload{Event: EventSucceeded, RunID: "7", Status: "DONE", Success: true, PullRequestURL: "https://github.com/acme/api/pull/9"})
+
+	require.Len(t, deliveries, 2)
+	assert.True(t, deliveries[0].OK)
+	assert.Equal(t, "7 DONE https://github.com/acme/api/pull/9\n", output.String())
+	assert.False(t, deliveries[1].OK)
+	assert.Equal(t, 3, deliveries[1].ExitCode)
+	assert.Len(t, readLog(t, runner.LogPath), 2)
+}
+
+func TestFireCommandTimesOut(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	runner := testRunner(t, io.Discard)
+	runner.CommandTimeout = 100 * time.Millisecond
+
+	started := time.Now()
+	deliveries := runner.Fire(context.Background(), []string{"sleep 10", "true"}, Payload{Event: EventFailed, RunID: "7"})
+
+	assert.Less(t, time.Since(started), 5*time.Second)
+	require.Len(t, deliveries, 2)
+	assert.False(t, deliveries[0].OK)
+	assert.Equal(t, "timed out after 100ms", deliveries[0].Error)
+	assert.True(t, deliveries[1].OK, "later hooks still run")
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build !unix && !windows
+
+package idempotency
+
+import "os"
+
+// Platforms without file locking rely on the atomic writes alone.
+func tryLockFile(*os.File) (bool, error) { return true, nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -8,12 +8,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
 var ErrDuplicate = errors.New("duplicate run submission")
 
+// ErrLocked is returned when the guard file stays locked by another process
+// for longer than the lock timeout.
+var ErrLocked = errors.New("run submission guard is locked by another process")
+
+const (
+	// historyRetention is how long reservations are kept for 'guard list'
+	// after their duplicate window has passed.
+	historyRetention = 24 * time.Hour
+	defaultLockWait  = 5 * time.Second
+	lockPollInterval = 20 * time.Millisecond
+)
+
 type RunIdentity struct {
 	Repository string
 	Prompt     string
@@ -24,10 +37,36 @@ type RunGuard struct {
 	cacheFile string
 	window    time.Duration
 	now       func() time.Time
+	lockWait  time.Duration
+}
+
+// Submission is what the guard remembers about a reserved key: when it was
+// reserved and, once known, the run it created or why it failed.
+type Submission struct {
+	Key        string    `json:"-"`
+	ReservedAt time.Time `json:"reserved_at"`
+	RunID      string    `json:"run_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// UnmarshalJSON also reads the bare timestamps written by earlier versions.
+func (s *Submission) UnmarshalJSON(data []byte) error {
+	var reservedAt time.Time
+	if err := json.Unmarshal(data, &reservedAt); err == nil {
+		*s = Submission{ReservedAt: reservedAt}
+		return nil
+	}
+	type submission Submission
+	var decoded submission
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = Submission(decoded)
+	return nil
 }
 
 type runGuardData struct {
-	Submissions map[string]time.Time `json:"submissions"`
+	Submissions map[string]Submission `json:"submissions"`
 }
 
 func BuildRunKey(identity RunIdentity) string {
@@ -48,6 +87,7 @@ func NewRunGuard(cacheDir string, window time.Duration, now func() time.Time) *R
 		cacheFile: filepath.Join(cacheDir, "run_submissions.json"),
 		window:    window,
 		now:       now,
+		lockWait:  defaultLockWait,
 	}
 }
 
@@ -63,59 +103,189 @@ func DefaultCacheDir() string {
 	return filepath.Join(baseDir, "repobird", "run-submissions")
 }
 
+// Reserve records key as submitted. It returns ErrDuplicate when the key was
+// reserved within the window and its submission has not failed, unless force
+// is set. The guard file is locked for the whole check, so of two processes
+// reserving the same key only one succeeds.
 func (g *RunGuard) Reserve(key string, force bool) error {
 	if key == "" {
 		return nil
 	}
 
+	return g.update(func(data *runGuardData, now time.Time) error {
+		if previous, ok := data.Submissions[key]; ok && now.Sub(previous.ReservedAt) < g.window && previous.Error == "" && !force {
+			return duplicateError(previous, now)
+		}
+		data.Submissions[key] = Submission{ReservedAt: now}
+		return nil
+	})
+}
+
+// Complete records the run a reserved key created.
+func (g *RunGuard) Complete(key, runID string) error {
+	return g.record(key, Submission{RunID: runID})
+}
+
+// Fail records why the submission of a reserved key failed. A failed
+// submission does not block a retry.
+func (g *RunGuard) Fail(key, message string) error {
+	if message == "" {
+		message = "submission failed"
+	}
+	return g.record(key, Submission{Error: message})
+}
+
+// List returns the remembered submissions, newest first.
+func (g *RunGuard) List() ([]Submission, error) {
+	var submissions []Submission
+	err := g.update(func(data *runGuardData, _ time.Time) error {
+		for key, submission := range data.Submissions {
+			submission.Key = key
+			submissions = append(submissions, submission)
+		}
+		return nil
+	})
+	sort.Slice(submissions, func(i, j int) bool {
+		return submissions[i].ReservedAt.After(submissions[j].ReservedAt)
+	})
+	return submissions, err
+}
+
+// Clear forgets the given keys, or every key when none are given, and
+// returns the number of submissions removed.
+func (g *RunGuard) Clear(keys ...string) (int, error) {
+	removed := 0
+	err := g.update(func(data *runGuardData, _ time.Time) error {
+		if len(keys) == 0 {
+			removed = len(data.Submissions)
+			data.Submissions = make(map[string]Submission)
+			return nil
+		}
+		for _, key := range keys {
+			if _, ok := data.Submissions[key]; ok {
+				delete(data.Submissions, key)
+				removed++
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+func (g *RunGuard) record(key string, outcome Submission) error {
+	if key == "" {
+		return nil
+	}
+	return g.update(func(data *runGuardData, now time.Time) error {
+		outcome.ReservedAt = now
+		if previous, ok := data.Submissions[key]; ok {
+			outcome.ReservedAt = previous.ReservedAt
+		}
+		data.Submissions[key] = outcome
+		return nil
+	})
+}
+
+func duplicateError(previous Submission, now time.Time) error {
+	age := now.Sub(previous.ReservedAt).Round(time.Second)
+	if previous.RunID != "" {
+		return fmt.Errorf("%w: identical run submitted %s ago, already created as run %s; pass --force to submit again",
+			ErrDuplicate, age, previous.RunID)
+	}
+	return fmt.Errorf("%w: identical run submitted %s ago; pass --force to submit again", ErrDuplicate, age)
+}
+
+// update runs fn on the guard data while holding the guard file lock and
+// saves the result.
+func (g *RunGuard) update(fn func(data *runGuardData, now time.Time) error) error {
+	unlock, err := g.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	data := g.load()
 	now := g.now().UTC()
-	if submittedAt, ok := data.Submissions[key]; ok && now.Sub(submittedAt) < g.window && !force {
-		return fmt.Errorf("%w: identical run submitted %s ago; pass --force to submit again",
-			ErrDuplicate,
-			now.Sub(submittedAt).Round(time.Second),
-		)
+	if err := fn(&data, now); err != nil {
+		return err
 	}
-
-	data.Submissions[key] = now
 	g.prune(data, now)
 	return g.save(data)
 }
 
+// lock takes an advisory lock on the guard's lock file, waiting up to
+// lockWait for another process to release it.
+func (g *RunGuard) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(g.cacheFile), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create idempotency cache directory: %w", err)
+	}
+	file, err := os.OpenFile(g.cacheFile+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency cache lock: %w", err)
+	}
+
+	deadline := time.Now().Add(g.lockWait)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to lock idempotency cache: %w", err)
+		}
+		if locked {
+			return func() {
+				_ = unlockFile(file)
+				_ = file.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = file.Close()
+			return nil, fmt.Errorf("%w after %s", ErrLocked, g.lockWait)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
 func (g *RunGuard) load() runGuardData {
-	data := runGuardData{Submissions: make(map[string]time.Time)}
+	data := runGuardData{Submissions: make(map[string]Submission)}
 
 	body, err := os.ReadFile(g.cacheFile)
 	if err != nil {
 		return data
 	}
 	if err := json.Unmarshal(body, &data); err != nil {
-		return runGuardData{Submissions: make(map[string]time.Time)}
+		return runGuardData{Submissions: make(map[string]Submission)}
 	}
 	if data.Submissions == nil {
-		data.Submissions = make(map[string]time.Time)
+		data.Submissions = make(map[string]Submission)
 	}
 	return data
 }
 
 func (g *RunGuard) save(data runGuardData) error {
-	if err := os.MkdirAll(filepath.Dir(g.cacheFile), 0o755); err != nil {
-		return fmt.Errorf("failed to create idempotency cache directory: %w", err)
-	}
-
 	body, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to encode idempotency cache: %w", err)
 	}
-	if err := os.WriteFile(g.cacheFile, body, 0o600); err != nil {
+	tmp := g.cacheFile + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o600); err != nil {
+		return fmt.Errorf("failed to write idempotency cache: %w", err)
+	}
+	if err := os.Rename(tmp, g.cacheFile); err != nil {
+		_ = os.Remove(tmp)
 		return fmt.Errorf("failed to write idempotency cache: %w", err)
 	}
 	return nil
 }
 
+// prune drops submissions older than both the window and the history
+// retention.
 func (g *RunGuard) prune(data runGuardData, now time.Time) {
-	for key, submittedAt := range data.Submissions {
-		if now.Sub(submittedAt) > g.window {
+	keep := g.window
+	if keep < historyRetention {
+		keep = historyRetention
+	}
+	for key, submission := range data.Submissions {
+		if now.Sub(submission.ReservedAt) > keep {
 			delete(data.Submissions, key)
 		}
 	}
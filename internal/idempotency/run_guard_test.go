@@ -1,6 +1,9 @@
 package idempotency
 
 import (
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -68,3 +71,92 @@ func TestGuardAllowsExpiredDuplicate(t *testing.T) {
 
 	require.NoError(t, guard.Reserve("key-1", false))
 }
+
+func TestGuardReportsCreatedRunOnDuplicate(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	guard := NewRunGuard(t.TempDir(), 30*time.Second, func() time.Time { return now })
+
+	require.NoError(t, guard.Reserve("key-1", false))
+	require.NoError(t, guard.Complete("key-1", "123"))
+	now = now.Add(5 * time.Second)
+
+	err := guard.Reserve("key-1", false)
+	require.ErrorIs(t, err, ErrDuplicate)
+	require.Contains(t, err.Error(), "already created as run 123")
+}
+
+func TestGuardAllowsRetryAfterFailure(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	guard := NewRunGuard(t.TempDir(), 30*time.Second, func() time.Time { return now })
+
+	require.NoError(t, guard.Reserve("key-1", false))
+	require.NoError(t, guard.Fail("key-1", "repository not found"))
+	require.NoError(t, guard.Reserve("key-1", false))
+}
+
+func TestGuardListAndClear(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	guard := NewRunGuard(dir, 30*time.Second, func() time.Time { return now })
+
+	// Earlier versions stored bare timestamps.
+	legacy := `{"submissions":{"old-key":"2026-06-10T11:59:00Z"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run_submissions.json"), []byte(legacy), 0o600))
+
+	now = now.Add(time.Second)
+	require.NoError(t, guard.Reserve("key-1", false))
+	require.NoError(t, guard.Complete("key-1", "42"))
+
+	submissions, err := guard.List()
+	require.NoError(t, err)
+	require.Equal(t, []Submission{
+		{Key: "key-1", ReservedAt: now, RunID: "42"},
+		{Key: "old-key", ReservedAt: time.Date(2026, 6, 10, 11, 59, 0, 0, time.UTC)},
+	}, submissions)
+
+	// The history outlives the duplicate window.
+	now = now.Add(time.Hour)
+	removed, err := guard.Clear("old-key", "missing")
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	removed, err = guard.Clear()
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+}
+
+func TestGuardAllowsOneOfConcurrentReservations(t *testing.T) {
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- NewRunGuard(dir, 30*time.Second, nil).Reserve("key-1", false)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	reserved := 0
+	for err := range errs {
+		if err == nil {
+			reserved++
+			continue
+		}
+		require.ErrorIs(t, err, ErrDuplicate)
+	}
+	require.Equal(t, 1, reserved)
+}
+
+func TestGuardTimesOutOnHeldLock(t *testing.T) {
+	dir := t.TempDir()
+	holder := NewRunGuard(dir, 30*time.Second, nil)
+	unlock, err := holder.lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	guard := NewRunGuard(dir, 30*time.Second, nil)
+	guard.lockWait = 50 * time.Millisecond
+	require.ErrorIs(t, guard.Reserve("key-1", false), ErrLocked)
+}
@@ -0,0 +1,273 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type githubIssue struct {
+	Number      int               `json:"number"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Labels      []json.RawMessage `json:"labels"`
+	URL         string            `json:"url"`
+	HTMLURL     string            `json:"html_url"`
+	PullRequest json.RawMessage   `json:"pull_request"`
+}
+
+// parseGitHub reads a JSON array of issues. Labels may be objects with a
+// name, as in 'gh issue list --json labels' and the REST API, or strings.
+// Pull requests listed by the REST issues endpoint are skipped.
+func parseGitHub(data []byte) ([]Issue, error) {
+	var raw []githubIssue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of issues: %w", err)
+	}
+	var issues []Issue
+	for _, item := range raw {
+		if len(item.PullRequest) > 0 && string(item.PullRequest) != "null" {
+			continue
+		}
+		issue := Issue{
+			Title: item.Title,
+			Body:  item.Body,
+			URL:   item.HTMLURL,
+		}
+		if issue.URL == "" {
+			issue.URL = item.URL
+		}
+		if item.Number > 0 {
+			issue.Key = fmt.Sprint(item.Number)
+		}
+		for _, label := range item.Labels {
+			if name := labelName(label); name != "" {
+				issue.Labels = append(issue.Labels, name)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func labelName(raw json.RawMessage) string {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name
+	}
+	var object struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(raw, &object)
+	return object.Name
+}
+
+// parseJiraJSON reads a Jira search result ({"issues": [...]}) or a plain
+// issue array. Descriptions may be strings or Atlassian document format.
+func parseJiraJSON(data []byte) ([]Issue, error) {
+	type jiraIssue struct {
+		Key    string `json:"key"`
+		Self   string `json:"self"`
+		Fields struct {
+			Summary     string          `json:"summary"`
+			Description json.RawMessage `json:"description"`
+			Labels      []string        `json:"labels"`
+		} `json:"fields"`
+	}
+	var raw []jiraIssue
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	} else {
+		var result struct {
+			Issues []jiraIssue `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		raw = result.Issues
+	}
+
+	var issues []Issue
+	for _, item := range raw {
+		issues = append(issues, Issue{
+			Key:    item.Key,
+			Title:  item.Fields.Summary,
+			Body:   jiraDescription(item.Fields.Description),
+			Labels: item.Fields.Labels,
+		})
+	}
+	return issues, nil
+}
+
+// jiraDescription returns the text of a description in wiki markup or in
+// Atlassian document format.
+func jiraDescription(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	doc.write(&b)
+	return strings.TrimSpace(b.String())
+}
+
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Attrs   adfAttrs  `json:"attrs"`
+	Content []adfNode `json:"content"`
+}
+
+type adfAttrs struct {
+	Level int `json:"level"`
+}
+
+// write renders the document as plain Markdown-like text, keeping headings
+// and list items so acceptance criteria can be found.
+func (n adfNode) write(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "heading":
+		b.WriteString(strings.Repeat("#", max(n.Attrs.Level, 1)) + " ")
+	case "listItem":
+		b.WriteString("- ")
+	}
+	for _, child := range n.Content {
+		child.write(b)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock":
+		b.WriteString("\n\n")
+	case "listItem":
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// parseJiraCSV reads a Jira CSV export. Jira repeats the Labels column once
+// per label and names custom fields "Custom field (Name)".
+func parseJiraCSV(data []byte) ([]Issue, error) {
+	return parseIssueCSV(data, func(header string) string {
+		switch {
+		case header == "issue key" || header == "key":
+			return "key"
+		case header == "summary":
+			return "title"
+		case header == "description":
+			return "body"
+		case header == "labels":
+			return "labels"
+		case strings.Contains(header, "acceptance criteria"):
+			return "acceptance"
+		case header == "url" || header == "issue url":
+			return "url"
+		}
+		return ""
+	}, false)
+}
+
+// parseLinearCSV reads a Linear CSV export, which lists labels in one
+// comma-separated column.
+func parseLinearCSV(data []byte) ([]Issue, error) {
+	return parseIssueCSV(data, func(header string) string {
+		switch header {
+		case "id", "identifier":
+			return "key"
+		case "title":
+			return "title"
+		case "description":
+			return "body"
+		case "labels":
+			return "labels"
+		case "acceptance criteria":
+			return "acceptance"
+		case "url":
+			return "url"
+		}
+		return ""
+	}, true)
+}
+
+// parseIssueCSV reads a CSV export whose columns are identified by field,
+// which maps a lower-case header to key, title, body, labels, acceptance or
+// url. splitLabels splits label cells on commas.
+func parseIssueCSV(data []byte, field func(header string) string, splitLabels bool) ([]Issue, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the export is empty")
+	}
+
+	columns := make([]string, len(records[0]))
+	hasTitle := false
+	for i, header := range records[0] {
+		columns[i] = field(strings.ToLower(strings.TrimSpace(header)))
+		hasTitle = hasTitle || columns[i] == "title"
+	}
+	if !hasTitle {
+		return nil, fmt.Errorf("no title column found in the header row")
+	}
+
+	var issues []Issue
+	for _, record := range records[1:] {
+		var issue Issue
+		for i, value := range record {
+			if i >= len(columns) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			switch columns[i] {
+			case "key":
+				issue.Key = value
+			case "title":
+				issue.Title = value
+			case "body":
+				issue.Body = value
+			case "acceptance":
+				issue.AcceptanceCriteria = value
+			case "url":
+				issue.URL = value
+			case "labels":
+				if !splitLabels {
+					if value != "" {
+						issue.Labels = append(issue.Labels, value)
+					}
+					continue
+				}
+				for _, label := range strings.Split(value, ",") {
+					if label = strings.TrimSpace(label); label != "" {
+						issue.Labels = append(issue.Labels, label)
+					}
+				}
+			}
+		}
+		if issue.Title != "" {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package importer reads issue-tracker exports and turns the issues into
+// bulk run configurations.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+// Source names an issue tracker whose export format can be imported.
+type Source string
+
+const (
+	SourceGitHub Source = "github"
+	SourceJira   Source = "jira"
+	SourceLinear Source = "linear"
+)
+
+// Sources lists the supported issue trackers.
+var Sources = []Source{SourceGitHub, SourceJira, SourceLinear}
+
+// ParseSource validates an issue tracker name.
+func ParseSource(name string) (Source, error) {
+	for _, source := range Sources {
+		if strings.EqualFold(name, string(source)) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unknown issue source %q (supported: github, jira, linear)", name)
+}
+
+// Issue is an issue read from an export.
+type Issue struct {
+	// Key identifies the issue in its tracker, such as "42" for a GitHub
+	// issue number or "PROJ-12" for Jira and Linear.
+	Key                string   `json:"key"`
+	Title              string   `json:"title"`
+	Body               string   `json:"body,omitempty"`
+	Labels             []string `json:"labels,omitempty"`
+	AcceptanceCriteria string   `json:"acceptanceCriteria,omitempty"`
+	URL                string   `json:"url,omitempty"`
+}
+
+// ParseFile reads the issues of an export file. GitHub exports are JSON, as
+// written by 'gh issue list --json' or the REST API; Jira exports are CSV or
+// JSON search results; Linear exports are CSV.
+func ParseFile(source Source, path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var issues []Issue
+	switch source {
+	case SourceGitHub:
+		issues, err = parseGitHub(data)
+	case SourceJira:
+		if strings.HasPrefix(strings.TrimSpace(string(data)), "{") || strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+			issues, err = parseJiraJSON(data)
+		} else {
+			issues, err = parseJiraCSV(data)
+		}
+	case SourceLinear:
+		issues, err = parseLinearCSV(data)
+	default:
+		return nil, fmt.Errorf("unknown issue source %q", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s export %s: %w", source, path, err)
+	}
+
+	for i := range issues {
+		issue := &issues[i]
+		issue.Title = strings.TrimSpace(issue.Title)
+		issue.Body = strings.TrimSpace(issue.Body)
+		if issue.AcceptanceCriteria == "" {
+			issue.Body, issue.AcceptanceCriteria = splitAcceptanceCriteria(issue.Body)
+		}
+		issue.AcceptanceCriteria = strings.TrimSpace(issue.AcceptanceCriteria)
+	}
+	return issues, nil
+}
+
+// acceptanceHeading matches an "Acceptance criteria" heading in Markdown or
+// Jira wiki markup, or as a line ending in a colon.
+var acceptanceHeading = regexp.MustCompile(`(?im)^[ \t]*(?:#{1,6}[ \t]*|h[1-6]\.[ \t]*|\*\*)?acceptance[ \t]+criteria(?:\*\*)?[ \t]*:?(?:\*\*)?[ \t]*$`)
+
+// nextHeading matches the heading that ends the acceptance criteria section.
+var nextHeading = regexp.MustCompile(`(?m)^[ \t]*(?:#{1,6}[ \t]+\S|h[1-6]\.[ \t]+\S)`)
+
+// splitAcceptanceCriteria moves an "Acceptance criteria" section out of the
+// issue body.
+func splitAcceptanceCriteria(body string) (string, string) {
+	loc := acceptanceHeading.FindStringIndex(body)
+	if loc == nil {
+		return body, ""
+	}
+	rest := body[loc[1]:]
+	end := len(rest)
+	if next := nextHeading.FindStringIndex(rest); next != nil {
+		end = next[0]
+	}
+	criteria := strings.TrimSpace(rest[:end])
+	remaining := strings.TrimSpace(body[:loc[0]] + rest[end:])
+	return remaining, criteria
+}
+
+// FilterLabels returns the issues that have at least one of labels. Labels
+// are compared case-insensitively; no labels keeps every issue.
+func FilterLabels(issues []Issue, labels []string) []Issue {
+	if len(labels) == 0 {
+		return issues
+	}
+	var filtered []Issue
+	for _, issue := range issues {
+		if hasAnyLabel(issue, labels) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+func hasAnyLabel(issue Issue, labels []string) bool {
+	for _, label := range issue.Labels {
+		for _, want := range labels {
+			if strings.EqualFold(label, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// defaultPrompt is used when no prompt template is selected.
+var defaultPrompt = template.Must(template.New("issue").Parse(`{{.Title}}
+{{- if .Body}}
+
+{{.Body}}
+{{- end}}
+{{- if .AcceptanceCriteria}}
+
+Acceptance criteria:
+{{.AcceptanceCriteria}}
+{{- end}}
+{{- if .URL}}
+
+Issue: {{.URL}}
+{{- end}}`))
+
+// Builder turns issues into bulk runs.
+type Builder struct {
+	// Template renders the prompt when set. It receives the key, title, body,
+	// labels, acceptance_criteria and url variables it declares, plus Vars.
+	Template *templates.Template
+	Vars     map[string]string
+}
+
+// Run returns the bulk run for an issue. A template's run settings, such as
+// its run type, model, base branch and files, are kept as run overrides.
+func (b *Builder) Run(issue Issue) (bulk.BulkRunConfig, error) {
+	run := bulk.BulkRunConfig{Title: RunTitle(issue)}
+	if b.Template == nil {
+		var buf bytes.Buffer
+		if err := defaultPrompt.Execute(&buf, issue); err != nil {
+			return run, fmt.Errorf("issue %s: %w", issue.Key, err)
+		}
+		run.Prompt = buf.String()
+		return run, nil
+	}
+
+	runConfig, err := b.Template.Render(b.variables(issue))
+	if err != nil {
+		return run, fmt.Errorf("issue %s: %w", issue.Key, err)
+	}
+	title := run.Title
+	run = bulk.RunFromConfig(runConfig)
+	if run.Title == "" {
+		run.Title = title
+	}
+	// The import picks one repository for every run.
+	run.Repository = ""
+	return run, nil
+}
+
+// variables returns the issue variables the template declares, since
+// templates reject unknown variables, followed by Vars.
+func (b *Builder) variables(issue Issue) map[string]string {
+	issueVars := map[string]string{
+		"key":                 issue.Key,
+		"title":               issue.Title,
+		"body":                issue.Body,
+		"labels":              strings.Join(issue.Labels, ", "),
+		"acceptance_criteria": issue.AcceptanceCriteria,
+		"url":                 issue.URL,
+	}
+	vars := make(map[string]string, len(b.Vars)+len(issueVars))
+	for _, variable := range b.Template.Variables {
+		if value, ok := issueVars[variable.Name]; ok {
+			vars[variable.Name] = value
+		}
+	}
+	for name, value := range b.Vars {
+		vars[name] = value
+	}
+	return vars
+}
+
+// RunTitle is the run title of an issue, prefixed with its key.
+func RunTitle(issue Issue) string {
+	if issue.Key == "" {
+		return issue.Title
+	}
+	if _, err := strconv.Atoi(issue.Key); err == nil {
+		return fmt.Sprintf("#%s: %s", issue.Key, issue.Title)
+	}
+	return fmt.Sprintf("%s: %s", issue.Key, issue.Title)
+}
+
+// githubIssueURL matches the repository part of a GitHub issue URL.
+var githubIssueURL = regexp.MustCompile(`^https?://[^/]+/(?:repos/)?([^/]+/[^/]+)/issues/\d+`)
+
+// Repository returns the owner/name repository shared by every issue URL,
+// or "" when the URLs do not name one repository.
+func Repository(issues []Issue) string {
+	repository := ""
+	for _, issue := range issues {
+		match := githubIssueURL.FindStringSubmatch(issue.URL)
+		if match == nil {
+			return ""
+		}
+		if repository != "" && !strings.EqualFold(repository, match[1]) {
+			return ""
+		}
+		repository = match[1]
+	}
+	return repository
+}
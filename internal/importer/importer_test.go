@@ -0,0 +1,148 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/templates"
+)
+
+func writeExport(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestParseGitHub(t *testing.T) {
+	path := writeExport(t, "issues.json", `[
+  {"number": 42, "title": "Fix login redirect", "body": "Users land on /home.\n\n## Acceptance criteria\n- Redirect to the original page\n- Add a test\n\n## Notes\nSee #40.",
+   "labels": [{"name": "bug"}, {"name": "ready"}], "html_url": "https://github.com/acme/webapp/issues/42"},
+  {"number": 43, "title": "Bump deps", "labels": ["chore"], "url": "https://github.com/acme/webapp/issues/43"},
+  {"number": 44, "title": "A pull request", "pull_request": {"url": "x"}}
+]`)
+
+	issues, err := ParseFile(SourceGitHub, path)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "42", issues[0].Key)
+	assert.Equal(t, []string{"bug", "ready"}, issues[0].Labels)
+	assert.Equal(t, "Users land on /home.\n\n## Notes\nSee #40.", issues[0].Body)
+	assert.Equal(t, "- Redirect to the original page\n- Add a test", issues[0].AcceptanceCriteria)
+	assert.Equal(t, []string{"chore"}, issues[1].Labels)
+	assert.Equal(t, "acme/webapp", Repository(issues))
+
+	assert.Equal(t, "#42: Fix login redirect", RunTitle(issues[0]))
+	assert.Len(t, FilterLabels(issues, []string{"READY"}), 1)
+}
+
+func TestParseJira(t *testing.T) {
+	csvPath := writeExport(t, "jira.csv", "Summary,Issue key,Description,Labels,Labels,Custom field (Acceptance Criteria)\n"+
+		"Add rate limiting,API-7,Limit requests per key.,backend,ready,Return 429 when exceeded\n"+
+		",API-8,no title,,,\n")
+	issues, err := ParseFile(SourceJira, csvPath)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, Issue{
+		Key:                "API-7",
+		Title:              "Add rate limiting",
+		Body:               "Limit requests per key.",
+		Labels:             []string{"backend", "ready"},
+		AcceptanceCriteria: "Return 429 when exceeded",
+	}, issues[0])
+	assert.Equal(t, "API-7: Add rate limiting", RunTitle(issues[0]))
+
+	jsonPath := writeExport(t, "jira.json", `{"issues": [{"key": "API-9", "fields": {
+  "summary": "Cache tokens", "labels": ["backend"],
+  "description": {"type": "doc", "content": [
+    {"type": "paragraph", "content": [{"type": "text", "text": "Tokens are fetched on every call."}]},
+    {"type": "heading", "attrs": {"level": 3}, "content": [{"type": "text", "text": "Acceptance criteria"}]},
+    {"type": "bulletList", "content": [
+      {"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Tokens are reused"}]}]}
+    ]}
+  ]}}}]}`)
+	issues, err = ParseFile(SourceJira, jsonPath)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "Tokens are fetched on every call.", issues[0].Body)
+	assert.Equal(t, "- Tokens are reused", issues[0].AcceptanceCriteria)
+}
+
+func TestParseLinear(t *testing.T) {
+	path := writeExport(t, "linear.csv", "\ufeffID,Title,Description,Status,Labels,URL\n"+
+		"WEB-3,Dark mode,\"Add a theme toggle.\n\nAcceptance criteria:\n- Persists across reloads\",Todo,\"frontend, ready\",https://linear.app/acme/issue/WEB-3\n")
+
+	issues, err := ParseFile(SourceLinear, path)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "WEB-3", issues[0].Key)
+	assert.Equal(t, []string{"frontend", "ready"}, issues[0].Labels)
+	assert.Equal(t, "Add a theme toggle.", issues[0].Body)
+	assert.Equal(t, "- Persists across reloads", issues[0].AcceptanceCriteria)
+	assert.Empty(t, Repository(issues))
+
+	_, err = ParseFile(SourceLinear, writeExport(t, "bad.csv", "Name,Notes\nx,y\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no title column")
+}
+
+func TestBuilder(t *testing.T) {
+	issue := Issue{
+		Key:                "42",
+		Title:              "Fix login redirect",
+		Body:               "Users land on /home.",
+		AcceptanceCriteria: "- Redirect back",
+		URL:                "https://github.com/acme/webapp/issues/42",
+	}
+
+	run, err := (&Builder{}).Run(issue)
+	require.NoError(t, err)
+	assert.Equal(t, "#42: Fix login redirect", run.Title)
+	assert.Equal(t, "Fix login redirect\n\nUsers land on /home.\n\nAcceptance criteria:\n- Redirect back\n\nIssue: https://github.com/acme/webapp/issues/42", run.Prompt)
+
+	tmpl, err := templates.Parse("fix-issue", "fix-issue.md", []byte(`---
+variables:
+  - name: title
+    required: true
+  - name: acceptance_criteria
+  - name: team
+    required: true
+---
+[{{.team}}] {{.title}}
+Done when: {{.acceptance_criteria}}
+`))
+	require.NoError(t, err)
+	run, err = (&Builder{Template: tmpl, Vars: map[string]string{"team": "web"}}).Run(issue)
+	require.NoError(t, err)
+	assert.Equal(t, "#42: Fix login redirect", run.Title)
+	assert.Equal(t, "[web] Fix login redirect\nDone when: - Redirect back", run.Prompt)
+
+	// The template's run settings are kept as run overrides.
+	tmpl, err = templates.Parse("fix-issue", "fix-issue.md", []byte(`---
+runType: plan
+model: sonnet
+baseBranch: develop
+files: [src/auth.ts]
+repository: acme/other
+variables:
+  - name: title
+---
+Fix {{.title}}
+`))
+	require.NoError(t, err)
+	run, err = (&Builder{Template: tmpl}).Run(issue)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix Fix login redirect", run.Prompt)
+	assert.Equal(t, "plan", run.RunType)
+	assert.Equal(t, "sonnet", run.Model)
+	assert.Equal(t, "develop", run.BaseBranch)
+	assert.Equal(t, []string{"src/auth.ts"}, run.Files)
+	assert.Empty(t, run.Repository)
+}
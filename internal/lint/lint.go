@@ -0,0 +1,189 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package lint checks run configurations for problems that field validation
+// does not catch, such as vague prompts or conflicting branch settings.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Severity is how seriously an issue is treated.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// rank orders severities so thresholds can be compared.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as severe as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() > 0 && s.rank() >= threshold.rank()
+}
+
+// ParseSeverity parses a configured severity name.
+func ParseSeverity(value string) (Severity, error) {
+	switch Severity(strings.ToLower(strings.TrimSpace(value))) {
+	case SeverityError:
+		return SeverityError, nil
+	case SeverityWarning, "warn":
+		return SeverityWarning, nil
+	case SeverityInfo, "note":
+		return SeverityInfo, nil
+	case SeverityOff, "none", "disabled":
+		return SeverityOff, nil
+	default:
+		return "", fmt.Errorf("invalid severity %q (use error, warning, info, or off)", value)
+	}
+}
+
+// Target is one run configuration to lint.
+type Target struct {
+	// Name identifies the target in output, for example a file path or
+	// "runs[2]" inside a bulk file.
+	Name   string
+	Config *models.RunConfig
+	// AdditionalContext is Markdown body content appended to the context.
+	AdditionalContext string
+}
+
+// Context returns the full context that would be submitted.
+func (t Target) Context() string {
+	if t.AdditionalContext == "" {
+		return t.Config.Context
+	}
+	if t.Config.Context == "" {
+		return t.AdditionalContext
+	}
+	return t.Config.Context + "\n\n" + t.AdditionalContext
+}
+
+// Issue is one problem reported by a rule.
+type Issue struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	Target   string   `json:"target,omitempty"`
+	Field    string   `json:"field"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Rule is a single lint check.
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity Severity
+	Check           func(env Env, target Target) []Issue
+}
+
+// Env is the environment rules run in.
+type Env struct {
+	// RepoRoot is the local checkout used to verify referenced paths. Rules
+	// that need it are skipped when it is empty.
+	RepoRoot string
+	// FileExists reports whether a repository-relative path exists.
+	FileExists func(path string) bool
+	// Repository is the owner/name of the checkout at RepoRoot. Paths are
+	// only verified for targets without a repository or for this one.
+	Repository string
+}
+
+// Linter applies rules with configured severities.
+type Linter struct {
+	rules      []Rule
+	severities map[string]Severity
+	env        Env
+}
+
+// New creates a linter with the default rules. overrides maps rule IDs to
+// severity names, typically from the `lint.rules` section of .repobird.yaml.
+func New(env Env, overrides map[string]string) (*Linter, error) {
+	l := &Linter{rules: DefaultRules(), severities: make(map[string]Severity), env: env}
+	known := make(map[string]bool, len(l.rules))
+	for _, rule := range l.rules {
+		known[rule.ID] = true
+		l.severities[rule.ID] = rule.DefaultSeverity
+	}
+
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown lint rule %q in lint.rules", id)
+		}
+		severity, err := ParseSeverity(overrides[id])
+		if err != nil {
+			return nil, fmt.Errorf("lint.rules.%s: %w", id, err)
+		}
+		l.severities[id] = severity
+	}
+	return l, nil
+}
+
+// Rules returns the linter's rules with their effective severities.
+func (l *Linter) Rules() []Rule {
+	rules := make([]Rule, len(l.rules))
+	for i, rule := range l.rules {
+		rule.DefaultSeverity = l.severities[rule.ID]
+		rules[i] = rule
+	}
+	return rules
+}
+
+// Lint runs every enabled rule against the targets.
+func (l *Linter) Lint(targets ...Target) []Issue {
+	var issues []Issue
+	for _, target := range targets {
+		if target.Config == nil {
+			continue
+		}
+		for _, rule := range l.rules {
+			severity := l.severities[rule.ID]
+			if severity == SeverityOff {
+				continue
+			}
+			for _, issue := range rule.Check(l.env, target) {
+				issue.RuleID = rule.ID
+				issue.Severity = severity
+				issue.Target = target.Name
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// Count returns how many issues are at least as severe as threshold.
+func Count(issues []Issue, threshold Severity) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Severity.AtLeast(threshold) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+const goodPrompt = "Add retry with exponential backoff to internal/api/client.go. Acceptance criteria: tests pass."
+
+func ruleIDs(issues []Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.RuleID)
+	}
+	return ids
+}
+
+func TestLintRules(t *testing.T) {
+	existing := map[string]bool{"internal/api/client.go": true}
+	env := Env{RepoRoot: "/repo", FileExists: func(path string) bool { return existing[path] }, Repository: "acme/api"}
+
+	tests := []struct {
+		name   string
+		config models.RunConfig
+		want   []string
+	}{
+		{
+			name:   "clean config",
+			config: models.RunConfig{Prompt: goodPrompt},
+		},
+		{
+			name:   "short and vague prompt",
+			config: models.RunConfig{Prompt: "fix the bug"},
+			want:   []string{RulePromptTooShort, RulePromptVague, RuleAcceptanceCriteria},
+		},
+		{
+			name:   "missing referenced file",
+			config: models.RunConfig{Prompt: goodPrompt + " Also update `internal/api/retry.go`."},
+			want:   []string{RuleMissingFile},
+		},
+		{
+			name:   "missing listed file",
+			config: models.RunConfig{Prompt: goodPrompt, Files: []string{"./docs/missing.md"}},
+			want:   []string{RuleMissingFile},
+		},
+		{
+			name:   "missing file in the checkout's repository",
+			config: models.RunConfig{Prompt: goodPrompt, Repository: "Acme/API", Files: []string{"docs/missing.md"}},
+			want:   []string{RuleMissingFile},
+		},
+		{
+			name:   "file in another repository",
+			config: models.RunConfig{Prompt: goodPrompt, Repository: "acme/web", Files: []string{"docs/missing.md"}},
+		},
+		{
+			name:   "branch only with pr target",
+			config: models.RunConfig{Prompt: goodPrompt, BranchOnly: true, PRTargetBranch: "main"},
+			want:   []string{RuleConflictingBranches},
+		},
+		{
+			name:   "source and base disagree",
+			config: models.RunConfig{Prompt: goodPrompt, Source: "dev", BaseBranch: "main"},
+			want:   []string{RuleConflictingBranches},
+		},
+		{
+			name:   "oversized context",
+			config: models.RunConfig{Prompt: goodPrompt, Context: strings.Repeat("x", maxContextBytes+1)},
+			want:   []string{RuleOversizedContext},
+		},
+	}
+
+	linter, err := New(env, nil)
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			issues := linter.Lint(Target{Name: "task.yaml", Config: &config})
+			if len(tt.want) == 0 {
+				assert.Empty(t, issues)
+				return
+			}
+			assert.Equal(t, tt.want, ruleIDs(issues))
+		})
+	}
+}
+
+func TestLintSkipsFileChecksWithoutCheckout(t *testing.T) {
+	linter, err := New(Env{}, nil)
+	require.NoError(t, err)
+	issues := linter.Lint(Target{Config: &models.RunConfig{Prompt: goodPrompt + " See internal/missing/file.go."}})
+	assert.Empty(t, issues)
+}
+
+func TestNewAppliesSeverityOverrides(t *testing.T) {
+	linter, err := New(Env{}, map[string]string{
+		RulePromptTooShort:     "error",
+		RulePromptVague:        "off",
+		RuleAcceptanceCriteria: "warn",
+	})
+	require.NoError(t, err)
+
+	issues := linter.Lint(Target{Config: &models.RunConfig{Prompt: "fix the bug"}})
+	require.Len(t, issues, 2)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, SeverityWarning, issues[1].Severity)
+	assert.Equal(t, 1, Count(issues, SeverityError))
+	assert.Equal(t, 2, Count(issues, SeverityWarning))
+
+	_, err = New(Env{}, map[string]string{"no-such-rule": "error"})
+	require.ErrorContains(t, err, "unknown lint rule")
+	_, err = New(Env{}, map[string]string{RulePromptVague: "loud"})
+	require.ErrorContains(t, err, "invalid severity")
+}
+
+func TestWriteSARIF(t *testing.T) {
+	linter, err := New(Env{}, nil)
+	require.NoError(t, err)
+	issues := linter.Lint(Target{Name: "tasks.yaml#runs[1]", Config: &models.RunConfig{Prompt: "fix the bug"}})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, linter.Rules(), issues))
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(DefaultRules()))
+	require.Len(t, log.Runs[0].Results, 3)
+	assert.Equal(t, "warning", log.Runs[0].Results[0].Level)
+	assert.Equal(t, "note", log.Runs[0].Results[2].Level)
+	assert.Equal(t, "tasks.yaml", log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestWriteJSONCountsSeverities(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, nil))
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "repobird.lint.v1", report.Schema)
+	assert.NotNil(t, report.Issues)
+	assert.Zero(t, report.Errors)
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported output formats.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// ValidateFormat reports whether format is a supported output format.
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatSARIF:
+		return nil
+	default:
+		return fmt.Errorf("unsupported lint format %q (use text, json, or sarif)", format)
+	}
+}
+
+// Location returns target:field[:line] for display.
+func (i Issue) Location() string {
+	var b strings.Builder
+	if i.Target != "" {
+		b.WriteString(i.Target)
+		b.WriteString(":")
+	}
+	b.WriteString(i.Field)
+	if i.Line > 0 {
+		fmt.Fprintf(&b, ":%d", i.Line)
+	}
+	return b.String()
+}
+
+// Summary returns a one-line count of issues by severity.
+func Summary(issues []Issue) string {
+	if len(issues) == 0 {
+		return "No lint issues found"
+	}
+	counts := make(map[Severity]int)
+	for _, issue := range issues {
+		counts[issue.Severity]++
+	}
+	return fmt.Sprintf("%d issue(s): %d error(s), %d warning(s), %d info",
+		len(issues), counts[SeverityError], counts[SeverityWarning], counts[SeverityInfo])
+}
+
+// JSONReport is the JSON output of a lint run.
+type JSONReport struct {
+	Schema   string  `json:"schema"`
+	Issues   []Issue `json:"issues"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Infos    int     `json:"infos"`
+}
+
+// WriteJSON writes issues as an indented JSON report.
+func WriteJSON(w io.Writer, issues []Issue) error {
+	report := JSONReport{Schema: "repobird.lint.v1", Issues: issues}
+	if report.Issues == nil {
+		report.Issues = []Issue{}
+	}
+	for _, issue := range issues {
+		switch issue.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		case SeverityInfo:
+			report.Infos++
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// SARIF 2.1.0 types, limited to the properties code scanning tools read.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// WriteSARIF writes issues as a SARIF 2.1.0 log for CI code scanning.
+// Targets are reported as artifact URIs, so file-based targets link back to
+// the run configuration that produced the issue.
+func WriteSARIF(w io.Writer, rules []Rule, issues []Issue) error {
+	driver := sarifDriver{
+		Name:           "repobird-lint",
+		InformationURI: "https://repobird.ai",
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.DefaultSeverity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(issues))
+	for _, issue := range issues {
+		result := sarifResult{
+			RuleID:  issue.RuleID,
+			Level:   sarifLevel(issue.Severity),
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", issue.Field, issue.Message)},
+		}
+		if uri := sarifURI(issue.Target); uri != "" {
+			// Issue lines are relative to the prompt or context text rather
+			// than the file, so no region is reported.
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: uri}},
+			}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifURI strips a bulk run suffix such as "#runs[2]" from a target name.
+func sarifURI(target string) string {
+	uri, _, _ := strings.Cut(target, "#")
+	return uri
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule IDs.
+const (
+	RulePromptTooShort      = "prompt-too-short"
+	RulePromptVague         = "prompt-vague"
+	RuleAcceptanceCriteria  = "missing-acceptance-criteria"
+	RuleMissingFile         = "missing-file"
+	RuleConflictingBranches = "conflicting-branches"
+	RuleOversizedContext    = "oversized-context"
+)
+
+const (
+	minPromptWords          = 5
+	maxContextBytes         = 64 * 1024
+	maxReportedMissingFiles = 10
+)
+
+// DefaultRules returns the built-in rule set.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:              RulePromptTooShort,
+			Description:     fmt.Sprintf("Prompt has fewer than %d words", minPromptWords),
+			DefaultSeverity: SeverityWarning,
+			Check:           checkPromptTooShort,
+		},
+		{
+			ID:              RulePromptVague,
+			Description:     "Prompt is a generic request without specifics",
+			DefaultSeverity: SeverityWarning,
+			Check:           checkPromptVague,
+		},
+		{
+			ID:              RuleAcceptanceCriteria,
+			Description:     "Prompt and context do not say how to verify the change",
+			DefaultSeverity: SeverityInfo,
+			Check:           checkAcceptanceCriteria,
+		},
+		{
+			ID:              RuleMissingFile,
+			Description:     "Referenced file path does not exist in the local checkout",
+			DefaultSeverity: SeverityWarning,
+			Check:           checkMissingFiles,
+		},
+		{
+			ID:              RuleConflictingBranches,
+			Description:     "Branch settings contradict each other",
+			DefaultSeverity: SeverityError,
+			Check:           checkConflictingBranches,
+		},
+		{
+			ID:              RuleOversizedContext,
+			Description:     fmt.Sprintf("Context is larger than %d KiB", maxContextBytes/1024),
+			DefaultSeverity: SeverityWarning,
+			Check:           checkOversizedContext,
+		},
+	}
+}
+
+func checkPromptTooShort(_ Env, target Target) []Issue {
+	words := len(strings.Fields(target.Config.Prompt))
+	if words == 0 || words >= minPromptWords {
+		return nil
+	}
+	return []Issue{{
+		Field:   "prompt",
+		Line:    1,
+		Message: fmt.Sprintf("prompt has %d word(s); describe the change, where it belongs, and why", words),
+	}}
+}
+
+// vaguePromptPattern matches prompts that are only a generic verb phrase,
+// such as "fix the bug" or "improve the code".
+var vaguePromptPattern = regexp.MustCompile(
+	`(?i)^\s*(please\s+)?(fix|improve|update|refactor|clean\s*up|optimi[sz]e|make)\s+(it|this|that|the\s+)?\s*(bugs?|code|issues?|errors?|things?|stuff|everything|app|project|it\s+better|better|work|faster)?\s*[.!]*\s*$`,
+)
+
+func checkPromptVague(_ Env, target Target) []Issue {
+	prompt := strings.TrimSpace(target.Config.Prompt)
+	if prompt == "" || !vaguePromptPattern.MatchString(prompt) {
+		return nil
+	}
+	return []Issue{{
+		Field:   "prompt",
+		Line:    1,
+		Message: fmt.Sprintf("prompt %q is too generic; name the files, behavior, or error to address", prompt),
+	}}
+}
+
+// acceptanceMarkers are phrases that indicate how a change will be verified.
+var acceptanceMarkers = []string{
+	"acceptance criteria",
+	"done when",
+	"definition of done",
+	"expected behavior",
+	"expected result",
+	"should ",
+	"must ",
+	"verify",
+	"tests pass",
+	"test passes",
+	"- [ ]",
+	"* [ ]",
+}
+
+func checkAcceptanceCriteria(_ Env, target Target) []Issue {
+	text := strings.ToLower(target.Config.Prompt + "\n" + target.Context())
+	for _, marker := range acceptanceMarkers {
+		if strings.Contains(text, marker) {
+			return nil
+		}
+	}
+	return []Issue{{
+		Field:   "prompt",
+		Message: "no acceptance criteria found; add what \"done\" looks like (for example an \"Acceptance criteria\" list or tests that must pass)",
+	}}
+}
+
+// filePathPattern matches repository-relative paths with an extension, such
+// as internal/api/client.go or ./README.md.
+var filePathPattern = regexp.MustCompile(`(?:^|[\s(\[` + "`" + `"'])((?:\./)?[A-Za-z0-9_.\-]+(?:/[A-Za-z0-9_.\-]+)*\.[A-Za-z][A-Za-z0-9]{0,9})\b`)
+
+func checkMissingFiles(env Env, target Target) []Issue {
+	if env.RepoRoot == "" || env.FileExists == nil {
+		return nil
+	}
+	if target.Config.Repository != "" && !strings.EqualFold(target.Config.Repository, env.Repository) {
+		return nil
+	}
+
+	var issues []Issue
+	seen := make(map[string]bool)
+	report := func(field string, line int, ref string) {
+		if len(issues) >= maxReportedMissingFiles || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		if env.FileExists(ref) {
+			return
+		}
+		issues = append(issues, Issue{
+			Field:   field,
+			Line:    line,
+			Message: fmt.Sprintf("referenced path %q does not exist in %s", ref, env.RepoRoot),
+		})
+	}
+
+	for i, file := range target.Config.Files {
+		if ref := cleanPathRef(file); ref != "" {
+			report(fmt.Sprintf("files[%d]", i), 0, ref)
+		}
+	}
+	for _, field := range []struct{ name, text string }{
+		{"prompt", target.Config.Prompt},
+		{"context", target.Context()},
+	} {
+		for lineIndex, line := range strings.Split(field.text, "\n") {
+			for _, match := range filePathPattern.FindAllStringSubmatch(line, -1) {
+				ref := cleanPathRef(match[1])
+				// Bare file names such as "README.md" are often mentioned
+				// without their directory; only check paths with a slash.
+				if ref != "" && strings.Contains(ref, "/") {
+					report(field.name, lineIndex+1, ref)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+func cleanPathRef(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.Contains(ref, "://") || strings.HasPrefix(ref, "/") || strings.Contains(ref, "*") {
+		return ""
+	}
+	ref = path.Clean(strings.TrimPrefix(ref, "./"))
+	if strings.HasPrefix(ref, "..") {
+		return ""
+	}
+	return ref
+}
+
+func checkConflictingBranches(_ Env, target Target) []Issue {
+	c := target.Config
+	var issues []Issue
+	add := func(field, message string) {
+		issues = append(issues, Issue{Field: field, Message: message})
+	}
+
+	outputMode := c.OutputMode
+	if outputMode == "pr" {
+		outputMode = "pull_request"
+	}
+	if c.Source != "" && c.BaseBranch != "" && c.Source != c.BaseBranch {
+		add("baseBranch", fmt.Sprintf("source %q and baseBranch %q disagree; source is a legacy alias for baseBranch", c.Source, c.BaseBranch))
+	}
+	if (c.BranchOnly || outputMode == "branch") && c.PRTargetBranch != "" {
+		add("prTargetBranch", "prTargetBranch is set but the run pushes to a branch without opening a pull request")
+	}
+	if c.BranchOnly && outputMode == "pull_request" {
+		add("branchOnly", "branchOnly is true but outputMode is pull_request")
+	}
+	if outputMode != "branch" && !c.BranchOnly && c.Target != "" && c.PRTargetBranch != "" && c.Target != c.PRTargetBranch {
+		add("prTargetBranch", fmt.Sprintf("target %q and prTargetBranch %q disagree", c.Target, c.PRTargetBranch))
+	}
+	base := c.BaseBranch
+	if base == "" {
+		base = c.Source
+	}
+	if c.OutputBranch != "" && base != "" && c.OutputBranch == base && c.OutputBranchPolicy == "create" {
+		add("outputBranch", fmt.Sprintf("outputBranch %q equals the base branch but outputBranchPolicy is create", c.OutputBranch))
+	}
+	if outputMode != "branch" && !c.BranchOnly && c.PRTargetBranch != "" && c.OutputBranch != "" && c.PRTargetBranch == c.OutputBranch {
+		add("prTargetBranch", fmt.Sprintf("the pull request would target its own output branch %q", c.OutputBranch))
+	}
+	return issues
+}
+
+func checkOversizedContext(_ Env, target Target) []Issue {
+	size := len(target.Context())
+	if size <= maxContextBytes {
+		return nil
+	}
+	return []Issue{{
+		Field:   "context",
+		Message: fmt.Sprintf("context is %d KiB; trim it below %d KiB or reference files instead of pasting them", size/1024, maxContextBytes/1024),
+	}}
+}
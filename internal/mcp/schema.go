@@ -0,0 +1,104 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mcp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used for tool inputs.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// Object returns an object schema with the given properties that rejects
+// other properties.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	closed := false
+	return &Schema{Type: "object", Properties: properties, Required: required, AdditionalProperties: &closed}
+}
+
+// String returns a string schema.
+func String(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+// Integer returns an integer schema with a minimum.
+func Integer(description string, minimum float64) *Schema {
+	return &Schema{Type: "integer", Description: description, Minimum: &minimum}
+}
+
+// Boolean returns a boolean schema.
+func Boolean(description string) *Schema {
+	return &Schema{Type: "boolean", Description: description}
+}
+
+// SchemaFor derives an object schema from the JSON encoding of struct v.
+// Property names come from the json tags; descriptions are looked up by
+// property path, with nested properties joined by dots.
+func SchemaFor(v any, descriptions map[string]string) *Schema {
+	return schemaForType(reflect.TypeOf(v), "", descriptions)
+}
+
+func schemaForType(t reflect.Type, path string, descriptions map[string]string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema := &Schema{Description: descriptions[path]}
+	switch t.Kind() {
+	case reflect.Struct:
+		closed := false
+		schema.Type = "object"
+		schema.AdditionalProperties = &closed
+		schema.Properties = make(map[string]*Schema)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, omitted := jsonName(field)
+			if omitted {
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			schema.Properties[name] = schemaForType(field.Type, fieldPath, descriptions)
+		}
+	case reflect.Slice, reflect.Array:
+		schema.Type = "array"
+		schema.Items = schemaForType(t.Elem(), path+"[]", descriptions)
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	}
+	return schema
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
@@ -0,0 +1,343 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package mcp is a Model Context Protocol server over stdio. It speaks
+// newline-delimited JSON-RPC 2.0 and implements the lifecycle, ping and tool
+// methods; tools are registered by the caller.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ProtocolVersion is the newest protocol revision the server speaks.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the revisions a client may ask for.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// maxMessage bounds one incoming message.
+const maxMessage = 16 << 20
+
+// Handler runs a tool with its JSON arguments. An error is reported to the
+// client as a tool result with isError set, so the model can see it.
+type Handler func(ctx context.Context, args json.RawMessage) (*Result, error)
+
+// Tool is a tool the server exposes.
+type Tool struct {
+	Name        string  `json:"name"`
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description"`
+	InputSchema *Schema `json:"inputSchema"`
+	Handler     Handler `json:"-"`
+}
+
+// Content is one block of a tool result.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Result is the result of a tool call.
+type Result struct {
+	Content           []Content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+// TextResult returns a result with one text block.
+func TextResult(text string) *Result {
+	return &Result{Content: []Content{{Type: "text", Text: text}}}
+}
+
+// JSONResult returns v as structured content, and as indented JSON text
+// for clients that only read text.
+func JSONResult(v any) (*Result, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Content: []Content{{Type: "text", Text: string(data)}}, StructuredContent: v}, nil
+}
+
+// ErrorResult returns a result reporting err.
+func ErrorResult(err error) *Result {
+	result := TextResult(err.Error())
+	result.IsError = true
+	return result
+}
+
+// Error is a JSON-RPC error. Handlers return it for invalid arguments.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// InvalidParams returns an invalid-params error.
+func InvalidParams(format string, args ...any) *Error {
+	return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Server serves tools to one client.
+type Server struct {
+	name         string
+	version      string
+	instructions string
+	tools        map[string]Tool
+
+	writeMu  sync.Mutex
+	encoder  *json.Encoder
+	cancelMu sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+// NewServer returns a server that introduces itself as name and version,
+// with instructions for the model on how to use its tools.
+func NewServer(name, version, instructions string) *Server {
+	return &Server{
+		name:         name,
+		version:      version,
+		instructions: instructions,
+		tools:        make(map[string]Tool),
+		inflight:     make(map[string]context.CancelFunc),
+	}
+}
+
+// AddTool registers a tool.
+func (s *Server) AddTool(tool Tool) {
+	s.tools[tool.Name] = tool
+}
+
+// Tools returns the registered tools by name.
+func (s *Server) Tools() []Tool {
+	tools := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// Serve reads requests from in and writes responses to out until in ends or
+// ctx is done. Tool calls run concurrently, so a long wait does not hold up
+// other requests; Serve returns after the calls in flight have answered.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.encoder = json.NewEncoder(out)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.cancelAll()
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				return <-readErr
+			}
+			if len(line) == 0 {
+				continue
+			}
+			var req request
+			if err := json.Unmarshal(line, &req); err != nil {
+				s.write(response{ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error: " + err.Error()}})
+				continue
+			}
+			if req.Method == "tools/call" && len(req.ID) > 0 {
+				callCtx, cancel := context.WithCancel(ctx)
+				s.track(req.ID, cancel)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer s.untrack(req.ID)
+					result, err := s.callTool(callCtx, req.Params)
+					s.reply(req, result, err)
+				}()
+				continue
+			}
+			s.handle(req)
+		}
+	}
+}
+
+func (s *Server) handle(req request) {
+	switch req.Method {
+	case "initialize":
+		result, err := s.initialize(req.Params)
+		s.reply(req, result, err)
+	case "ping":
+		s.reply(req, struct{}{}, nil)
+	case "tools/list":
+		s.reply(req, map[string]any{"tools": s.Tools()}, nil)
+	case "notifications/cancelled":
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if json.Unmarshal(req.Params, &params) == nil {
+			s.cancel(params.RequestID)
+		}
+	default:
+		if len(req.ID) == 0 {
+			// Other notifications, such as notifications/initialized,
+			// need no answer.
+			return
+		}
+		s.reply(req, nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method})
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &init); err != nil {
+			return nil, InvalidParams("invalid initialize params: %v", err)
+		}
+	}
+	version := ProtocolVersion
+	for _, supported := range supportedVersions {
+		if init.ProtocolVersion == supported {
+			version = supported
+		}
+	}
+	result := map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+		"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+	}
+	if s.instructions != "" {
+		result["instructions"] = s.instructions
+	}
+	return result, nil
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, InvalidParams("invalid tools/call params: %v", err)
+	}
+	tool, ok := s.tools[call.Name]
+	if !ok {
+		return nil, InvalidParams("unknown tool: %s", call.Name)
+	}
+	if len(call.Arguments) == 0 || string(call.Arguments) == "null" {
+		call.Arguments = json.RawMessage("{}")
+	}
+	result, err := tool.Handler(ctx, call.Arguments)
+	if err != nil {
+		if rpcErr, ok := err.(*Error); ok {
+			return nil, rpcErr
+		}
+		return ErrorResult(err), nil
+	}
+	if result == nil {
+		result = TextResult("")
+	}
+	return result, nil
+}
+
+func (s *Server) reply(req request, result any, err error) {
+	if len(req.ID) == 0 {
+		return
+	}
+	resp := response{ID: req.ID, Result: result}
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		resp.Result = nil
+		resp.Error = rpcErr
+	}
+	s.write(resp)
+}
+
+func (s *Server) write(resp response) {
+	resp.JSONRPC = "2.0"
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.encoder.Encode(resp)
+}
+
+func (s *Server) track(id json.RawMessage, cancel context.CancelFunc) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	s.inflight[string(id)] = cancel
+}
+
+func (s *Server) untrack(id json.RawMessage) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	if cancel, ok := s.inflight[string(id)]; ok {
+		cancel()
+		delete(s.inflight, string(id))
+	}
+}
+
+func (s *Server) cancel(id json.RawMessage) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	if cancel, ok := s.inflight[string(id)]; ok {
+		cancel()
+	}
+}
+
+func (s *Server) cancelAll() {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	for _, cancel := range s.inflight {
+		cancel()
+	}
+}
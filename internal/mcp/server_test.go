@@ -0,0 +1,187 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedClient drives a server over a pipe, one JSON-RPC message a line.
+type scriptedClient struct {
+	t       *testing.T
+	in      *io.PipeWriter
+	out     *bufio.Scanner
+	done    chan error
+	nextID  int
+	pending []map[string]any
+}
+
+func startScripted(t *testing.T, server *Server) *scriptedClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	client := &scriptedClient{t: t, in: inW, out: bufio.NewScanner(outR), done: make(chan error, 1)}
+	go func() {
+		err := server.Serve(context.Background(), inR, outW)
+		_ = outW.Close()
+		client.done <- err
+	}()
+	t.Cleanup(func() { _ = inW.Close() })
+	return client
+}
+
+func (c *scriptedClient) send(message map[string]any) {
+	c.t.Helper()
+	message["jsonrpc"] = "2.0"
+	data, err := json.Marshal(message)
+	require.NoError(c.t, err)
+	_, err = c.in.Write(append(data, '\n'))
+	require.NoError(c.t, err)
+}
+
+// call sends a request and returns its response, keeping responses to other
+// requests that arrive first.
+func (c *scriptedClient) call(method string, params any) map[string]any {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	c.send(map[string]any{"id": id, "method": method, "params": params})
+	return c.await(id)
+}
+
+func (c *scriptedClient) await(id int) map[string]any {
+	c.t.Helper()
+	for i, resp := range c.pending {
+		if resp["id"] == float64(id) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return resp
+		}
+	}
+	for c.out.Scan() {
+		var resp map[string]any
+		require.NoError(c.t, json.Unmarshal(c.out.Bytes(), &resp))
+		assert.Equal(c.t, "2.0", resp["jsonrpc"])
+		if resp["id"] == float64(id) {
+			return resp
+		}
+		c.pending = append(c.pending, resp)
+	}
+	c.t.Fatalf("no response to request %d", id)
+	return nil
+}
+
+func testServer() *Server {
+	server := NewServer("test", "1.0.0", "be nice")
+	server.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the text",
+		InputSchema: Object(map[string]*Schema{"text": String("text to echo")}, "text"),
+		Handler: func(_ context.Context, args json.RawMessage) (*Result, error) {
+			var in struct{ Text string }
+			if err := json.Unmarshal(args, &in); err != nil {
+				return nil, InvalidParams("%v", err)
+			}
+			if in.Text == "" {
+				return nil, errors.New("nothing to echo")
+			}
+			return JSONResult(map[string]string{"text": in.Text})
+		},
+	})
+	server.AddTool(Tool{
+		Name:        "block",
+		Description: "Block until cancelled",
+		InputSchema: Object(nil),
+		Handler: func(ctx context.Context, _ json.RawMessage) (*Result, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return TextResult("not cancelled"), nil
+			}
+		},
+	})
+	return server
+}
+
+func TestServeLifecycleAndTools(t *testing.T) {
+	client := startScripted(t, testServer())
+
+	init := client.call("initialize", map[string]any{"protocolVersion": "2025-03-26", "capabilities": map[string]any{}, "clientInfo": map[string]any{"name": "script"}})
+	result := init["result"].(map[string]any)
+	assert.Equal(t, "2025-03-26", result["protocolVersion"])
+	assert.Equal(t, "be nice", result["instructions"])
+	client.send(map[string]any{"method": "notifications/initialized"})
+
+	tools := client.call("tools/list", nil)["result"].(map[string]any)["tools"].([]any)
+	require.Len(t, tools, 2)
+	echo := tools[1].(map[string]any)
+	assert.Equal(t, "echo", echo["name"])
+	assert.Equal(t, []any{"text"}, echo["inputSchema"].(map[string]any)["required"])
+
+	resp := client.call("tools/call", map[string]any{"name": "echo", "arguments": map[string]any{"text": "hi"}})
+	result = resp["result"].(map[string]any)
+	assert.Equal(t, map[string]any{"text": "hi"}, result["structuredContent"])
+	assert.Nil(t, result["isError"])
+
+	resp = client.call("tools/call", map[string]any{"name": "echo", "arguments": map[string]any{}})
+	result = resp["result"].(map[string]any)
+	assert.Equal(t, true, result["isError"])
+	assert.Equal(t, "nothing to echo", result["content"].([]any)[0].(map[string]any)["text"])
+
+	resp = client.call("tools/call", map[string]any{"name": "missing"})
+	assert.Equal(t, float64(CodeInvalidParams), resp["error"].(map[string]any)["code"])
+	resp = client.call("resources/list", nil)
+	assert.Equal(t, float64(CodeMethodNotFound), resp["error"].(map[string]any)["code"])
+	assert.NotNil(t, client.call("ping", nil)["result"])
+
+	require.NoError(t, client.in.Close())
+	require.NoError(t, <-client.done)
+}
+
+func TestServeCancelsToolCall(t *testing.T) {
+	client := startScripted(t, testServer())
+	client.send(map[string]any{"id": 1, "method": "tools/call", "params": map[string]any{"name": "block"}})
+	// The server keeps answering while the call blocks.
+	assert.NotNil(t, client.call("ping", nil)["result"])
+	client.send(map[string]any{"method": "notifications/cancelled", "params": map[string]any{"requestId": 1}})
+
+	resp := client.await(1)
+	result := resp["result"].(map[string]any)
+	assert.Equal(t, true, result["isError"])
+	assert.Contains(t, fmt.Sprint(result["content"]), "context canceled")
+}
+
+func TestSchemaForUsesJSONTags(t *testing.T) {
+	type nested struct {
+		Token string `json:"token"`
+	}
+	type config struct {
+		Prompt  string   `json:"prompt" yaml:"prompt"`
+		Files   []string `json:"files,omitempty"`
+		Draft   bool     `json:"draft,omitempty"`
+		Count   int      `json:"count"`
+		Secret  *nested  `json:"secret,omitempty"`
+		Ignored string   `json:"-"`
+	}
+	schema := SchemaFor(config{}, map[string]string{"prompt": "the task", "secret.token": "a token"})
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "the task", schema.Properties["prompt"].Description)
+	assert.Equal(t, "array", schema.Properties["files"].Type)
+	assert.Equal(t, "string", schema.Properties["files"].Items.Type)
+	assert.Equal(t, "boolean", schema.Properties["draft"].Type)
+	assert.Equal(t, "integer", schema.Properties["count"].Type)
+	assert.Equal(t, "a token", schema.Properties["secret"].Properties["token"].Description)
+	assert.NotContains(t, schema.Properties, "Ignored")
+	assert.NotContains(t, schema.Properties, "-")
+}
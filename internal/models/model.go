@@ -0,0 +1,16 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package models
+
+// ModelInfo is an OpenCode model that runs may select.
+type ModelInfo struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// ModelListResponse represents the API response for the allowed model list
+type ModelListResponse struct {
+	Data []ModelInfo `json:"data"`
+}
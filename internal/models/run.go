@@ -46,6 +46,8 @@ type RunRequest struct {
 	PRTargetBranch        string                   `json:"prTargetBranch,omitempty"`
 	OutputBranchPolicy    string                   `json:"outputBranchPolicy,omitempty"`
 	RunType               RunType                  `json:"runType"`
+	Model                 string                   `json:"model,omitempty"`
+	Provider              string                   `json:"provider,omitempty"`
 	Title                 string                   `json:"title,omitempty"`
 	Context               string                   `json:"context,omitempty"`
 	Files                 []string                 `json:"files,omitempty"`
@@ -74,6 +76,8 @@ type RunConfig struct {
 	PRTargetBranch        string                   `json:"prTargetBranch,omitempty" yaml:"prTargetBranch,omitempty"`
 	OutputBranchPolicy    string                   `json:"outputBranchPolicy,omitempty" yaml:"outputBranchPolicy,omitempty"`
 	RunType               string                   `json:"runType" yaml:"runType"`
+	Model                 string                   `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider              string                   `json:"provider,omitempty" yaml:"provider,omitempty"`
 	Title                 string                   `json:"title,omitempty" yaml:"title,omitempty"`
 	Context               string                   `json:"context,omitempty" yaml:"context,omitempty"`
 	Files                 []string                 `json:"files,omitempty" yaml:"files,omitempty"`
@@ -139,6 +143,8 @@ func (r *RunRequest) ToAPIRequest() *APIRunRequest {
 		OutputBranchPolicy:    config.OutputBranchPolicy,
 		RunType:               r.RunType,
 		Agent:                 "opencode",
+		OpenCodeModel:         r.Model,
+		OpenCodeProvider:      r.Provider,
 		Title:                 r.Title,
 		Context:               r.Context,
 		Files:                 r.Files,
@@ -397,6 +403,8 @@ func parseJSONWithUnknownFieldsAndPrompts(file *os.File) (*RunConfig, *prompts.V
 		PRTargetBranch:        runReq.PRTargetBranch,
 		OutputBranchPolicy:    runReq.OutputBranchPolicy,
 		RunType:               string(runReq.RunType),
+		Model:                 runReq.Model,
+		Provider:              runReq.Provider,
 		Title:                 runReq.Title,
 		Context:               runReq.Context,
 		Files:                 runReq.Files,
@@ -430,6 +438,8 @@ func findUnsupportedJSONFieldsWithSuggestions(data map[string]interface{}) ([]st
 		"prTargetBranch":        true,
 		"outputBranchPolicy":    true,
 		"runType":               true,
+		"model":                 true,
+		"provider":              true,
 		"title":                 true,
 		"context":               true,
 		"files":                 true,
@@ -444,7 +454,7 @@ func findUnsupportedJSONFieldsWithSuggestions(data map[string]interface{}) ([]st
 	supportedFieldsList := []string{
 		"prompt", "repository", "source", "target", "baseBranch",
 		"outputMode", "outputBranch", "prTargetBranch", "outputBranchPolicy",
-		"runType", "title", "context", "files", "providerCredentialId", "providerMode",
+		"runType", "model", "provider", "title", "context", "files", "providerCredentialId", "providerMode",
 		"gitlabCredential", "branchOnly", "acknowledgePromptRisk", "idempotencyKey",
 	}
 
@@ -48,3 +48,11 @@ func TestRunStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestRunRequestToAPIRequestSetsModel(t *testing.T) {
+	req := &RunRequest{Prompt: "Fix auth", Repository: "acme/webapp", Model: "openrouter/z-ai/glm-5.2", Provider: "openrouter"}
+	apiReq := req.ToAPIRequest()
+	if apiReq.OpenCodeModel != "openrouter/z-ai/glm-5.2" || apiReq.OpenCodeProvider != "openrouter" {
+		t.Errorf("expected model and provider to be set, got %q and %q", apiReq.OpenCodeModel, apiReq.OpenCodeProvider)
+	}
+}
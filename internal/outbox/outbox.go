@@ -0,0 +1,251 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package outbox keeps run requests that could not reach the API, so they
+// can be submitted once it is reachable again. Every request carries an
+// idempotency key, which is sent with each attempt, so a request that did
+// reach the API before the connection dropped is not created twice.
+package outbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// flushLockStale is the age after which the lock of a flush that died is
+// replaced.
+const flushLockStale = 5 * time.Minute
+
+// ErrHeld is returned by a Submitter that is not ready to submit an entry,
+// such as while the active run cap is reached. The entry stays queued without
+// counting an attempt, and the flush goes on with the next entry.
+var ErrHeld = stderrors.New("run held in the outbox")
+
+// Entry is a run request waiting in the outbox.
+type Entry struct {
+	ID            string               `json:"id"`
+	Request       models.APIRunRequest `json:"request"`
+	QueuedAt      time.Time            `json:"queued_at"`
+	Attempts      int                  `json:"attempts,omitempty"`
+	LastAttemptAt *time.Time           `json:"last_attempt_at,omitempty"`
+	LastError     string               `json:"last_error,omitempty"`
+	// Rejected is set when the API refused the request. Rejected entries are
+	// only retried by an explicit flush.
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// Outbox is a directory holding one JSON file per entry.
+type Outbox struct {
+	dir string
+	now func() time.Time
+}
+
+// New returns the outbox in dir.
+func New(dir string) *Outbox {
+	return &Outbox{dir: dir, now: time.Now}
+}
+
+// DefaultDir returns the outbox directory in the user cache dir.
+func DefaultDir() string {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		baseDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(baseDir, "repobird", "outbox")
+}
+
+// EntryID derives the entry ID from an idempotency key, so queuing the same
+// request twice keeps one entry.
+func EntryID(idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(idempotencyKey))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Add queues a request. The request must have an idempotency key. When an
+// entry with the same key is queued already, it is returned with existing
+// set.
+func (o *Outbox) Add(req models.APIRunRequest) (entry *Entry, existing bool, err error) {
+	key := strings.TrimSpace(req.IdempotencyKey)
+	if key == "" {
+		return nil, false, fmt.Errorf("a queued run needs an idempotency key")
+	}
+	id := EntryID(key)
+	if entry, err := o.Get(id); err == nil {
+		return entry, true, nil
+	}
+	entry = &Entry{ID: id, Request: req, QueuedAt: o.now().UTC()}
+	if err := o.Save(entry); err != nil {
+		return nil, false, err
+	}
+	return entry, false, nil
+}
+
+// Get returns the entry with id.
+func (o *Outbox) Get(id string) (*Entry, error) {
+	data, err := os.ReadFile(o.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("queued run %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to read queued run %s: %w", id, err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse queued run %s: %w", id, err)
+	}
+	return &entry, nil
+}
+
+// List returns the entries, oldest first. A missing outbox is empty.
+func (o *Outbox) List() ([]*Entry, error) {
+	files, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		entry, err := o.Get(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].QueuedAt.Before(entries[j].QueuedAt) })
+	return entries, nil
+}
+
+// Len returns the number of entries, rejected ones included.
+func (o *Outbox) Len() (int, error) {
+	files, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	return len(files), err
+}
+
+// Pending returns the number of entries a background flush would submit.
+func (o *Outbox) Pending() (int, error) {
+	entries, err := o.List()
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, entry := range entries {
+		if !entry.Rejected {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Save writes the entry.
+func (o *Outbox) Save(entry *Entry) error {
+	if err := os.MkdirAll(o.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queued run: %w", err)
+	}
+	tmp := o.path(entry.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write queued run: %w", err)
+	}
+	if err := os.Rename(tmp, o.path(entry.ID)); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write queued run: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the entry with id.
+func (o *Outbox) Remove(id string) error {
+	if err := os.Remove(o.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("queued run %s not found", id)
+		}
+		return fmt.Errorf("failed to remove queued run %s: %w", id, err)
+	}
+	return nil
+}
+
+// Submitter creates a run from a request.
+type Submitter func(req *models.APIRunRequest) (*models.RunResponse, error)
+
+// Result is the outcome of submitting one entry.
+type Result struct {
+	Entry *Entry
+	Run   *models.RunResponse
+	Err   error
+}
+
+// Flush submits the entries in queue order. A created run removes its entry,
+// and an entry the submitter holds with ErrHeld is skipped. An error worth
+// retrying later, such as a network, authentication or server error, stops
+// the flush and leaves the rest queued; any other error marks the entry
+// rejected. Rejected entries are skipped unless includeRejected is set. A
+// lock file keeps two flushes from submitting the same entry.
+func (o *Outbox) Flush(submit Submitter, includeRejected bool) ([]Result, error) {
+	unlock, err := cache.LockFile(filepath.Join(o.dir, "flush.lock"), flushLockStale)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := o.List()
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, entry := range entries {
+		if entry.Rejected && !includeRejected {
+			continue
+		}
+		req := entry.Request
+		run, err := submit(&req)
+		if stderrors.Is(err, ErrHeld) {
+			entry.LastError = strings.TrimPrefix(err.Error(), ErrHeld.Error()+": ")
+			if saveErr := o.Save(entry); saveErr != nil {
+				return results, saveErr
+			}
+			results = append(results, Result{Entry: entry, Err: err})
+			continue
+		}
+		if err == nil {
+			if removeErr := o.Remove(entry.ID); removeErr != nil {
+				err = removeErr
+			}
+			results = append(results, Result{Entry: entry, Run: run, Err: err})
+			continue
+		}
+
+		now := o.now().UTC()
+		entry.Attempts++
+		entry.LastAttemptAt = &now
+		entry.LastError = errors.FormatUserError(err)
+		retryLater := errors.IsRetryable(err) || errors.IsAuthError(err)
+		entry.Rejected = !retryLater
+		if saveErr := o.Save(entry); saveErr != nil {
+			return results, saveErr
+		}
+		results = append(results, Result{Entry: entry, Err: err})
+		if retryLater {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (o *Outbox) path(id string) string {
+	return filepath.Join(o.dir, filepath.Base(id)+".json")
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package outbox
+
+import (
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/cache"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func queuedRequest(key, prompt string) models.APIRunRequest {
+	return models.APIRunRequest{Prompt: prompt, RepositoryName: "acme/api", RunType: models.RunTypeRun, IdempotencyKey: key}
+}
+
+func TestOutboxAdd(t *testing.T) {
+	box := New(t.TempDir())
+	clock := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	box.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+
+	_, _, err := box.Add(queuedRequest("", "Fix the login redirect"))
+	require.Error(t, err)
+
+	first, existing, err := box.Add(queuedRequest("key-1", "Fix the login redirect"))
+	require.NoError(t, err)
+	assert.False(t, existing)
+	assert.Equal(t, EntryID("key-1"), first.ID)
+
+	again, existing, err := box.Add(queuedRequest("key-1", "Fix the login redirect"))
+	require.NoError(t, err)
+	assert.True(t, existing)
+	assert.Equal(t, first.ID, again.ID)
+
+	_, _, err = box.Add(queuedRequest("key-2", "Add rate limiting"))
+	require.NoError(t, err)
+
+	entries, err := box.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Fix the login redirect", entries[0].Request.Prompt)
+	assert.Equal(t, "key-2", entries[1].Request.IdempotencyKey)
+
+	require.NoError(t, box.Remove(first.ID))
+	assert.Error(t, box.Remove(first.ID))
+	pending, err := box.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+}
+
+func TestOutboxFlush(t *testing.T) {
+	box := New(t.TempDir())
+	clock := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	box.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+	for _, key := range []string{"ok", "invalid", "offline", "later"} {
+		_, _, err := box.Add(queuedRequest(key, "prompt "+key))
+		require.NoError(t, err)
+	}
+
+	var sent []string
+	submit := func(req *models.APIRunRequest) (*models.RunResponse, error) {
+		sent = append(sent, req.IdempotencyKey)
+		switch req.IdempotencyKey {
+		case "invalid":
+			return nil, &errors.APIError{StatusCode: 422, Message: "repository not found"}
+		case "offline":
+			return nil, &errors.NetworkError{Err: stderrors.New("no route to host")}
+		}
+		return &models.RunResponse{ID: "run-" + req.IdempotencyKey}, nil
+	}
+
+	results, err := box.Flush(submit, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ok", "invalid", "offline"}, sent, "a network error stops the flush")
+	require.Len(t, results, 3)
+	assert.Equal(t, "run-ok", results[0].Run.GetIDString())
+	assert.True(t, results[1].Entry.Rejected)
+	assert.False(t, results[2].Entry.Rejected)
+	assert.Equal(t, 1, results[2].Entry.Attempts)
+
+	entries, err := box.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "repository not found", entries[0].LastError)
+
+	// Back online: a background flush skips the rejected entry.
+	sent = nil
+	submit2 := func(req *models.APIRunRequest) (*models.RunResponse, error) {
+		sent = append(sent, req.IdempotencyKey)
+		return &models.RunResponse{ID: "run-" + req.IdempotencyKey}, nil
+	}
+	_, err = box.Flush(submit2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"offline", "later"}, sent)
+
+	sent = nil
+	_, err = box.Flush(submit2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invalid"}, sent)
+	entries, err = box.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestOutboxFlushLocked(t *testing.T) {
+	dir := t.TempDir()
+	unlock, err := cache.LockFile(filepath.Join(dir, "flush.lock"), time.Hour)
+	require.NoError(t, err)
+	defer unlock()
+
+	_, err = New(dir).Flush(func(*models.APIRunRequest) (*models.RunResponse, error) {
+		t.Fatal("a locked outbox must not submit")
+		return nil, nil
+	}, true)
+	assert.ErrorIs(t, err, cache.ErrLocked)
+}
+
+func TestOutboxFlushSkipsHeldEntries(t *testing.T) {
+	box := New(t.TempDir())
+	clock := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	box.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+	for _, key := range []string{"held", "ok"} {
+		_, _, err := box.Add(queuedRequest(key, "prompt "+key))
+		require.NoError(t, err)
+	}
+
+	results, err := box.Flush(func(req *models.APIRunRequest) (*models.RunResponse, error) {
+		if req.IdempotencyKey == "held" {
+			return nil, fmt.Errorf("%w: max_active_runs is reached", ErrHeld)
+		}
+		return &models.RunResponse{ID: "run-ok"}, nil
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0].Err, ErrHeld)
+	assert.NotNil(t, results[1].Run)
+
+	entries, err := box.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "max_active_runs is reached", entries[0].LastError)
+	assert.Zero(t, entries[0].Attempts)
+	assert.False(t, entries[0].Rejected)
+	count, err := box.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package project discovers and loads the project-level .repobird.yaml file.
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the project file names checked in each directory, in order.
+var FileNames = []string{".repobird.yaml", ".repobird.yml"}
+
+// File is the content of a project .repobird.yaml.
+type File struct {
+	Defaults `yaml:",inline"`
+	// Templates maps template names to template files, relative to the
+	// project file.
+	Templates map[string]string `yaml:"templates,omitempty" json:"templates,omitempty"`
+	// ModelProfiles names model and provider pairs that runs can select by
+	// name. They replace user config profiles with the same name.
+	ModelProfiles map[string]ModelProfile `yaml:"modelProfiles,omitempty" json:"modelProfiles,omitempty"`
+	Lint          LintSettings            `yaml:"lint,omitempty" json:"lint,omitempty"`
+}
+
+// ModelProfile is an OpenCode model and the provider that serves it.
+type ModelProfile struct {
+	Model    string `yaml:"model" json:"model"`
+	Provider string `yaml:"provider,omitempty" json:"provider,omitempty"`
+}
+
+// Defaults are run settings applied when a run does not set them.
+type Defaults struct {
+	Repository     string `yaml:"repository,omitempty" json:"repository,omitempty"`
+	BaseBranch     string `yaml:"baseBranch,omitempty" json:"baseBranch,omitempty"`
+	PRTargetBranch string `yaml:"prTargetBranch,omitempty" json:"prTargetBranch,omitempty"`
+	OutputMode     string `yaml:"outputMode,omitempty" json:"outputMode,omitempty"`
+	Model          string `yaml:"model,omitempty" json:"model,omitempty"`
+	Provider       string `yaml:"provider,omitempty" json:"provider,omitempty"`
+	// ContextFiles are read and used as the run context when the run has
+	// none of its own. Relative paths are resolved against the project file.
+	ContextFiles []string `yaml:"contextFiles,omitempty" json:"contextFiles,omitempty"`
+}
+
+// LintSettings configures `repobird lint` and the pre-submit lint hook.
+type LintSettings struct {
+	// Rules maps a rule ID to a severity: error, warning, info, or off.
+	Rules map[string]string `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// Find walks up from startDir looking for a project file. The search stops
+// at the git root (the first directory containing .git) or the filesystem
+// root. It returns an empty path when no project file exists.
+func Find(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", startDir, err)
+	}
+
+	for {
+		for _, name := range FileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+		if isGitRoot(dir) {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// GitRoot returns the nearest ancestor of startDir that contains .git, or an
+// empty string when startDir is not inside a git checkout.
+func GitRoot(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if isGitRoot(dir) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads the project file found from startDir. It returns an empty File
+// and an empty path when no project file exists.
+func Load(startDir string) (*File, string, error) {
+	path, err := Find(startDir)
+	if err != nil {
+		return nil, "", err
+	}
+	if path == "" {
+		return &File{}, "", nil
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		return nil, path, err
+	}
+	return file, path, nil
+}
+
+// LoadFile parses a project file at path.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file %s: %w", path, err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse project file %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i, contextFile := range file.ContextFiles {
+		file.ContextFiles[i] = resolvePath(dir, contextFile)
+	}
+	for name, templateFile := range file.Templates {
+		file.Templates[name] = resolvePath(dir, templateFile)
+	}
+	return &file, nil
+}
+
+// resolvePath makes a relative path absolute against dir.
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+func isGitRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWalksUpToGitRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	nested := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".repobird.yaml"), []byte("lint:\n  rules:\n    prompt-too-short: error\n"), 0644))
+
+	file, path, err := Load(nested)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".repobird.yaml"), path)
+	assert.Equal(t, "error", file.Lint.Rules["prompt-too-short"])
+	assert.Equal(t, root, GitRoot(nested))
+}
+
+func TestLoadStopsAtGitRoot(t *testing.T) {
+	parent := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(parent, ".repobird.yaml"), []byte("lint: {}\n"), 0644))
+	checkout := filepath.Join(parent, "checkout")
+	require.NoError(t, os.MkdirAll(filepath.Join(checkout, ".git"), 0755))
+
+	file, path, err := Load(checkout)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.Empty(t, file.Lint.Rules)
+}
+
+func TestLoadFileRejectsInvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".repobird.yml")
+	require.NoError(t, os.WriteFile(path, []byte("lint: [\n"), 0644))
+
+	_, err := LoadFile(path)
+	require.ErrorContains(t, err, "failed to parse project file")
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	defaults, settings := Resolve(
+		Layer{Source: SourceProject, Path: "/repo/.repobird.yaml", Defaults: Defaults{BaseBranch: "develop"}},
+		Layer{Source: SourceUser, Defaults: Defaults{
+			Repository:   "acme/webapp",
+			BaseBranch:   "main",
+			ContextFiles: []string{"/home/me/agent.md"},
+		}},
+	)
+
+	assert.Equal(t, "acme/webapp", defaults.Repository)
+	assert.Equal(t, "develop", defaults.BaseBranch)
+	assert.Equal(t, []string{"/home/me/agent.md"}, defaults.ContextFiles)
+
+	byKey := make(map[string]Setting)
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	assert.Equal(t, Setting{Key: "baseBranch", Value: "develop", Source: SourceProject, Path: "/repo/.repobird.yaml"}, byKey["baseBranch"])
+	assert.Equal(t, SourceUser, byKey["repository"].Source)
+	assert.Equal(t, Setting{Key: "model"}, byKey["model"])
+}
+
+func TestResolveKeepsBranchesWithTheirRepository(t *testing.T) {
+	defaults, settings := Resolve(
+		Layer{Source: SourceProject, Defaults: Defaults{Repository: "acme/webapp", Model: "openrouter/x/y"}},
+		Layer{Source: SourceUser, Defaults: Defaults{
+			Repository:     "acme/api",
+			BaseBranch:     "develop",
+			PRTargetBranch: "release",
+			OutputMode:     "pull_request",
+			ContextFiles:   []string{"/home/me/api.md"},
+		}},
+	)
+
+	assert.Equal(t, "acme/webapp", defaults.Repository)
+	assert.Empty(t, defaults.BaseBranch)
+	assert.Empty(t, defaults.PRTargetBranch)
+	assert.Empty(t, defaults.ContextFiles)
+	assert.Equal(t, "pull_request", defaults.OutputMode, "settings that name no branch or file still apply")
+	for _, setting := range settings {
+		if setting.Key == "baseBranch" {
+			assert.Empty(t, setting.Source)
+		}
+	}
+}
+
+func TestLoadFileResolvesRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".repobird.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("contextFiles: [docs/agent.md]\ntemplates:\n  tests: .repobird/templates/tests.md\n"), 0644))
+
+	file, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "docs", "agent.md")}, file.ContextFiles)
+	assert.Equal(t, filepath.Join(dir, ".repobird", "templates", "tests.md"), file.Templates["tests"])
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package project
+
+import "strings"
+
+// Sources of a resolved setting, from highest to lowest precedence.
+const (
+	SourceFlag    = "flag"
+	SourceFile    = "file"
+	SourceProject = "project"
+	// SourceUserRepository is a user config default for one repository.
+	SourceUserRepository = "user config repository"
+	SourceUser           = "user config"
+)
+
+// Layer is one source of run defaults.
+type Layer struct {
+	Source string
+	// Path is the file the defaults were read from, if any.
+	Path     string
+	Defaults Defaults
+}
+
+// Setting is one resolved value and the layer it came from. Source is empty
+// when no layer sets the value.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// Resolve merges layers given in precedence order, highest first. Each
+// setting takes the first non-empty value; context files are taken as a
+// whole from the first layer that lists any. Branches and context files
+// name things in one repository, so they are only taken from layers of the
+// resolved repository or of none.
+func Resolve(layers ...Layer) (Defaults, []Setting) {
+	var merged Defaults
+	fields := []struct {
+		key string
+		get func(*Defaults) *string
+		// scoped marks settings of the layer's repository.
+		scoped bool
+	}{
+		{"repository", func(d *Defaults) *string { return &d.Repository }, false},
+		{"baseBranch", func(d *Defaults) *string { return &d.BaseBranch }, true},
+		{"prTargetBranch", func(d *Defaults) *string { return &d.PRTargetBranch }, true},
+		{"outputMode", func(d *Defaults) *string { return &d.OutputMode }, false},
+		{"model", func(d *Defaults) *string { return &d.Model }, false},
+		{"provider", func(d *Defaults) *string { return &d.Provider }, false},
+	}
+
+	settings := make([]Setting, 0, len(fields)+1)
+	for _, field := range fields {
+		setting := Setting{Key: field.key}
+		for i := range layers {
+			if field.scoped && !layers[i].fits(merged.Repository) {
+				continue
+			}
+			if value := strings.TrimSpace(*field.get(&layers[i].Defaults)); value != "" {
+				*field.get(&merged) = value
+				setting.Value, setting.Source, setting.Path = value, layers[i].Source, layers[i].Path
+				break
+			}
+		}
+		settings = append(settings, setting)
+	}
+
+	contextSetting := Setting{Key: "contextFiles"}
+	for _, layer := range layers {
+		if len(layer.Defaults.ContextFiles) > 0 && layer.fits(merged.Repository) {
+			merged.ContextFiles = append([]string(nil), layer.Defaults.ContextFiles...)
+			contextSetting.Value = strings.Join(merged.ContextFiles, ", ")
+			contextSetting.Source, contextSetting.Path = layer.Source, layer.Path
+			break
+		}
+	}
+	settings = append(settings, contextSetting)
+	return merged, settings
+}
+
+// fits reports whether the layer's repository settings apply to repository:
+// the layer names no repository or the same one.
+func (l Layer) fits(repository string) bool {
+	layerRepository := strings.TrimSpace(l.Defaults.Repository)
+	return layerRepository == "" || strings.EqualFold(layerRepository, repository)
+}
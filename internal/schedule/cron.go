@@ -0,0 +1,183 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week.
+type Cron struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domAny and dowAny record a "*" day field. As in cron, when both day
+	// fields are restricted a day matches if either does.
+	domAny bool
+	dowAny bool
+}
+
+// cronMacros are the supported shorthand expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names for min, min+1, ...
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is accepted as Sunday and folded to 0.
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ParseCron parses a cron expression. Fields accept "*", values, ranges
+// ("1-5"), steps ("*/15", "10-40/10"), lists ("1,15") and month and weekday
+// names. The macros @hourly, @daily, @weekly, @monthly and @yearly are also
+// accepted.
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	c := &Cron{expr: expr}
+	sets := []*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		*sets[i] = set
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domAny = parts[2] == "*"
+	c.dowAny = parts[4] == "*"
+	return c, nil
+}
+
+func parseCronField(text string, field cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(text, ",") {
+		rangeText, step := item, 1
+		if base, stepText, ok := strings.Cut(item, "/"); ok {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepText, field.name)
+			}
+			rangeText, step = base, n
+		}
+
+		var low, high int
+		switch {
+		case rangeText == "*":
+			low, high = field.min, field.max
+			if field.names != nil && field.max == 7 {
+				high = 6
+			}
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = cronValue(lowText, field); err != nil {
+				return 0, err
+			}
+			if high, err = cronValue(highText, field); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeText, field.name)
+			}
+		default:
+			value, err := cronValue(rangeText, field)
+			if err != nil {
+				return 0, err
+			}
+			low, high = value, value
+			if step > 1 {
+				high = field.max
+			}
+		}
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func cronValue(text string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if strings.EqualFold(text, name) {
+			return field.min + i, nil
+		}
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < field.min || value > field.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (expected %d-%d)", text, field.name, field.min, field.max)
+	}
+	return value, nil
+}
+
+// String returns the expression as written.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first time after t that matches the expression, in the
+// location of t, or the zero time if none does within five years.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	// 2026-10-14 is a Wednesday.
+	start := time.Date(2026, 10, 14, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 14, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 14, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * mon", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"30 8 1,15 * *", time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * jan-mar *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 1-5", time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// With both day fields restricted a day matches either one.
+		{"0 0 20 * fri", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cron.Next(start))
+		})
+	}
+}
+
+func TestCronNextKeepsLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	cron, err := ParseCron("0 9 * * *")
+	require.NoError(t, err)
+
+	next := cron.Next(time.Date(2026, 10, 14, 14, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2026, 10, 15, 9, 0, 0, 0, loc), next)
+	assert.Equal(t, 13, next.UTC().Hour())
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * foo *", "*/0 * * * *", "5-1 * * * *", "@sometimes"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
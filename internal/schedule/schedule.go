@@ -0,0 +1,298 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package schedule describes recurring runs. A schedule pairs a cron
+// expression with a run config file or a template, and is stored as a YAML
+// file in the config dir.
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/templates"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// Schedule is a recurring run.
+type Schedule struct {
+	Name string `json:"name" yaml:"name"`
+	Cron string `json:"cron" yaml:"cron"`
+	// Timezone is the IANA zone the cron expression is read in; empty uses
+	// the local zone.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Config is the path of a run config file. Template names a template
+	// rendered with Vars. Exactly one of them is set.
+	Config   string            `json:"config,omitempty" yaml:"config,omitempty"`
+	Template string            `json:"template,omitempty" yaml:"template,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty" yaml:"vars,omitempty"`
+	// Repository replaces the repository of the run config.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	// CatchUpWindow limits how late a missed slot still fires, as a Go
+	// duration. Empty fires the latest missed slot however late it is; "0"
+	// fires a slot only if it is at most a few minutes late.
+	CatchUpWindow string    `json:"catchUpWindow,omitempty" yaml:"catchUpWindow,omitempty"`
+	CreatedAt     time.Time `json:"createdAt" yaml:"createdAt"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// onTimeGrace is how late a slot may fire and still count as on time when
+// catch-up is disabled, so a daemon polling every minute does not miss it.
+const onTimeGrace = 5 * time.Minute
+
+// Dir returns the directory holding schedule definitions in the config dir.
+func Dir() string {
+	return filepath.Join(config.ConfigDir(), "schedules")
+}
+
+// Validate checks the name, the cron expression, the time zone, the run
+// source and the catch-up window.
+func (s *Schedule) Validate() error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("name is required")
+	case !namePattern.MatchString(s.Name):
+		return fmt.Errorf("name %q may only contain letters, digits, '.', '_' and '-'", s.Name)
+	case s.Config == "" && s.Template == "":
+		return fmt.Errorf("schedule %s needs a run config file or a template", s.Name)
+	case s.Config != "" && s.Template != "":
+		return fmt.Errorf("schedule %s cannot use both a run config file and a template", s.Name)
+	}
+	if _, err := ParseCron(s.Cron); err != nil {
+		return err
+	}
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	if _, _, err := s.catchUp(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Location returns the time zone of the schedule.
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// catchUp returns the catch-up window and whether it is limited.
+func (s *Schedule) catchUp() (time.Duration, bool, error) {
+	if s.CatchUpWindow == "" {
+		return 0, false, nil
+	}
+	window, err := time.ParseDuration(s.CatchUpWindow)
+	if err != nil || window < 0 {
+		return 0, false, fmt.Errorf("invalid catch-up window %q: use a duration such as 24h", s.CatchUpWindow)
+	}
+	return max(window, onTimeGrace), true, nil
+}
+
+// Next returns the first slot after t.
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cron.Next(t.In(loc)), nil
+}
+
+// Slot is a due time of a schedule.
+type Slot struct {
+	Time time.Time
+	// Missed counts the earlier slots that were collapsed into this one.
+	Missed int
+	// Late reports that the slot is past the catch-up window and is passed
+	// over instead of fired.
+	Late bool
+}
+
+// Due returns the slot to handle at now, given the last slot handled; a zero
+// last counts from the creation of the schedule. Missed slots are collapsed
+// into the latest one, which fires unless it is past the catch-up window. ok
+// is false when no slot is due.
+func (s *Schedule) Due(last, now time.Time) (slot Slot, ok bool, err error) {
+	from := last
+	if from.IsZero() {
+		from = s.CreatedAt
+	}
+	if from.IsZero() {
+		from = now
+	}
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return Slot{}, false, err
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return Slot{}, false, err
+	}
+	window, limited, err := s.catchUp()
+	if err != nil {
+		return Slot{}, false, err
+	}
+	count := 0
+	for {
+		next := cron.Next(from.In(loc))
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		slot.Time, from = next, next
+		count++
+	}
+	if count == 0 {
+		return Slot{}, false, nil
+	}
+	slot.Missed = count - 1
+	slot.Late = limited && now.Sub(slot.Time) > window
+	return slot, true, nil
+}
+
+// SlotKey is the idempotency key of one slot of a schedule, so a slot is
+// submitted at most once.
+func SlotKey(name string, slot time.Time) string {
+	return "schedule:" + name + ":" + slot.UTC().Format(time.RFC3339)
+}
+
+// RunConfig loads the run config file or renders the template, and applies
+// the repository override.
+func (s *Schedule) RunConfig() (*models.RunConfig, error) {
+	var runConfig *models.RunConfig
+	if s.Config != "" {
+		loaded, additionalContext, err := utils.LoadConfigFromFileNoPrompts(s.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load run config %s: %w", s.Config, err)
+		}
+		if additionalContext != "" {
+			if loaded.Context != "" {
+				loaded.Context += "\n\n"
+			}
+			loaded.Context += additionalContext
+		}
+		runConfig = loaded
+	} else {
+		library, err := templates.LoadDefault()
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := library.Get(s.Template)
+		if err != nil {
+			return nil, err
+		}
+		if runConfig, err = tmpl.Render(s.Vars); err != nil {
+			return nil, err
+		}
+	}
+	if s.Repository != "" {
+		runConfig.Repository = s.Repository
+	}
+	return runConfig, nil
+}
+
+// Source describes where the run of the schedule comes from.
+func (s *Schedule) Source() string {
+	if s.Config != "" {
+		return s.Config
+	}
+	return "template " + s.Template
+}
+
+// Save writes the schedule to dir as <name>.yaml.
+func Save(dir string, s *Schedule) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create schedule directory: %w", err)
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule: %w", err)
+	}
+	if err := os.WriteFile(path(dir, s.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to save schedule %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// Load reads the named schedule from dir.
+func Load(dir, name string) (*Schedule, error) {
+	data, err := os.ReadFile(path(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("schedule %s not found", name)
+		}
+		return nil, fmt.Errorf("failed to read schedule %s: %w", name, err)
+	}
+	var s Schedule
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %s: %w", name, err)
+	}
+	if s.Name == "" {
+		s.Name = name
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+// List returns the schedules in dir sorted by name. A missing directory has
+// no schedules.
+func List(dir string) ([]*Schedule, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schedules: %w", err)
+	}
+	var schedules []*Schedule
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		s, err := Load(dir, strings.TrimSuffix(entry.Name(), ".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	return schedules, nil
+}
+
+// Remove deletes the named schedule from dir.
+func Remove(dir, name string) error {
+	if err := os.Remove(path(dir, name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("schedule %s not found", name)
+		}
+		return fmt.Errorf("failed to remove schedule %s: %w", name, err)
+	}
+	return nil
+}
+
+func path(dir, name string) string {
+	return filepath.Join(dir, filepath.Base(name)+".yaml")
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleDue(t *testing.T) {
+	s := &Schedule{
+		Name:      "nightly",
+		Cron:      "0 2 * * *",
+		Timezone:  "UTC",
+		Config:    "run.yaml",
+		CreatedAt: time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	_, due, err := s.Due(time.Time{}, time.Date(2026, 10, 11, 1, 59, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, due, "nothing is due before the first slot")
+
+	slot, due, err := s.Due(time.Time{}, time.Date(2026, 10, 11, 2, 0, 30, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, due)
+	assert.Equal(t, Slot{Time: time.Date(2026, 10, 11, 2, 0, 0, 0, time.UTC)}, slot)
+
+	_, due, err = s.Due(slot.Time, time.Date(2026, 10, 11, 3, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, due, "a handled slot does not fire again")
+
+	// Missed slots collapse into the latest one.
+	slot, due, err = s.Due(time.Date(2026, 10, 11, 2, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, due)
+	assert.Equal(t, Slot{Time: time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC), Missed: 2}, slot)
+
+	// Past the catch-up window the slot is passed over.
+	s.CatchUpWindow = "6h"
+	slot, due, err = s.Due(time.Date(2026, 10, 11, 2, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, due)
+	assert.True(t, slot.Late)
+
+	s.CatchUpWindow = "0"
+	slot, _, err = s.Due(time.Date(2026, 10, 13, 2, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 2, 3, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, slot.Late, "a slot a few minutes late is on time")
+}
+
+func TestScheduleValidate(t *testing.T) {
+	valid := Schedule{Name: "weekly", Cron: "@weekly", Config: "run.yaml"}
+	require.NoError(t, valid.Validate())
+
+	tests := map[string]func(s *Schedule){
+		"bad name":     func(s *Schedule) { s.Name = "../weekly" },
+		"bad cron":     func(s *Schedule) { s.Cron = "every monday" },
+		"no source":    func(s *Schedule) { s.Config = "" },
+		"two sources":  func(s *Schedule) { s.Template = "triage" },
+		"bad timezone": func(s *Schedule) { s.Timezone = "Mars/Olympus" },
+		"bad catch-up": func(s *Schedule) { s.CatchUpWindow = "a while" },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := valid
+			mutate(&s)
+			assert.Error(t, s.Validate())
+		})
+	}
+}
+
+func TestScheduleStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "schedules")
+	schedules, err := List(dir)
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, Save(dir, &Schedule{Name: "triage", Cron: "@daily", Template: "triage", Vars: map[string]string{"suite": "e2e"}, CreatedAt: created}))
+	require.NoError(t, Save(dir, &Schedule{Name: "deps", Cron: "0 9 * * mon", Config: "/tmp/deps.yaml", Repository: "acme/api", CreatedAt: created}))
+	require.Error(t, Save(dir, &Schedule{Name: "broken", Cron: "@daily"}))
+
+	schedules, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "deps", schedules[0].Name)
+	assert.Equal(t, "acme/api", schedules[0].Repository)
+	assert.Equal(t, map[string]string{"suite": "e2e"}, schedules[1].Vars)
+	assert.True(t, created.Equal(schedules[1].CreatedAt))
+
+	require.NoError(t, Remove(dir, "deps"))
+	_, err = Load(dir, "deps")
+	assert.EqualError(t, err, "schedule deps not found")
+	assert.Error(t, Remove(dir, "deps"))
+}
+
+func TestScheduleRunConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "deps.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("prompt: Bump the dependencies to their latest minor versions\nrepository: acme/web\n"), 0644))
+
+	s := &Schedule{Name: "deps", Cron: "@weekly", Config: file, Repository: "acme/api"}
+	runConfig, err := s.RunConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "acme/api", runConfig.Repository)
+	assert.Equal(t, "Bump the dependencies to their latest minor versions", runConfig.Prompt)
+}
+
+func TestSlotKey(t *testing.T) {
+	slot := time.Date(2026, 10, 19, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, "schedule:deps:2026-10-19T07:00:00Z", SlotKey("deps", slot))
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/config"
+	"github.com/repobird/repobird-cli/internal/project"
+)
+
+// Template sources, from lowest to highest precedence.
+const (
+	SourceUser    = "user"
+	SourceProject = "project"
+)
+
+// ProjectDir is the template directory inside a project checkout.
+const ProjectDir = ".repobird/templates"
+
+// templateExtensions are the file extensions loaded from template directories.
+var templateExtensions = map[string]bool{".md": true, ".markdown": true, ".tmpl": true}
+
+// Library is the set of templates available in a directory.
+type Library struct {
+	templates map[string]*Template
+}
+
+// UserDir returns the per-user template directory in the config dir.
+func UserDir() string {
+	return filepath.Join(config.ConfigDir(), "templates")
+}
+
+// Load reads templates from userDir, then ProjectDir under projectRoot, then
+// the named files from the project .repobird.yaml. A template with the same
+// name in a later source replaces the earlier one. Missing directories are
+// skipped.
+func Load(userDir, projectRoot string, projectFiles map[string]string) (*Library, error) {
+	l := &Library{templates: make(map[string]*Template)}
+	if err := l.loadDir(userDir, SourceUser); err != nil {
+		return nil, err
+	}
+	if projectRoot != "" {
+		if err := l.loadDir(filepath.Join(projectRoot, filepath.FromSlash(ProjectDir)), SourceProject); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(projectFiles))
+	for name := range projectFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := l.loadFile(name, projectFiles[name], SourceProject); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// LoadDefault loads the user templates and the templates of the project
+// containing the working directory.
+func LoadDefault() (*Library, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	projectFile, projectPath, err := project.Load(cwd)
+	if err != nil {
+		return nil, err
+	}
+	projectRoot := project.GitRoot(cwd)
+	if projectPath != "" {
+		projectRoot = filepath.Dir(projectPath)
+	}
+	return Load(UserDir(), projectRoot, projectFile.Templates)
+}
+
+// Get returns the named template.
+func (l *Library) Get(name string) (*Template, error) {
+	if t, ok := l.templates[name]; ok {
+		return t, nil
+	}
+	available := make([]string, 0, len(l.templates))
+	for _, t := range l.List() {
+		available = append(available, t.Name)
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("template %q not found; add templates to %s or %s", name, UserDir(), ProjectDir)
+	}
+	return nil, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(available, ", "))
+}
+
+// List returns all templates sorted by name.
+func (l *Library) List() []*Template {
+	list := make([]*Template, 0, len(l.templates))
+	for _, t := range l.templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (l *Library) loadDir(dir, source string) error {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !templateExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := l.loadFile(NameFromPath(path), path, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Library) loadFile(name, path, source string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	t, err := Parse(name, path, data)
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	t.Source = source
+	l.templates[name] = t
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package templates loads reusable prompt templates. A template is a Markdown
+// file whose body is a Go text/template rendered into the run prompt, with
+// YAML frontmatter declaring variables and default run settings.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/adrg/frontmatter"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Variable is a declared template variable.
+type Variable struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     string `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+// Template is a parsed prompt template.
+type Template struct {
+	Name        string
+	Path        string
+	Source      string
+	Description string
+	Variables   []Variable
+	// Defaults are run settings from the frontmatter. String fields are
+	// rendered with the same variables as the body.
+	Defaults models.RunConfig
+	Body     string
+}
+
+type templateFrontmatter struct {
+	Description      string     `yaml:"description"`
+	Variables        []Variable `yaml:"variables"`
+	models.RunConfig `yaml:",inline"`
+}
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Parse parses template content. name is how the template is selected, and
+// path is used in error messages.
+func Parse(name, path string, data []byte) (*Template, error) {
+	var meta templateFrontmatter
+	rest, err := frontmatter.Parse(bytes.NewReader(data), &meta)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: failed to parse frontmatter: %w", name, err)
+	}
+
+	t := &Template{
+		Name:        name,
+		Path:        path,
+		Description: meta.Description,
+		Variables:   meta.Variables,
+		Defaults:    meta.RunConfig,
+		Body:        strings.TrimSpace(string(rest)),
+	}
+	if t.Body == "" {
+		t.Body = strings.TrimSpace(meta.Prompt)
+	}
+	t.Defaults.Prompt = ""
+	if t.Body == "" {
+		return nil, fmt.Errorf("template %s: body is empty", name)
+	}
+
+	seen := make(map[string]bool)
+	for _, variable := range t.Variables {
+		if !variableNamePattern.MatchString(variable.Name) {
+			return nil, fmt.Errorf("template %s: invalid variable name %q", name, variable.Name)
+		}
+		if seen[variable.Name] {
+			return nil, fmt.Errorf("template %s: variable %q declared twice", name, variable.Name)
+		}
+		seen[variable.Name] = true
+	}
+
+	// Parse once up front so syntax errors surface when templates are listed.
+	for _, text := range t.texts() {
+		if _, err := newTextTemplate(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+	}
+	return t, nil
+}
+
+// NameFromPath returns the template name for a file: its base name without
+// extension.
+func NameFromPath(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// Render resolves variables and returns the run configuration produced by the
+// template. Every declared variable must either be supplied, have a default,
+// or be optional; undeclared variables are rejected to catch typos.
+func (t *Template) Render(vars map[string]string) (*models.RunConfig, error) {
+	values, err := t.resolve(vars, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.execute(values)
+}
+
+// Preview renders the template with defaults, showing missing required
+// variables as <name> placeholders for the user to fill in.
+func (t *Template) Preview() (*models.RunConfig, error) {
+	values, err := t.resolve(nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return t.execute(values)
+}
+
+// RequiredVariables returns the names of required variables.
+func (t *Template) RequiredVariables() []string {
+	var names []string
+	for _, variable := range t.Variables {
+		if variable.Required {
+			names = append(names, variable.Name)
+		}
+	}
+	return names
+}
+
+func (t *Template) resolve(vars map[string]string, placeholders bool) (map[string]string, error) {
+	declared := make(map[string]bool, len(t.Variables))
+	values := make(map[string]string, len(t.Variables))
+	var missing []string
+	for _, variable := range t.Variables {
+		declared[variable.Name] = true
+		value, ok := vars[variable.Name]
+		switch {
+		case ok:
+			values[variable.Name] = value
+		case variable.Default != "":
+			values[variable.Name] = variable.Default
+		case variable.Required && placeholders:
+			values[variable.Name] = "<" + variable.Name + ">"
+		case variable.Required:
+			missing = append(missing, variable.Name)
+		default:
+			values[variable.Name] = ""
+		}
+	}
+
+	var unknown []string
+	for name := range vars {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("template %s: unknown variable(s): %s (declared: %s)",
+			t.Name, strings.Join(unknown, ", "), strings.Join(t.variableNames(), ", "))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s: missing required variable(s): %s (set them with --var name=value)",
+			t.Name, strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+func (t *Template) execute(values map[string]string) (*models.RunConfig, error) {
+	config := t.Defaults
+	config.Prompt = t.Body
+	config.Files = append([]string(nil), t.Defaults.Files...)
+
+	render := func(field, text string) (string, error) {
+		if text == "" {
+			return "", nil
+		}
+		tmpl, err := newTextTemplate(t.Name).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("template %s: %s: %w", t.Name, field, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, values); err != nil {
+			return "", fmt.Errorf("template %s: %s: %w", t.Name, field, err)
+		}
+		return strings.TrimSpace(b.String()), nil
+	}
+
+	var err error
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"prompt", &config.Prompt},
+		{"title", &config.Title},
+		{"context", &config.Context},
+		{"repository", &config.Repository},
+		{"baseBranch", &config.BaseBranch},
+		{"target", &config.Target},
+		{"outputBranch", &config.OutputBranch},
+		{"prTargetBranch", &config.PRTargetBranch},
+	} {
+		if *field.value, err = render(field.name, *field.value); err != nil {
+			return nil, err
+		}
+	}
+	for i, file := range config.Files {
+		if config.Files[i], err = render(fmt.Sprintf("files[%d]", i), file); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+func (t *Template) texts() []string {
+	texts := []string{t.Body, t.Defaults.Title, t.Defaults.Context, t.Defaults.Repository,
+		t.Defaults.BaseBranch, t.Defaults.Target, t.Defaults.OutputBranch, t.Defaults.PRTargetBranch}
+	return append(texts, t.Defaults.Files...)
+}
+
+func (t *Template) variableNames() []string {
+	names := make([]string, 0, len(t.Variables))
+	for _, variable := range t.Variables {
+		names = append(names, variable.Name)
+	}
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
+
+// newTextTemplate returns a template that fails on references to variables
+// that were not declared.
+func newTextTemplate(name string) *template.Template {
+	return template.New(name).Option("missingkey=error")
+}
+
+// ParseVars parses name=value pairs from repeated --var flags.
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q (use name=value)", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
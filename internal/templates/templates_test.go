@@ -0,0 +1,138 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addTestsTemplate = `---
+description: Add unit tests
+title: "Add tests for {{.package}}"
+baseBranch: develop
+variables:
+  - name: package
+    required: true
+  - name: framework
+    default: testify
+  - name: notes
+---
+Add table-driven tests for {{.package}} using {{.framework}}.{{if .notes}} {{.notes}}{{end}}
+`
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("add-tests", "add-tests.md", []byte(addTestsTemplate))
+	require.NoError(t, err)
+	assert.Equal(t, "Add unit tests", tmpl.Description)
+	assert.Equal(t, []string{"package"}, tmpl.RequiredVariables())
+
+	config, err := tmpl.Render(map[string]string{"package": "internal/api"})
+	require.NoError(t, err)
+	assert.Equal(t, "Add table-driven tests for internal/api using testify.", config.Prompt)
+	assert.Equal(t, "Add tests for internal/api", config.Title)
+	assert.Equal(t, "develop", config.BaseBranch)
+
+	config, err = tmpl.Render(map[string]string{"package": "cmd", "framework": "stdlib", "notes": "Cover errors."})
+	require.NoError(t, err)
+	assert.Equal(t, "Add table-driven tests for cmd using stdlib. Cover errors.", config.Prompt)
+}
+
+func TestRenderValidatesVariables(t *testing.T) {
+	tmpl, err := Parse("add-tests", "add-tests.md", []byte(addTestsTemplate))
+	require.NoError(t, err)
+
+	_, err = tmpl.Render(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required variable(s): package")
+
+	_, err = tmpl.Render(map[string]string{"package": "x", "pkg": "y"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown variable(s): pkg")
+}
+
+func TestPreviewUsesPlaceholders(t *testing.T) {
+	tmpl, err := Parse("add-tests", "add-tests.md", []byte(addTestsTemplate))
+	require.NoError(t, err)
+
+	config, err := tmpl.Preview()
+	require.NoError(t, err)
+	assert.Equal(t, "Add table-driven tests for <package> using testify.", config.Prompt)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty body", "---\ndescription: x\n---\n", "body is empty"},
+		{"invalid variable", "---\nvariables:\n  - name: bad-name\n---\nhi", "invalid variable name"},
+		{"duplicate variable", "---\nvariables:\n  - name: a\n  - name: a\n---\n{{.a}}", "declared twice"},
+		{"syntax error", "Fix {{.issue", "template broken"},
+		{"undeclared reference", "Fix {{.issue}}", "issue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse("broken", "broken.md", []byte(tt.content))
+			if err == nil {
+				_, err = tmpl.Render(nil)
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"package=internal/api", "expr=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"package": "internal/api", "expr": "a=b", "empty": ""}, vars)
+
+	_, err = ParseVars([]string{"novalue"})
+	require.Error(t, err)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	userDir := t.TempDir()
+	projectRoot := t.TempDir()
+	projectDir := filepath.Join(projectRoot, filepath.FromSlash(ProjectDir))
+	require.NoError(t, os.MkdirAll(projectDir, 0o755))
+
+	write := func(path, body string) {
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	}
+	write(filepath.Join(userDir, "fix.md"), "user fix")
+	write(filepath.Join(userDir, "docs.md"), "user docs")
+	write(filepath.Join(userDir, "notes.txt"), "ignored")
+	write(filepath.Join(projectDir, "fix.md"), "project fix")
+	named := filepath.Join(projectRoot, "release.tmpl")
+	write(named, "release notes")
+
+	library, err := Load(userDir, projectRoot, map[string]string{"release": named})
+	require.NoError(t, err)
+
+	var names []string
+	for _, tmpl := range library.List() {
+		names = append(names, tmpl.Name)
+	}
+	assert.Equal(t, []string{"docs", "fix", "release"}, names)
+
+	fix, err := library.Get("fix")
+	require.NoError(t, err)
+	assert.Equal(t, "project fix", fix.Body)
+	assert.Equal(t, SourceProject, fix.Source)
+
+	docs, err := library.Get("docs")
+	require.NoError(t, err)
+	assert.Equal(t, SourceUser, docs.Source)
+
+	_, err = library.Get("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: docs, fix, release")
+}
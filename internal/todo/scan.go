@@ -0,0 +1,294 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package todo finds TODO(repobird) marker comments in a checkout and turns
+// them into bulk runs.
+package todo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/bulk"
+	"github.com/repobird/repobird-cli/internal/project"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+const (
+	// DefaultContextLines is the number of code lines kept above and below a
+	// marker.
+	DefaultContextLines = 10
+
+	// maxFileSize skips generated and data files that are unlikely to hold
+	// hand-written notes.
+	maxFileSize = 1 << 20
+)
+
+// markerPattern matches the marker and the separator after it.
+var markerPattern = regexp.MustCompile(`TODO\(repobird\)[ \t]*[:\-]?[ \t]*`)
+
+// Marker is a TODO(repobird) comment found in a file.
+type Marker struct {
+	// Path is slash-separated and relative to the root of the git checkout,
+	// or to the scanned directory outside a checkout.
+	Path string `json:"path"`
+	// Line and EndLine are the 1-based lines the comment spans.
+	Line    int    `json:"line"`
+	EndLine int    `json:"endLine"`
+	Text    string `json:"text"`
+	// Snippet is the comment with the code around it, starting at
+	// SnippetLine.
+	Snippet     string `json:"snippet"`
+	SnippetLine int    `json:"snippetLine"`
+	// Fingerprint identifies the marker by path and text, so it survives
+	// edits that only move it within the file.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Options control a scan.
+type Options struct {
+	// ContextLines is the number of lines kept above and below each marker;
+	// zero uses DefaultContextLines and a negative value keeps none.
+	ContextLines int
+}
+
+// Scan walks root, skipping paths ignored by the .gitignore files of its
+// checkout and the usual dependency and build directories, and returns the
+// markers in path order.
+func Scan(root string, opts Options) ([]Marker, error) {
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+	contextLines := opts.ContextLines
+	if contextLines == 0 {
+		contextLines = DefaultContextLines
+	} else if contextLines < 0 {
+		contextLines = 0
+	}
+
+	files, err := utils.FindFiles(root, utils.FileDiscoveryOptions{
+		MaxDepth:         -1,
+		MaxFiles:         -1,
+		IgnorePatterns:   utils.DefaultIgnorePatterns,
+		RespectGitignore: true,
+		GitRoot:          project.GitRoot(root),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	prefix := checkoutPrefix(root)
+	var markers []Marker
+	seen := make(map[string]bool)
+	for _, file := range files {
+		data, err := readTextFile(filepath.Join(root, file))
+		if err != nil || data == nil {
+			continue
+		}
+		path := filepath.ToSlash(filepath.Join(prefix, file))
+		for _, marker := range ScanText(path, string(data), contextLines) {
+			if seen[marker.Fingerprint] {
+				continue
+			}
+			seen[marker.Fingerprint] = true
+			markers = append(markers, marker)
+		}
+	}
+	return markers, nil
+}
+
+// checkoutPrefix returns the path of root within its git checkout, so marker
+// paths name files the way the repository does.
+func checkoutPrefix(root string) string {
+	gitRoot := project.GitRoot(root)
+	if gitRoot == "" {
+		return ""
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	prefix, err := filepath.Rel(gitRoot, absRoot)
+	if err != nil || prefix == "." {
+		return ""
+	}
+	return prefix
+}
+
+// readTextFile returns the file contents, or nil for large or binary files.
+func readTextFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxFileSize {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// commentStyle describes how a comment continues past the marker line.
+type commentStyle struct {
+	line     string // line comment token repeated on each line
+	blockEnd string // closing token of a block comment
+}
+
+// blockComments maps block comment openers to their closers. A leading "*"
+// is a line inside a doc comment whose opener was on an earlier line.
+var blockComments = []struct{ open, end string }{
+	{"/**", "*/"}, {"/*", "*/"}, {"<!--", "-->"}, {`"""`, `"""`}, {"'''", "'''"},
+	{"{-", "-}"}, {"(*", "*)"}, {"--[[", "]]"}, {"*", "*/"},
+}
+
+// lineComments lists line comment tokens, longest first.
+var lineComments = []string{"///", "//!", "//", "##", "#", "--", ";;", ";", "%"}
+
+// styleOf returns the comment style of the code before a marker.
+func styleOf(prefix string) commentStyle {
+	prefix = strings.TrimSpace(prefix)
+	for _, block := range blockComments {
+		if strings.HasSuffix(prefix, block.open) {
+			return commentStyle{blockEnd: block.end}
+		}
+	}
+	for _, token := range lineComments {
+		if strings.HasSuffix(prefix, token) {
+			return commentStyle{line: token}
+		}
+	}
+	return commentStyle{}
+}
+
+// ScanText returns the markers in the contents of one file. Only markers
+// that follow a comment token count, so TODO(repobird) in code or strings is
+// skipped. A marker in a line comment continues over the following lines of the same comment, up to
+// a blank comment line; one in a block comment continues to its closer.
+func ScanText(path, content string, contextLines int) []Marker {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n"), "\n")
+	var markers []Marker
+	for i := 0; i < len(lines); i++ {
+		loc := markerPattern.FindStringIndex(lines[i])
+		if loc == nil {
+			continue
+		}
+		style := styleOf(lines[i][:loc[0]])
+		if style == (commentStyle{}) {
+			// The marker is in code or a string, not a comment
+			continue
+		}
+		parts := []string{lines[i][loc[1]:]}
+		end := i
+
+		switch {
+		case style.blockEnd != "":
+			if idx := strings.Index(parts[0], style.blockEnd); idx >= 0 {
+				parts[0] = parts[0][:idx]
+				break
+			}
+			for j := i + 1; j < len(lines); j++ {
+				end = j
+				line := lines[j]
+				idx := strings.Index(line, style.blockEnd)
+				if idx >= 0 {
+					line = line[:idx]
+				}
+				parts = append(parts, trimBlockLine(line))
+				if idx >= 0 {
+					break
+				}
+			}
+		case style.line != "":
+			for j := i + 1; j < len(lines); j++ {
+				trimmed := strings.TrimSpace(lines[j])
+				if !strings.HasPrefix(trimmed, style.line) {
+					break
+				}
+				body := strings.TrimSpace(strings.TrimPrefix(trimmed, style.line))
+				if body == "" || markerPattern.MatchString(body) {
+					break
+				}
+				parts = append(parts, body)
+				end = j
+			}
+		}
+
+		text := joinParts(parts)
+		if text == "" {
+			continue
+		}
+		start := max(0, i-contextLines)
+		stop := min(len(lines), end+contextLines+1)
+		markers = append(markers, Marker{
+			Path:        path,
+			Line:        i + 1,
+			EndLine:     end + 1,
+			Text:        text,
+			Snippet:     strings.Join(lines[start:stop], "\n"),
+			SnippetLine: start + 1,
+			Fingerprint: Fingerprint(path, text),
+		})
+		i = end
+	}
+	return markers
+}
+
+// trimBlockLine strips the indentation and "*" decoration of a block comment
+// line.
+func trimBlockLine(line string) string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "*/") {
+		line = strings.TrimPrefix(line, "*")
+	}
+	return strings.TrimSpace(line)
+}
+
+// joinParts joins the comment lines, dropping blank lines at either end.
+func joinParts(parts []string) string {
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// Fingerprint identifies a marker by its path and whitespace-normalized
+// text.
+func Fingerprint(path, text string) string {
+	sum := sha256.Sum256([]byte(path + "\n" + strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Run returns the bulk run for a marker. The prompt is the comment text; the
+// context names the location and holds the surrounding code.
+func (m Marker) Run() bulk.BulkRunConfig {
+	title := strings.SplitN(m.Text, "\n", 2)[0]
+	if len(title) > 72 {
+		title = strings.TrimSpace(title[:69]) + "..."
+	}
+	location := fmt.Sprintf("%s:%d", m.Path, m.Line)
+	run := bulk.BulkRunConfig{
+		Title: title,
+		Prompt: fmt.Sprintf("%s\n\nThis task was left as a TODO(repobird) comment at %s. Remove the comment once the task is done.",
+			m.Text, location),
+		Context: fmt.Sprintf("%s (lines %d-%d):\n\n```%s\n%s\n```",
+			m.Path, m.SnippetLine, m.SnippetLine+strings.Count(m.Snippet, "\n"), language(m.Path), m.Snippet),
+	}
+	run.Files = []string{m.Path}
+	return run
+}
+
+// language returns the code fence language for a path.
+func language(path string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
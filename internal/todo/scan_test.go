@@ -0,0 +1,141 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTextCommentStyles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    string
+		endLine int
+	}{
+		{
+			name:    "line comment block",
+			content: "func f() {\n\t// TODO(repobird): retry uploads\n\t//   with backoff.\n\t//\n\t// Unrelated note.\n}\n",
+			text:    "retry uploads\nwith backoff.",
+			endLine: 3,
+		},
+		{
+			name:    "trailing hash comment",
+			content: "x = load()  # TODO(repobird) - handle None\ny = 2\n",
+			text:    "handle None",
+			endLine: 1,
+		},
+		{
+			name:    "block comment",
+			content: "/*\n * TODO(repobird): split this file\n * into one module per resource.\n */\nint x;\n",
+			text:    "split this file\ninto one module per resource.",
+			endLine: 4,
+		},
+		{
+			name:    "single-line block comment",
+			content: "/* TODO(repobird): rename */ int y;\n",
+			text:    "rename",
+			endLine: 1,
+		},
+		{
+			name:    "html comment",
+			content: "<!-- TODO(repobird): add alt text\n     to every image -->\n<img src=a.png>\n",
+			text:    "add alt text\nto every image",
+			endLine: 2,
+		},
+		{
+			name:    "sql comment",
+			content: "-- TODO(repobird): add an index on user_id\nSELECT 1;\n",
+			text:    "add an index on user_id",
+			endLine: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markers := ScanText("file", tt.content, 1)
+			require.Len(t, markers, 1)
+			assert.Equal(t, tt.text, markers[0].Text)
+			assert.Equal(t, tt.endLine, markers[0].EndLine)
+		})
+	}
+}
+
+func TestScanTextSeparatesMarkers(t *testing.T) {
+	content := "// TODO(repobird): first task\n// TODO(repobird): second task\n// TODO(repobird):\n"
+	markers := ScanText("a.go", content, 0)
+	require.Len(t, markers, 2)
+	assert.Equal(t, "first task", markers[0].Text)
+	assert.Equal(t, 2, markers[1].Line)
+	assert.NotEqual(t, markers[0].Fingerprint, markers[1].Fingerprint)
+
+	// Moving the comment keeps its fingerprint.
+	moved := ScanText("a.go", "package a\n\n"+content, 0)
+	assert.Equal(t, markers[0].Fingerprint, moved[0].Fingerprint)
+	assert.Equal(t, 3, moved[0].Line)
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write(".gitignore", "generated/\n")
+	write("api/client.go", "package api\n\nfunc Get() {\n\t// TODO(repobird): add retries\n\treturn\n}\n")
+	write("generated/models.go", "// TODO(repobird): ignored\n")
+	write("vendor/lib.go", "// TODO(repobird): ignored\n")
+	write("image.bin", "\x00\x01TODO(repobird): ignored\n")
+
+	markers, err := Scan(root, Options{ContextLines: 1})
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+	marker := markers[0]
+	assert.Equal(t, "api/client.go", marker.Path)
+	assert.Equal(t, 4, marker.Line)
+	assert.Equal(t, "func Get() {\n\t// TODO(repobird): add retries\n\treturn", marker.Snippet)
+
+	run := marker.Run()
+	assert.Equal(t, "add retries", run.Title)
+	assert.Contains(t, run.Prompt, "add retries\n\nThis task was left as a TODO(repobird) comment at api/client.go:4.")
+	assert.Equal(t, "api/client.go (lines 3-5):\n\n```go\nfunc Get() {\n\t// TODO(repobird): add retries\n\treturn\n```", run.Context)
+	assert.Equal(t, []string{"api/client.go"}, run.Files)
+}
+
+func TestScanTextSkipsMarkersOutsideComments(t *testing.T) {
+	content := "cmd.Short = \"Turn TODO(repobird) comments into runs\"\n" +
+		"return fmt.Errorf(\"TODO(repobird): %s\", text)\n" +
+		"TODO(repobird): not in a comment\n" +
+		"// TODO(repobird): the only task\n"
+	markers := ScanText("a.go", content, 0)
+	require.Len(t, markers, 1)
+	assert.Equal(t, "the only task", markers[0].Text)
+	assert.Equal(t, 4, markers[0].Line)
+}
+
+func TestScanSubdirectoryRespectsParentGitignore(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	write(".gitignore", "*.gen.go\n/services/api/tmp/\n")
+	write("services/.gitignore", "fixtures/\n")
+	write("services/api/handler.go", "// TODO(repobird): validate input\n")
+	write("services/api/models.gen.go", "// TODO(repobird): ignored\n")
+	write("services/api/tmp/scratch.go", "// TODO(repobird): ignored\n")
+	write("services/api/fixtures/data.go", "// TODO(repobird): ignored\n")
+
+	markers, err := Scan(filepath.Join(root, "services", "api"), Options{ContextLines: -1})
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, "services/api/handler.go", markers[0].Path)
+}
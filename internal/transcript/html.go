@@ -0,0 +1,94 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package transcript
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlStyles is embedded in the document so exports render offline.
+const htmlStyles = `
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; background: #fff; line-height: 1.5; }
+h1 { font-size: 1.6rem; border-bottom: 1px solid #d0d7de; padding-bottom: .4rem; }
+h2 { font-size: 1.2rem; margin-top: 2rem; }
+table.meta { border-collapse: collapse; margin: 1rem 0; }
+table.meta th { text-align: left; padding: .2rem 1rem .2rem 0; color: #59636e; font-weight: 600; }
+table.meta td { padding: .2rem 0; }
+pre { background: #f6f8fa; border: 1px solid #d0d7de; border-radius: 6px; padding: .75rem; overflow-x: auto; white-space: pre-wrap; word-break: break-word; font-size: .85rem; }
+.msg { border-left: 3px solid #d0d7de; padding: .25rem 0 .25rem .75rem; margin: 1rem 0; }
+.msg .kind { font-weight: 600; font-size: .8rem; text-transform: uppercase; color: #59636e; }
+.msg.assistant { border-color: #0969da; }
+.msg.user { border-color: #8250df; }
+.msg.tool { border-color: #9a6700; }
+.msg.error { border-color: #cf222e; }
+.msg .content { white-space: pre-wrap; }
+details { margin: .4rem 0; }
+summary { cursor: pointer; color: #59636e; font-size: .85rem; }
+.error-banner { background: #ffebe9; border: 1px solid #ff818266; border-radius: 6px; padding: .5rem .75rem; }
+footer { margin-top: 3rem; color: #59636e; font-size: .8rem; border-top: 1px solid #d0d7de; padding-top: .5rem; }
+@media (prefers-color-scheme: dark) {
+  body { color: #e6edf3; background: #0d1117; }
+  pre { background: #161b22; border-color: #30363d; }
+  h1, footer { border-color: #30363d; }
+  .error-banner { background: #490202; }
+}
+`
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"rfc3339": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	"css":     func() template.CSS { return template.CSS(htmlStyles) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Heading}}</title>
+<style>{{css}}</style>
+</head>
+<body>
+<h1>{{.Heading}}</h1>
+<table class="meta">
+{{- if .Repository}}<tr><th>Repository</th><td>{{.Repository}}</td></tr>{{end}}
+{{- if .Status}}<tr><th>Status</th><td>{{.Status}}</td></tr>{{end}}
+{{- if .RunType}}<tr><th>Run type</th><td>{{.RunType}}</td></tr>{{end}}
+{{- range .Branches}}<tr><th>{{.Label}} branch</th><td><code>{{.Value}}</code></td></tr>{{end}}
+{{- if .PRURL}}<tr><th>Pull request</th><td><a href="{{.PRURL}}">{{.PRURL}}</a></td></tr>{{end}}
+{{- if not .CreatedAt.IsZero}}<tr><th>Created</th><td>{{rfc3339 .CreatedAt}}</td></tr>{{end}}
+</table>
+{{- if .Error}}
+<p class="error-banner"><strong>Error:</strong> {{.Error}}</p>
+{{- end}}
+<h2>Prompt</h2>
+<pre>{{.Prompt}}</pre>
+{{- if .Context}}
+<h2>Context</h2>
+<pre>{{.Context}}</pre>
+{{- end}}
+<h2>Conversation ({{len .Messages}} messages)</h2>
+{{- range .Messages}}
+<div class="msg {{.Kind}}{{if .IsError}} error{{end}}">
+{{- if eq .Kind "tool"}}
+<div class="kind">tool: {{.ToolName}}{{if .IsError}} (error){{end}}</div>
+{{- if and .Content (ne .Content .ToolName)}}<div class="content">{{.Content}}</div>{{end}}
+{{- if .ToolParams}}<details><summary>Parameters</summary><pre>{{.ToolParams}}</pre></details>{{end}}
+{{- if .ToolResult}}<details><summary>Result</summary><pre>{{.ToolResult}}</pre></details>{{end}}
+{{- else}}
+<div class="kind">{{.Kind}}{{if .IsError}} (error){{end}}</div>
+<div class="content">{{.Content}}</div>
+{{- end}}
+</div>
+{{- end}}
+<footer>Exported by repobird on {{rfc3339 .GeneratedAt}}. Secrets are redacted.</footer>
+</body>
+</html>
+`))
+
+// WriteHTML renders the transcript as a single self-contained HTML page.
+// Styles are inlined and no external resources are referenced, so the file
+// can be opened offline or attached to a post-mortem as-is.
+func WriteHTML(w io.Writer, t Transcript) error {
+	return htmlTemplate.Execute(w, t)
+}
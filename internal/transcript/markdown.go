@@ -0,0 +1,99 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteMarkdown renders the transcript as GitHub-flavored Markdown. Tool
+// params and results are wrapped in <details> blocks so they stay collapsed
+// when pasted into issues and pull requests.
+func WriteMarkdown(w io.Writer, t Transcript) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", t.Heading())
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	writeMarkdownRow(&b, "Repository", t.Repository)
+	writeMarkdownRow(&b, "Status", t.Status)
+	writeMarkdownRow(&b, "Run type", t.RunType)
+	for _, branch := range t.Branches {
+		writeMarkdownRow(&b, branch.Label+" branch", "`"+branch.Value+"`")
+	}
+	if t.PRURL != "" {
+		writeMarkdownRow(&b, "Pull request", fmt.Sprintf("[%s](%s)", t.PRURL, t.PRURL))
+	}
+	if !t.CreatedAt.IsZero() {
+		writeMarkdownRow(&b, "Created", t.CreatedAt.UTC().Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+
+	if t.Error != "" {
+		fmt.Fprintf(&b, "> **Error:** %s\n\n", t.Error)
+	}
+
+	b.WriteString("## Prompt\n\n")
+	writeFence(&b, t.Prompt)
+	if t.Context != "" {
+		b.WriteString("## Context\n\n")
+		writeFence(&b, t.Context)
+	}
+
+	fmt.Fprintf(&b, "## Conversation (%d messages)\n\n", len(t.Messages))
+	for _, message := range t.Messages {
+		if message.Kind == "tool" {
+			status := ""
+			if message.IsError {
+				status = " (error)"
+			}
+			fmt.Fprintf(&b, "**tool: `%s`**%s\n\n", message.ToolName, status)
+			if message.Content != "" && message.Content != message.ToolName {
+				fmt.Fprintf(&b, "%s\n\n", message.Content)
+			}
+			writeDetails(&b, "Parameters", message.ToolParams)
+			writeDetails(&b, "Result", message.ToolResult)
+			continue
+		}
+
+		fmt.Fprintf(&b, "**%s**", message.Kind)
+		if message.IsError {
+			b.WriteString(" (error)")
+		}
+		fmt.Fprintf(&b, "\n\n%s\n\n", strings.TrimSpace(message.Content))
+	}
+
+	fmt.Fprintf(&b, "---\n\n_Exported by repobird on %s. Secrets are redacted._\n", t.GeneratedAt.UTC().Format(time.RFC3339))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, label, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "| %s | %s |\n", label, strings.ReplaceAll(value, "|", `\|`))
+}
+
+func writeDetails(b *strings.Builder, summary, body string) {
+	if body == "" {
+		return
+	}
+	fmt.Fprintf(b, "<details>\n<summary>%s</summary>\n\n", summary)
+	writeFence(b, body)
+	b.WriteString("</details>\n\n")
+}
+
+// writeFence writes body in a code fence long enough not to collide with
+// any backtick run inside it.
+func writeFence(b *strings.Builder, body string) {
+	fence := "```"
+	for strings.Contains(body, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s\n%s\n%s\n\n", fence, strings.TrimRight(body, "\n"), fence)
+}
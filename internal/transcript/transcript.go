@@ -0,0 +1,135 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package transcript renders a run and its agent conversation log as a
+// shareable Markdown or self-contained HTML document.
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// Supported export formats.
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// Transcript is the redacted content of an export.
+type Transcript struct {
+	RunID       string
+	Title       string
+	Repository  string
+	Status      string
+	RunType     string
+	Branches    []Field
+	PRURL       string
+	CreatedAt   time.Time
+	Prompt      string
+	Context     string
+	Error       string
+	Messages    []Message
+	GeneratedAt time.Time
+}
+
+// Field is a labeled header value.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Message is one redacted log entry.
+type Message struct {
+	Kind       string
+	Content    string
+	ToolName   string
+	ToolParams string
+	ToolResult string
+	IsError    bool
+}
+
+// New builds a redacted transcript from a run and its log messages.
+// Secrets in the title, prompt, context, and every message are masked with
+// utils.RedactSecrets before rendering.
+func New(run *models.RunResponse, messages []models.RunLogMessage, now time.Time) Transcript {
+	t := Transcript{GeneratedAt: now}
+	if run != nil {
+		t.RunID = run.GetIDString()
+		t.Title = utils.RedactSecrets(run.Title)
+		t.Repository = run.GetRepositoryName()
+		t.Status = string(run.Status)
+		t.RunType = run.RunType
+		t.CreatedAt = run.CreatedAt
+		t.Prompt = utils.RedactSecrets(run.Prompt)
+		t.Context = utils.RedactSecrets(run.Context)
+		t.Error = utils.RedactSecrets(run.Error)
+		if run.PullRequestURL != nil {
+			t.PRURL = *run.PullRequestURL
+		}
+		for _, field := range []Field{
+			{Label: "Source", Value: run.Source},
+			{Label: "Target", Value: run.Target},
+			{Label: "Base", Value: run.BaseBranch},
+			{Label: "Output", Value: run.OutputBranch},
+			{Label: "PR target", Value: run.PRTargetBranch},
+		} {
+			if field.Value != "" {
+				t.Branches = append(t.Branches, field)
+			}
+		}
+	}
+
+	for _, message := range messages {
+		kind := message.Type
+		if kind == "" {
+			kind = "log"
+		}
+		if kind == "tool_call" || message.ToolName != "" {
+			kind = "tool"
+		}
+		t.Messages = append(t.Messages, Message{
+			Kind:       kind,
+			Content:    utils.RedactSecrets(message.Content),
+			ToolName:   message.ToolName,
+			ToolParams: utils.RedactSecrets(message.ToolParams),
+			ToolResult: utils.RedactSecrets(message.ToolResult),
+			IsError:    message.IsError,
+		})
+	}
+	return t
+}
+
+// Heading returns the document title.
+func (t Transcript) Heading() string {
+	if t.Title != "" {
+		return fmt.Sprintf("Run %s: %s", t.RunID, t.Title)
+	}
+	return "Run " + t.RunID
+}
+
+// ValidateFormat reports whether format is a supported export format.
+func ValidateFormat(format string) error {
+	switch strings.ToLower(format) {
+	case FormatMarkdown, "markdown", FormatHTML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q (use md or html)", format)
+	}
+}
+
+// Write renders the transcript in the requested format.
+func Write(w io.Writer, t Transcript, format string) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+	if strings.EqualFold(format, FormatHTML) {
+		return WriteHTML(w, t)
+	}
+	return WriteMarkdown(w, t)
+}
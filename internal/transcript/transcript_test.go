@@ -0,0 +1,71 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package transcript
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func testTranscript() Transcript {
+	prURL := "https://github.com/acme/webapp/pull/7"
+	run := &models.RunResponse{
+		ID:             "123",
+		Status:         models.StatusDone,
+		RepositoryName: "acme/webapp",
+		Source:         "main",
+		Target:         "fix/auth",
+		Title:          "Fix auth with token=titlesecretvalue42",
+		Prompt:         "Fix login. Use token=supersecretvalue123 for staging.",
+		Context:        "See <script>alert(1)</script>",
+		PullRequestURL: &prURL,
+	}
+	messages := []models.RunLogMessage{
+		{Type: "user", Content: "Fix login"},
+		{Type: "assistant", Content: "Looking at the handler."},
+		{Type: "tool_call", ToolName: "bash", ToolParams: "curl -H 'Authorization: Bearer abcdef0123456789'", ToolResult: "200 OK"},
+	}
+	return New(run, messages, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestWriteMarkdownIncludesHeaderAndCollapsibleTools(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, testTranscript(), FormatMarkdown))
+
+	md := out.String()
+	require.Contains(t, md, "# Run 123: Fix auth")
+	require.Contains(t, md, "| Repository | acme/webapp |")
+	require.Contains(t, md, "| Target branch | `fix/auth` |")
+	require.Contains(t, md, "https://github.com/acme/webapp/pull/7")
+	require.Contains(t, md, "<details>\n<summary>Parameters</summary>")
+	require.Contains(t, md, "**tool: `bash`**")
+	require.NotContains(t, md, "supersecretvalue123")
+	require.NotContains(t, md, "abcdef0123456789")
+	require.NotContains(t, md, "titlesecretvalue42")
+}
+
+func TestWriteHTMLIsSelfContainedAndEscaped(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, testTranscript(), FormatHTML))
+
+	html := out.String()
+	require.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	require.Contains(t, html, "<style>")
+	require.Contains(t, html, "<details><summary>Parameters</summary>")
+	require.NotContains(t, html, "<link")
+	require.NotContains(t, html, "<script>alert(1)</script>")
+	require.NotContains(t, html, "supersecretvalue123")
+	require.NotContains(t, html, "titlesecretvalue42")
+}
+
+func TestWriteRejectsUnknownFormat(t *testing.T) {
+	err := Write(&bytes.Buffer{}, testTranscript(), "pdf")
+	require.Error(t, err)
+}
@@ -202,6 +202,7 @@ type FormData struct {
 	Issue          string
 	Prompt         string
 	Context        string
+	Model          string
 	RunType        string
 	Fields         map[string]string // Additional form fields
 	ShowContext    bool
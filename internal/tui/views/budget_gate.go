@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/budget"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// budgetCheckTimeout bounds the history refresh done before a submission.
+const budgetCheckTimeout = 30 * time.Second
+
+// budgetCheck estimates a run for repository and checks it against the
+// configured credit budgets. A nil decision means no budget is configured.
+type budgetCheck func(ctx context.Context, repository string) (*budget.Decision, error)
+
+// budgetGate holds back the first submission of a run that would breach a
+// credit budget, or whose estimate is above the warning threshold, and shows
+// the estimate. Submitting the same run again counts as confirmation, which
+// is the TUI equivalent of the CLI's --override-budget flag.
+type budgetGate struct {
+	acknowledged string
+	check        budgetCheck
+}
+
+// budgetCheckedMsg is sent instead of runCreatedMsg when the budget gate
+// holds a submission back.
+type budgetCheckedMsg struct {
+	key    string
+	notice string
+}
+
+// defaultBudgetCheck evaluates the budgets from the user config with client,
+// when it can read run logs.
+func defaultBudgetCheck(client APIClient) budgetCheck {
+	return func(ctx context.Context, repository string) (*budget.Decision, error) {
+		evalClient, ok := client.(budget.EvalClient)
+		if !ok {
+			return nil, nil
+		}
+		limits, err := budget.LoadLimits()
+		if err != nil || !budget.Configured(limits) {
+			return nil, nil
+		}
+		decision, err := budget.Evaluate(ctx, evalClient, budget.DefaultHistoryPath(), limits, repository, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &decision, nil
+	}
+}
+
+// hold runs the budget check for request and returns the notice to show
+// when the submission must be confirmed first, or "" to submit it.
+func (g *budgetGate) hold(acknowledged string, request *models.APIRunRequest) string {
+	if g.check == nil || acknowledged == request.IdempotencyKey {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), budgetCheckTimeout)
+	defer cancel()
+	decision, err := g.check(ctx, request.RepositoryName)
+	if err != nil {
+		return fmt.Sprintf("Could not check the credit budget: %v\nSubmit again to send anyway", err)
+	}
+	if decision == nil || (!decision.Blocked() && len(decision.Warnings) == 0) {
+		return ""
+	}
+	return formatBudgetNotice(*decision)
+}
+
+func formatBudgetNotice(decision budget.Decision) string {
+	lines := []string{"Estimated cost: " + decision.Estimate.String()}
+	for _, breach := range decision.Breaches {
+		lines = append(lines, "Over budget: "+breach)
+	}
+	for _, warning := range decision.Warnings {
+		lines = append(lines, "Warning: "+warning)
+	}
+	if decision.Blocked() {
+		lines = append(lines, "Submit again to send anyway")
+	} else {
+		lines = append(lines, "Submit again to confirm")
+	}
+	return strings.Join(lines, "\n")
+}
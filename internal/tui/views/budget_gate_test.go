@@ -0,0 +1,48 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package views
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/budget"
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func TestCreateRunViewConfirmsOverBudgetRuns(t *testing.T) {
+	created := 0
+	client := &MockAPIClient{CreateRunFunc: func(request *models.APIRunRequest) (*models.RunResponse, error) {
+		created++
+		return &models.RunResponse{ID: "900", Repository: request.RepositoryName}, nil
+	}}
+	view := NewCreateRunView(client, nil)
+	view.queueRun = nil
+	view.budget.check = func(context.Context, string) (*budget.Decision, error) {
+		return &budget.Decision{
+			Estimate: budget.Estimate{Credits: 12, Samples: 3, Repository: true},
+			Breaches: []string{"the daily budget of 20 credits would be exceeded"},
+		}, nil
+	}
+	msg := CustomFormSubmitMsg{Values: map[string]string{"repository": "acme/webapp", "prompt": "Add audit logging"}}
+
+	_, cmd := view.handleCustomFormSubmit(msg)
+	require.NotNil(t, cmd)
+	checked, ok := cmd().(budgetCheckedMsg)
+	require.True(t, ok)
+	view.Update(checked)
+	require.False(t, view.submitting)
+	require.Contains(t, view.budgetNotice, "Estimated cost: about 12 credits (average of the last 3 runs in this repository)")
+	require.Contains(t, view.budgetNotice, "Over budget: the daily budget of 20 credits would be exceeded")
+	require.Zero(t, created)
+
+	_, cmd = view.handleCustomFormSubmit(msg)
+	require.NotNil(t, cmd)
+	_, ok = cmd().(runCreatedMsg)
+	require.True(t, ok)
+	require.Equal(t, 1, created)
+	require.Empty(t, view.budgetNotice)
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/repobird/repobird-cli/internal/api"
 	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
+	appcache "github.com/repobird/repobird-cli/internal/cache"
 	"github.com/repobird/repobird-cli/internal/tui/cache"
 	"github.com/repobird/repobird-cli/internal/tui/components"
 	"github.com/repobird/repobird-cli/internal/tui/debug"
@@ -35,14 +37,16 @@ const (
 
 // BulkRunItem represents a single run in the bulk collection
 type BulkRunItem struct {
-	Prompt   string
-	Title    string
-	Target   string
-	Context  string
-	Selected bool
-	Status   RunStatus
-	Error    string
-	FileHash string
+	Prompt  string
+	Title   string
+	Target  string
+	Context string
+	// Overrides are the run's settings that replace the batch settings.
+	Overrides bulk.RunOverrides
+	Selected  bool
+	Status    RunStatus
+	Error     string
+	FileHash  string
 }
 
 // RunStatus represents the status of a run
@@ -95,6 +99,13 @@ type BulkView struct {
 	error                  error
 	showSubmissionPrompt   bool // Show submission confirmation prompt in status line
 	submissionPromptActive bool // Whether the submission prompt is active
+	secrets                secretGate
+
+	// Chunked submission state for selections above bulk.MaxBulkBatchSize
+	chunkRequest   *dto.BulkRunRequest
+	manifest       *appcache.BulkManifest
+	chunkResponses []*dto.BulkRunResponse
+	chunkOffsets   []int
 
 	// Components
 	spinner    spinner.Model
@@ -270,6 +281,9 @@ func (v *BulkView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case bulkSubmittedMsg:
 		return v.handleBulkSubmittedMsg(msg)
 
+	case bulkChunkSubmittedMsg:
+		return v.handleBulkChunkSubmittedMsg(msg)
+
 	case bulkProgressMsg:
 		// Progress update received - this should not happen anymore as we navigate immediately
 		debug.LogToFilef("⚠️ BULK: Received bulkProgressMsg but should have navigated to results\n")
@@ -476,6 +490,47 @@ func (v *BulkView) handleBulkSubmittedMsg(msg bulkSubmittedMsg) (tea.Model, tea.
 	return v, v.navigateToResults(msg)
 }
 
+// handleBulkChunkSubmittedMsg records a submitted chunk in the manifest and
+// submits the next one, or finishes the submission once none are left.
+func (v *BulkView) handleBulkChunkSubmittedMsg(msg bulkChunkSubmittedMsg) (tea.Model, tea.Cmd) {
+	var batchID string
+	var runIDs []int
+	if msg.resp != nil {
+		batchID, runIDs = msg.resp.Data.BatchID, msg.resp.Data.RunIDs()
+	}
+	v.manifest.RecordChunk(msg.index, batchID, runIDs, msg.err)
+	if err := appcache.SaveBulkManifest(appcache.BulkManifestDir(), v.manifest); err != nil {
+		debug.LogToFilef("⚠️ BULK: Failed to save super-batch manifest: %v\n", err)
+	}
+
+	if msg.err == nil {
+		recordBulkBatch(msg.req, msg.resp, v.manifest.ID)
+		v.chunkResponses = append(v.chunkResponses, msg.resp)
+		v.chunkOffsets = append(v.chunkOffsets, v.manifest.Chunks[msg.index].Start)
+		if next := v.submitNextChunk(); next != nil {
+			return v, next
+		}
+	}
+
+	results := bulkRunResults(bulk.MergeResponses(v.chunkResponses, v.chunkOffsets))
+	if msg.err != nil {
+		// Runs of the failed and remaining chunks were not submitted; submitting
+		// the same runs again resumes with them.
+		message := fmt.Sprintf("%v (submit again to resume super-batch %s)", msg.err, v.manifest.ID)
+		for _, index := range v.manifest.Pending() {
+			chunk := v.manifest.Chunks[index]
+			for _, run := range v.chunkRequest.Runs[chunk.Start:chunk.End] {
+				results = append(results, BulkRunResult{Title: run.Prompt, Status: "failed", Error: message})
+			}
+		}
+	}
+
+	return v.handleBulkSubmittedMsg(bulkSubmittedMsg{
+		batchID: v.manifest.ID,
+		results: results,
+	})
+}
+
 // handleErrMsg handles error messages
 func (v *BulkView) handleErrMsg(msg errMsg) (tea.Model, tea.Cmd) {
 	debug.LogToFilef("DEBUG: BulkView - error occurred: %v\n", msg.err)
@@ -1263,6 +1318,7 @@ func (v *BulkView) renderRunList() string {
 	// Run items
 	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
 	normalStyle := lipgloss.NewStyle()
+	overrideStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
 
 	if len(v.runs) == 0 {
 		content.WriteString(lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("243")).Render("No runs loaded yet. Press f to add files."))
@@ -1294,6 +1350,13 @@ func (v *BulkView) renderRunList() string {
 				content.WriteString(normalStyle.Render("  " + line))
 			}
 			content.WriteString("\n")
+			if overrides := run.Overrides.Summary(); len(overrides) > 0 {
+				summary := strings.Join(overrides, " ")
+				if maxTitleLen > 0 && len(summary) > maxTitleLen {
+					summary = summary[:maxTitleLen-3] + "..."
+				}
+				content.WriteString(overrideStyle.Render("      "+summary) + "\n")
+			}
 		}
 	}
 
@@ -1496,6 +1559,14 @@ func (v *BulkView) renderRunEdit() string {
 			fmt.Sprintf("Title: %s", run.Title),
 			fmt.Sprintf("Target: %s", run.Target),
 			fmt.Sprintf("Prompt: %s", run.Prompt),
+		)
+		if overrides := run.Overrides.Summary(); len(overrides) > 0 {
+			contentLines = append(contentLines, "", "Overrides:")
+			for _, override := range overrides {
+				contentLines = append(contentLines, "  "+override)
+			}
+		}
+		contentLines = append(contentLines,
 			"",
 			"Edit mode not yet implemented.",
 			"Press ESC to return to list.",
@@ -1544,6 +1615,17 @@ func (v *BulkView) renderProgress() string {
 			"",
 			fmt.Sprintf("Batch: %s", v.batchTitle),
 		)
+		if v.manifest != nil {
+			created := 0
+			for _, resp := range v.chunkResponses {
+				created += len(resp.Data.Successful)
+			}
+			contentLines = append(contentLines,
+				fmt.Sprintf("Super-batch: %s", v.manifest.ID),
+				fmt.Sprintf("Batches submitted: %d/%d", len(v.manifest.BatchIDs()), len(v.manifest.Chunks)),
+				fmt.Sprintf("Runs created: %d/%d", created, v.manifest.TotalRuns),
+			)
+		}
 	} else if v.batchID != "" {
 		contentLines = append(contentLines,
 			fmt.Sprintf("Batch ID: %s", v.batchID),
@@ -10,7 +10,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/repobird/repobird-cli/internal/api/dto"
 	"github.com/repobird/repobird-cli/internal/bulk"
+	appcache "github.com/repobird/repobird-cli/internal/cache"
 	"github.com/repobird/repobird-cli/internal/tui/cache"
+	"github.com/repobird/repobird-cli/internal/tui/debug"
+	"github.com/repobird/repobird-cli/internal/utils"
 )
 
 // Command functions for bulk view operations
@@ -28,12 +31,13 @@ func (v *BulkView) loadFiles(files []string) (tea.Model, tea.Cmd) {
 		var runs []BulkRunItem
 		for _, run := range bulkConfig.Runs {
 			runs = append(runs, BulkRunItem{
-				Prompt:   run.Prompt,
-				Title:    run.Title,
-				Target:   run.Target,
-				Context:  run.Context,
-				Selected: true,
-				Status:   StatusPending,
+				Prompt:    run.Prompt,
+				Title:     run.Title,
+				Target:    run.Target,
+				Context:   run.Context,
+				Overrides: run.RunOverrides,
+				Selected:  true,
+				Status:    StatusPending,
 			})
 		}
 
@@ -48,91 +52,170 @@ func (v *BulkView) loadFiles(files []string) (tea.Model, tea.Cmd) {
 	}
 }
 
-// submitBulkRuns submits selected runs to the API
+// submitBulkRuns submits selected runs to the API. Selections larger than
+// bulk.MaxBulkBatchSize are submitted as a chunked super-batch.
 func (v *BulkView) submitBulkRuns() tea.Cmd {
-	v.submitting = true
+	if err := v.secrets.check(scanSelectedBulkRuns(v.runs)); err != nil {
+		return func() tea.Msg { return errMsg{err} }
+	}
 
-	return func() tea.Msg {
-		// Filter selected runs
-		var selectedRuns []BulkRunItem
-		for _, run := range v.runs {
-			if run.Selected {
-				selectedRuns = append(selectedRuns, run)
-			}
+	// Filter selected runs
+	var selectedRuns []BulkRunItem
+	for _, run := range v.runs {
+		if run.Selected {
+			selectedRuns = append(selectedRuns, run)
 		}
+	}
 
-		if len(selectedRuns) == 0 {
-			return errMsg{fmt.Errorf("no runs selected")}
-		}
+	if len(selectedRuns) == 0 {
+		return func() tea.Msg { return errMsg{fmt.Errorf("no runs selected")} }
+	}
+	v.submitting = true
 
-		// Generate file hashes
-		var runItems []dto.RunItem
-
-		for i, run := range selectedRuns {
-			// Create hash from run content
-			hashContent := fmt.Sprintf("%s-%s-%s-%s-%d",
-				v.repository,
-				run.Prompt,
-				run.Target,
-				run.Context,
-				i,
-			)
-			hash := cache.CalculateStringHash(hashContent)
-			run.FileHash = hash
-
-			runItems = append(runItems, dto.RunItem{
-				Prompt:   run.Prompt,
-				Title:    run.Title,
-				Target:   run.Target,
-				Context:  run.Context,
-				FileHash: hash,
-			})
-		}
+	// Generate file hashes
+	var runItems []dto.RunItem
+
+	for i, run := range selectedRuns {
+		// Create hash from run content
+		hashContent := fmt.Sprintf("%s-%s-%s-%s-%d",
+			v.repository,
+			run.Prompt,
+			run.Target,
+			run.Context,
+			i,
+		)
+		hash := cache.CalculateStringHash(hashContent)
+		run.FileHash = hash
+
+		item := bulk.BulkRunConfig{
+			Prompt:       run.Prompt,
+			Title:        run.Title,
+			Target:       run.Target,
+			Context:      run.Context,
+			RunOverrides: run.Overrides,
+		}.RunItem()
+		item.FileHash = hash
+		runItems = append(runItems, item)
+	}
 
-		// Create bulk request
-		req := &dto.BulkRunRequest{
-			RepositoryName: v.repository,
-			RepoID:         v.repoID,
-			RunType:        v.runType,
-			SourceBranch:   v.sourceBranch,
-			BatchTitle:     v.batchTitle,
-			Force:          v.force,
-			Runs:           runItems,
-			Options: dto.BulkOptions{
-				Parallel: 5,
-			},
-		}
+	// Create bulk request
+	req := &dto.BulkRunRequest{
+		RepositoryName: v.repository,
+		RepoID:         v.repoID,
+		RunType:        v.runType,
+		SourceBranch:   v.sourceBranch,
+		BatchTitle:     v.batchTitle,
+		Force:          v.force,
+		Runs:           runItems,
+		Options: dto.BulkOptions{
+			Parallel: 5,
+		},
+	}
 
+	if len(runItems) > bulk.MaxBulkBatchSize {
+		return v.startChunkedSubmission(req)
+	}
+
+	return func() tea.Msg {
 		// Submit to API
 		ctx := context.Background()
 		resp, err := v.client.CreateBulkRuns(ctx, req)
 		if err != nil {
 			return bulkSubmittedMsg{err: err}
 		}
-
-		// Convert response to results
-		var results []BulkRunResult
-		for _, run := range resp.Data.Successful {
-			results = append(results, BulkRunResult{
-				ID:     run.ID,
-				Title:  run.Title,
-				Status: run.Status,
-				URL:    "",
-			})
-		}
-
-		for _, runErr := range resp.Data.Failed {
-			results = append(results, BulkRunResult{
-				Title:  runErr.Prompt,
-				Status: "failed",
-				Error:  runErr.Message,
-			})
-		}
+		recordBulkBatch(req, resp, "")
 
 		return bulkSubmittedMsg{
 			batchID: resp.Data.BatchID,
-			results: results,
+			results: bulkRunResults(resp),
 			err:     nil,
 		}
 	}
 }
+
+// startChunkedSubmission records the super-batch manifest, resuming an
+// interrupted submission of the same runs, and submits the first pending chunk.
+func (v *BulkView) startChunkedSubmission(req *dto.BulkRunRequest) tea.Cmd {
+	runHashes := make([]string, len(req.Runs))
+	for i, run := range req.Runs {
+		runHashes[i] = run.FileHash
+	}
+	dir := appcache.BulkManifestDir()
+	manifest := appcache.NewBulkManifest(req.RepositoryName, req.BatchTitle, runHashes, bulk.MaxBulkBatchSize)
+	if resumed, err := appcache.FindIncompleteBulkManifest(dir, manifest.ConfigHash); err == nil && resumed != nil {
+		debug.LogToFilef("📦 BULK: Resuming super-batch %s (%d/%d batches submitted)\n", resumed.ID, len(resumed.BatchIDs()), len(resumed.Chunks))
+		manifest = resumed
+	}
+	if err := appcache.SaveBulkManifest(dir, manifest); err != nil {
+		v.submitting = false
+		return func() tea.Msg { return errMsg{err} }
+	}
+
+	v.mode = ModeProgress
+	v.chunkRequest = req
+	v.manifest = manifest
+	v.chunkResponses = nil
+	v.chunkOffsets = nil
+	return v.submitNextChunk()
+}
+
+// submitNextChunk submits the first chunk of the manifest that has not been
+// submitted yet.
+func (v *BulkView) submitNextChunk() tea.Cmd {
+	pending := v.manifest.Pending()
+	if len(pending) == 0 {
+		return nil
+	}
+	index := pending[0]
+	chunk := v.manifest.Chunks[index]
+	req := bulk.ChunkRequest(v.chunkRequest, chunk.Start, chunk.End, index+1, len(v.manifest.Chunks))
+	client := v.client
+
+	return func() tea.Msg {
+		resp, err := client.CreateBulkRuns(context.Background(), req)
+		return bulkChunkSubmittedMsg{index: index, req: req, resp: resp, err: err}
+	}
+}
+
+// recordBulkBatch adds a submitted batch to the local batch ledger used by
+// 'repobird bulk status|retry-failed'.
+func recordBulkBatch(req *dto.BulkRunRequest, resp *dto.BulkRunResponse, superBatchID string) {
+	record := appcache.NewBulkBatchRecord(req, resp)
+	record.SuperBatchID = superBatchID
+	if err := appcache.SaveBulkBatch(appcache.BulkLedgerDir(), record); err != nil {
+		debug.LogToFilef("⚠️ BULK: Failed to record batch in the ledger: %v\n", err)
+	}
+}
+
+// bulkRunResults converts a bulk response to per-run results.
+func bulkRunResults(resp *dto.BulkRunResponse) []BulkRunResult {
+	var results []BulkRunResult
+	for _, run := range resp.Data.Successful {
+		results = append(results, BulkRunResult{
+			ID:     run.ID,
+			Title:  run.Title,
+			Status: run.Status,
+			URL:    "",
+		})
+	}
+
+	for _, runErr := range resp.Data.Failed {
+		results = append(results, BulkRunResult{
+			Title:  runErr.Prompt,
+			Status: "failed",
+			Error:  runErr.Message,
+		})
+	}
+	return results
+}
+
+// scanSelectedBulkRuns scans the prompt and context of every selected run.
+func scanSelectedBulkRuns(runs []BulkRunItem) []utils.SecretFinding {
+	config := &bulk.BulkConfig{}
+	for _, run := range runs {
+		if run.Selected {
+			config.Runs = append(config.Runs, bulk.BulkRunConfig{Prompt: run.Prompt, Context: run.Context, RunOverrides: run.Overrides})
+		}
+	}
+	return bulk.ScanSecrets(config)
+}
@@ -35,6 +35,15 @@ type bulkSubmittedMsg struct {
 	err     error
 }
 
+// bulkChunkSubmittedMsg is sent when one chunk of a chunked submission has
+// been submitted
+type bulkChunkSubmittedMsg struct {
+	index int
+	req   *dto.BulkRunRequest
+	resp  *dto.BulkRunResponse
+	err   error
+}
+
 // bulkProgressMsg is sent with progress updates for bulk operations
 type bulkProgressMsg struct {
 	batchID    string
@@ -5,11 +5,16 @@ package views
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/repobird/repobird-cli/internal/api"
 	"github.com/repobird/repobird-cli/internal/api/dto"
+	"github.com/repobird/repobird-cli/internal/bulk"
 	"github.com/repobird/repobird-cli/internal/tui/cache"
 	"github.com/repobird/repobird-cli/internal/tui/components"
 	"github.com/repobird/repobird-cli/internal/tui/messages"
@@ -721,3 +726,61 @@ func TestBulkViewDimensions(t *testing.T) {
 		assert.NotEmpty(t, output)
 	}
 }
+
+func TestBulkViewShowsRunOverrides(t *testing.T) {
+	view := NewBulkView(&api.Client{}, cache.NewSimpleCache())
+	view.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view.runs = []BulkRunItem{
+		{Title: "Bump deps", Selected: true},
+		{Title: "Split auth", Selected: true, Overrides: bulk.RunOverrides{BaseBranch: "develop", Model: "pro"}},
+	}
+
+	view.mode = ModeRunList
+	assert.Contains(t, view.renderRunList(), "baseBranch=develop model=pro")
+
+	view.mode = ModeRunEdit
+	view.selectedRun = 1
+	output := view.renderRunEdit()
+	assert.Contains(t, output, "Overrides:")
+	assert.Contains(t, output, "model=pro")
+}
+
+func TestBulkViewSubmitsLargeSelectionInChunks(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var titles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dto.BulkRunRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		titles = append(titles, req.BatchTitle)
+		resp := dto.BulkRunResponse{Data: dto.BulkRunData{BatchID: fmt.Sprintf("batch-%d", len(titles))}}
+		for i := range req.Runs {
+			resp.Data.Successful = append(resp.Data.Successful, dto.RunCreatedItem{ID: i + 1, RequestIndex: i})
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	view := NewBulkView(api.NewClient("test-key", server.URL, false), cache.NewSimpleCache())
+	view.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view.repository = "org/repo"
+	view.batchTitle = "Nightly"
+	for i := 0; i < bulk.MaxBulkBatchSize+5; i++ {
+		view.runs = append(view.runs, BulkRunItem{Prompt: fmt.Sprintf("Task %d", i+1), Selected: true})
+	}
+
+	cmd := view.submitBulkRuns()
+	assert.Equal(t, ModeProgress, view.mode)
+	assert.Contains(t, view.renderProgress(), "Batches submitted: 0/2")
+
+	_, cmd = view.Update(cmd())
+	assert.Contains(t, view.renderProgress(), "Batches submitted: 1/2")
+	assert.Contains(t, view.renderProgress(), "Runs created: 40/45")
+
+	_, cmd = view.Update(cmd())
+	assert.NotNil(t, cmd)
+	assert.False(t, view.submitting)
+	assert.Equal(t, view.manifest.ID, view.batchID)
+	assert.Len(t, view.results, 45)
+	assert.Equal(t, []string{"Nightly (part 1/2)", "Nightly (part 2/2)"}, titles)
+}
@@ -10,11 +10,16 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/repobird/repobird-cli/internal/errors"
+	"github.com/repobird/repobird-cli/internal/idempotency"
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
+	"github.com/repobird/repobird-cli/internal/templates"
 	tuicache "github.com/repobird/repobird-cli/internal/tui/cache"
 	"github.com/repobird/repobird-cli/internal/tui/components"
 	"github.com/repobird/repobird-cli/internal/tui/debug"
 	"github.com/repobird/repobird-cli/internal/tui/messages"
+	"github.com/repobird/repobird-cli/internal/utils"
 )
 
 // CreateRunView implements a form-based view for creating new runs
@@ -29,6 +34,21 @@ type CreateRunView struct {
 	// State
 	submitting bool
 	error      error
+	secrets    secretGate
+	budget     budgetGate
+
+	// Template picker, open while non-nil
+	picker        *templatePicker
+	loadTemplates func() (*templates.Library, error)
+
+	// queueRun keeps a run that could not reach the API in the outbox;
+	// queued describes the last run queued that way.
+	queueRun func(models.APIRunRequest) (*outbox.Entry, bool, error)
+	queued   string
+
+	// budgetNotice is the cost estimate shown when the budget gate holds a
+	// submission back.
+	budgetNotice string
 }
 
 // NewCreateRunView creates a new create run view with proper dependencies
@@ -40,7 +60,11 @@ func NewCreateRunView(client APIClient, cache *tuicache.SimpleCache) *CreateRunV
 		cache:  cache,
 		layout: components.NewWindowLayout(80, 24), // Default dimensions
 		form:   NewCustomCreateForm(),              // Use custom form
+
+		loadTemplates: templates.LoadDefault,
+		queueRun:      outbox.New(outbox.DefaultDir()).Add,
 	}
+	v.budget.check = defaultBudgetCheck(client)
 
 	return v
 }
@@ -86,6 +110,7 @@ func (v *CreateRunView) Init() tea.Cmd {
 		v.form.SetValue("target", savedFormData.Target)
 		v.form.SetValue("prompt", savedFormData.Prompt)
 		v.form.SetValue("context", savedFormData.Context)
+		v.form.SetValue("model", savedFormData.Model)
 
 		// Restore the runtype
 		if savedFormData.RunType != "" {
@@ -137,6 +162,12 @@ func (v *CreateRunView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case runCreatedMsg:
 		return v.handleRunCreated(msg)
 
+	case budgetCheckedMsg:
+		v.submitting = false
+		v.budget.acknowledged = msg.key
+		v.budgetNotice = msg.notice
+		return v, nil
+
 	default:
 		// Delegate to form component
 		newForm, cmd := v.form.Update(msg)
@@ -231,6 +262,12 @@ func (v *CreateRunView) handleFormSubmit(msg components.FormSubmitMsg) (tea.Mode
 		Agent:          "opencode",
 	}
 
+	if err := v.secrets.check(utils.ScanRunInputs(request.Prompt, request.Context, request.Files)); err != nil {
+		v.submitting = false
+		v.error = err
+		return v, nil
+	}
+
 	// Submit asynchronously
 	return v, v.submitRunCmd(request)
 }
@@ -262,6 +299,13 @@ func (v *CreateRunView) handleCustomFormSubmit(msg CustomFormSubmitMsg) (tea.Mod
 		Context:        msg.Values["context"],
 		RunType:        models.RunType(runType), // Use the selected run type
 		Agent:          "opencode",
+		OpenCodeModel:  msg.Values["model"],
+	}
+
+	if err := v.secrets.check(utils.ScanRunInputs(request.Prompt, request.Context, request.Files)); err != nil {
+		v.submitting = false
+		v.error = err
+		return v, nil
 	}
 
 	// Submit asynchronously
@@ -272,6 +316,14 @@ func (v *CreateRunView) handleCustomFormSubmit(msg CustomFormSubmitMsg) (tea.Mod
 func (v *CreateRunView) handleRunCreated(msg runCreatedMsg) (tea.Model, tea.Cmd) {
 	v.submitting = false
 
+	if msg.err != nil && errors.IsNetworkError(msg.err) && msg.request != nil && v.queueRun != nil {
+		if entry, _, err := v.queueRun(*msg.request); err == nil {
+			debug.LogToFilef("📥 CREATE VIEW: API unreachable, queued run %s", entry.ID)
+			v.queued = fmt.Sprintf("API unreachable: run queued as %s. It is submitted after the next successful command, or with 'repobird queue flush'.", entry.ID)
+			v.cache.ClearFormData()
+			return v, nil
+		}
+	}
 	if msg.err != nil {
 		v.error = msg.err
 		debug.LogToFilef("❌ CREATE VIEW: Run creation failed: %v", msg.err)
@@ -324,8 +376,12 @@ func (v *CreateRunView) View() string {
 	content.WriteString(titleStyle.Render("Create New Run"))
 	content.WriteString("\n\n")
 
-	// Add form
-	content.WriteString(v.form.View())
+	// Add form, or the template picker while it is open
+	if v.picker != nil {
+		content.WriteString(v.renderTemplatePicker())
+	} else {
+		content.WriteString(v.form.View())
+	}
 
 	// Add submission status
 	if v.submitting {
@@ -336,6 +392,14 @@ func (v *CreateRunView) View() string {
 		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("red"))
 		content.WriteString("\n")
 		content.WriteString(errorStyle.Render(fmt.Sprintf("❌ Error: %v", v.error)))
+	} else if v.queued != "" {
+		queuedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("yellow"))
+		content.WriteString("\n")
+		content.WriteString(queuedStyle.Render("📥 " + v.queued))
+	} else if v.budgetNotice != "" {
+		budgetStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("yellow"))
+		content.WriteString("\n")
+		content.WriteString(budgetStyle.Render("💰 " + v.budgetNotice))
 	}
 
 	// Wrap in styled box
@@ -357,12 +421,15 @@ func (v *CreateRunView) renderStatusLine(layoutName string) string {
 	var mode string
 	var helpText string
 
-	if v.form.IsInsertMode() {
+	if v.picker != nil {
+		mode = "TEMPLATE"
+		helpText = "[j/k/↑↓]select [enter]apply [esc]cancel"
+	} else if v.form.IsInsertMode() {
 		mode = "INPUT"
 		helpText = "[esc]normal [tab]next [shift+tab]prev [ctrl+s]submit"
 	} else {
 		mode = ""
-		helpText = "[i]insert [d]delete [c]change [j/k/↑↓/tab]nav [h]back [q]dashboard [ctrl+s]submit"
+		helpText = "[i]insert [d]delete [c]change [t]template [j/k/↑↓/tab]nav [h]back [q]dashboard [ctrl+s]submit"
 	}
 
 	// Format left content consistently
@@ -396,6 +463,7 @@ func (v *CreateRunView) saveFormData() {
 		Target:     values["target"],
 		Prompt:     values["prompt"],
 		Context:    values["context"],
+		Model:      values["model"],
 		RunType:    runType, // Save the selected run type
 		Fields:     fields,  // Store focus index and other metadata
 	}
@@ -404,18 +472,34 @@ func (v *CreateRunView) saveFormData() {
 	debug.LogToFilef("💾 CREATE VIEW: Form data saved to cache (focus: %d, runtype: %s)", v.form.GetFocusIndex(), runType)
 }
 
-// submitRunCmd creates a command to submit the run asynchronously
+// submitRunCmd creates a command to submit the run asynchronously. The
+// request gets an idempotency key first, so a run queued after a network
+// error is not created twice if the first attempt did reach the API.
 func (v *CreateRunView) submitRunCmd(request *models.APIRunRequest) tea.Cmd {
+	v.queued = ""
+	v.budgetNotice = ""
+	if request.IdempotencyKey == "" {
+		request.IdempotencyKey = idempotency.BuildRunKey(idempotency.RunIdentity{
+			Repository: request.RepositoryName,
+			Prompt:     request.Prompt,
+			RunType:    string(request.RunType),
+		})
+	}
+	acknowledged := v.budget.acknowledged
 	return func() tea.Msg {
+		if notice := v.budget.hold(acknowledged, request); notice != "" {
+			return budgetCheckedMsg{key: request.IdempotencyKey, notice: notice}
+		}
 		run, err := v.client.CreateRunAPI(request)
-		return runCreatedMsg{run: run, err: err}
+		return runCreatedMsg{run: run, err: err, request: request}
 	}
 }
 
 // runCreatedMsg is sent when run creation completes
 type runCreatedMsg struct {
-	run *models.RunResponse
-	err error
+	run     *models.RunResponse
+	err     error
+	request *models.APIRunRequest
 }
 
 // Backward compatibility constructor - redirects to proper constructor
@@ -465,6 +549,16 @@ func (v *CreateRunView) HandleKey(keyMsg tea.KeyMsg) (handled bool, model tea.Mo
 	keyString := keyMsg.String()
 	debug.LogToFilef("🔑 CREATE VIEW HandleKey: key='%s', insertMode=%v", keyString, v.form.IsInsertMode())
 
+	// The template picker captures all keys while open
+	if v.picker != nil {
+		v.handlePickerKey(keyString)
+		return true, v, nil
+	}
+	if keyString == "t" && !v.form.IsInsertMode() {
+		v.openTemplatePicker()
+		return true, v, nil
+	}
+
 	// IMPORTANT: Handle ESC specially to prevent navigation
 	if keyString == "esc" {
 		if v.form.IsInsertMode() {
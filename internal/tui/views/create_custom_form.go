@@ -110,6 +110,14 @@ func NewCustomCreateForm() *CustomCreateForm {
 			Required:    false,
 			Icon:        "📋",
 		},
+		{
+			Name:        "model",
+			Label:       "Model",
+			Type:        "text",
+			Placeholder: "model ID or profile (blank for default)",
+			Required:    false,
+			Icon:        "🧠",
+		},
 		{
 			Name:    "runtype",
 			Label:   "Run Type",
@@ -191,6 +191,7 @@ func TestCustomCreateForm_FieldIcons(t *testing.T) {
 		"target":     "🎯",
 		"prompt":     "💭",
 		"context":    "📋",
+		"model":      "🧠",
 		"runtype":    "⚙️",
 		"submit":     "🚀",
 	}
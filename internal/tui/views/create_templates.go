@@ -0,0 +1,103 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/repobird/repobird-cli/internal/templates"
+	"github.com/repobird/repobird-cli/internal/tui/debug"
+)
+
+// templatePicker lists prompt templates for filling the create form.
+type templatePicker struct {
+	templates []*templates.Template
+	selected  int
+}
+
+// openTemplatePicker loads the template library and shows the picker.
+func (v *CreateRunView) openTemplatePicker() {
+	library, err := v.loadTemplates()
+	if err != nil {
+		v.error = err
+		return
+	}
+	list := library.List()
+	if len(list) == 0 {
+		v.error = fmt.Errorf("no templates found; add Markdown templates to %s or %s", templates.UserDir(), templates.ProjectDir)
+		return
+	}
+	v.error = nil
+	v.picker = &templatePicker{templates: list}
+}
+
+// handlePickerKey handles keys while the template picker is open. The picker
+// captures every key so form and navigation bindings stay inactive.
+func (v *CreateRunView) handlePickerKey(keyString string) {
+	switch keyString {
+	case "j", "down", "tab":
+		v.picker.selected = (v.picker.selected + 1) % len(v.picker.templates)
+	case "k", "up", "shift+tab":
+		v.picker.selected = (v.picker.selected - 1 + len(v.picker.templates)) % len(v.picker.templates)
+	case "enter":
+		v.applyTemplate(v.picker.templates[v.picker.selected])
+		v.picker = nil
+	case "esc", "q", "t":
+		v.picker = nil
+	}
+}
+
+// applyTemplate fills the form from a template preview. Required variables
+// without defaults remain as <name> placeholders to edit in the form.
+func (v *CreateRunView) applyTemplate(tmpl *templates.Template) {
+	preview, err := tmpl.Preview()
+	if err != nil {
+		v.error = err
+		return
+	}
+	debug.LogToFilef("📄 CREATE VIEW: Applying template %s", tmpl.Name)
+
+	source := preview.BaseBranch
+	if source == "" {
+		source = preview.Source
+	}
+	for field, value := range map[string]string{
+		"title":      preview.Title,
+		"repository": preview.Repository,
+		"source":     source,
+		"target":     preview.Target,
+		"context":    preview.Context,
+		"model":      preview.Model,
+	} {
+		if value != "" {
+			v.form.SetValue(field, value)
+		}
+	}
+	v.form.SetValue("prompt", preview.Prompt)
+	v.saveFormData()
+}
+
+// renderTemplatePicker renders the template list.
+func (v *CreateRunView) renderTemplatePicker() string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Select a template"))
+	b.WriteString("\n\n")
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("63")).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for i, tmpl := range v.picker.templates {
+		line := tmpl.Name
+		if tmpl.Description != "" {
+			line += mutedStyle.Render(" - " + tmpl.Description)
+		}
+		if i == v.picker.selected {
+			b.WriteString(selectedStyle.Render("▸ ") + line)
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
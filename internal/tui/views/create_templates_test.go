@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/repobird/repobird-cli/internal/templates"
+	"github.com/repobird/repobird-cli/internal/tui/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTemplateTestView(t *testing.T) *CreateRunView {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	writeFile("docs.md", "Update the README.")
+	writeFile("fix-issue.md", `---
+description: Fix an issue
+title: "Fix #{{.issue}}"
+baseBranch: develop
+variables:
+  - name: issue
+    required: true
+---
+Fix issue #{{.issue}}.`)
+
+	view := NewCreateRunView(&MockCreateAPIClient{}, cache.NewSimpleCache())
+	view.loadTemplates = func() (*templates.Library, error) {
+		return templates.Load(dir, "", nil)
+	}
+	return view
+}
+
+func TestCreateRunView_TemplatePicker(t *testing.T) {
+	view := newTemplateTestView(t)
+	view.form.SetInsertMode(false)
+
+	handled, _, _ := view.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	assert.True(t, handled)
+	require.NotNil(t, view.picker)
+	assert.Contains(t, view.View(), "fix-issue")
+
+	// Picker keys must not reach the form
+	view.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	assert.Equal(t, 1, view.picker.selected)
+	view.HandleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, view.picker)
+
+	values := view.form.GetValues()
+	assert.Equal(t, "Fix issue #<issue>.", values["prompt"])
+	assert.Equal(t, "Fix #<issue>", values["title"])
+	assert.Equal(t, "develop", values["source"])
+}
+
+func TestCreateRunView_TemplatePickerCancel(t *testing.T) {
+	view := newTemplateTestView(t)
+	view.form.SetValue("prompt", "keep me")
+
+	view.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.NotNil(t, view.picker)
+	handled, _, _ := view.HandleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.True(t, handled)
+	assert.Nil(t, view.picker)
+	assert.Equal(t, "keep me", view.form.GetValues()["prompt"])
+}
+
+func TestCreateRunView_TemplateKeyTypesInInsertMode(t *testing.T) {
+	view := newTemplateTestView(t)
+	view.form.SetInsertMode(true)
+
+	handled, _, _ := view.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	assert.False(t, handled)
+	assert.Nil(t, view.picker)
+}
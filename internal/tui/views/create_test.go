@@ -5,10 +5,13 @@ package views
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	rberrors "github.com/repobird/repobird-cli/internal/errors"
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
 	"github.com/repobird/repobird-cli/internal/tui/cache"
 	"github.com/repobird/repobird-cli/internal/tui/messages"
 	"github.com/stretchr/testify/assert"
@@ -376,3 +379,31 @@ func TestCreateRunView_FormStatePersistence(t *testing.T) {
 	assert.Equal(t, "test/repo", values["repository"])
 	assert.Equal(t, "Test prompt", values["prompt"])
 }
+
+func TestCreateRunViewQueuesRunWhenOffline(t *testing.T) {
+	view := NewCreateRunView(new(MockCreateAPIClient), cache.NewSimpleCache())
+	box := outbox.New(t.TempDir())
+	view.queueRun = box.Add
+
+	request := &models.APIRunRequest{Prompt: "Fix the login redirect", RepositoryName: "acme/web", RunType: models.RunTypeRun}
+	cmd := view.submitRunCmd(request)
+	assert.NotNil(t, cmd)
+	assert.NotEmpty(t, request.IdempotencyKey, "runs get an idempotency key before the first attempt")
+
+	view.handleRunCreated(runCreatedMsg{
+		err:     &rberrors.NetworkError{Err: errors.New("no route to host"), Operation: "POST /api/v1/runs"},
+		request: request,
+	})
+	assert.Nil(t, view.error)
+	assert.Contains(t, view.View(), "run queued as "+outbox.EntryID(request.IdempotencyKey))
+
+	entries, err := box.List()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, *request, entries[0].Request)
+	}
+
+	// Other errors are shown and nothing more is queued.
+	view.handleRunCreated(runCreatedMsg{err: errors.New("validation failed"), request: request})
+	assert.EqualError(t, view.error, "validation failed")
+}
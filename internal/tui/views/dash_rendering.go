@@ -611,6 +611,10 @@ func (d *DashboardView) renderStatusLine(layoutName string) string {
 		debug.LogToFilef("🔄 STATUS: Refresh state - dataInfo empty but spinner should animate 🔄\n")
 	}
 
+	if d.queuedRuns > 0 {
+		dataInfo = fmt.Sprintf("📥 %d queued locally", d.queuedRuns)
+	}
+
 	// Format left content consistently
 	leftContent := formatter.FormatViewName()
 
@@ -15,6 +15,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/repobird/repobird-cli/internal/models"
+	"github.com/repobird/repobird-cli/internal/outbox"
 	"github.com/repobird/repobird-cli/internal/tui/cache"
 	"github.com/repobird/repobird-cli/internal/tui/components"
 	"github.com/repobird/repobird-cli/internal/tui/debug"
@@ -64,6 +65,10 @@ type DashboardView struct {
 	refreshInterval time.Duration
 	detailsCache    map[string]*models.RunResponse // Cached run details
 
+	// Runs waiting in the local outbox, shown in the status line
+	queuedRuns      int
+	countQueuedRuns func() (int, error)
+
 	// User info
 	userInfo *models.UserInfo
 	userID   *int // User ID for cache isolation
@@ -194,6 +199,7 @@ func NewDashboardView(client APIClient, cache *cache.SimpleCache) *DashboardView
 		repoViewport:     viewport.New(0, 0), // Will be sized in Update
 		runsViewport:     viewport.New(0, 0),
 		detailsViewport:  viewport.New(0, 0),
+		countQueuedRuns:  outbox.New(outbox.DefaultDir()).Len,
 	}
 
 	// Note: cache is already set from parameter, no need to create a new one
@@ -632,6 +638,9 @@ func (d *DashboardView) handleDataLoadSuccess(msg dashboardDataLoadedMsg) (tea.M
 	d.allRuns = msg.allRuns
 	d.detailsCache = msg.detailsCache
 	d.lastDataRefresh = time.Now()
+	if queued, err := d.countQueuedRuns(); err == nil {
+		d.queuedRuns = queued
+	}
 
 	d.updateViewportSizes()
 
@@ -163,3 +163,17 @@ func TestDashboardViewStatusLineNoEllipsisForShortText(t *testing.T) {
 		}
 	}
 }
+
+func TestDashboardStatusLineShowsLocallyQueuedRuns(t *testing.T) {
+	view := NewDashboardView(nil, cache.NewSimpleCache())
+	view.width = 120
+	view.height = 30
+	view.countQueuedRuns = func() (int, error) { return 2, nil }
+
+	_, _ = view.handleDataLoadSuccess(dashboardDataLoadedMsg{})
+	view.loading, view.initializing = false, false
+
+	if line := view.renderStatusLine("DASH"); !strings.Contains(line, "2 queued locally") {
+		t.Errorf("expected the status line to show the locally queued runs, got %q", line)
+	}
+}
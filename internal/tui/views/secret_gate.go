@@ -0,0 +1,40 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/repobird/repobird-cli/internal/utils"
+)
+
+// secretGate blocks the first submission that contains suspected secrets.
+// Submitting the same content again counts as explicit confirmation, which
+// is the TUI equivalent of the CLI's --allow-secrets flag.
+type secretGate struct {
+	acknowledged string
+}
+
+func (g *secretGate) check(findings []utils.SecretFinding) error {
+	if len(findings) == 0 {
+		g.acknowledged = ""
+		return nil
+	}
+
+	key := secretFindingsKey(findings)
+	if g.acknowledged == key {
+		return nil
+	}
+	g.acknowledged = key
+	return fmt.Errorf("%w\nRemove them, or submit again to send anyway", &utils.SecretsDetectedError{Findings: findings})
+}
+
+func secretFindingsKey(findings []utils.SecretFinding) string {
+	parts := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		parts = append(parts, finding.Location()+"="+finding.Redacted)
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package usage
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RunUsage is the usage summary of a single run.
+type RunUsage struct {
+	RunID      string    `json:"runId"`
+	Repository string    `json:"repository"`
+	Title      string    `json:"title,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Summary    Summary   `json:"summary"`
+}
+
+// RepoUsage is the usage rollup for one repository.
+type RepoUsage struct {
+	Repository string  `json:"repository"`
+	Runs       int     `json:"runs"`
+	Summary    Summary `json:"summary"`
+}
+
+// Report aggregates usage across runs and repositories.
+type Report struct {
+	Since        *time.Time  `json:"since,omitempty"`
+	Until        *time.Time  `json:"until,omitempty"`
+	Runs         []RunUsage  `json:"runs"`
+	Repositories []RepoUsage `json:"repositories"`
+	Total        Summary     `json:"total"`
+}
+
+// BuildReport rolls run summaries up per repository and in total.
+// Repositories are ordered by descending cost.
+func BuildReport(runs []RunUsage) Report {
+	report := Report{Runs: runs}
+	byRepo := make(map[string]*RepoUsage)
+	order := make([]string, 0)
+
+	for _, run := range runs {
+		report.Total.Merge(run.Summary)
+		repo, ok := byRepo[run.Repository]
+		if !ok {
+			repo = &RepoUsage{Repository: run.Repository}
+			byRepo[run.Repository] = repo
+			order = append(order, run.Repository)
+		}
+		repo.Runs++
+		repo.Summary.Merge(run.Summary)
+	}
+
+	report.Repositories = make([]RepoUsage, 0, len(order))
+	for _, name := range order {
+		report.Repositories = append(report.Repositories, *byRepo[name])
+	}
+	sort.SliceStable(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Summary.Cost > report.Repositories[j].Summary.Cost
+	})
+	if report.Total.Tools == nil {
+		report.Total.Tools = []ToolStats{}
+	}
+	return report
+}
+
+var summaryCSVHeader = []string{
+	"cost",
+	"input_tokens",
+	"output_tokens",
+	"reasoning_tokens",
+	"cache_read_tokens",
+	"cache_write_tokens",
+	"tool_calls",
+	"tool_duration_ms",
+}
+
+func summaryCSVFields(summary Summary) []string {
+	return []string{
+		strconv.FormatFloat(summary.Cost, 'f', 6, 64),
+		strconv.FormatInt(summary.Tokens.Input, 10),
+		strconv.FormatInt(summary.Tokens.Output, 10),
+		strconv.FormatInt(summary.Tokens.Reasoning, 10),
+		strconv.FormatInt(summary.Tokens.CacheRead, 10),
+		strconv.FormatInt(summary.Tokens.CacheWrite, 10),
+		strconv.Itoa(summary.ToolCalls),
+		strconv.FormatInt(summary.ToolDuration.Milliseconds(), 10),
+	}
+}
+
+// WriteRunsCSV writes one CSV row per run.
+func (r Report) WriteRunsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{"run_id", "repository", "title", "status", "created_at"}, summaryCSVHeader...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, run := range r.Runs {
+		created := ""
+		if !run.CreatedAt.IsZero() {
+			created = run.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		row := append([]string{run.RunID, run.Repository, run.Title, run.Status, created}, summaryCSVFields(run.Summary)...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteRepositoriesCSV writes one CSV row per repository.
+func (r Report) WriteRepositoriesCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{"repository", "runs"}, summaryCSVHeader...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, repo := range r.Repositories {
+		row := append([]string{repo.Repository, strconv.Itoa(repo.Runs)}, summaryCSVFields(repo.Summary)...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
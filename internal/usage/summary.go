@@ -0,0 +1,231 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package usage aggregates cost, token, and tool timing information from
+// agent conversation logs.
+package usage
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Tokens holds token counts reported by the agent.
+type Tokens struct {
+	Input      int64 `json:"input"`
+	Output     int64 `json:"output"`
+	Reasoning  int64 `json:"reasoning,omitempty"`
+	CacheRead  int64 `json:"cacheRead"`
+	CacheWrite int64 `json:"cacheWrite"`
+}
+
+// Total returns the sum of all token counters.
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output + t.Reasoning + t.CacheRead + t.CacheWrite
+}
+
+// Add accumulates other into t.
+func (t *Tokens) Add(other Tokens) {
+	t.Input += other.Input
+	t.Output += other.Output
+	t.Reasoning += other.Reasoning
+	t.CacheRead += other.CacheRead
+	t.CacheWrite += other.CacheWrite
+}
+
+// ToolStats summarizes usage of one tool.
+type ToolStats struct {
+	Name     string        `json:"name"`
+	Calls    int           `json:"calls"`
+	Errors   int           `json:"errors,omitempty"`
+	Duration time.Duration `json:"-"`
+}
+
+// MarshalJSON reports the tool duration in milliseconds.
+func (s ToolStats) MarshalJSON() ([]byte, error) {
+	type alias ToolStats
+	return json.Marshal(struct {
+		alias
+		Duration int64 `json:"durationMs"`
+	}{alias: alias(s), Duration: s.Duration.Milliseconds()})
+}
+
+// Summary is the aggregate usage for one or more runs.
+type Summary struct {
+	Messages     int           `json:"messages"`
+	Cost         float64       `json:"cost"`
+	Tokens       Tokens        `json:"tokens"`
+	ToolCalls    int           `json:"toolCalls"`
+	ToolErrors   int           `json:"toolErrors,omitempty"`
+	ToolDuration time.Duration `json:"-"`
+	Tools        []ToolStats   `json:"tools"`
+}
+
+// MarshalJSON reports the total tool duration in milliseconds.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	type alias Summary
+	return json.Marshal(struct {
+		alias
+		ToolDurationMs int64 `json:"toolDurationMs"`
+	}{alias: alias(s), ToolDurationMs: s.ToolDuration.Milliseconds()})
+}
+
+// Tool returns the stats for the named tool, if present.
+func (s Summary) Tool(name string) (ToolStats, bool) {
+	for _, tool := range s.Tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return ToolStats{}, false
+}
+
+// Merge accumulates other into s.
+func (s *Summary) Merge(other Summary) {
+	s.Messages += other.Messages
+	s.Cost += other.Cost
+	s.Tokens.Add(other.Tokens)
+	s.ToolCalls += other.ToolCalls
+	s.ToolErrors += other.ToolErrors
+	s.ToolDuration += other.ToolDuration
+
+	byName := make(map[string]int, len(s.Tools))
+	for i, tool := range s.Tools {
+		byName[tool.Name] = i
+	}
+	for _, tool := range other.Tools {
+		if i, ok := byName[tool.Name]; ok {
+			s.Tools[i].Calls += tool.Calls
+			s.Tools[i].Errors += tool.Errors
+			s.Tools[i].Duration += tool.Duration
+			continue
+		}
+		byName[tool.Name] = len(s.Tools)
+		s.Tools = append(s.Tools, tool)
+	}
+	sortTools(s.Tools)
+}
+
+// Summarize aggregates a run's agent log messages.
+//
+// Cost and token counters are summed across messages. Tool durations are
+// reported by the log endpoint in milliseconds.
+func Summarize(messages []models.RunLogMessage) Summary {
+	summary := Summary{}
+	tools := make(map[string]*ToolStats)
+
+	for _, message := range messages {
+		summary.Messages++
+		if message.Cost != nil {
+			summary.Cost += *message.Cost
+		}
+		summary.Tokens.Add(ParseTokens(message.Tokens))
+
+		if message.ToolName == "" {
+			continue
+		}
+		stats, ok := tools[message.ToolName]
+		if !ok {
+			stats = &ToolStats{Name: message.ToolName}
+			tools[message.ToolName] = stats
+		}
+		stats.Calls++
+		summary.ToolCalls++
+		if message.IsError {
+			stats.Errors++
+			summary.ToolErrors++
+		}
+		if message.Duration != nil && *message.Duration > 0 {
+			d := time.Duration(*message.Duration * float64(time.Millisecond))
+			stats.Duration += d
+			summary.ToolDuration += d
+		}
+	}
+
+	summary.Tools = make([]ToolStats, 0, len(tools))
+	for _, stats := range tools {
+		summary.Tools = append(summary.Tools, *stats)
+	}
+	sortTools(summary.Tools)
+	return summary
+}
+
+// sortTools orders tools by time spent, then call count, then name.
+func sortTools(tools []ToolStats) {
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Duration != tools[j].Duration {
+			return tools[i].Duration > tools[j].Duration
+		}
+		if tools[i].Calls != tools[j].Calls {
+			return tools[i].Calls > tools[j].Calls
+		}
+		return tools[i].Name < tools[j].Name
+	})
+}
+
+// ParseTokens reads a token map from a log message. The agent log format is
+// not strictly typed, so camelCase, snake_case, and nested cache objects
+// ({"cache": {"read": n, "write": n}}) are all accepted.
+func ParseTokens(raw map[string]any) Tokens {
+	if len(raw) == 0 {
+		return Tokens{}
+	}
+
+	var tokens Tokens
+	for key, value := range raw {
+		switch normalizeTokenKey(key) {
+		case "input", "inputtokens", "prompt", "prompttokens":
+			tokens.Input += toInt64(value)
+		case "output", "outputtokens", "completion", "completiontokens":
+			tokens.Output += toInt64(value)
+		case "reasoning", "reasoningtokens":
+			tokens.Reasoning += toInt64(value)
+		case "cacheread", "cachereadtokens", "cachereadinputtokens":
+			tokens.CacheRead += toInt64(value)
+		case "cachewrite", "cachewritetokens", "cachecreation", "cachecreationinputtokens":
+			tokens.CacheWrite += toInt64(value)
+		case "cache":
+			if nested, ok := value.(map[string]any); ok {
+				for nestedKey, nestedValue := range nested {
+					switch normalizeTokenKey(nestedKey) {
+					case "read":
+						tokens.CacheRead += toInt64(nestedValue)
+					case "write", "creation":
+						tokens.CacheWrite += toInt64(nestedValue)
+					}
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+func normalizeTokenKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
+
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
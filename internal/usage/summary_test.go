@@ -0,0 +1,113 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestSummarizeTotalsCostTokensAndTools(t *testing.T) {
+	messages := []models.RunLogMessage{
+		{Type: "user", Content: "Fix the bug"},
+		{
+			Type:   "assistant",
+			Cost:   floatPtr(0.25),
+			Tokens: map[string]any{"input": float64(1000), "output": float64(200), "cache": map[string]any{"read": float64(50), "write": float64(10)}},
+		},
+		{Type: "tool_call", ToolName: "bash", Duration: floatPtr(1500)},
+		{Type: "tool_call", ToolName: "bash", Duration: floatPtr(500), IsError: true},
+		{Type: "tool_call", ToolName: "read", Duration: floatPtr(100)},
+		{
+			Type:   "assistant",
+			Cost:   floatPtr(0.05),
+			Tokens: map[string]any{"input_tokens": float64(300), "outputTokens": "40", "cacheRead": float64(5)},
+		},
+	}
+
+	summary := Summarize(messages)
+
+	require.Equal(t, 6, summary.Messages)
+	require.InDelta(t, 0.30, summary.Cost, 1e-9)
+	require.Equal(t, Tokens{Input: 1300, Output: 240, CacheRead: 55, CacheWrite: 10}, summary.Tokens)
+	require.Equal(t, 3, summary.ToolCalls)
+	require.Equal(t, 1, summary.ToolErrors)
+	require.Equal(t, 2100*time.Millisecond, summary.ToolDuration)
+
+	require.Len(t, summary.Tools, 2)
+	require.Equal(t, "bash", summary.Tools[0].Name)
+	require.Equal(t, 2, summary.Tools[0].Calls)
+	require.Equal(t, 1, summary.Tools[0].Errors)
+	require.Equal(t, 2*time.Second, summary.Tools[0].Duration)
+}
+
+func TestSummaryMergeCombinesTools(t *testing.T) {
+	a := Summarize([]models.RunLogMessage{{ToolName: "bash", Duration: floatPtr(100)}})
+	b := Summarize([]models.RunLogMessage{
+		{ToolName: "bash", Duration: floatPtr(300)},
+		{ToolName: "edit", Duration: floatPtr(50)},
+	})
+
+	a.Merge(b)
+
+	bash, ok := a.Tool("bash")
+	require.True(t, ok)
+	require.Equal(t, 2, bash.Calls)
+	require.Equal(t, 400*time.Millisecond, bash.Duration)
+	_, ok = a.Tool("edit")
+	require.True(t, ok)
+	require.Equal(t, 3, a.ToolCalls)
+}
+
+func TestSummaryJSONReportsMilliseconds(t *testing.T) {
+	summary := Summarize([]models.RunLogMessage{{ToolName: "bash", Duration: floatPtr(1250)}})
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, float64(1250), decoded["toolDurationMs"])
+	tools := decoded["tools"].([]any)
+	require.Equal(t, float64(1250), tools[0].(map[string]any)["durationMs"])
+}
+
+func TestBuildReportRollsUpPerRepository(t *testing.T) {
+	runs := []RunUsage{
+		{RunID: "1", Repository: "acme/api", Summary: Summary{Cost: 1, Tokens: Tokens{Input: 10}}},
+		{RunID: "2", Repository: "acme/web", Summary: Summary{Cost: 3, Tokens: Tokens{Input: 30}}},
+		{RunID: "3", Repository: "acme/api", Summary: Summary{Cost: 4, Tokens: Tokens{Input: 40}}},
+	}
+
+	report := BuildReport(runs)
+
+	require.Len(t, report.Repositories, 2)
+	require.Equal(t, "acme/api", report.Repositories[0].Repository)
+	require.Equal(t, 2, report.Repositories[0].Runs)
+	require.InDelta(t, 5.0, report.Repositories[0].Summary.Cost, 1e-9)
+	require.InDelta(t, 8.0, report.Total.Cost, 1e-9)
+	require.Equal(t, int64(80), report.Total.Tokens.Input)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteRepositoriesCSV(&out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasPrefix(lines[0], "repository,runs,cost,input_tokens"))
+	require.True(t, strings.HasPrefix(lines[1], "acme/api,2,5.000000,50"))
+
+	out.Reset()
+	require.NoError(t, report.WriteRunsCSV(&out))
+	require.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 4)
+}
@@ -30,6 +30,13 @@ type FileDiscoveryOptions struct {
 	FileExtensions []string
 	SortByModTime  bool
 	MaxFiles       int // Limit number of results for performance
+	// RespectGitignore skips paths matched by .gitignore files in the walked
+	// tree.
+	RespectGitignore bool
+	// GitRoot, when set with RespectGitignore, is the root of the checkout
+	// holding the walked tree; the .gitignore files from it down to the walk
+	// root apply as well.
+	GitRoot string
 }
 
 // FindJSONFiles finds JSON files in the given directory with default options
@@ -56,7 +63,8 @@ func FindConfigFiles(rootPath string) ([]string, error) {
 	return FindFiles(rootPath, opts)
 }
 
-// FindFiles recursively finds files matching the given options
+// FindFiles recursively finds files matching the given options. A negative
+// MaxDepth or MaxFiles removes that limit.
 func FindFiles(rootPath string, opts FileDiscoveryOptions) ([]string, error) {
 	if opts.MaxDepth == 0 {
 		opts.MaxDepth = 3
@@ -66,6 +74,19 @@ func FindFiles(rootPath string, opts FileDiscoveryOptions) ([]string, error) {
 	}
 
 	var files []FileInfo
+	var gitIgnore *GitIgnore
+	if opts.RespectGitignore {
+		gitIgnore = &GitIgnore{}
+		if opts.GitRoot != "" {
+			absRoot, err := filepath.Abs(rootPath)
+			if err != nil {
+				return nil, err
+			}
+			if err := gitIgnore.AddParentFiles(opts.GitRoot, absRoot); err != nil {
+				return nil, err
+			}
+		}
+	}
 	ignoreMap := make(map[string]bool)
 	for _, pattern := range opts.IgnorePatterns {
 		ignoreMap[pattern] = true
@@ -82,7 +103,7 @@ func FindFiles(rootPath string, opts FileDiscoveryOptions) ([]string, error) {
 			return nil
 		}
 		depth := strings.Count(relPath, string(filepath.Separator))
-		if depth > opts.MaxDepth {
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -93,6 +114,21 @@ func FindFiles(rootPath string, opts FileDiscoveryOptions) ([]string, error) {
 		if info.IsDir() && ignoreMap[info.Name()] {
 			return filepath.SkipDir
 		}
+		if gitIgnore != nil && relPath != "." {
+			if gitIgnore.Ignored(filepath.ToSlash(relPath), info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if gitIgnore != nil && info.IsDir() {
+			base := filepath.ToSlash(relPath)
+			if base == "." {
+				base = ""
+			}
+			_ = gitIgnore.AddFile(filepath.Join(path, ".gitignore"), base)
+		}
 
 		// Check if it's a file we want
 		if !info.IsDir() {
@@ -118,7 +154,7 @@ func FindFiles(rootPath string, opts FileDiscoveryOptions) ([]string, error) {
 			})
 
 			// Stop if we hit the max files limit
-			if len(files) >= opts.MaxFiles {
+			if opts.MaxFiles > 0 && len(files) >= opts.MaxFiles {
 				return filepath.SkipDir
 			}
 		}
@@ -22,6 +22,23 @@ type GenericPollConfig struct {
 	Debug         bool
 }
 
+// NextInterval returns the interval before the next poll: the current one
+// grown by BackoffFactor, up to MaxInterval, after a failed poll, and
+// Interval again after a successful one.
+func (c *GenericPollConfig) NextInterval(current time.Duration, failed bool) time.Duration {
+	if !failed {
+		return c.Interval
+	}
+	if c.BackoffFactor <= 1 {
+		return current
+	}
+	next := time.Duration(float64(current) * c.BackoffFactor)
+	if next > c.MaxInterval {
+		next = c.MaxInterval
+	}
+	return next
+}
+
 // PollFunc is a generic function that fetches the current state
 type GenericPollFunc[T any] func(ctx context.Context) (T, error)
 
@@ -113,19 +130,16 @@ func (p *GenericPoller[T]) PollUntilComplete(
 				}
 
 				// Implement exponential backoff on errors
-				if p.config.BackoffFactor > 1 {
-					currentInterval = time.Duration(float64(currentInterval) * p.config.BackoffFactor)
-					if currentInterval > p.config.MaxInterval {
-						currentInterval = p.config.MaxInterval
-					}
+				if next := p.config.NextInterval(currentInterval, true); next != currentInterval {
+					currentInterval = next
 					ticker.Reset(currentInterval)
 				}
 				continue
 			}
 
 			// Reset interval on success
-			if currentInterval != p.config.Interval {
-				currentInterval = p.config.Interval
+			if next := p.config.NextInterval(currentInterval, false); next != currentInterval {
+				currentInterval = next
 				ticker.Reset(currentInterval)
 			}
 
@@ -0,0 +1,154 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitIgnore matches paths against the patterns of .gitignore files. Paths
+// are slash-separated and relative to the walk root.
+type GitIgnore struct {
+	rules []gitignoreRule
+	// prefix is the path of the walk root within the checkout once the
+	// .gitignore files above it were added.
+	prefix string
+}
+
+type gitignoreRule struct {
+	base     string // directory of the .gitignore file, "" for the root
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// AddFile reads the patterns of the .gitignore file at filePath, which lies
+// in the directory base. A missing file adds nothing.
+func (g *GitIgnore) AddFile(filePath, base string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		g.AddPattern(scanner.Text(), base)
+	}
+	return scanner.Err()
+}
+
+// AddParentFiles reads the .gitignore files of gitRoot, the root of the
+// checkout, and of every directory between it and root, the walk root, so
+// patterns written for the whole repository apply to a walk of one of its
+// directories. Paths and bases stay relative to root.
+func (g *GitIgnore) AddParentFiles(gitRoot, root string) error {
+	rel, err := filepath.Rel(gitRoot, root)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return err
+	}
+	prefix := filepath.ToSlash(rel)
+	dir := ""
+	for _, segment := range strings.Split(prefix, "/") {
+		if err := g.AddFile(filepath.Join(gitRoot, filepath.FromSlash(dir), ".gitignore"), dir); err != nil {
+			return err
+		}
+		dir = path.Join(dir, segment)
+	}
+	g.prefix = prefix
+	return nil
+}
+
+// AddPattern adds one .gitignore line from the directory base.
+func (g *GitIgnore) AddPattern(line, base string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	rule := gitignoreRule{base: strings.Trim(path.Join(g.prefix, base), "/")}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	// A slash anywhere but the end anchors the pattern to its directory.
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return
+	}
+	rule.pattern = line
+	g.rules = append(g.rules, rule)
+}
+
+// Ignored reports whether relPath is ignored. The last matching pattern
+// wins, so a later "!pattern" re-includes a path.
+func (g *GitIgnore) Ignored(relPath string, isDir bool) bool {
+	if g == nil {
+		return false
+	}
+	relPath = strings.Trim(path.Join(g.prefix, strings.ReplaceAll(relPath, `\`, "/")), "/")
+	ignored := false
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(relPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (r gitignoreRule) matches(relPath string) bool {
+	if r.base != "" {
+		if !strings.HasPrefix(relPath, r.base+"/") {
+			return false
+		}
+		relPath = strings.TrimPrefix(relPath, r.base+"/")
+	}
+	if !r.anchored {
+		return matchGlob(r.pattern, path.Base(relPath))
+	}
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 || !matchGlob(pattern[0], segments[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+func matchGlob(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitIgnore(t *testing.T) {
+	var ignore GitIgnore
+	for _, line := range []string{"# comment", "*.log", "!keep.log", "/generated", "tmp/", "docs/**/*.pdf"} {
+		ignore.AddPattern(line, "")
+	}
+	ignore.AddPattern("fixtures", "internal/api")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},
+		{"logs/app.log", false, true},
+		{"keep.log", false, false},
+		{"generated", true, true},
+		{"src/generated", true, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"docs/a/b/guide.pdf", false, true},
+		{"docs/guide.pdf", false, true},
+		{"internal/api/fixtures", true, true},
+		{"internal/fixtures", true, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ignore.Ignored(tt.path, tt.isDir), tt.path)
+	}
+}
+
+func TestFindFilesRespectsGitignore(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{".gitignore", "main.go", "debug.log", "out/app", "pkg/a/b/c/deep.go", "pkg/.gitignore", "pkg/local.txt", "node_modules/x.js"} {
+		path := filepath.Join(root, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\nout/\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", ".gitignore"), []byte("local.txt\n"), 0644))
+
+	files, err := FindFiles(root, FileDiscoveryOptions{
+		MaxDepth:         -1,
+		MaxFiles:         -1,
+		IgnorePatterns:   DefaultIgnorePatterns,
+		RespectGitignore: true,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		".gitignore", "main.go", "pkg/.gitignore", filepath.Join("pkg", "a", "b", "c", "deep.go"),
+	}, files)
+}
@@ -22,6 +22,8 @@ type MarkdownConfig struct {
 	PRTargetBranch        string                          `yaml:"prTargetBranch" json:"prTargetBranch"`
 	OutputBranchPolicy    string                          `yaml:"outputBranchPolicy" json:"outputBranchPolicy"`
 	RunType               string                          `yaml:"runType" json:"runType"`
+	Model                 string                          `yaml:"model" json:"model"`
+	Provider              string                          `yaml:"provider" json:"provider"`
 	Title                 string                          `yaml:"title" json:"title"`
 	Context               string                          `yaml:"context" json:"context"`
 	Files                 []string                        `yaml:"files" json:"files"`
@@ -76,6 +78,8 @@ func ParseMarkdownConfigFromReader(r io.Reader) (*models.RunConfig, string, erro
 		PRTargetBranch:        config.PRTargetBranch,
 		OutputBranchPolicy:    config.OutputBranchPolicy,
 		RunType:               config.RunType,
+		Model:                 config.Model,
+		Provider:              config.Provider,
 		Title:                 config.Title,
 		Context:               config.Context,
 		Files:                 config.Files,
@@ -106,6 +110,8 @@ func validateMarkdownConfig(config *MarkdownConfig) error {
 		PRTargetBranch:        config.PRTargetBranch,
 		OutputBranchPolicy:    config.OutputBranchPolicy,
 		RunType:               config.RunType,
+		Model:                 config.Model,
+		Provider:              config.Provider,
 		Title:                 config.Title,
 		Context:               config.Context,
 		Files:                 config.Files,
@@ -165,7 +171,30 @@ func ValidateRunConfig(config *models.RunConfig) error {
 
 	if config.RunType == "" {
 		config.RunType = "run" // Default to "run" if not specified
-	} else if config.RunType != "run" &&
+	}
+	errors = append(errors, ValidateRunSettings(config)...)
+
+	// Title is optional - server will generate if not provided
+
+	// Validate repository format (basic check)
+	if config.Repository != "" && !strings.Contains(config.Repository, "/") {
+		errors = append(errors, "repository must be in format 'owner/repo'")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// ValidateRunSettings checks the optional run settings of a RunConfig and
+// returns one message per problem. It normalizes the legacy "pr" output mode.
+func ValidateRunSettings(config *models.RunConfig) []string {
+	var errors []string
+
+	if config.RunType != "" &&
+		config.RunType != "run" &&
 		config.RunType != "plan" &&
 		config.RunType != "basic" &&
 		config.RunType != "pro" {
@@ -203,16 +232,5 @@ func ValidateRunConfig(config *models.RunConfig) error {
 		}
 	}
 
-	// Title is optional - server will generate if not provided
-
-	// Validate repository format (basic check)
-	if config.Repository != "" && !strings.Contains(config.Repository, "/") {
-		errors = append(errors, "repository must be in format 'owner/repo'")
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("validation errors:\n  - %s", strings.Join(errors, "\n  - "))
-	}
-
-	return nil
+	return errors
 }
@@ -210,6 +210,20 @@ Content`,
 	}
 }
 
+func TestParseMarkdownConfigFromReader_ModelFields(t *testing.T) {
+	input := `---
+prompt: "Refactor the polling loop"
+repository: "acme/webapp"
+model: "pro"
+provider: "openrouter"
+---
+`
+	config, _, err := ParseMarkdownConfigFromReader(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, "pro", config.Model)
+	assert.Equal(t, "openrouter", config.Provider)
+}
+
 func TestValidateRunConfig(t *testing.T) {
 	tests := []struct {
 		name          string
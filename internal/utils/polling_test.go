@@ -211,6 +211,17 @@ func TestPoller_Poll(t *testing.T) {
 	})
 }
 
+func TestGenericPollConfigNextInterval(t *testing.T) {
+	config := &GenericPollConfig{Interval: time.Second, MaxInterval: 3 * time.Second, BackoffFactor: 2}
+
+	assert.Equal(t, 2*time.Second, config.NextInterval(time.Second, true))
+	assert.Equal(t, 3*time.Second, config.NextInterval(2*time.Second, true), "backoff is capped at MaxInterval")
+	assert.Equal(t, time.Second, config.NextInterval(3*time.Second, false), "a successful poll resets the interval")
+
+	config.BackoffFactor = 1
+	assert.Equal(t, 2*time.Second, config.NextInterval(2*time.Second, true), "no backoff without a factor above 1")
+}
+
 func TestIsTerminalStatus(t *testing.T) {
 	tests := []struct {
 		name       string
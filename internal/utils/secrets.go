@@ -0,0 +1,329 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactShowChars is how many leading characters of a secret stay visible
+// after redaction, matching the masking used for API keys.
+const redactShowChars = 4
+
+// SecretRule describes one kind of credential the scanner looks for.
+type SecretRule struct {
+	ID          string
+	Description string
+	Pattern     *regexp.Regexp
+	// ValueGroup selects the capture group holding the secret. Zero means the
+	// whole match is the secret.
+	ValueGroup int
+	// Accept, when set, decides whether ScanSecrets reports a match, given
+	// the text before the value and the value. RedactSecrets masks every
+	// match regardless.
+	Accept func(prefix, value string) bool
+}
+
+// SecretRules are the patterns shared by RedactSecrets and ScanSecrets.
+var SecretRules = []SecretRule{
+	{
+		ID:          "private-key",
+		Description: "PEM private key",
+		Pattern:     regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	},
+	{
+		ID:          "aws-access-key",
+		Description: "AWS access key ID",
+		Pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		ID:          "aws-secret-key",
+		Description: "AWS secret access key",
+		Pattern:     regexp.MustCompile(`(?i)(aws_?secret_?access_?key["']?\s*[:=]\s*["']?)([A-Za-z0-9/+=]{40})\b`),
+		ValueGroup:  2,
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Pattern:     regexp.MustCompile(`\b(?:(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	},
+	{
+		ID:          "gitlab-token",
+		Description: "GitLab personal access token",
+		Pattern:     regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}\b`),
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Pattern:     regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`),
+	},
+	{
+		ID:          "api-key",
+		Description: "provider API key",
+		Pattern:     regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{20,}\b`),
+	},
+	{
+		ID:          "jwt",
+		Description: "JSON Web Token",
+		Pattern:     regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{8,}\.eyJ[A-Za-z0-9_\-]{8,}\.[A-Za-z0-9_\-]{8,}\b`),
+	},
+	{
+		ID:          "secret-assignment",
+		Description: "credential assigned to a secret-named key",
+		Pattern: regexp.MustCompile(
+			`(?i)((?:api[_-]?key|secret|token|password|passwd|authorization|access[_-]?key|private[_-]?key)["']?\s*[:=]\s*["']?(?:bearer\s+)?)([^\s"',;]{6,})`,
+		),
+		ValueGroup: 2,
+		Accept:     isAssignedSecret,
+	},
+}
+
+// assignedSecretMinBitsPer is the entropy an unquoted assigned value needs to
+// count as a secret rather than an identifier.
+const assignedSecretMinBitsPer = 3.0
+
+// isAssignedSecret keeps the secret-assignment rule from blocking ordinary
+// code such as `password: string` or `token = getToken()`: a value counts
+// when it is a quoted literal, or an unquoted token mixing letters and
+// digits with enough entropy. Template placeholders never count.
+func isAssignedSecret(prefix, value string) bool {
+	if strings.ContainsAny(value[:1], "$<{%") {
+		return false
+	}
+	if strings.HasSuffix(prefix, `"`) || strings.HasSuffix(prefix, "'") {
+		return true
+	}
+	if strings.ContainsAny(value, "()[].") {
+		return false
+	}
+	hasLetter := strings.IndexFunc(value, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' }) >= 0
+	hasDigit := strings.IndexFunc(value, func(r rune) bool { return r >= '0' && r <= '9' }) >= 0
+	return hasLetter && hasDigit && shannonEntropy(value) >= assignedSecretMinBitsPer
+}
+
+// High-entropy detection looks for long opaque tokens that no specific rule
+// recognizes. Hex strings such as commit SHAs stay below the threshold.
+const (
+	highEntropyRuleID     = "high-entropy"
+	highEntropyMinLength  = 32
+	highEntropyMinBitsPer = 4.2
+)
+
+var highEntropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_\-]{32,}={0,2}`)
+
+// SecretFinding is one suspected secret with its location.
+type SecretFinding struct {
+	RuleID      string `json:"ruleId"`
+	Description string `json:"description"`
+	Field       string `json:"field"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Redacted    string `json:"redacted"`
+}
+
+// Location returns the finding location as field:line:column.
+func (f SecretFinding) Location() string {
+	return fmt.Sprintf("%s:%d:%d", f.Field, f.Line, f.Column)
+}
+
+// SecretField is a named piece of text to scan.
+type SecretField struct {
+	Name  string
+	Value string
+}
+
+// RedactSecrets masks credential-looking substrings in free text. Matches are
+// masked with MaskSensitiveString so only the first few characters remain.
+func RedactSecrets(text string) string {
+	if text == "" {
+		return text
+	}
+
+	for _, rule := range SecretRules {
+		rule := rule
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.ValueGroup == 0 {
+				return redactMatch(match)
+			}
+			parts := rule.Pattern.FindStringSubmatch(match)
+			if len(parts) <= rule.ValueGroup || isRedacted(parts[rule.ValueGroup]) {
+				return match
+			}
+			return strings.Replace(match, parts[rule.ValueGroup], redactMatch(parts[rule.ValueGroup]), 1)
+		})
+	}
+	return text
+}
+
+// ScanSecrets reports suspected secrets in the given fields. Each location is
+// reported once, by the most specific rule that matched it.
+func ScanSecrets(fields ...SecretField) []SecretFinding {
+	var findings []SecretFinding
+	for _, field := range fields {
+		findings = append(findings, scanSecretField(field)...)
+	}
+	return findings
+}
+
+// ScanRunInputs scans the user-provided text of a run request: prompt,
+// context, and the paths listed in files. The files are only named by the
+// request, so their contents are not read.
+func ScanRunInputs(prompt, context string, files []string) []SecretFinding {
+	fields := []SecretField{
+		{Name: "prompt", Value: prompt},
+		{Name: "context", Value: context},
+	}
+	for i, file := range files {
+		fields = append(fields, SecretField{Name: fmt.Sprintf("files[%d]", i), Value: file})
+	}
+	return ScanSecrets(fields...)
+}
+
+// FormatSecretFindings renders findings as an indented list for error output.
+func FormatSecretFindings(findings []SecretFinding) string {
+	var b strings.Builder
+	for _, finding := range findings {
+		fmt.Fprintf(&b, "  %s  %s (%s): %s\n", finding.Location(), finding.Description, finding.RuleID, finding.Redacted)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func scanSecretField(field SecretField) []SecretFinding {
+	if field.Value == "" {
+		return nil
+	}
+
+	type span struct{ start, end int }
+	var covered []span
+	overlaps := func(start, end int) bool {
+		for _, s := range covered {
+			if start < s.end && end > s.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	var findings []SecretFinding
+	add := func(ruleID, description string, start, end int) {
+		if overlaps(start, end) {
+			return
+		}
+		covered = append(covered, span{start, end})
+		line, column := lineAndColumn(field.Value, start)
+		redacted, _, _ := strings.Cut(redactMatch(field.Value[start:end]), "\n")
+		findings = append(findings, SecretFinding{
+			RuleID:      ruleID,
+			Description: description,
+			Field:       field.Name,
+			Line:        line,
+			Column:      column,
+			Redacted:    redacted,
+		})
+	}
+
+	for _, rule := range SecretRules {
+		for _, match := range rule.Pattern.FindAllStringSubmatchIndex(field.Value, -1) {
+			start, end := match[0], match[1]
+			if rule.ValueGroup > 0 && len(match) > 2*rule.ValueGroup+1 && match[2*rule.ValueGroup] >= 0 {
+				start, end = match[2*rule.ValueGroup], match[2*rule.ValueGroup+1]
+			}
+			if rule.Accept != nil && !rule.Accept(field.Value[match[0]:start], field.Value[start:end]) {
+				continue
+			}
+			add(rule.ID, rule.Description, start, end)
+		}
+	}
+
+	for _, match := range highEntropyCandidate.FindAllStringIndex(field.Value, -1) {
+		candidate := field.Value[match[0]:match[1]]
+		if looksLikeHighEntropySecret(candidate) {
+			add(highEntropyRuleID, "high-entropy string", match[0], match[1])
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+func looksLikeHighEntropySecret(value string) bool {
+	if len(value) < highEntropyMinLength {
+		return false
+	}
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigit {
+		return false
+	}
+	return shannonEntropy(value) >= highEntropyMinBitsPer
+}
+
+// shannonEntropy returns the bits of entropy per character of s.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	length := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func lineAndColumn(text string, offset int) (int, int) {
+	line := 1 + strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndex(text[:offset], "\n") + 1
+	return line, offset - lineStart + 1
+}
+
+// pemHeader captures the key type of a PEM private key header, such as
+// "RSA " or "OPENSSH ".
+var pemHeader = regexp.MustCompile(`^-----BEGIN ([A-Z ]*)PRIVATE KEY-----`)
+
+func redactMatch(secret string) string {
+	if header := pemHeader.FindStringSubmatch(secret); header != nil {
+		// Keep the PEM header and a matching footer so readers know what was
+		// removed.
+		return header[0] + "\n" + strings.Repeat("*", 16) + "\n-----END " + header[1] + "PRIVATE KEY-----"
+	}
+	return MaskSensitiveString(secret, redactShowChars)
+}
+
+func isRedacted(value string) bool {
+	return len(value) > redactShowChars && strings.Trim(value[redactShowChars:], "*") == ""
+}
+
+// SecretsDetectedError blocks a submission whose input contains suspected
+// secrets.
+type SecretsDetectedError struct {
+	Findings []SecretFinding
+}
+
+func (e *SecretsDetectedError) Error() string {
+	return fmt.Sprintf("submission blocked: %d possible secret(s) found\n%s", len(e.Findings), FormatSecretFindings(e.Findings))
+}
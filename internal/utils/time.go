@@ -5,6 +5,8 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -39,3 +41,28 @@ func FormatDurationLong(d time.Duration) string {
 		return fmt.Sprintf("%dd %dh", days, hours)
 	}
 }
+
+// ParseTimeBound parses a CLI time bound. It accepts RFC3339 timestamps,
+// YYYY-MM-DD dates (local midnight), and relative ages such as "36h" or "7d"
+// which are subtracted from now.
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339, YYYY-MM-DD, or an age like 24h or 7d)", value)
+}
@@ -293,3 +293,32 @@ func BenchmarkCalculateProgress(b *testing.B) {
 		CalculateProgress(pair[0], pair[1])
 	}
 }
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "empty", value: "", expected: time.Time{}},
+		{name: "rfc3339", value: "2025-03-01T08:00:00Z", expected: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{name: "date", value: "2025-03-01", expected: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "days", value: "7d", expected: now.AddDate(0, 0, -7)},
+		{name: "duration", value: "36h", expected: now.Add(-36 * time.Hour)},
+		{name: "invalid", value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeBound(tt.value, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(got), "expected %s, got %s", tt.expected, got)
+		})
+	}
+}
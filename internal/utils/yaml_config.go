@@ -25,6 +25,8 @@ type YAMLConfig struct {
 	PRTargetBranch        string                          `yaml:"prTargetBranch" json:"prTargetBranch"`
 	OutputBranchPolicy    string                          `yaml:"outputBranchPolicy" json:"outputBranchPolicy"`
 	RunType               string                          `yaml:"runType" json:"runType"`
+	Model                 string                          `yaml:"model" json:"model"`
+	Provider              string                          `yaml:"provider" json:"provider"`
 	Title                 string                          `yaml:"title" json:"title"`
 	Context               string                          `yaml:"context" json:"context"`
 	Files                 []string                        `yaml:"files" json:"files"`
@@ -120,6 +122,12 @@ func parseYAMLWithUnknownFieldsAndPrompts(data []byte) (*models.RunConfig, *prom
 	if runType, ok := genericMap["runType"].(string); ok {
 		config.RunType = runType
 	}
+	if model, ok := genericMap["model"].(string); ok {
+		config.Model = model
+	}
+	if provider, ok := genericMap["provider"].(string); ok {
+		config.Provider = provider
+	}
 	if title, ok := genericMap["title"].(string); ok {
 		config.Title = title
 	}
@@ -195,6 +203,8 @@ func parseYAMLWithUnknownFieldsAndPrompts(data []byte) (*models.RunConfig, *prom
 		PRTargetBranch:        config.PRTargetBranch,
 		OutputBranchPolicy:    config.OutputBranchPolicy,
 		RunType:               config.RunType,
+		Model:                 config.Model,
+		Provider:              config.Provider,
 		Title:                 config.Title,
 		Context:               config.Context,
 		Files:                 config.Files,
@@ -229,6 +239,8 @@ func findUnsupportedYAMLFieldsWithSuggestions(data map[string]interface{}) ([]st
 		"prTargetBranch":        true,
 		"outputBranchPolicy":    true,
 		"runType":               true,
+		"model":                 true,
+		"provider":              true,
 		"title":                 true,
 		"context":               true,
 		"files":                 true,
@@ -244,7 +256,7 @@ func findUnsupportedYAMLFieldsWithSuggestions(data map[string]interface{}) ([]st
 	supportedFieldsList := []string{
 		"prompt", "repository", "source", "target", "baseBranch",
 		"outputMode", "outputBranch", "prTargetBranch", "outputBranchPolicy",
-		"runType", "title", "context", "files", "providerCredentialId", "providerMode",
+		"runType", "model", "provider", "title", "context", "files", "providerCredentialId", "providerMode",
 		"gitlabCredential", "branchOnly", "acknowledgePromptRisk", "pullRequest", "metadata",
 	}
 
@@ -494,6 +506,8 @@ func ParseJSONFromStdinWithPrompts() (*models.RunConfig, *prompts.ValidationProm
 		Source:                runReq.Source,
 		Target:                runReq.Target,
 		RunType:               string(runReq.RunType),
+		Model:                 runReq.Model,
+		Provider:              runReq.Provider,
 		Title:                 runReq.Title,
 		Context:               runReq.Context,
 		Files:                 runReq.Files,
@@ -515,6 +529,8 @@ func findUnsupportedJSONFieldsForStdinWithSuggestions(data map[string]interface{
 		"source":                true,
 		"target":                true,
 		"runType":               true,
+		"model":                 true,
+		"provider":              true,
 		"title":                 true,
 		"context":               true,
 		"files":                 true,
@@ -525,7 +541,7 @@ func findUnsupportedJSONFieldsForStdinWithSuggestions(data map[string]interface{
 	}
 
 	supportedFieldsList := []string{
-		"prompt", "repository", "source", "target", "runType",
+		"prompt", "repository", "source", "target", "runType", "model", "provider",
 		"title", "context", "files", "providerCredentialId", "providerMode",
 		"gitlabCredential", "acknowledgePromptRisk",
 	}
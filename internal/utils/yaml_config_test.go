@@ -127,6 +127,21 @@ gitlabCredential:
 	assert.Equal(t, "glref_123", config.GitLabCredential.TokenReferenceID)
 }
 
+func TestParseYAMLConfig_ModelFields(t *testing.T) {
+	input := bytes.NewBufferString(`
+prompt: "Rename the config loader"
+repository: "acme/webapp"
+model: "openrouter/z-ai/glm-5.2"
+provider: "openrouter"
+`)
+
+	config, err := ParseYAMLConfigFromReader(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "openrouter/z-ai/glm-5.2", config.Model)
+	assert.Equal(t, "openrouter", config.Provider)
+}
+
 func TestLoadConfigFromFile(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -0,0 +1,230 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package workflow describes chained runs. A workflow is a DAG of steps,
+// each a run config that starts once the steps it needs have succeeded and
+// may build on the branch an earlier step produced.
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/repobird/repobird-cli/internal/models"
+)
+
+// Failure policies.
+const (
+	// OnFailureStop starts no further steps once a step fails.
+	OnFailureStop = "stop"
+	// OnFailureContinue keeps starting steps that do not depend on a failed
+	// step.
+	OnFailureContinue = "continue"
+)
+
+// Config is a workflow file.
+type Config struct {
+	Name string `json:"name" yaml:"name"`
+	// Repository, Source, RunType, Model and Provider apply to every step
+	// that does not set them.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Source     string `json:"source,omitempty" yaml:"source,omitempty"`
+	RunType    string `json:"runType,omitempty" yaml:"runType,omitempty"`
+	Model      string `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider   string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// OnFailure is OnFailureStop (the default) or OnFailureContinue.
+	OnFailure string `json:"onFailure,omitempty" yaml:"onFailure,omitempty"`
+	Steps     []Step `json:"steps" yaml:"steps"`
+}
+
+// Step is one run of a workflow.
+type Step struct {
+	ID string `json:"id" yaml:"id"`
+	// Needs lists the steps that must succeed before this one starts. A step
+	// whose baseBranch references another step needs it implicitly.
+	Needs            []string `json:"needs,omitempty" yaml:"needs,omitempty"`
+	models.RunConfig `yaml:",inline"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// branchRef matches a baseBranch that takes the output branch of a step.
+var branchRef = regexp.MustCompile(`^\$\{\{\s*steps\.([A-Za-z0-9._-]+)\.outputBranch\s*\}\}$`)
+
+// LoadConfig reads and validates a YAML or JSON workflow file.
+func LoadConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %w", file, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", file, err)
+	}
+	return &config, nil
+}
+
+// Validate checks names and dependencies, adds the implicit dependencies of
+// branch references and rejects cycles.
+func (c *Config) Validate() error {
+	switch {
+	case c.Name == "":
+		return fmt.Errorf("name is required")
+	case !namePattern.MatchString(c.Name):
+		return fmt.Errorf("name %q may only contain letters, digits, '.', '_' and '-'", c.Name)
+	case c.OnFailure != "" && c.OnFailure != OnFailureStop && c.OnFailure != OnFailureContinue:
+		return fmt.Errorf("onFailure must be %q or %q", OnFailureStop, OnFailureContinue)
+	case len(c.Steps) == 0:
+		return fmt.Errorf("at least one step is required")
+	}
+
+	ids := make(map[string]bool, len(c.Steps))
+	for i, step := range c.Steps {
+		switch {
+		case step.ID == "":
+			return fmt.Errorf("step %d: id is required", i+1)
+		case !namePattern.MatchString(step.ID):
+			return fmt.Errorf("step %q: id may only contain letters, digits, '.', '_' and '-'", step.ID)
+		case ids[step.ID]:
+			return fmt.Errorf("step %q is defined twice", step.ID)
+		case strings.TrimSpace(step.Prompt) == "":
+			return fmt.Errorf("step %q: prompt is required", step.ID)
+		}
+		ids[step.ID] = true
+	}
+
+	for i := range c.Steps {
+		step := &c.Steps[i]
+		if upstream, ok := Upstream(step.BaseBranch); ok && !contains(step.Needs, upstream) {
+			step.Needs = append(step.Needs, upstream)
+		} else if strings.Contains(step.BaseBranch, "${{") && !ok {
+			return fmt.Errorf("step %q: baseBranch %q must have the form ${{ steps.<id>.outputBranch }}", step.ID, step.BaseBranch)
+		}
+		for _, need := range step.Needs {
+			switch {
+			case need == step.ID:
+				return fmt.Errorf("step %q needs itself", step.ID)
+			case !ids[need]:
+				return fmt.Errorf("step %q needs unknown step %q", step.ID, need)
+			}
+		}
+	}
+
+	if cycle := c.findCycle(); cycle != nil {
+		return fmt.Errorf("steps depend on each other in a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle returns the step IDs of a dependency cycle, or nil.
+func (c *Config) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(c.Steps))
+	var stack []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			for i, entry := range stack {
+				if entry == id {
+					return append(append([]string(nil), stack[i:]...), id)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, need := range c.Step(id).Needs {
+			if cycle := visit(need); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return nil
+	}
+	for _, step := range c.Steps {
+		if cycle := visit(step.ID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Step returns the step with id, or nil.
+func (c *Config) Step(id string) *Step {
+	for i := range c.Steps {
+		if c.Steps[i].ID == id {
+			return &c.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Policy returns the failure policy, defaulting to OnFailureStop.
+func (c *Config) Policy() string {
+	if c.OnFailure == "" {
+		return OnFailureStop
+	}
+	return c.OnFailure
+}
+
+// Upstream returns the step whose output branch baseBranch references.
+func Upstream(baseBranch string) (string, bool) {
+	match := branchRef.FindStringSubmatch(strings.TrimSpace(baseBranch))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// RunConfig returns the run config of the step with the workflow defaults
+// applied and a branch reference replaced by the upstream output branch,
+// looked up in outputBranches by step ID.
+func (c *Config) RunConfig(step *Step, outputBranches map[string]string) (*models.RunConfig, error) {
+	runConfig := step.RunConfig
+	if upstream, ok := Upstream(runConfig.BaseBranch); ok {
+		branch := outputBranches[upstream]
+		if branch == "" {
+			return nil, fmt.Errorf("step %q did not report an output branch for step %q to build on", upstream, step.ID)
+		}
+		runConfig.BaseBranch = branch
+	}
+	if runConfig.Repository == "" {
+		runConfig.Repository = c.Repository
+	}
+	if runConfig.Source == "" {
+		runConfig.Source = c.Source
+	}
+	if runConfig.RunType == "" {
+		runConfig.RunType = c.RunType
+	}
+	if runConfig.Model == "" && runConfig.Provider == "" {
+		runConfig.Model, runConfig.Provider = c.Model, c.Provider
+	}
+	if runConfig.Title == "" {
+		runConfig.Title = fmt.Sprintf("%s: %s", c.Name, step.ID)
+	}
+	return &runConfig, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2025 Ariel Frischer
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesWorkflow = `name: user-profiles
+repository: acme/webapp
+source: main
+steps:
+  - id: schema
+    prompt: Add a profiles table
+    outputBranch: feature/profiles-schema
+  - id: api
+    prompt: Add profile endpoints
+    baseBranch: ${{ steps.schema.outputBranch }}
+  - id: docs
+    prompt: Document profiles
+  - id: ui
+    needs: [api, docs]
+    prompt: Add the profile page
+    repository: acme/frontend
+`
+
+func TestLoadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "profiles.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(profilesWorkflow), 0644))
+
+	config, err := LoadConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, OnFailureStop, config.Policy())
+	require.Len(t, config.Steps, 4)
+	assert.Equal(t, []string{"schema"}, config.Step("api").Needs, "a branch reference adds the dependency")
+	assert.Equal(t, "feature/profiles-schema", config.Step("schema").OutputBranch)
+
+	_, err = config.RunConfig(config.Step("api"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `step "schema" did not report an output branch`)
+
+	runConfig, err := config.RunConfig(config.Step("api"), map[string]string{"schema": "feature/profiles-schema"})
+	require.NoError(t, err)
+	assert.Equal(t, "feature/profiles-schema", runConfig.BaseBranch)
+	assert.Equal(t, "acme/webapp", runConfig.Repository)
+	assert.Equal(t, "main", runConfig.Source)
+	assert.Equal(t, "user-profiles: api", runConfig.Title)
+
+	runConfig, err = config.RunConfig(config.Step("ui"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "acme/frontend", runConfig.Repository)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    string
+	}{
+		{"missing name", Config{Steps: []Step{{ID: "a"}}}, "name is required"},
+		{"bad policy", Config{Name: "w", OnFailure: "retry", Steps: []Step{{ID: "a"}}}, "onFailure must be"},
+		{"no steps", Config{Name: "w"}, "at least one step"},
+		{"duplicate", Config{Name: "w", Steps: []Step{step("a"), step("a")}}, `step "a" is defined twice`},
+		{"unknown need", Config{Name: "w", Steps: []Step{step("a", "b")}}, `needs unknown step "b"`},
+		{"self", Config{Name: "w", Steps: []Step{step("a", "a")}}, "needs itself"},
+		{"cycle", Config{Name: "w", Steps: []Step{step("a", "c"), step("b", "a"), step("c", "b")}}, "cycle: a -> c -> b -> a"},
+		{"bad reference", Config{Name: "w", Steps: []Step{withBase(step("a"), "${{ steps.b.branch }}")}}, "must have the form"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func step(id string, needs ...string) Step {
+	s := Step{ID: id, Needs: needs}
+	s.Prompt = "do " + id
+	return s
+}
+
+func withBase(s Step, baseBranch string) Step {
+	s.BaseBranch = baseBranch
+	return s
+}